@@ -0,0 +1,158 @@
+package phonical
+
+import (
+	"errors"
+	"time"
+	"unicode"
+)
+
+// ErrStopped is returned by activities when the engine stops before they
+// finish.
+var ErrStopped = errors.New("phonical: engine stopped")
+
+// Activity is an interactive exercise, such as a game or lesson, that takes
+// over key presses while it runs.
+type Activity interface {
+	Run(t *Tutor) error
+}
+
+// ActivityFunc adapts an ordinary function to the Activity interface.
+type ActivityFunc func(t *Tutor) error
+
+// Run calls f(t).
+func (f ActivityFunc) Run(t *Tutor) error {
+	return f(t)
+}
+
+// Tutor is an activity's handle on the engine. Its methods block until the
+// sound has played so activities read as a simple script.
+type Tutor struct {
+	e    *Engine
+	keys chan Key
+}
+
+// RunActivity runs a until it returns, routing key presses to it instead of
+// the usual letter playback. Keys must still reach the engine, either from
+// Run in another goroutine or through HandleKey.
+func (e *Engine) RunActivity(a Activity) error {
+	if err := e.Start(); err != nil {
+		return err
+	}
+
+	t := &Tutor{e: e, keys: make(chan Key, 16)}
+	e.mu.Lock()
+	if e.tutor != nil {
+		e.mu.Unlock()
+		return errors.New("phonical: an activity is already running")
+	}
+	e.tutor = t
+	e.mu.Unlock()
+
+	defer func() {
+		e.mu.Lock()
+		e.tutor = nil
+		e.mu.Unlock()
+	}()
+	return a.Run(t)
+}
+
+// routeToActivity hands key to the running activity, if any.
+func (e *Engine) routeToActivity(key Key) bool {
+	e.mu.Lock()
+	t := e.tutor
+	e.mu.Unlock()
+	if t == nil {
+		return false
+	}
+
+	select {
+	case t.keys <- key:
+	default:
+		e.logf("Activity not reading keys, dropping key")
+	}
+	return true
+}
+
+// Stats returns the engine's stats store, or nil when none is configured
+// or guest mode is on.
+func (t *Tutor) Stats() *StatsStore {
+	return t.e.recordingStats()
+}
+
+// Pack returns the sounds in use.
+func (t *Tutor) Pack() *Pack {
+	return t.e.pack.Load()
+}
+
+// Play plays the phoneme for letter.
+func (t *Tutor) Play(letter rune) {
+	if file, ok := t.e.pack.Load().letterSound(letter, t.e.variant); ok {
+		t.e.playAndWait(&queuedSound{sound: file, letter: letter})
+	}
+}
+
+// Say speaks text.
+func (t *Tutor) Say(text string) {
+	t.e.playAndWait(&queuedSound{sound: "say:" + text, say: text})
+}
+
+// Pause waits for d, or until the engine stops.
+func (t *Tutor) Pause(d time.Duration) {
+	select {
+	case <-t.e.clock.After(d):
+	case <-t.e.ctx.Done():
+	}
+}
+
+// NextKey waits up to timeout for a character key, discarding keys pressed
+// while sounds were playing. It returns false on timeout or when the engine
+// stops.
+func (t *Tutor) NextKey(timeout time.Duration) (rune, bool) {
+	t.drain()
+
+	deadline := t.e.clock.After(timeout)
+	for {
+		select {
+		case key := <-t.keys:
+			if key.Char != 0 {
+				return unicode.ToLower(key.Char), true
+			}
+		case <-deadline:
+			return 0, false
+		case <-t.e.ctx.Done():
+			return 0, false
+		}
+	}
+}
+
+// Stopped reports whether the engine has stopped.
+func (t *Tutor) Stopped() bool {
+	select {
+	case <-t.e.ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *Tutor) drain() {
+	for {
+		select {
+		case <-t.keys:
+		default:
+			return
+		}
+	}
+}
+
+// playAndWait queues item and blocks until it has played.
+func (e *Engine) playAndWait(item *queuedSound) {
+	// The player clears item.played once it closes it, so wait on a copy
+	played := make(chan struct{})
+	item.played = played
+	e.enqueue(item)
+	select {
+	case <-played:
+	case <-e.ctx.Done():
+	}
+}
@@ -0,0 +1,66 @@
+package phonical_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// runActivity runs a on h's engine, sending its result on the channel
+// returned.
+func runActivity(h *phonicaltest.Harness, a phonical.Activity) <-chan error {
+	done := make(chan error, 1)
+	go func() { done <- h.Engine.RunActivity(a) }()
+	return done
+}
+
+// waitPlayed lets virtual time run on until n sounds have played, and
+// returns them.
+func waitPlayed(t *testing.T, h *phonicaltest.Harness, n int) []string {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if played, ok := h.Sink.WaitFor(n, 5*time.Millisecond); ok {
+			return played
+		}
+		h.Clock.AdvanceToNext()
+	}
+	t.Fatalf("played %q, want %d sounds", h.Sink.Played(), n)
+	return nil
+}
+
+// answer types script once the activity has had a moment to start waiting
+// for a key after its last sound, since keys pressed while it is still
+// talking are thrown away.
+func answer(h *phonicaltest.Harness, script string) {
+	time.Sleep(20 * time.Millisecond)
+	h.Type(script)
+}
+
+// letterOf is the letter a sound such as "b.wav" stands for.
+func letterOf(sound string) string {
+	return strings.TrimSuffix(sound, ".wav")
+}
+
+func TestActivityTakesKeys(t *testing.T) {
+	h := phonicaltest.New(t)
+	keys := make(chan rune)
+	done := runActivity(h, phonical.ActivityFunc(func(tutor *phonical.Tutor) error {
+		c, _ := tutor.NextKey(time.Minute)
+		keys <- c
+		return nil
+	}))
+	// The activity gets the press instead of it playing
+	answer(h, "A")
+	if c := <-keys; c != 'a' {
+		t.Errorf("activity got %q, want 'a'", c)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	h.Type("b")
+	h.Expect("b.wav")
+}
@@ -0,0 +1,109 @@
+package phonical
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// digraphs are letter groups segmented as a single grapheme, longest first.
+var digraphs = []string{
+	"igh", "tch", "dge",
+	"sh", "ch", "th", "wh", "ph", "ck", "ng", "qu",
+	"ai", "ay", "ee", "ea", "ie", "oa", "oe", "oo", "ou", "ow", "oi", "oy",
+	"ar", "er", "ir", "or", "ur", "aw", "au", "ew",
+}
+
+// segment splits word into graphemes, grouping known digraphs.
+func segment(word string) []string {
+	return segmentWith(word, nil)
+}
+
+// segmentWith splits word into graphemes, grouping first the extra
+// graphemes, longest first, and then known digraphs.
+func segmentWith(word string, extra []string) []string {
+	groups := append(append([]string(nil), extra...), digraphs...)
+	var graphemes []string
+	for i := 0; i < len(word); {
+		_, n := utf8.DecodeRuneInString(word[i:])
+		for _, d := range groups {
+			if strings.HasPrefix(word[i:], d) {
+				n = len(d)
+				break
+			}
+		}
+		graphemes = append(graphemes, word[i:i+n])
+		i += n
+	}
+	return graphemes
+}
+
+// isVowelGrapheme reports whether g is a vowel sound. A "y" counts as a
+// vowel except at the start of a word.
+func isVowelGrapheme(g string, first bool) bool {
+	switch g[0] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return true
+	case 'y':
+		return !first
+	}
+	return false
+}
+
+// onsetRime splits word into the consonants before its first vowel and the
+// remainder, e.g. "chat" into "ch" and "at". Either part may be empty.
+func onsetRime(word string) (onset, rime string) {
+	graphemes := segment(word)
+	for i, g := range graphemes {
+		if isVowelGrapheme(g, i == 0) {
+			return strings.Join(graphemes[:i], ""), strings.Join(graphemes[i:], "")
+		}
+	}
+	return word, ""
+}
+
+// syllables splits word into spoken syllables using the usual classroom
+// rules: each vowel group is a syllable, a single consonant between vowels
+// starts the next syllable (ro-bot), two consonants are divided between
+// them (rab-bit) unless they form a digraph (fa-ther), and a final silent
+// "e" or consonant-"le" ending is kept with its neighbour (ta-ble).
+func syllables(word string) []string {
+	graphemes := segment(word)
+	n := len(graphemes)
+
+	// Find vowel graphemes, ignoring a silent final "e".
+	var vowels []int
+	for i, g := range graphemes {
+		if !isVowelGrapheme(g, i == 0) {
+			continue
+		}
+		if g == "e" && i == n-1 && len(vowels) > 0 && !(i >= 2 && graphemes[i-1] == "l" && !isVowelGrapheme(graphemes[i-2], false)) {
+			continue
+		}
+		if len(vowels) > 0 && vowels[len(vowels)-1] == i-1 && len(g) == 1 && len(graphemes[i-1]) == 1 {
+			// Adjacent single vowels without a digraph still share a syllable.
+			continue
+		}
+		vowels = append(vowels, i)
+	}
+	if len(vowels) < 2 {
+		return []string{word}
+	}
+
+	var parts []string
+	start := 0
+	for k := 0; k < len(vowels)-1; k++ {
+		consonants := vowels[k+1] - vowels[k] - 1
+		split := vowels[k] + 1
+		if consonants >= 2 {
+			split = vowels[k] + 1 + consonants/2
+		}
+		if consonants >= 2 && vowels[k+1] == n-1 && graphemes[n-1] == "e" && graphemes[n-2] == "l" {
+			// Consonant-le: "table" splits as ta-ble, "bubble" as bub-ble.
+			split = n - 3
+		}
+		parts = append(parts, strings.Join(graphemes[start:split], ""))
+		start = split
+	}
+	parts = append(parts, strings.Join(graphemes[start:], ""))
+	return parts
+}
@@ -0,0 +1,100 @@
+package phonical
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Announcer speaks short phrases such as "b, three times". Phrases are
+// spoken synchronously so they stay in order with the phoneme queue.
+type Announcer interface {
+	Say(text string) error
+}
+
+// ContextAnnouncer is an Announcer that can be cut off part-way through a
+// phrase. The engine uses SayContext when it is available so Stop and
+// Interrupt don't wait for speech to finish.
+type ContextAnnouncer interface {
+	Announcer
+	SayContext(ctx context.Context, text string) error
+}
+
+// AnnouncerFunc adapts an ordinary function to the Announcer interface.
+type AnnouncerFunc func(text string) error
+
+// Say calls f(text).
+func (f AnnouncerFunc) Say(text string) error {
+	return f(text)
+}
+
+// SystemAnnouncer speaks with the platform's text-to-speech command: say on
+// macOS, espeak or spd-say on Linux and System.Speech on Windows.
+type SystemAnnouncer struct{}
+
+// Say speaks text and waits for it to finish.
+func (a SystemAnnouncer) Say(text string) error {
+	return a.SayContext(context.Background(), text)
+}
+
+// SayContext speaks text and waits for it to finish, stopping the speech
+// command if ctx is cancelled first.
+func (SystemAnnouncer) SayContext(ctx context.Context, text string) error {
+	cmd, err := speechCommand(ctx, text)
+	if err != nil {
+		return err
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("speaking %q: %w: %s", text, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func speechCommand(ctx context.Context, text string) (*exec.Cmd, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.CommandContext(ctx, "say", text), nil
+	case "windows":
+		script := "Add-Type -AssemblyName System.Speech; " +
+			"(New-Object System.Speech.Synthesis.SpeechSynthesizer).Speak($args[0])"
+		return exec.CommandContext(ctx, "powershell", "-NoProfile", "-Command", script, text), nil
+	default:
+		for _, name := range []string{"espeak-ng", "espeak", "spd-say"} {
+			if path, err := exec.LookPath(name); err == nil {
+				if name == "spd-say" {
+					return exec.CommandContext(ctx, path, "--wait", text), nil
+				}
+				return exec.CommandContext(ctx, path, text), nil
+			}
+		}
+		return nil, errors.New("no text-to-speech command found (install espeak-ng or speech-dispatcher)")
+	}
+}
+
+var numberWords = []string{
+	"zero", "one", "two", "three", "four", "five", "six", "seven", "eight",
+	"nine", "ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen",
+	"sixteen", "seventeen", "eighteen", "nineteen", "twenty",
+}
+
+// numberWord spells out small counts so they are spoken naturally.
+func numberWord(n int) string {
+	if n >= 0 && n < len(numberWords) {
+		return numberWords[n]
+	}
+	return fmt.Sprint(n)
+}
+
+// repeatPhrase describes a letter pressed count times, e.g. "b, three times".
+func repeatPhrase(letter rune, count int) string {
+	if count == 2 {
+		return fmt.Sprintf("%c, twice", letter)
+	}
+	return fmt.Sprintf("%c, %s times", letter, numberWord(count))
+}
@@ -0,0 +1,143 @@
+package phonical
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Assessment sections.
+const (
+	SectionLetterSounds  = "letter-sounds"
+	SectionRealWords     = "real-words"
+	SectionNonsenseWords = "nonsense-words"
+)
+
+// assessWords is how many words each decoding section asks for.
+const assessWords = 5
+
+// AssessmentItem is one question of an assessment.
+type AssessmentItem struct {
+	Section string `json:"section"`
+	Target  string `json:"target"`
+	Answer  string `json:"answer"`
+	Correct bool   `json:"correct"`
+	// Seconds is how long the answer took; unanswered items have the full
+	// time allowed.
+	Seconds float64 `json:"seconds"`
+}
+
+// AssessmentReport is the scored result of an assessment, for teachers to
+// track against benchmarks.
+type AssessmentReport struct {
+	Profile  string           `json:"profile,omitempty"`
+	Date     time.Time        `json:"date"`
+	Sections map[string]Score `json:"sections"`
+	Items    []AssessmentItem `json:"items"`
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *AssessmentReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes one row per item, with a header, for spreadsheets.
+func (r *AssessmentReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"profile", "date", "section", "target", "answer", "correct", "seconds"})
+	date := r.Date.Format(time.DateOnly)
+	for _, item := range r.Items {
+		cw.Write([]string{
+			r.Profile, date, item.Section, item.Target, item.Answer,
+			strconv.FormatBool(item.Correct), strconv.FormatFloat(item.Seconds, 'f', 1, 64),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Assess returns a structured assessment of letter-sound recall and
+// simple decoding, using the letters taught so far. It plays every letter
+// sound for the child to press, then blends a few real and nonsense CVC
+// words for them to type. No hints or corrections are given, so the
+// scores stay comparable between sittings. The report is passed to done.
+func Assess(done func(*AssessmentReport)) Activity {
+	return ActivityFunc(func(t *Tutor) error {
+		report := &AssessmentReport{Date: t.e.clock.Now(), Sections: make(map[string]Score)}
+		add := func(item AssessmentItem) {
+			report.Items = append(report.Items, item)
+			score := report.Sections[item.Section]
+			score.record(item.Correct)
+			report.Sections[item.Section] = score
+		}
+
+		letters := t.e.packLetters()
+		sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+		rand.Shuffle(len(letters), func(i, j int) { letters[i], letters[j] = letters[j], letters[i] })
+		t.Say("Let's see what you know. Press the letter that makes each sound.")
+		for _, letter := range letters {
+			t.Play(letter)
+			start := t.e.clock.Now()
+			pressed, ok := t.NextKey(answerTimeout)
+			if t.Stopped() {
+				return ErrStopped
+			}
+			item := AssessmentItem{Section: SectionLetterSounds, Target: string(letter), Seconds: answerTimeout.Seconds()}
+			if ok {
+				item.Answer = string(pressed)
+				item.Correct = pressed == letter
+				item.Seconds = t.e.clock.Now().Sub(start).Seconds()
+			}
+			add(item)
+		}
+
+		var realWords []string
+		for _, f := range t.e.taughtFamilies() {
+			realWords = append(realWords, f.words[rand.Intn(len(f.words))])
+		}
+		rand.Shuffle(len(realWords), func(i, j int) { realWords[i], realWords[j] = realWords[j], realWords[i] })
+		if len(realWords) > assessWords {
+			realWords = realWords[:assessWords]
+		}
+		sections := []struct {
+			name, intro string
+			words       []string
+		}{
+			{SectionRealWords, "Now listen to each word, type it, then press space.", realWords},
+			{SectionNonsenseWords, "These are made-up words. Type each one, then press space.", NonsenseWords(t.e.packLetters(), assessWords)},
+		}
+		for _, section := range sections {
+			if len(section.words) == 0 {
+				continue
+			}
+			t.Say(section.intro)
+			for _, word := range section.words {
+				t.playBlend(word)
+				start := t.e.clock.Now()
+				typed, err := readSpelling(t)
+				if err != nil {
+					return err
+				}
+				add(AssessmentItem{
+					Section: section.name,
+					Target:  word,
+					Answer:  typed,
+					Correct: typed == word,
+					Seconds: t.e.clock.Now().Sub(start).Seconds(),
+				})
+			}
+		}
+
+		t.Say("All done. Thank you!")
+		if done != nil {
+			done(report)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,80 @@
+package phonical_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestAssess(t *testing.T) {
+	// With only vowels taught there are no words to decode
+	pack := letterPack(t, map[string]string{"a": "a", "i": "i"})
+	h := phonicaltest.New(t, phonical.WithPack(pack), phonical.WithWatchdog(0))
+	reports := make(chan *phonical.AssessmentReport, 1)
+	done := runActivity(h, phonical.Assess(func(r *phonical.AssessmentReport) { reports <- r }))
+
+	first := letterOf(waitPlayed(t, h, 2)[1])
+	answer(h, first)
+	second := letterOf(waitPlayed(t, h, 3)[2])
+	answer(h, "z")
+	if got := waitPlayed(t, h, 4)[3]; got != "say:All done. Thank you!" {
+		t.Errorf("ended with %q", got)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	r := <-reports
+	if got := r.Sections[phonical.SectionLetterSounds]; got != (phonical.Score{Attempts: 2, Correct: 1}) {
+		t.Errorf("letter sounds %+v, want one of two", got)
+	}
+	want := []phonical.AssessmentItem{
+		{Section: phonical.SectionLetterSounds, Target: first, Answer: first, Correct: true},
+		{Section: phonical.SectionLetterSounds, Target: second, Answer: "z"},
+	}
+	if len(r.Items) != len(want) {
+		t.Fatalf("items %+v, want %+v", r.Items, want)
+	}
+	for i, item := range r.Items {
+		item.Seconds = 0
+		if item != want[i] {
+			t.Errorf("item %d is %+v, want %+v", i, item, want[i])
+		}
+	}
+}
+
+func TestAssessmentReport(t *testing.T) {
+	r := &phonical.AssessmentReport{
+		Profile:  "sam",
+		Date:     time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+		Sections: map[string]phonical.Score{phonical.SectionRealWords: {Attempts: 1, Correct: 1}},
+		Items: []phonical.AssessmentItem{
+			{Section: phonical.SectionRealWords, Target: "cat", Answer: "cat", Correct: true, Seconds: 2.5},
+		},
+	}
+
+	var csv bytes.Buffer
+	if err := r.WriteCSV(&csv); err != nil {
+		t.Fatal(err)
+	}
+	want := "profile,date,section,target,answer,correct,seconds\nsam,2024-03-01,real-words,cat,cat,true,2.5\n"
+	if csv.String() != want {
+		t.Errorf("CSV\n%s\nwant\n%s", csv.String(), want)
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var back phonical.AssessmentReport
+	if err := json.Unmarshal(buf.Bytes(), &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.Profile != "sam" || !back.Date.Equal(r.Date) || back.Items[0] != r.Items[0] {
+		t.Errorf("JSON round trip gave %+v", back)
+	}
+}
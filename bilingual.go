@@ -0,0 +1,80 @@
+package phonical
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Bilingual chooses when a letter plays in the second language set with
+// WithSecondLanguage.
+type Bilingual int
+
+const (
+	// BilingualShift plays the second language for letters typed with
+	// Shift, and the first language otherwise.
+	BilingualShift Bilingual = iota
+	// BilingualAlternate switches language with every letter.
+	BilingualAlternate
+	// BilingualBoth plays each letter in the first language and then the
+	// second.
+	BilingualBoth
+)
+
+var bilingualNames = map[Bilingual]string{
+	BilingualShift:     "shift",
+	BilingualAlternate: "alternate",
+	BilingualBoth:      "both",
+}
+
+func (b Bilingual) String() string {
+	if name, ok := bilingualNames[b]; ok {
+		return name
+	}
+	return fmt.Sprintf("Bilingual(%d)", int(b))
+}
+
+// ParseBilingual converts a scheme name such as "alternate" into a
+// Bilingual.
+func ParseBilingual(name string) (Bilingual, error) {
+	for b, n := range bilingualNames {
+		if strings.EqualFold(n, name) {
+			return b, nil
+		}
+	}
+	return BilingualShift, fmt.Errorf("unknown scheme %q (want shift, alternate or both)", name)
+}
+
+// WithSecondLanguage plays letters from pack as well as the main pack, for
+// immersion-style practice, choosing between them by scheme. Letters pack
+// has no sound for always play in the main language.
+func WithSecondLanguage(pack *Pack, scheme Bilingual) Option {
+	return func(e *Engine) {
+		e.second = pack
+		e.bilingual = scheme
+	}
+}
+
+// secondSound returns the second language's sound for a letter typed
+// with or without Shift, and whether it replaces the main sound rather
+// than following it.
+func (e *Engine) secondSound(char rune, shifted bool) (file string, replace, ok bool) {
+	if e.second == nil {
+		return "", false, false
+	}
+	file, ok = e.second.letterSound(char, "")
+	if !ok {
+		return "", false, false
+	}
+	switch e.bilingual {
+	case BilingualShift:
+		return file, true, shifted
+	case BilingualAlternate:
+		e.mu.Lock()
+		e.alternate = !e.alternate
+		turn := e.alternate
+		e.mu.Unlock()
+		return file, true, !turn
+	default:
+		return file, false, true
+	}
+}
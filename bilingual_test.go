@@ -0,0 +1,69 @@
+package phonical_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// secondPack returns a pack with its own recordings of a and b only.
+func secondPack(t *testing.T) *phonical.Pack {
+	t.Helper()
+	dir := t.TempDir()
+	manifest := "name = \"Second\"\n\n[letters]\na = \"second-a.wav\"\nb = \"second-b.wav\"\n"
+	for _, name := range []string{"a", "b"} {
+		data, err := os.ReadFile(filepath.Join("sounds", phonical.DefaultSounds, name+".wav"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "second-"+name+".wav"), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, phonical.ManifestName), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pack, err := phonical.LoadPack(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pack
+}
+
+func TestBilingual(t *testing.T) {
+	tests := []struct {
+		scheme phonical.Bilingual
+		script string
+		want   []string
+	}{
+		// Shift picks the second language; c has no second sound
+		{phonical.BilingualShift, "aAbBC", []string{"a.wav", "second-a.wav", "b.wav", "second-b.wav", "c.wav"}},
+		{phonical.BilingualAlternate, "abab", []string{"a.wav", "second-b.wav", "a.wav", "second-b.wav"}},
+		{phonical.BilingualBoth, "ac", []string{"a.wav", "second-a.wav", "c.wav"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scheme.String(), func(t *testing.T) {
+			h := phonicaltest.New(t, phonical.WithSecondLanguage(secondPack(t), tt.scheme))
+			h.Type(tt.script)
+			h.Expect(tt.want...)
+		})
+	}
+}
+
+func TestParseBilingual(t *testing.T) {
+	for _, name := range []string{"shift", "Alternate", "BOTH"} {
+		b, err := phonical.ParseBilingual(name)
+		if err != nil {
+			t.Errorf("ParseBilingual(%q): %v", name, err)
+		} else if b.String() != strings.ToLower(name) {
+			t.Errorf("ParseBilingual(%q) = %v", name, b)
+		}
+	}
+	if _, err := phonical.ParseBilingual("mixed"); err == nil {
+		t.Error("unknown scheme accepted")
+	}
+}
@@ -0,0 +1,112 @@
+package phonical
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// SuggestedBluetoothLatency is a typical delay added by Bluetooth
+// headphones, suggested for WithOutputLatency when they are detected.
+const SuggestedBluetoothLatency = 200 * time.Millisecond
+
+// WithOutputLatency tells the engine how late the output device plays
+// sound, as Bluetooth headphones do. The speaker buffers more, so the
+// wireless link doesn't drop out, and in visual mode each letter is shown
+// after d so it appears as its sound is heard.
+func WithOutputLatency(d time.Duration) Option {
+	return func(e *Engine) {
+		e.outputLatency = d
+	}
+}
+
+// speakerBufferFor returns the speaker buffer to use for an output with
+// the given latency: a quarter of it, never less than the usual buffer.
+func speakerBufferFor(latency time.Duration) time.Duration {
+	return max(speakerBuffer, latency/4)
+}
+
+// BluetoothOutput reports whether sound currently goes to a Bluetooth
+// device, and its name. It is supported on macOS and on Linux with
+// PulseAudio or PipeWire.
+func BluetoothOutput() (string, bool, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		out, err := exec.Command("system_profiler", "SPAudioDataType").Output()
+		if err != nil {
+			return "", false, err
+		}
+		name, ok := macBluetoothOutput(out)
+		return name, ok, nil
+	case "linux":
+		out, err := exec.Command("pactl", "get-default-sink").Output()
+		if err != nil {
+			return "", false, err
+		}
+		sink := strings.TrimSpace(string(out))
+		return sink, strings.HasPrefix(sink, "bluez_"), nil
+	default:
+		return "", false, errors.New("bluetooth detection is not supported on " + runtime.GOOS)
+	}
+}
+
+// macBluetoothOutput finds the default output device in system_profiler's
+// audio report and whether it uses Bluetooth.
+func macBluetoothOutput(report []byte) (string, bool) {
+	return macDefaultOutput(report)
+}
+
+// macDefaultOutput finds the default output device in system_profiler's
+// audio report and whether it uses Bluetooth. Each device is a line ending
+// in a colon followed by indented properties.
+func macDefaultOutput(report []byte) (string, bool) {
+	var device string
+	var bluetooth, output bool
+	scanner := bufio.NewScanner(bytes.NewReader(report))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasSuffix(line, ":") && !strings.Contains(line, ": "):
+			if output {
+				return device, bluetooth
+			}
+			device, bluetooth, output = strings.TrimSuffix(line, ":"), false, false
+		case line == "Transport: Bluetooth":
+			bluetooth = true
+		case line == "Default Output Device: Yes":
+			output = true
+		}
+	}
+	if !output {
+		return "", false
+	}
+	return device, bluetooth
+}
+
+// OutputDevice returns the name of the default sound output device. It is
+// supported on macOS and on Linux with PulseAudio or PipeWire.
+func OutputDevice() (string, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		out, err := exec.Command("system_profiler", "SPAudioDataType").Output()
+		if err != nil {
+			return "", err
+		}
+		if name, _ := macDefaultOutput(out); name != "" {
+			return name, nil
+		}
+		return "", errors.New("no default output device found")
+	case "linux":
+		out, err := exec.Command("pactl", "get-default-sink").Output()
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(out)), nil
+	default:
+		return "", errors.New("output device detection is not supported on " + runtime.GOOS)
+	}
+}
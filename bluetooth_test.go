@@ -0,0 +1,54 @@
+package phonical_test
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestOutputLatencyDelaysFlash(t *testing.T) {
+	var out syncBuffer
+	h := phonicaltest.New(t, phonical.WithVisual(true), phonical.WithWatchdog(0),
+		phonical.WithOutputLatency(phonical.SuggestedBluetoothLatency), phonical.WithOutput(&out))
+	h.Type("a")
+	h.Expect("a.wav")
+
+	// The letter shows as the headphones play it, not as it is sent
+	waitWaiters(t, h.Clock, 1)
+	h.Clock.Advance(phonical.SuggestedBluetoothLatency - time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	if strings.Contains(out.String(), "   A a   ") {
+		t.Fatal("letter shown before its sound was heard")
+	}
+	h.Clock.Advance(time.Millisecond)
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(out.String(), "   A a   ") {
+		if time.Now().After(deadline) {
+			t.Fatalf("output %q, want the letter in it", out.String())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBluetoothOutput(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("reads the PulseAudio default sink")
+	}
+	for _, tt := range []struct {
+		sink      string
+		bluetooth bool
+	}{
+		{"bluez_output.00_1B_66_AA_BB_CC.1", true},
+		{"alsa_output.pci-0000_00_1f.3.analog-stereo", false},
+	} {
+		fakeCommand(t, "pactl", tt.sink)
+		name, ok, err := phonical.BluetoothOutput()
+		if err != nil || name != tt.sink || ok != tt.bluetooth {
+			t.Errorf("BluetoothOutput() = %q, %v, %v, want %q, %v", name, ok, err, tt.sink, tt.bluetooth)
+		}
+	}
+}
@@ -0,0 +1,377 @@
+package phonical
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/faiface/beep/wav"
+)
+
+// PackArchiveExt is the extension of a pack bundled by BundlePack.
+const PackArchiveExt = ".phonpack"
+
+// bundlePeak is the level every recording in a bundle is scaled to, a
+// little under full scale, so one voice's letters match each other and
+// other packs.
+const bundlePeak = 0.9
+
+// sharingLicenses are the licenses that let a pack be passed on. Packs
+// under any other license must include its text in a LICENSE file.
+var sharingLicenses = map[string]bool{
+	"CC0-1.0":         true,
+	"CC-BY-4.0":       true,
+	"CC-BY-SA-4.0":    true,
+	"CC-BY-NC-4.0":    true,
+	"CC-BY-NC-SA-4.0": true,
+	"MIT":             true,
+	"Apache-2.0":      true,
+}
+
+// maxPackSize is the most an installed pack may unpack to, so a small
+// archive can't fill the disk.
+const maxPackSize = 256 << 20
+
+// licenseFiles are the names a pack's license text may be kept under.
+var licenseFiles = []string{"LICENSE", "LICENSE.txt", "LICENSE.md", "COPYING"}
+
+// PacksDir returns where installed packs are kept, one directory each.
+func PacksDir() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "packs"), nil
+}
+
+// BundlePack checks the pack in dir and writes it to w as a .phonpack
+// archive for sharing: a zip of its manifest, license, checksums and
+// pictures and of every recording, converted to WAV at the output rate and
+// scaled to the same peak level. The pack must load without problems and
+// name its license.
+func BundlePack(w io.Writer, dir string) error {
+	p, err := LoadPack(dir)
+	if err != nil {
+		return err
+	}
+	if problems := p.Validate(); len(problems) > 0 {
+		return fmt.Errorf("%s has %d sounds that can't be played, starting with %s (%s): %v",
+			p.Name, len(problems), problems[0].Entry, problems[0].File, problems[0].Err)
+	}
+	license, err := checkLicense(p)
+	if err != nil {
+		return err
+	}
+
+	data, err := fs.ReadFile(p.fsys, ManifestName)
+	if err != nil {
+		return err
+	}
+	var m manifest
+	if _, err := toml.Decode(string(data), &m); err != nil {
+		return err
+	}
+	files := map[string]bool{}
+	for _, file := range p.entries() {
+		files[file] = true
+	}
+	names := make([]string, 0, len(files))
+	for file := range files {
+		names = append(names, file)
+	}
+	sort.Strings(names)
+
+	contents := map[string][]byte{}
+	converted := map[string]string{}
+	for _, file := range names {
+		buffer, err := p.load(file)
+		if err != nil {
+			return err
+		}
+		samples := pcm(buffer)
+		normalize(samples, bundlePeak)
+		var out memFile
+		if err := wav.Encode(&out, samplesStreamer(samples), outputFormat); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		renamed := strings.TrimSuffix(file, path.Ext(file)) + ".wav"
+		if _, ok := contents[renamed]; ok {
+			return fmt.Errorf("%s and another recording would both become %s; rename one", file, renamed)
+		}
+		converted[file] = renamed
+		contents[renamed] = out.data
+	}
+	for _, file := range p.Pictures {
+		if _, ok := contents[file]; ok {
+			continue
+		}
+		if contents[file], err = fs.ReadFile(p.fsys, file); err != nil {
+			return err
+		}
+	}
+
+	// Point the manifest at the converted files
+	m.renameFiles(func(file string) string {
+		if renamed, ok := converted[file]; ok {
+			return renamed
+		}
+		return file
+	})
+	var manifest bytes.Buffer
+	if err := toml.NewEncoder(&manifest).Encode(m); err != nil {
+		return err
+	}
+	contents[ManifestName] = manifest.Bytes()
+	if license != "" {
+		if contents[license], err = fs.ReadFile(p.fsys, license); err != nil {
+			return err
+		}
+	}
+
+	archive := zip.NewWriter(w)
+	archive.SetComment(describePack(p))
+	var sums bytes.Buffer
+	written := make([]string, 0, len(contents))
+	for name := range contents {
+		written = append(written, name)
+	}
+	sort.Strings(written)
+	for _, name := range written {
+		f, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(contents[name]); err != nil {
+			return err
+		}
+		sum := sha256.Sum256(contents[name])
+		fmt.Fprintf(&sums, "%s  %s\n", hex.EncodeToString(sum[:]), name)
+	}
+	f, err := archive.Create(ChecksumsName)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(sums.Bytes()); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// checkLicense makes sure the pack may be shared, returning its license
+// file, if it has one.
+func checkLicense(p *Pack) (string, error) {
+	if p.License == "" {
+		return "", fmt.Errorf("%s: set license in %s, such as \"CC-BY-4.0\", before sharing it", p.Name, ManifestName)
+	}
+	for _, name := range licenseFiles {
+		if _, err := fs.Stat(p.fsys, name); err == nil {
+			return name, nil
+		}
+	}
+	if !sharingLicenses[p.License] {
+		return "", fmt.Errorf("%s: license %q isn't one Phonical knows, so include its text in a LICENSE file", p.Name, p.License)
+	}
+	return "", nil
+}
+
+// describePack summarises the pack's metadata for the archive comment.
+func describePack(p *Pack) string {
+	desc := p.Name
+	if p.Version != "" {
+		desc += " " + p.Version
+	}
+	if p.Author != "" {
+		desc += " by " + p.Author
+	}
+	return desc + " (" + p.License + ")"
+}
+
+// normalize scales samples in place so their peak is at level.
+func normalize(samples [][2]float64, level float64) {
+	peak := 0.0
+	for _, s := range samples {
+		peak = math.Max(peak, math.Max(math.Abs(s[0]), math.Abs(s[1])))
+	}
+	if peak == 0 {
+		return
+	}
+	gain := level / peak
+	for i := range samples {
+		samples[i][0] *= gain
+		samples[i][1] *= gain
+	}
+}
+
+// memFile is an in-memory io.WriteSeeker for encoding WAV files.
+type memFile struct {
+	data []byte
+	pos  int
+}
+
+func (m *memFile) Write(b []byte) (int, error) {
+	if end := m.pos + len(b); end > len(m.data) {
+		m.data = append(m.data, make([]byte, end-len(m.data))...)
+	}
+	n := copy(m.data[m.pos:], b)
+	m.pos += n
+	return n, nil
+}
+
+func (m *memFile) Seek(offset int64, whence int) (int64, error) {
+	pos := int(offset)
+	switch whence {
+	case io.SeekCurrent:
+		pos += m.pos
+	case io.SeekEnd:
+		pos += len(m.data)
+	}
+	if pos < 0 {
+		return 0, errors.New("seek before start of file")
+	}
+	m.pos = pos
+	return int64(pos), nil
+}
+
+// InstallPack unpacks the .phonpack archive at file into PacksDir, under
+// the archive's name, and checks it against its checksums. It returns the
+// installed pack, usable by that name with --pack.
+func InstallPack(file string) (*Pack, error) {
+	if !strings.EqualFold(filepath.Ext(file), PackArchiveExt) {
+		return nil, fmt.Errorf("%s is not a %s file", file, PackArchiveExt)
+	}
+	archive, err := zip.OpenReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+	if _, err := fs.Stat(archive, ManifestName); err != nil {
+		return nil, fmt.Errorf("%s has no %s", file, ManifestName)
+	}
+
+	packs, err := PacksDir()
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(packs, strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)))
+	// Unpack beside the destination, so a bad archive leaves any earlier
+	// install alone
+	tmp := dir + ".installing"
+	if err := os.RemoveAll(tmp); err != nil {
+		return nil, err
+	}
+	remaining := int64(maxPackSize)
+	for _, f := range archive.File {
+		if strings.HasSuffix(f.Name, "/") {
+			continue
+		}
+		dest, err := extractPath(tmp, f)
+		if err == nil {
+			err = extract(f, dest, &remaining)
+		}
+		if err != nil {
+			os.RemoveAll(tmp)
+			return nil, fmt.Errorf("installing %s: %w", file, err)
+		}
+	}
+
+	p, err := LoadPack(tmp)
+	if err == nil {
+		err = checkInstall(p)
+	}
+	if err == nil {
+		if err = os.RemoveAll(dir); err == nil {
+			err = os.Rename(tmp, dir)
+		}
+	}
+	if err != nil {
+		os.RemoveAll(tmp)
+		return nil, fmt.Errorf("installing %s: %w", file, err)
+	}
+	return LoadPack(dir)
+}
+
+// checkInstall makes sure an unpacked pack matches its checksums and has
+// every file its manifest names.
+func checkInstall(p *Pack) error {
+	problems, err := p.Verify()
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s: %s", problems[0].File, problems[0].Problem)
+	}
+	var files []string
+	for _, file := range p.entries() {
+		files = append(files, file)
+	}
+	for _, file := range p.Pictures {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		if _, err := fs.Stat(p.fsys, file); err != nil {
+			return fmt.Errorf("%s is missing from the archive", file)
+		}
+	}
+	return nil
+}
+
+// extractPath returns where the archived file f is unpacked under dir,
+// refusing names that would land outside it, as "..\x" does on Windows,
+// and anything but plain files.
+func extractPath(dir string, f *zip.File) (string, error) {
+	if !fs.ValidPath(f.Name) || strings.ContainsAny(f.Name, `\:`) {
+		return "", fmt.Errorf("%q is not a file name a pack may use", f.Name)
+	}
+	if !f.Mode().IsRegular() {
+		return "", fmt.Errorf("%s is not a plain file", f.Name)
+	}
+	dest := filepath.Join(dir, filepath.FromSlash(f.Name))
+	if rel, err := filepath.Rel(dir, dest); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the pack", f.Name)
+	}
+	return dest, nil
+}
+
+// extract writes the archived file f to dest, taking what it writes from
+// remaining, the bytes the rest of the pack may unpack to.
+func extract(f *zip.File, dest string, remaining *int64) error {
+	if f.UncompressedSize64 > uint64(*remaining) {
+		return fmt.Errorf("pack unpacks to more than %d MB", maxPackSize>>20)
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	// The header's size may lie
+	n, err := io.Copy(out, io.LimitReader(r, *remaining+1))
+	if err == nil && n > *remaining {
+		err = fmt.Errorf("pack unpacks to more than %d MB", maxPackSize>>20)
+	}
+	if err != nil {
+		out.Close()
+		return err
+	}
+	*remaining -= n
+	return out.Close()
+}
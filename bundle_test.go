@@ -0,0 +1,167 @@
+package phonical_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"phonical"
+)
+
+// silentMP3 is a few frames of silent MPEG-1 layer III audio.
+func silentMP3() []byte {
+	frame := make([]byte, 417) // 128 kbit/s at 44.1 kHz
+	copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+	return bytes.Repeat(frame, 10)
+}
+
+// writePack writes a pack of manifest and the named files.
+func writePack(t *testing.T, manifest string, files map[string][]byte) string {
+	t.Helper()
+	dir := t.TempDir()
+	files[phonical.ManifestName] = []byte(manifest)
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestBundleAndInstall(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	// Literal strings and no spacing, and a recording converted to WAV on
+	// the way
+	dir := writePack(t, "name='Mine'\nlicense='CC0-1.0'\n[letters]\na='a.mp3'\n[limits]\n'a.mp3'=500\n[pictures]\na='apple.png'\n",
+		map[string][]byte{"a.mp3": silentMP3(), "apple.png": []byte("picture")})
+
+	archive := filepath.Join(t.TempDir(), "mine"+phonical.PackArchiveExt)
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := phonical.BundlePack(f, dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	pack, err := phonical.InstallPack(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pack.Name != "Mine" || pack.Letters['a'] != "a.wav" {
+		t.Errorf("installed %s with a = %q, want Mine with a = a.wav", pack.Name, pack.Letters['a'])
+	}
+	if limit := pack.Limits["a.wav"]; limit != 500*time.Millisecond {
+		t.Errorf("a.wav limited to %s, want 500ms", limit)
+	}
+	if problems := pack.Validate(); len(problems) > 0 {
+		t.Errorf("installed pack has problems: %v", problems)
+	}
+	if pack.Pictures['a'] != "apple.png" {
+		t.Errorf("installed picture of a %q, want apple.png", pack.Pictures['a'])
+	}
+}
+
+func TestInstallNeedsEveryFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	archive := filepath.Join(t.TempDir(), "broken"+phonical.PackArchiveExt)
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Nothing fails its checksum, but the manifest names a recording left
+	// out
+	zw := zip.NewWriter(f)
+	w, err := zw.Create(phonical.ManifestName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("name = \"Broken\"\n\n[letters]\na = \"a.mp3\"\n"))
+	if _, err := zw.Create(phonical.ChecksumsName); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if _, err := phonical.InstallPack(archive); err == nil || !strings.Contains(err.Error(), "a.mp3 is missing") {
+		t.Errorf("installing got %v, want a.mp3 missing", err)
+	}
+	packs, err := phonical.PacksDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(packs, "broken")); !os.IsNotExist(err) {
+		t.Errorf("broken pack was installed: %v", err)
+	}
+}
+
+func TestInstallRefusesUnsafeArchives(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	manifest := "name = \"Unsafe\"\n\n[letters]\na = \"a.wav\"\n"
+	for _, tc := range []struct {
+		name   string
+		header zip.FileHeader
+		want   string
+	}{
+		// Climbs out of the pack on Windows
+		{"backslash", zip.FileHeader{Name: `..\..\x.wav`}, "not a file name"},
+		{"drive", zip.FileHeader{Name: "C:x.wav"}, "not a file name"},
+		{"symlink", func() zip.FileHeader {
+			h := zip.FileHeader{Name: "a.wav"}
+			h.SetMode(os.ModeSymlink | 0o777)
+			return h
+		}(), "not a plain file"},
+		// A few bytes claiming to unpack to a terabyte
+		{"huge", zip.FileHeader{Name: "a.wav", Method: zip.Store, UncompressedSize64: 1 << 40}, "more than 256 MB"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			archive := filepath.Join(t.TempDir(), "unsafe"+phonical.PackArchiveExt)
+			f, err := os.Create(archive)
+			if err != nil {
+				t.Fatal(err)
+			}
+			zw := zip.NewWriter(f)
+			w, err := zw.Create(phonical.ManifestName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w.Write([]byte(manifest))
+			if tc.header.UncompressedSize64 > 0 {
+				w, err = zw.CreateRaw(&tc.header)
+			} else {
+				w, err = zw.CreateHeader(&tc.header)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			w.Write([]byte("data"))
+			if err := zw.Close(); err != nil {
+				t.Fatal(err)
+			}
+			f.Close()
+
+			if _, err := phonical.InstallPack(archive); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("installing got %v, want %q", err, tc.want)
+			}
+			packs, err := phonical.PacksDir()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if entries, _ := os.ReadDir(packs); len(entries) > 0 {
+				t.Errorf("left %s in the packs directory", entries[0].Name())
+			}
+		})
+	}
+}
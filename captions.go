@@ -0,0 +1,216 @@
+package phonical
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// captionLetters is the most letters a caption keeps while a long run is
+// typed without a space.
+const captionLetters = 40
+
+// Caption is what the captions show, sent to the page as JSON.
+type Caption struct {
+	// Text is the whole line: the letters sounded so far in the word being
+	// typed, or the phrase being spoken.
+	Text string `json:"text"`
+	// Letter is the letter playing now, the last of Text, for the page to
+	// highlight karaoke-style.
+	Letter string `json:"letter,omitempty"`
+}
+
+// Captions turns what the engine plays into on-screen captions for
+// streamed typing lessons. It is an EventSink to add with WithSink, and an
+// http.Handler serving a page to add as an OBS browser source, updated over
+// a WebSocket at /captions, and it can also keep a text file up to date for
+// an OBS text source.
+type Captions struct {
+	file    string
+	updates chan Caption
+	done    chan struct{}
+	once    sync.Once
+	stop    sync.Once
+
+	mu      sync.Mutex
+	letters []rune
+	current Caption
+	clients map[*wsConn]bool
+}
+
+// NewCaptions returns captions that also write each caption to file, if
+// it isn't empty.
+func NewCaptions(file string) *Captions {
+	return &Captions{
+		file:    file,
+		updates: make(chan Caption, 64),
+		done:    make(chan struct{}),
+		clients: make(map[*wsConn]bool),
+	}
+}
+
+// Close stops updating the captions and closes the open pages'
+// connections.
+func (c *Captions) Close() error {
+	c.stop.Do(func() { close(c.done) })
+	c.mu.Lock()
+	clients := c.clients
+	c.clients = make(map[*wsConn]bool)
+	c.mu.Unlock()
+	for ws := range clients {
+		ws.Close()
+	}
+	return nil
+}
+
+// Played updates the caption for a sound starting to play. Letters build
+// up a line until a cue or other sound without one ends the word.
+func (c *Captions) Played(ev PlayEvent) {
+	c.once.Do(func() { go c.publish() })
+
+	c.mu.Lock()
+	switch {
+	case ev.Text != "":
+		c.letters = c.letters[:0]
+		c.current = Caption{Text: ev.Text}
+	case ev.Letter != 0:
+		if len(c.letters) >= captionLetters {
+			c.letters = c.letters[1:]
+		}
+		c.letters = append(c.letters, ev.Letter)
+		c.current = Caption{Text: string(c.letters), Letter: string(ev.Letter)}
+	default:
+		// Show the finished word until the next one starts
+		c.letters = c.letters[:0]
+		c.mu.Unlock()
+		return
+	}
+	caption := c.current
+	c.mu.Unlock()
+
+	// Drop the update rather than hold up playback when behind
+	select {
+	case <-c.done:
+	case c.updates <- caption:
+	default:
+	}
+}
+
+// publish writes each caption to the file and the connected pages, off
+// the player's goroutine.
+func (c *Captions) publish() {
+	for {
+		var caption Caption
+		select {
+		case <-c.done:
+			return
+		case caption = <-c.updates:
+		}
+		if c.file != "" {
+			tmp := c.file + ".tmp"
+			if err := os.WriteFile(tmp, []byte(caption.Text+"\n"), 0o644); err == nil {
+				os.Rename(tmp, c.file)
+			}
+		}
+		msg, _ := json.Marshal(caption)
+		c.mu.Lock()
+		clients := make([]*wsConn, 0, len(c.clients))
+		for ws := range c.clients {
+			clients = append(clients, ws)
+		}
+		c.mu.Unlock()
+		for _, ws := range clients {
+			if err := ws.WriteText(msg); err != nil {
+				c.drop(ws)
+			}
+		}
+	}
+}
+
+func (c *Captions) drop(ws *wsConn) {
+	c.mu.Lock()
+	delete(c.clients, ws)
+	c.mu.Unlock()
+	ws.Close()
+}
+
+// ServeHTTP serves the captions page at / and its feed at /captions.
+func (c *Captions) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(captionsPage))
+	case "/captions":
+		ws, err := upgradeWebSocket(w, r)
+		if err != nil {
+			return
+		}
+		c.mu.Lock()
+		select {
+		case <-c.done:
+			c.mu.Unlock()
+			ws.Close()
+			return
+		default:
+			c.clients[ws] = true
+		}
+		current := c.current
+		c.mu.Unlock()
+		if msg, _ := json.Marshal(current); ws.WriteText(msg) != nil {
+			c.drop(ws)
+			return
+		}
+		// Read until the page goes away; it sends nothing itself
+		for {
+			if _, err := ws.ReadMessage(); err != nil {
+				c.drop(ws)
+				return
+			}
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// captionsPage shows the captions large on a transparent background, for
+// an OBS browser source. ?size= sets the text size in pixels.
+var captionsPage = strings.TrimSpace(`
+<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Phonical captions</title>
+<style>
+  html, body { margin: 0; background: transparent; overflow: hidden; }
+  #caption { font: bold 96px sans-serif; color: #fff; text-align: center;
+    -webkit-text-stroke: 3px #000; padding: 0.2em; white-space: pre; }
+  #caption .now { color: #ffd400; }
+</style>
+</head>
+<body>
+<div id="caption"></div>
+<script>
+const el = document.getElementById("caption");
+const size = new URLSearchParams(location.search).get("size");
+if (size) el.style.fontSize = size + "px";
+function show(c) {
+  el.textContent = "";
+  if (!c.letter) { el.textContent = c.text; return; }
+  el.append(c.text.slice(0, -c.letter.length));
+  const now = document.createElement("span");
+  now.className = "now";
+  now.textContent = c.letter;
+  el.append(now);
+}
+function connect() {
+  const ws = new WebSocket("ws://" + location.host + "/captions");
+  ws.onmessage = (m) => show(JSON.parse(m.data));
+  ws.onclose = () => setTimeout(connect, 1000);
+}
+connect();
+</script>
+</body>
+</html>
+`)
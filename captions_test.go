@@ -0,0 +1,62 @@
+package phonical_test
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestCaptions(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "caption.txt")
+	captions := phonical.NewCaptions(file)
+	h := phonicaltest.New(t, phonical.WithSink(captions))
+	srv := httptest.NewServer(captions)
+	defer srv.Close()
+
+	page := dialWebSocket(t, srv, "/captions")
+	if msg := page.read(t); msg != `{"text":""}` {
+		t.Errorf("page first shown %s", msg)
+	}
+	h.Type("ca")
+	h.Expect("c.wav", "a.wav")
+	for _, want := range []string{`{"text":"c","letter":"c"}`, `{"text":"ca","letter":"a"}`} {
+		if msg := page.read(t); msg != want {
+			t.Errorf("page shown %s, want %s", msg, want)
+		}
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		text, _ := os.ReadFile(file)
+		if string(text) == "ca\n" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("caption file holds %q, want %q", text, "ca\n")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCaptionsCloseDropsPages(t *testing.T) {
+	captions := phonical.NewCaptions("")
+	srv := httptest.NewServer(captions)
+	defer srv.Close()
+
+	page := dialWebSocket(t, srv, "/captions")
+	page.read(t)
+	if err := captions.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !page.closed() {
+		t.Error("page still connected after Close")
+	}
+	// Sounds after closing go nowhere rather than blocking
+	for i := 0; i < 100; i++ {
+		captions.Played(phonical.PlayEvent{Letter: 'b'})
+	}
+}
@@ -0,0 +1,103 @@
+package phonical
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ChecksumsName is the file in a pack listing the SHA-256 of each of its
+// files, in the "<sha256>  <name>" form written by sha256sum.
+const ChecksumsName = "SHA256SUMS"
+
+// ErrNoChecksums is returned by Verify for a pack without a ChecksumsName
+// file.
+var ErrNoChecksums = errors.New("pack has no " + ChecksumsName)
+
+// ChecksumProblem is a pack file that is missing or doesn't match its
+// recorded checksum.
+type ChecksumProblem struct {
+	File    string
+	Problem string
+}
+
+// Verify checks the pack's files against its recorded checksums, catching
+// truncated downloads and damaged installs.
+func (p *Pack) Verify() ([]ChecksumProblem, error) {
+	data, err := fs.ReadFile(p.fsys, ChecksumsName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNoChecksums
+	}
+	if err != nil {
+		return nil, err
+	}
+	sums, err := parseChecksums(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []ChecksumProblem
+	for name, want := range sums {
+		contents, err := fs.ReadFile(p.fsys, name)
+		if err != nil {
+			problems = append(problems, ChecksumProblem{File: name, Problem: "missing"})
+			continue
+		}
+		if got := sha256.Sum256(contents); hex.EncodeToString(got[:]) != want {
+			problems = append(problems, ChecksumProblem{File: name, Problem: "checksum mismatch"})
+		}
+	}
+	sort.Slice(problems, func(i, j int) bool { return problems[i].File < problems[j].File })
+	return problems, nil
+}
+
+// parseChecksums reads a checksums file into a map from file name to hex
+// digest.
+func parseChecksums(data []byte) (map[string]string, error) {
+	sums := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		sum, name, ok := strings.Cut(line, " ")
+		name = strings.TrimPrefix(strings.TrimSpace(name), "*")
+		if !ok || len(sum) != sha256.Size*2 || name == "" {
+			return nil, fmt.Errorf("%s:%d: want \"<sha256>  <file>\"", ChecksumsName, n)
+		}
+		sums[name] = strings.ToLower(sum)
+	}
+	return sums, scanner.Err()
+}
+
+// WriteChecksums records the checksum of every file in the pack directory
+// dir, for pack authors to ship alongside their recordings.
+func WriteChecksums(dir string) error {
+	var buf bytes.Buffer
+	fsys := os.DirFS(dir)
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || name == ChecksumsName {
+			return err
+		}
+		contents, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(contents)
+		fmt.Fprintf(&buf, "%s  %s\n", hex.EncodeToString(sum[:]), name)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ChecksumsName), buf.Bytes(), 0o644)
+}
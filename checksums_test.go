@@ -0,0 +1,92 @@
+package phonical_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"phonical"
+)
+
+func TestBuiltinSoundsVerify(t *testing.T) {
+	if !phonical.HasBuiltinSounds() {
+		t.Skip("lite build")
+	}
+	problems, err := phonical.DefaultPack().Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range problems {
+		t.Errorf("%s: %s", p.File, p.Problem)
+	}
+}
+
+func TestVerifyFindsDamage(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		phonical.ManifestName: "name = \"Checked\"\n\n[letters]\na = \"a.wav\"\nb = \"b.wav\"\n",
+		"a.wav":               "aaaa",
+		"b.wav":               "bbbb",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pack, err := phonical.LoadPack(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pack.Verify(); !errors.Is(err, phonical.ErrNoChecksums) {
+		t.Fatalf("verify without checksums: %v, want ErrNoChecksums", err)
+	}
+
+	if err := phonical.WriteChecksums(dir); err != nil {
+		t.Fatal(err)
+	}
+	if problems, err := pack.Verify(); err != nil || len(problems) != 0 {
+		t.Fatalf("fresh checksums: %v, %v", problems, err)
+	}
+
+	// A truncated download and a lost file
+	if err := os.WriteFile(filepath.Join(dir, "a.wav"), []byte("aa"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "b.wav")); err != nil {
+		t.Fatal(err)
+	}
+	problems, err := pack.Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []phonical.ChecksumProblem{
+		{File: "a.wav", Problem: "checksum mismatch"},
+		{File: "b.wav", Problem: "missing"},
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("problems %v, want %v", problems, want)
+	}
+	for i := range want {
+		if problems[i] != want[i] {
+			t.Errorf("problem %d is %v, want %v", i, problems[i], want[i])
+		}
+	}
+}
+
+func TestVerifyRejectsMalformedChecksums(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, phonical.ManifestName), []byte("name = \"Bad sums\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, phonical.ChecksumsName), []byte("abc123  a.wav\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pack, err := phonical.LoadPack(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pack.Verify(); err == nil {
+		t.Error("short checksum accepted")
+	}
+}
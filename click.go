@@ -0,0 +1,66 @@
+package phonical
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// DefaultClickVolume is the key-click level used when none is given; low
+// enough to sit under the phonemes rather than compete with them.
+const DefaultClickVolume = 0.15
+
+const clickLength = 12 * time.Millisecond
+
+const clapLength = 90 * time.Millisecond
+
+var (
+	clickBuffer *beep.Buffer
+	clickOnce   sync.Once
+	clapBuffer  *beep.Buffer
+	clapOnce    sync.Once
+)
+
+// keyClick returns a short percussive click synthesized from decaying noise,
+// so no recording needs to be embedded.
+func keyClick() *beep.Buffer {
+	clickOnce.Do(func() {
+		clickBuffer = noiseBurst(clickLength, 8, 1)
+	})
+	return clickBuffer
+}
+
+// clap returns a hand-clap-like noise burst played between syllables.
+func clap() *beep.Buffer {
+	clapOnce.Do(func() {
+		clapBuffer = noiseBurst(clapLength, 5, 0.5)
+	})
+	return clapBuffer
+}
+
+// noiseBurst renders length of white noise at amplitude amp with an
+// exponential decay; higher decay gives a sharper sound.
+func noiseBurst(length time.Duration, decay, amp float64) *beep.Buffer {
+	n := outputFormat.SampleRate.N(length)
+	noise := rand.New(rand.NewSource(1))
+	i := 0
+	buffer := beep.NewBuffer(outputFormat)
+	buffer.Append(beep.StreamerFunc(func(samples [][2]float64) (int, bool) {
+		if i >= n {
+			return 0, false
+		}
+		filled := 0
+		for filled < len(samples) && i < n {
+			envelope := math.Exp(-decay * float64(i) / float64(n))
+			v := amp * (noise.Float64()*2 - 1) * envelope
+			samples[filled] = [2]float64{v, v}
+			filled++
+			i++
+		}
+		return filled, true
+	}))
+	return buffer
+}
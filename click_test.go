@@ -0,0 +1,24 @@
+package phonical_test
+
+import (
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestKeyClickUnderEveryPress(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithKeyClick(phonical.DefaultClickVolume))
+	// Shift has no sound of its own but still clicks
+	h.Type("ab{code 0x2a}")
+	h.Expect("a.wav", "b.wav")
+	if got := h.Sink.Layers(); got != 3 {
+		t.Errorf("%d clicks, want 3", got)
+	}
+}
+
+func TestKeyClickVolumeChecked(t *testing.T) {
+	if _, err := phonical.New(phonical.WithKeyClick(1.5)); err == nil {
+		t.Error("click volume 1.5 accepted")
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"phonical"
+)
+
+// startCaptions serves the captions page at addr and keeps file up to
+// date, for whichever are set.
+func startCaptions(addr, file string) *phonical.Captions {
+	captions := phonical.NewCaptions(file)
+	if addr != "" {
+		go func() {
+			if err := http.ListenAndServe(addr, captions); err != nil {
+				log.Printf("Captions server stopped: %v", err)
+			}
+		}()
+		fmt.Printf("Captions: add a browser source for http://%s/ in OBS\n", addr)
+	}
+	return captions
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+
+	"phonical"
+)
+
+// startCompanion serves the companion protocol at addr so a tablet app can
+// be the keyboard. Without a token it makes one up and prints the address
+// to give the app.
+func startCompanion(addr, token string) (*phonical.Companion, error) {
+	if token == "" {
+		var err error
+		if token, err = phonical.NewCompanionToken(); err != nil {
+			return nil, err
+		}
+	}
+	companion := phonical.NewCompanion(token)
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		if err := http.Serve(ln, companion); err != nil {
+			log.Printf("Companion server stopped: %v", err)
+		}
+	}()
+	url := companionURL(ln.Addr(), token)
+	fmt.Printf("Tablet keyboard: connect the app to %s\n", url)
+	// A code to scan saves typing the address and token on a tablet
+	if q, err := encodeQR(url); err == nil && isTerminal(os.Stdout) {
+		fmt.Println("or scan this code with it:")
+		printQR(os.Stdout, q)
+	}
+	return companion, nil
+}
+
+// companionURL is the address apps on the local network connect to.
+func companionURL(addr net.Addr, token string) string {
+	host := "localhost"
+	port := ""
+	if tcp, ok := addr.(*net.TCPAddr); ok {
+		port = fmt.Sprint(tcp.Port)
+		if !tcp.IP.IsUnspecified() {
+			host = tcp.IP.String()
+		} else if ip := lanIP(); ip != nil {
+			host = ip.String()
+		}
+	}
+	return fmt.Sprintf("ws://%s%s?token=%s", net.JoinHostPort(host, port), phonical.CompanionPath, token)
+}
+
+// lanIP returns this computer's first IPv4 address on the local network,
+// or nil.
+func lanIP() net.IP {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil
+	}
+	for _, a := range addrs {
+		if ipnet, ok := a.(*net.IPNet); ok && !ipnet.IP.IsLoopback() && ipnet.IP.To4() != nil {
+			return ipnet.IP
+		}
+	}
+	return nil
+}
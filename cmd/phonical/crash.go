@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"phonical"
+)
+
+// crashChildEnv marks the Phonical process run under the crash supervisor.
+const crashChildEnv = "PHONICAL_CRASH_CHILD"
+
+// issuesURL is where crash reports can be filed by hand.
+const issuesURL = "https://github.com/carldaws/phonical/issues/new"
+
+// crashTailSize is how much of the end of the output is kept to find a
+// crash in.
+const crashTailSize = 256 << 10
+
+// crashReport is everything a crash report holds: what Phonical was doing
+// in its own code and what it runs on, never anything typed.
+type crashReport struct {
+	Version string    `json:"version"`
+	Commit  string    `json:"commit,omitempty"`
+	OS      string    `json:"os"`
+	Arch    string    `json:"arch"`
+	Go      string    `json:"go"`
+	Time    time.Time `json:"time"`
+	Trace   string    `json:"trace"`
+}
+
+// tailBuffer keeps the last crashTailSize bytes written to it.
+type tailBuffer struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buf = append(t.buf, p...)
+	if over := len(t.buf) - crashTailSize; over > 0 {
+		t.buf = t.buf[over:]
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return string(t.buf)
+}
+
+// superviseCrashes runs Phonical again with the same arguments, passing
+// its input and output through, and saves a report if it crashes. It
+// returns the exit code to finish with. Crashes in the keyboard hook and
+// audio libraries can't be caught in the process itself, hence the
+// second process.
+func superviseCrashes(reportURL string) int {
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Crash reports are off: %v\n", err)
+		return -1
+	}
+	var tail tailBuffer
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Env = append(os.Environ(), crashChildEnv+"=1")
+	cmd.Stdin, cmd.Stdout = os.Stdin, os.Stdout
+	cmd.Stderr = io.MultiWriter(os.Stderr, &tail)
+
+	// Ctrl+C reaches both processes; only Phonical itself acts on it
+	signal.Ignore(os.Interrupt)
+	terms := make(chan os.Signal, 1)
+	signal.Notify(terms, syscall.SIGTERM)
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "Crash reports are off: %v\n", err)
+		return -1
+	}
+	go func() {
+		for sig := range terms {
+			cmd.Process.Signal(sig)
+		}
+	}()
+	cmd.Wait()
+	signal.Stop(terms)
+
+	code := cmd.ProcessState.ExitCode()
+	if code < 0 {
+		code = 1
+	}
+	if trace := crashTrace(tail.String()); trace != "" {
+		saveCrashReport(newCrashReport(trace), reportURL)
+	}
+	return code
+}
+
+// crashTrace returns the Go crash report at the end of output, cleaned of
+// anything that might come from typing, or "" if there was no crash.
+func crashTrace(output string) string {
+	start := -1
+	for _, marker := range []string{"panic: ", "fatal error: ", "SIGSEGV", "SIGABRT"} {
+		if i := strings.Index(output, marker); i >= 0 && (start < 0 || i < start) {
+			start = i
+		}
+	}
+	if start < 0 {
+		return ""
+	}
+	// Back up to the start of the line
+	start = strings.LastIndex(output[:start], "\n") + 1
+
+	var lines []string
+	for _, line := range strings.Split(output[start:], "\n") {
+		lines = append(lines, scrubTraceLine(line))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// frameArgs matches the argument values at the end of a stack frame.
+var frameArgs = regexp.MustCompile(`\([^()]*\)$`)
+
+// scrubTraceLine leaves out the parts of a trace line that can hold
+// program data: panic messages other than runtime errors, and the argument
+// values of each function.
+func scrubTraceLine(line string) string {
+	switch {
+	case strings.HasPrefix(line, "panic: runtime error:"), strings.HasPrefix(line, "fatal error:"):
+		return line
+	case strings.HasPrefix(line, "panic: "):
+		return "panic: (message left out)"
+	case strings.HasPrefix(line, "\t"), strings.HasPrefix(line, "goroutine "), strings.HasPrefix(line, "["):
+		return line
+	}
+	return frameArgs.ReplaceAllString(line, "(...)")
+}
+
+func newCrashReport(trace string) *crashReport {
+	b := currentBuild()
+	return &crashReport{
+		Version: b.Version,
+		Commit:  b.Commit,
+		OS:      b.OS,
+		Arch:    b.Arch,
+		Go:      b.Go,
+		Time:    time.Now().UTC(),
+		Trace:   trace,
+	}
+}
+
+// saveCrashReport writes report to the crashes folder and sends it to url,
+// if given.
+func saveCrashReport(report *crashReport, url string) {
+	dir, err := phonical.ConfigDir()
+	if err == nil {
+		dir = filepath.Join(dir, "crashes")
+		err = os.MkdirAll(dir, 0o755)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save crash report: %v\n", err)
+		return
+	}
+	path := filepath.Join(dir, "crash-"+report.Time.Format("20060102-150405")+".txt")
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "Phonical %s (%s) on %s/%s, %s\n", report.Version, report.Commit, report.OS, report.Arch, report.Go)
+	fmt.Fprintf(&buf, "Crashed at %s\n\n%s\n", report.Time.Format(time.RFC3339), report.Trace)
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save crash report: %v\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\nPhonical crashed. A report, with nothing you typed in it, is in\n%s\n", path)
+
+	if url == "" {
+		fmt.Fprintf(os.Stderr, "Please attach it to a new issue at %s\n", issuesURL)
+		return
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return
+	}
+	resp, err := httpClient.Post(url, "application/json", bytes.NewReader(data))
+	if err == nil {
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			err = fmt.Errorf("%s", resp.Status)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to send the crash report: %v\n", err)
+		return
+	}
+	fmt.Fprintln(os.Stderr, "It has been sent to help fix the problem.")
+}
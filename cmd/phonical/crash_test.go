@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCrashTrace(t *testing.T) {
+	output := "Typed: secret\n" +
+		"panic: unexpected key secret\n" +
+		"\n" +
+		"goroutine 1 [running]:\n" +
+		"phonical.(*Engine).handle(0xc000010000, {0x5, 0x73})\n" +
+		"\t/src/phonical/engine.go:120 +0x1f\n"
+	want := "panic: (message left out)\n" +
+		"\n" +
+		"goroutine 1 [running]:\n" +
+		"phonical.(*Engine).handle(...)\n" +
+		"\t/src/phonical/engine.go:120 +0x1f"
+	if got := crashTrace(output); got != want {
+		t.Errorf("trace\n%s\nwant\n%s", got, want)
+	}
+
+	// Runtime errors are kept, since they come from Phonical's own code
+	if got := crashTrace("panic: runtime error: index out of range [3] with length 2\n"); got != "panic: runtime error: index out of range [3] with length 2" {
+		t.Errorf("runtime error trace %q", got)
+	}
+	if got := crashTrace("Typed: panic\nbye\n"); got != "" {
+		t.Errorf("found a crash in %q", got)
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	var tail tailBuffer
+	tail.Write([]byte(strings.Repeat("a", crashTailSize)))
+	tail.Write([]byte("end"))
+	if got := tail.String(); len(got) != crashTailSize || !strings.HasSuffix(got, "aend") {
+		t.Errorf("kept %d bytes ending %q", len(got), got[len(got)-4:])
+	}
+}
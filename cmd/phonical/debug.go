@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"phonical"
+)
+
+// debugCommands are the subcommands of `phonical debug`.
+var debugCommands = map[string]func(args []string) error{
+	"dump": runDebugDump,
+}
+
+func runDebug(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: phonical debug dump [--json]")
+	}
+	cmd, ok := debugCommands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown debug command %q", args[0])
+	}
+	return cmd(args[1:])
+}
+
+// runDebugDump prints the running instance's recent events, kept even
+// when it was started without --verbose.
+func runDebugDump(args []string) error {
+	fs := flag.NewFlagSet("debug dump", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "")
+	fs.Parse(args)
+
+	reply, err := sendControl("trace")
+	if err != nil {
+		return err
+	}
+	var events []phonical.TraceEvent
+	if err := json.Unmarshal(reply.Data, &events); err != nil {
+		return err
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		for _, ev := range events {
+			if err := enc.Encode(&ev); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, ev := range events {
+		fmt.Printf("%s  %s\n", ev.Time.Format("15:04:05.000"), ev.Message)
+	}
+	return nil
+}
@@ -0,0 +1,602 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"phonical"
+)
+
+// engineFlags are the options shared by every command that drives the
+// engine.
+type engineFlags struct {
+	verbose   bool
+	queueSize int
+	traceSize int
+	overflow  string
+	coalesce  time.Duration
+	announce  bool
+	click     bool
+	clickVol  float64
+	echo      bool
+	echoVol   float64
+	packDir   string
+	cues      bool
+	removals  bool
+	nameKeys  bool
+	rhymes    bool
+	rhymeGap  time.Duration
+	modeName  string
+	claps     bool
+	boxGap    time.Duration
+	statsPath string
+	focus     string
+	rotate    time.Duration
+	focusPool string
+	adaptive  bool
+	promptGap time.Duration
+	popQuiz   time.Duration
+	profile   string
+	celebrate bool
+	syllabus  string
+	level     int
+	voiceName string
+	voiceKey  bool
+	pan       bool
+	visual    bool
+	silent    bool
+	ignoreDND bool
+	callPause bool
+	metrics   string
+	captions  string
+	capFile   string
+	openrgb   string
+	overlay   string
+	companion string
+	compToken string
+	volume    int
+	updates   bool
+	input     string
+	replay    string
+	record    string
+	deadKeys  bool
+	emoji     bool
+	hints     string
+	speedGap  time.Duration
+	reinit    time.Duration
+	reinitMax time.Duration
+	hookRetry time.Duration
+	hookMax   time.Duration
+	watchdog  time.Duration
+	unknown   string
+	script    bool
+	targets   bool
+	slowKey   string
+	scriptAge time.Duration
+	language  string
+	langKey   string
+	second    string
+	scheme    string
+	kana      string
+	pinyin    bool
+	maxLength time.Duration
+	crossfade time.Duration
+	audioTo   string
+	dryRun    bool
+	latency   time.Duration
+	cacheDisk bool
+	mono      bool
+	helpKey   bool
+	profKey   string
+	guest     bool
+	guestKey  bool
+	timeOfDay bool
+	theme     string
+	layers    string
+	crashes   bool
+	crashURL  string
+	notify    bool
+	// sinks are the feedback channels from the settings file.
+	sinks []phonical.SinkConfig
+}
+
+func (f *engineFlags) register(fs *flag.FlagSet) {
+	fs.BoolVar(&f.verbose, "v", false, "")
+	fs.BoolVar(&f.verbose, "verbose", false, "")
+	fs.IntVar(&f.queueSize, "queue-size", phonical.DefaultQueueSize, "")
+	fs.IntVar(&f.traceSize, "trace-size", phonical.DefaultTraceSize, "")
+	fs.StringVar(&f.overflow, "overflow", phonical.DropNewest.String(), "")
+	fs.DurationVar(&f.coalesce, "coalesce", 0, "")
+	fs.BoolVar(&f.announce, "announce-repeats", false, "")
+	fs.BoolVar(&f.click, "key-click", false, "")
+	fs.Float64Var(&f.clickVol, "click-volume", phonical.DefaultClickVolume, "")
+	fs.BoolVar(&f.echo, "echo", false, "")
+	fs.Float64Var(&f.echoVol, "echo-volume", phonical.DefaultEchoVolume, "")
+	fs.IntVar(&f.volume, "volume", 100, "")
+	fs.StringVar(&f.packDir, "pack", "", "")
+	fs.BoolVar(&f.cues, "cues", false, "")
+	fs.BoolVar(&f.removals, "announce-removals", false, "")
+	fs.BoolVar(&f.nameKeys, "name-keys", false, "")
+	fs.BoolVar(&f.rhymes, "rhymes", false, "")
+	fs.DurationVar(&f.rhymeGap, "rhyme-interval", phonical.DefaultRhymeInterval, "")
+	fs.StringVar(&f.modeName, "mode", phonical.ModeLetters.String(), "")
+	fs.BoolVar(&f.claps, "claps", false, "")
+	fs.DurationVar(&f.boxGap, "box-gap", phonical.DefaultBoxGap, "")
+	fs.StringVar(&f.voiceName, "voice", phonical.VoiceNormal.String(), "")
+	fs.BoolVar(&f.voiceKey, "voice-key", false, "")
+	fs.BoolVar(&f.pan, "pan", false, "")
+	fs.BoolVar(&f.visual, "visual", false, "")
+	fs.BoolVar(&f.silent, "silent", false, "")
+	fs.BoolVar(&f.ignoreDND, "ignore-dnd", false, "")
+	fs.BoolVar(&f.callPause, "pause-on-calls", false, "")
+	fs.StringVar(&f.language, "language", "en", "")
+	fs.StringVar(&f.langKey, "language-key", "", "")
+	fs.StringVar(&f.second, "second-language", "", "")
+	fs.StringVar(&f.scheme, "bilingual", "shift", "")
+	fs.StringVar(&f.kana, "kana", "", "")
+	fs.BoolVar(&f.pinyin, "pinyin", false, "")
+	fs.DurationVar(&f.maxLength, "max-sound-length", 0, "")
+	fs.DurationVar(&f.crossfade, "crossfade", 0, "")
+	fs.StringVar(&f.audioTo, "audio-to", "", "")
+	fs.BoolVar(&f.dryRun, "dry-run", false, "")
+	fs.DurationVar(&f.latency, "output-latency", 0, "")
+	fs.BoolVar(&f.cacheDisk, "cache-sounds", false, "")
+	fs.BoolVar(&f.mono, "mono", false, "")
+	fs.BoolVar(&f.helpKey, "help-key", false, "")
+	fs.StringVar(&f.profKey, "profile-key", "", "")
+	fs.BoolVar(&f.guest, "guest", false, "")
+	fs.BoolVar(&f.guestKey, "guest-key", false, "")
+	fs.BoolVar(&f.timeOfDay, "time-of-day", false, "")
+	fs.StringVar(&f.theme, "theme", "", "")
+	fs.StringVar(&f.layers, "layer", "", "")
+	fs.BoolVar(&f.crashes, "crash-reports", false, "")
+	fs.StringVar(&f.crashURL, "crash-report-url", "", "")
+	fs.BoolVar(&f.notify, "notify", false, "")
+	fs.StringVar(&f.metrics, "metrics", "", "")
+	fs.StringVar(&f.captions, "captions", "", "")
+	fs.StringVar(&f.capFile, "captions-file", "", "")
+	fs.StringVar(&f.openrgb, "openrgb", "", "")
+	fs.StringVar(&f.overlay, "overlay", "", "")
+	fs.StringVar(&f.companion, "companion", "", "")
+	fs.StringVar(&f.compToken, "companion-token", "", "")
+	fs.DurationVar(&f.reinit, "audio-retry", phonical.DefaultReinitBackoff, "")
+	fs.DurationVar(&f.reinitMax, "audio-retry-max", phonical.DefaultMaxReinitBackoff, "")
+	fs.DurationVar(&f.hookRetry, "hook-retry", phonical.DefaultHookRetry, "")
+	fs.DurationVar(&f.hookMax, "hook-retry-max", phonical.DefaultMaxHookRetry, "")
+	fs.DurationVar(&f.watchdog, "watchdog", phonical.DefaultWatchdog, "")
+	fs.StringVar(&f.unknown, "unknown", phonical.UnknownSilent, "")
+	fs.BoolVar(&f.script, "transcript", false, "")
+	fs.BoolVar(&f.targets, "targets", false, "")
+	fs.StringVar(&f.slowKey, "slow-key", "", "")
+	fs.DurationVar(&f.scriptAge, "transcript-keep", phonical.DefaultTranscriptRetention, "")
+	fs.BoolVar(&f.updates, "check-updates", false, "")
+	fs.StringVar(&f.input, "input", defaultInput(), "")
+	fs.StringVar(&f.replay, "replay", "", "")
+	fs.StringVar(&f.record, "record", "", "")
+	fs.BoolVar(&f.deadKeys, "dead-keys", false, "")
+	fs.BoolVar(&f.emoji, "emoji", false, "")
+	fs.StringVar(&f.hints, "finger-hints", "", "")
+	fs.DurationVar(&f.speedGap, "speed-feedback", 0, "")
+	fs.StringVar(&f.statsPath, "stats", "", "")
+	fs.StringVar(&f.profile, "profile", phonical.DefaultProfile, "")
+	fs.BoolVar(&f.celebrate, "celebrate", false, "")
+	fs.StringVar(&f.focus, "focus", "", "")
+	fs.DurationVar(&f.rotate, "focus-rotate", 0, "")
+	fs.StringVar(&f.focusPool, "focus-pool", "", "")
+	fs.StringVar(&f.syllabus, "curriculum", "", "")
+	fs.IntVar(&f.level, "level", 1, "")
+	fs.BoolVar(&f.adaptive, "adaptive", false, "")
+	fs.DurationVar(&f.promptGap, "prompt-every", phonical.DefaultAdaptive.PromptEvery, "")
+	fs.DurationVar(&f.popQuiz, "pop-quiz", 0, "")
+}
+
+// parse reads the settings file, if there is one, then args. Settings
+// become the flags' defaults so the command line still wins.
+func (f *engineFlags) parse(fs *flag.FlagSet, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if cfg != nil {
+		applyConfig(fs, cfg)
+		f.sinks = cfg.Sinks
+	}
+	return fs.Parse(args)
+}
+
+// parseWithConfig parses args into fs after applying the settings file,
+// for commands that share a few of the engine flags.
+func parseWithConfig(fs *flag.FlagSet, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if cfg != nil {
+		applyConfig(fs, cfg)
+	}
+	return fs.Parse(args)
+}
+
+// loadConfig reads the settings file, returning nil when there is none.
+func loadConfig() (*phonical.Config, error) {
+	path, err := phonical.DefaultConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := phonical.LoadConfig(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// applyConfig sets flag values from cfg without marking them as given on
+// the command line.
+func applyConfig(fs *flag.FlagSet, cfg *phonical.Config) {
+	set := func(name, value string) {
+		if fl := fs.Lookup(name); fl != nil && value != "" {
+			fl.Value.Set(value)
+		}
+	}
+	set("language", cfg.Language)
+	set("pack", cfg.Pack)
+	set("mode", cfg.Mode)
+	set("voice", cfg.Voice)
+	set("curriculum", cfg.Curriculum)
+	set("profile", cfg.Profile)
+	if cfg.Volume != nil {
+		set("volume", strconv.Itoa(*cfg.Volume))
+	}
+	if cfg.Level > 0 {
+		set("level", strconv.Itoa(cfg.Level))
+	}
+	if cfg.KeyClick {
+		set("key-click", "true")
+	}
+	if cfg.Notifications {
+		set("notify", "true")
+	}
+	if cfg.CrashReports {
+		set("crash-reports", "true")
+	}
+}
+
+// defaultInput is the system-wide hook when the build has one.
+func defaultInput() string {
+	if phonical.HasHook() {
+		return "hook"
+	}
+	return "stdin"
+}
+
+// run feeds keys to engine from the chosen input until it ends or the
+// engine stops.
+func (f *engineFlags) run(engine *phonical.Engine) error {
+	if f.replay != "" {
+		file, err := os.Open(f.replay)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		return engine.Replay(file)
+	}
+	switch f.input {
+	case "hook":
+		err := engine.Run()
+		if errors.Is(err, phonical.ErrNoInputHook) && isTerminal(os.Stdin) {
+			// Over SSH, say, keys typed into this terminal still work
+			log.Printf("Warning: %v", err)
+			fmt.Println("Reading keys from standard input instead...")
+			return engine.RunReader(os.Stdin)
+		}
+		return err
+	case "stdin":
+		return engine.RunReader(os.Stdin)
+	default:
+		return fmt.Errorf("unknown input %q (want hook or stdin)", f.input)
+	}
+}
+
+// hotkeyProfiles lists the profiles F11 cycles through: --profile, then
+// the others named by --profile-key.
+func (f *engineFlags) hotkeyProfiles() []string {
+	profiles := []string{f.profile}
+	for _, name := range strings.Split(f.profKey, ",") {
+		if name = strings.TrimSpace(name); name != "" && name != f.profile {
+			profiles = append(profiles, name)
+		}
+	}
+	return profiles
+}
+
+// openStats opens the stats file named by --stats, or the profile's.
+func (f *engineFlags) openStats() (*phonical.StatsStore, error) {
+	path, err := f.statsFile()
+	if err != nil {
+		return nil, err
+	}
+	return phonical.OpenStats(path)
+}
+
+// statsFile returns where the profile's stats are kept.
+func (f *engineFlags) statsFile() (string, error) {
+	if f.statsPath != "" {
+		return f.statsPath, nil
+	}
+	return phonical.ProfileStatsPath(f.profile)
+}
+
+// options converts the flags into engine options, returning the stats
+// store so the caller can save it on exit, and the --record file, if any,
+// for the caller to close once the engine has stopped.
+func (f *engineFlags) options() (opts []phonical.Option, stats *phonical.StatsStore, recording io.Closer, err error) {
+	policy, err := phonical.ParseOverflowPolicy(f.overflow)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	mode, err := phonical.ParseMode(f.modeName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	voice, err := phonical.ParseVoice(f.voiceName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	clickVol := f.clickVol
+	if !f.click {
+		clickVol = 0
+	}
+	echoVol := f.echoVol
+	if !f.echo {
+		echoVol = 0
+	}
+
+	pack, err := f.chosenPack()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var languages []*phonical.Pack
+	if f.langKey != "" {
+		for _, name := range strings.Split(f.langKey, ",") {
+			p, err := openLanguage(strings.TrimSpace(name))
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			languages = append(languages, p)
+		}
+	}
+
+	var second *phonical.Pack
+	scheme, err := phonical.ParseBilingual(f.scheme)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if f.second != "" {
+		if second, err = openLanguage(f.second); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	theme, err := openTheme(f.theme)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	stats, err = f.openStats()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	focus := []rune(strings.ToLower(f.focus))
+	if f.rotate > 0 {
+		pool := []rune(strings.ToLower(f.focusPool))
+		if len(pool) == 0 {
+			for r := range pack.Letters {
+				pool = append(pool, r)
+			}
+		}
+		schedule := phonical.FocusSchedule{Period: f.rotate, Pool: pool}
+		// A guest keeps today's letter without moving the rotation on
+		letter, ok := schedule.CurrentFocus(stats)
+		if !f.guest {
+			letter, ok = schedule.ChooseFocus(stats, time.Now()), true
+		}
+		focus = nil
+		if ok {
+			focus = []rune{letter}
+			fmt.Printf("Letter of the day: %c\n", letter)
+		}
+	}
+
+	opts = []phonical.Option{
+		phonical.WithVerbose(f.verbose),
+		phonical.WithKeyClick(clickVol),
+		phonical.WithWordEcho(echoVol),
+		phonical.WithVolume(float64(f.volume) / 100),
+		phonical.WithPack(pack),
+		phonical.WithCues(f.cues),
+		phonical.WithRemovalAnnouncements(f.removals),
+		phonical.WithNavigationNames(f.nameKeys),
+		phonical.WithRhymes(f.rhymes, f.rhymeGap),
+		phonical.WithMode(mode),
+		phonical.WithSyllableClaps(f.claps),
+		phonical.WithBoxGap(f.boxGap),
+		phonical.WithVoice(voice),
+		phonical.WithVoiceHotkey(f.voiceKey),
+		phonical.WithHelpHotkey(f.helpKey),
+		phonical.WithLanguageHotkey(languages...),
+		phonical.WithSecondLanguage(second, scheme),
+		phonical.WithKana(f.kana),
+		phonical.WithPinyin(f.pinyin),
+		phonical.WithMaxSoundLength(f.maxLength),
+		phonical.WithCrossfade(f.crossfade),
+		phonical.WithOutputLatency(f.latency),
+		phonical.WithMono(f.mono),
+		phonical.WithKeyPanning(f.pan),
+		phonical.WithDeadKeys(f.deadKeys),
+		phonical.WithEmoji(f.emoji),
+		phonical.WithFingerHints(f.hints),
+		phonical.WithSpeedFeedback(f.speedGap),
+		phonical.WithVisual(f.visual || f.silent),
+		phonical.WithSilent(f.silent),
+		phonical.WithDoNotDisturb(!f.ignoreDND),
+		phonical.WithCallPause(f.callPause),
+		phonical.WithSpeakerRecovery(f.reinit, f.reinitMax),
+		phonical.WithHookRetry(f.hookRetry, f.hookMax),
+		phonical.WithWatchdog(f.watchdog),
+		phonical.WithUnknownChars(f.unknown),
+		phonical.WithPopQuiz(f.popQuiz),
+		phonical.WithGuest(f.guest),
+		phonical.WithGuestHotkey(f.guestKey),
+		phonical.WithTimeOfDay(f.timeOfDay),
+		phonical.WithTheme(theme),
+		phonical.WithQueueSize(f.queueSize),
+		phonical.WithTraceSize(f.traceSize),
+		phonical.WithOverflowPolicy(policy),
+		phonical.WithRepeatCoalescing(f.coalesce, f.announce),
+		phonical.WithStats(stats),
+		phonical.WithFocus(focus...),
+		phonical.WithFirstLetterCelebration(f.celebrate),
+	}
+	if f.syllabus != "" {
+		curriculum, err := phonical.LoadCurriculum(f.syllabus)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		opts = append(opts, phonical.WithCurriculum(curriculum, f.level))
+	}
+	if f.adaptive {
+		cfg := phonical.DefaultAdaptive
+		cfg.PromptEvery = f.promptGap
+		opts = append(opts, phonical.WithAdaptive(cfg))
+	}
+	if f.slowKey != "" {
+		mod, err := phonical.ParseModifier(f.slowKey)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		opts = append(opts, phonical.WithSlowMotion(mod))
+	}
+	if f.targets {
+		opts = append(opts, phonical.WithTherapyTargets())
+	}
+	if f.script {
+		opts = append(opts, phonical.WithTranscript(f.scriptAge))
+	}
+	if f.audioTo != "" {
+		opts = append(opts, phonical.WithAudioSink(phonical.NewNetSink(f.audioTo)))
+	}
+	if f.profKey != "" {
+		opts = append(opts, phonical.WithProfileHotkey(f.hotkeyProfiles()...))
+	}
+	if f.dryRun {
+		opts = append(opts, phonical.WithDryRun(os.Stdout))
+	}
+	if f.notify {
+		opts = append(opts, phonical.WithNotifier(phonical.SystemNotifier{}))
+	}
+	if f.cacheDisk {
+		dir, err := phonical.DefaultSoundCacheDir()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		opts = append(opts, phonical.WithSoundCache(dir))
+	}
+	// Last, so no error after it leaves the file open
+	if f.record != "" {
+		log, err := os.Create(f.record)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		opts = append(opts, phonical.WithKeyLog(log))
+		recording = log
+	}
+	return opts, stats, recording, nil
+}
+
+// chosenPack loads the sounds chosen by --pack or --language, with any
+// --layer packs over them.
+func (f *engineFlags) chosenPack() (*phonical.Pack, error) {
+	var pack *phonical.Pack
+	var err error
+	switch {
+	case f.packDir != "":
+		if pack, err = openPack(f.packDir); err != nil {
+			return nil, err
+		}
+	case !phonical.HasBuiltinSounds():
+		return nil, errors.New("this is a lite build without built-in sounds; use --pack DIR")
+	default:
+		if pack, err = phonical.LanguagePack(f.language); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.layers == "" {
+		return pack, nil
+	}
+	layers := []*phonical.Pack{pack}
+	for _, dir := range strings.Split(f.layers, ",") {
+		p, err := phonical.LoadPack(strings.TrimSpace(dir))
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, p)
+	}
+	return phonical.Layer(layers...), nil
+}
+
+// openPack loads the pack in dir, or the installed pack of that name.
+func openPack(dir string) (*phonical.Pack, error) {
+	if _, err := os.Stat(dir); err == nil || filepath.Base(dir) != dir {
+		return phonical.LoadPack(dir)
+	}
+	packs, err := phonical.PacksDir()
+	if err != nil {
+		return nil, err
+	}
+	return phonical.LoadPack(filepath.Join(packs, dir))
+}
+
+// openTheme loads the theme named by --theme: "auto" for whichever
+// installed theme is in season, the name of an installed theme, or a
+// theme directory. It returns nil for no theme.
+func openTheme(name string) (*phonical.Pack, error) {
+	if name == "" {
+		return nil, nil
+	}
+	if name == "auto" {
+		themes, err := phonical.InstalledThemes()
+		if err != nil {
+			return nil, err
+		}
+		return phonical.SeasonalTheme(themes, time.Now()), nil
+	}
+	if info, err := os.Stat(name); err == nil && info.IsDir() {
+		return phonical.LoadTheme(name)
+	}
+	dir, err := phonical.ThemesDir()
+	if err != nil {
+		return nil, err
+	}
+	return phonical.LoadTheme(filepath.Join(dir, name))
+}
+
+// openLanguage loads the built-in sounds for a language, or the pack in a
+// directory of that name.
+func openLanguage(name string) (*phonical.Pack, error) {
+	if info, err := os.Stat(name); err == nil && info.IsDir() {
+		return phonical.LoadPack(name)
+	}
+	return phonical.LanguagePack(name)
+}
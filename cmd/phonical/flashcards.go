@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"phonical"
+)
+
+// runFlashcards writes printable flashcards for the letters taught so far,
+// with the words and pictures of the sound pack in use.
+func runFlashcards(args []string) error {
+	fs := flag.NewFlagSet("flashcards", flag.ExitOnError)
+	var flags engineFlags
+	fs.StringVar(&flags.language, "language", "en", "built-in sounds whose words to use")
+	fs.StringVar(&flags.language, "lang", "en", "short for --language")
+	fs.StringVar(&flags.packDir, "pack", "", "sound pack whose words and pictures to use")
+	fs.StringVar(&flags.layers, "layer", "", "packs layered over the sounds, comma-separated")
+	fs.StringVar(&flags.syllabus, "curriculum", "", "teaching order (default letters-and-sounds with --level)")
+	fs.IntVar(&flags.level, "level", 0, "print the letters taught up to this level (default every letter)")
+	out := fs.String("o", "", "PDF file to write")
+	if err := flags.parse(fs, args); err != nil {
+		return err
+	}
+	if *out == "" || fs.NArg() != 0 {
+		return errors.New("usage: phonical flashcards [--lang LANG] [--level N] -o FILE.pdf")
+	}
+	if !strings.EqualFold(filepath.Ext(*out), ".pdf") {
+		return fmt.Errorf("%s: only PDF output is supported", *out)
+	}
+
+	pack, err := flags.chosenPack()
+	if err != nil {
+		return err
+	}
+	var curriculum *phonical.Curriculum
+	if flags.level > 0 {
+		if flags.syllabus == "" {
+			flags.syllabus = "letters-and-sounds"
+		}
+		if curriculum, err = phonical.LoadCurriculum(flags.syllabus); err != nil {
+			return err
+		}
+	}
+	cards := phonical.Flashcards(pack, curriculum, flags.level)
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := phonical.WriteFlashcardsPDF(f, pack, cards); err != nil {
+		f.Close()
+		os.Remove(*out)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d flashcards to %s\n", len(cards), *out)
+	return nil
+}
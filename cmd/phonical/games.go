@@ -0,0 +1,387 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"phonical"
+)
+
+// runActivity runs activity with the system-wide hook feeding it keys,
+// saving stats when it finishes or is interrupted.
+func runActivity(flags *engineFlags, activity phonical.Activity) error {
+	control, err := listenControl()
+	if errors.Is(err, errAlreadyRunning) {
+		return errors.New("phonical is already running; stop it before starting an activity")
+	} else if err != nil {
+		return err
+	}
+	defer control.Close()
+
+	opts, stats, recording, err := flags.options()
+	if err != nil {
+		return err
+	}
+	if recording != nil {
+		defer recording.Close()
+	}
+	engine, err := phonical.New(opts...)
+	if err != nil {
+		return err
+	}
+	go serveControl(control, &instance{engine: engine, profile: flags.profile})
+	serveMetrics(flags.metrics, engine)
+	if err := engine.Start(); err != nil {
+		return fmt.Errorf("failed to initialize audio: %w", err)
+	}
+	stopOnSignal(engine)
+
+	go flags.run(engine)
+	err = engine.RunActivity(activity)
+	engine.Stop()
+
+	if saveErr := stats.Save(); saveErr != nil {
+		return fmt.Errorf("failed to save stats: %w", saveErr)
+	}
+	if errors.Is(err, phonical.ErrStopped) {
+		return nil
+	}
+	return err
+}
+
+func runPairs(args []string) error {
+	fs := flag.NewFlagSet("pairs", flag.ExitOnError)
+	var flags engineFlags
+	flags.register(fs)
+	rounds := fs.Int("rounds", 10, "number of rounds to play")
+	if err := flags.parse(fs, args); err != nil {
+		return err
+	}
+
+	fmt.Println("Minimal pairs: listen, then press the letter you heard.")
+	fmt.Println("Press Ctrl+C to stop")
+	return runActivity(&flags, phonical.MinimalPairs(*rounds))
+}
+
+func runAssess(args []string) error {
+	fs := flag.NewFlagSet("assess", flag.ExitOnError)
+	var flags engineFlags
+	flags.register(fs)
+	out := fs.String("o", "", "write the report to this .json or .csv file")
+	if err := flags.parse(fs, args); err != nil {
+		return err
+	}
+	ext := strings.ToLower(filepath.Ext(*out))
+	if *out != "" && ext != ".json" && ext != ".csv" {
+		return fmt.Errorf("report must be a .json or .csv file, not %s", *out)
+	}
+
+	fmt.Println("Assessment: letter sounds, then reading real and made-up words.")
+	fmt.Println("Press Ctrl+C to stop")
+	var report *phonical.AssessmentReport
+	err := runActivity(&flags, phonical.Assess(func(r *phonical.AssessmentReport) {
+		report = r
+	}))
+	if err != nil || report == nil {
+		return err
+	}
+
+	report.Profile = flags.profile
+	for _, section := range []string{phonical.SectionLetterSounds, phonical.SectionRealWords, phonical.SectionNonsenseWords} {
+		if score, ok := report.Sections[section]; ok {
+			fmt.Printf("%-15s %d/%d\n", section, score.Correct, score.Attempts)
+		}
+	}
+	if *out == "" {
+		return nil
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if ext == ".csv" {
+		err = report.WriteCSV(f)
+	} else {
+		err = report.WriteJSON(f)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Report written to %s\n", *out)
+	return nil
+}
+
+func runFalling(args []string) error {
+	fs := flag.NewFlagSet("falling", flag.ExitOnError)
+	var flags engineFlags
+	flags.register(fs)
+	lives := fs.Int("lives", 3, "letters that may land before the game ends")
+	if err := flags.parse(fs, args); err != nil {
+		return err
+	}
+	if *lives < 1 {
+		return fmt.Errorf("lives must be at least 1, got %d", *lives)
+	}
+
+	fmt.Println("Falling letters: press each letter you hear before it lands.")
+	fmt.Println("Press Ctrl+C to stop")
+	return runActivity(&flags, phonical.FallingLetters(*lives, func(r phonical.GameResult) {
+		fmt.Printf("Score: %d (level %d)\n", r.Score, r.Level)
+		if r.Rank > 0 {
+			fmt.Printf("Number %d in the high scores\n", r.Rank)
+		}
+	}))
+}
+
+func runFamilies(args []string) error {
+	fs := flag.NewFlagSet("families", flag.ExitOnError)
+	var flags engineFlags
+	flags.register(fs)
+	count := fs.Int("count", 3, "number of word families to drill")
+	if err := flags.parse(fs, args); err != nil {
+		return err
+	}
+
+	fmt.Println("Word families: listen to the family, then type each word in it.")
+	fmt.Println("Press Ctrl+C to stop")
+	return runActivity(&flags, phonical.WordFamilyDrill(*count, func(r phonical.SpellingResult) {
+		fmt.Printf("Score: %d/%d\n", r.Correct, r.Total)
+	}))
+}
+
+func runNonsense(args []string) error {
+	fs := flag.NewFlagSet("nonsense", flag.ExitOnError)
+	var flags engineFlags
+	flags.register(fs)
+	rounds := fs.Int("rounds", 10, "number of words")
+	if err := flags.parse(fs, args); err != nil {
+		return err
+	}
+
+	fmt.Println("Nonsense words: listen to each made-up word, type it, then press space.")
+	fmt.Println("Press Ctrl+C to stop")
+	return runActivity(&flags, phonical.NonsenseWordCheck(*rounds, func(r phonical.SpellingResult) {
+		fmt.Printf("Score: %d/%d\n", r.Correct, r.Total)
+	}))
+}
+
+func runSpell(args []string) error {
+	fs := flag.NewFlagSet("spell", flag.ExitOnError)
+	var flags engineFlags
+	flags.register(fs)
+	list := fs.String("list", "", "word list file, one word per line")
+	if err := flags.parse(fs, args); err != nil {
+		return err
+	}
+	if *list == "" {
+		return errors.New("usage: phonical spell --list WORDS.txt")
+	}
+	words, err := phonical.LoadWordList(*list)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Spelling bee: %d words. Type each word you hear, then press space.\n", len(words))
+	fmt.Println("Press Ctrl+C to stop")
+	return runActivity(&flags, phonical.SpellingBee(words, func(r phonical.SpellingResult) {
+		fmt.Printf("Score: %d/%d\n", r.Correct, r.Total)
+	}))
+}
+
+func runStats(args []string) error {
+	if len(args) > 0 && args[0] == "purge" {
+		return runStatsPurge(args[1:])
+	}
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	var flags engineFlags
+	fs.StringVar(&flags.statsPath, "stats", "", "stats file to read")
+	fs.StringVar(&flags.profile, "profile", phonical.DefaultProfile, "profile to show")
+	if err := flags.parse(fs, args); err != nil {
+		return err
+	}
+
+	store, err := flags.openStats()
+	if err != nil {
+		return err
+	}
+	stats := store.Snapshot()
+
+	total := 0
+	letters := make([]string, 0, len(stats.Letters))
+	for letter, count := range stats.Letters {
+		letters = append(letters, letter)
+		total += count
+	}
+	sort.Strings(letters)
+	fmt.Printf("Letters typed: %d\n", total)
+	for _, letter := range letters {
+		fmt.Printf("  %s  %d\n", letter, stats.Letters[letter])
+	}
+
+	if len(stats.Pairs) > 0 {
+		fmt.Println("\nMinimal pairs:")
+		pairs := make([]string, 0, len(stats.Pairs))
+		for pair := range stats.Pairs {
+			pairs = append(pairs, pair)
+		}
+		sort.Strings(pairs)
+		for _, pair := range pairs {
+			score := stats.Pairs[pair]
+			fmt.Printf("  %s  %d/%d correct\n", pair, score.Correct, score.Attempts)
+		}
+	}
+
+	if len(stats.Families) > 0 {
+		var mastered, practising []string
+		for rime, m := range stats.Families {
+			if m.Mastered() {
+				mastered = append(mastered, "-"+rime)
+			} else {
+				practising = append(practising, "-"+rime)
+			}
+		}
+		sort.Strings(mastered)
+		sort.Strings(practising)
+		fmt.Println("\nWord families:")
+		if len(mastered) > 0 {
+			fmt.Printf("  mastered:    %s\n", strings.Join(mastered, " "))
+		}
+		if len(practising) > 0 {
+			fmt.Printf("  practising:  %s\n", strings.Join(practising, " "))
+		}
+	}
+
+	if n := stats.Nonsense; n.Attempts > 0 {
+		fmt.Printf("\nNonsense words: %d/%d correct\n", n.Correct, n.Attempts)
+	}
+
+	if len(stats.Spelling) > 0 {
+		words := make([]string, 0, len(stats.Spelling))
+		mastered := 0
+		for word, m := range stats.Spelling {
+			words = append(words, word)
+			if m.Mastered() {
+				mastered++
+			}
+		}
+		sort.Strings(words)
+		fmt.Printf("\nSpelling: %d of %d words mastered\n", mastered, len(words))
+		for _, word := range words {
+			if m := stats.Spelling[word]; !m.Mastered() {
+				fmt.Printf("  %s  %d/%d correct\n", word, m.Correct, m.Attempts)
+			}
+		}
+	}
+
+	if scores := stats.HighScores[phonical.FallingLettersGame]; len(scores) > 0 {
+		fmt.Println("\nFalling letters high scores:")
+		for i, hs := range scores {
+			fmt.Printf("  %2d. %4d  level %d  %s\n", i+1, hs.Score, hs.Level, hs.Date.Format("2 Jan 2006"))
+		}
+	}
+
+	if confusions := stats.TopConfusions(5); len(confusions) > 0 {
+		fmt.Println("\nSounds that need work:")
+		for _, c := range confusions {
+			fmt.Printf("  heard %c, pressed %c  (%d times)\n", c.Heard, c.Pressed, c.Count)
+		}
+	}
+	return nil
+}
+
+// runStatsPurge deletes a profile's session transcripts, and with --all its
+// stats too.
+func runStatsPurge(args []string) error {
+	fs := flag.NewFlagSet("stats purge", flag.ExitOnError)
+	var flags engineFlags
+	fs.StringVar(&flags.statsPath, "stats", "", "stats file whose transcripts to delete")
+	fs.StringVar(&flags.profile, "profile", phonical.DefaultProfile, "profile to purge")
+	age := fs.Duration("older-than", 0, "only delete transcripts older than this")
+	all := fs.Bool("all", false, "also delete the profile's stats")
+	if err := flags.parse(fs, args); err != nil {
+		return err
+	}
+
+	path, err := flags.statsFile()
+	if err != nil {
+		return err
+	}
+	n, err := phonical.PurgeTranscripts(phonical.TranscriptDir(path), *age)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Deleted %d transcripts\n", n)
+	if *all {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		fmt.Printf("Deleted stats %s\n", path)
+	}
+	return nil
+}
+
+func runStory(args []string) error {
+	fs := flag.NewFlagSet("story", flag.ExitOnError)
+	var flags engineFlags
+	flags.register(fs)
+	file := fs.String("file", "", "plain-text story to type along with")
+	name := fs.String("name", "", "built-in story to use: "+strings.Join(phonical.BuiltinStories(), ", "))
+	if err := flags.parse(fs, args); err != nil {
+		return err
+	}
+
+	var story phonical.Story
+	var err error
+	switch {
+	case *file != "":
+		story, err = phonical.LoadStory(*file)
+	case *name != "":
+		story, err = phonical.BuiltinStory(*name)
+	default:
+		stories := phonical.BuiltinStories()
+		story, err = phonical.BuiltinStory(stories[rand.Intn(len(stories))])
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Story mode: %s\n", story.Title)
+	fmt.Println("Listen, then type each letter you hear. Press Ctrl+C to stop")
+	return runActivity(&flags, phonical.TypeAlong(story))
+}
+
+func runLesson(args []string) error {
+	fs := flag.NewFlagSet("lesson", flag.ExitOnError)
+	var flags engineFlags
+	flags.register(fs)
+	if err := flags.parse(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: phonical lesson [options] LESSON.toml")
+	}
+
+	lesson, err := phonical.LoadLesson(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Lesson: %s (%d targets)\n", lesson.Title, len(lesson.Targets))
+	fmt.Println("Press Ctrl+C to stop")
+	return runActivity(&flags, phonical.RunLesson(lesson, func(r phonical.LessonResult) {
+		verdict := "not passed"
+		if r.Passed {
+			verdict = "passed"
+		}
+		fmt.Printf("Score: %d/%d (%s)\n", r.Correct, r.Total, verdict)
+	}))
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"phonical"
+)
+
+// errAlreadyRunning is returned by listenControl when another instance
+// holds the control socket.
+var errAlreadyRunning = errors.New("phonical is already running")
+
+// controlRequest is one command sent over the control socket, encoded as a
+// line of JSON.
+type controlRequest struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+}
+
+// controlReply answers a controlRequest.
+type controlReply struct {
+	OK    bool            `json:"ok"`
+	Error string          `json:"error,omitempty"`
+	PID   int             `json:"pid"`
+	Data  json.RawMessage `json:"data,omitempty"`
+}
+
+// instance is the running engine as seen by control commands.
+type instance struct {
+	engine  *phonical.Engine
+	profile string
+}
+
+// controlHandler runs a control command against the running instance.
+type controlHandler func(inst *instance, args []string) (any, error)
+
+// controlCommands are the commands the running instance accepts.
+var controlCommands = map[string]controlHandler{
+	"ping":   func(*instance, []string) (any, error) { return nil, nil },
+	"set":    controlSet,
+	"status": controlStatus,
+	"trace": func(inst *instance, _ []string) (any, error) {
+		return inst.engine.Trace(), nil
+	},
+	"stop": func(inst *instance, _ []string) (any, error) {
+		inst.engine.Stop()
+		return nil, nil
+	},
+}
+
+// forwardFlags are the flags a second invocation passes on to the running
+// instance instead of starting its own.
+var forwardFlags = []string{"mode", "voice", "language", "guest"}
+
+// controlSet changes a setting of the running engine: set NAME VALUE.
+func controlSet(inst *instance, args []string) (any, error) {
+	if len(args) != 2 {
+		return nil, errors.New("usage: set NAME VALUE")
+	}
+	switch args[0] {
+	case "mode":
+		mode, err := phonical.ParseMode(args[1])
+		if err != nil {
+			return nil, err
+		}
+		inst.engine.SetMode(mode)
+	case "voice":
+		voice, err := phonical.ParseVoice(args[1])
+		if err != nil {
+			return nil, err
+		}
+		inst.engine.SetVoice(voice)
+	case "language":
+		pack, err := openLanguage(args[1])
+		if err != nil {
+			return nil, err
+		}
+		inst.engine.SetPack(pack)
+	case "guest":
+		on, err := strconv.ParseBool(args[1])
+		if err != nil {
+			return nil, fmt.Errorf("guest must be true or false, got %q", args[1])
+		}
+		inst.engine.SetGuest(on)
+	default:
+		return nil, fmt.Errorf("%s cannot be changed while running", args[0])
+	}
+	return nil, nil
+}
+
+// socketPath returns where the running instance listens for commands.
+func socketPath() (string, error) {
+	dir, err := phonical.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "phonical.sock"), nil
+}
+
+// listenControl claims the control socket, which also ensures only one
+// instance plays at a time. A socket left behind by a crashed instance is
+// replaced.
+func listenControl() (net.Listener, error) {
+	path, err := socketPath()
+	if err != nil {
+		return nil, err
+	}
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return nil, errAlreadyRunning
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	os.Remove(path)
+	return net.Listen("unix", path)
+}
+
+// serveControl answers control commands until l is closed.
+func serveControl(l net.Listener, inst *instance) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go handleControl(conn, inst)
+	}
+}
+
+func handleControl(conn net.Conn, inst *instance) {
+	defer conn.Close()
+
+	reply := controlReply{PID: os.Getpid()}
+	var req controlRequest
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+		reply.Error = err.Error()
+	} else if handler, ok := controlCommands[req.Command]; !ok {
+		reply.Error = fmt.Sprintf("unknown command %q", req.Command)
+	} else if data, err := handler(inst, req.Args); err != nil {
+		reply.Error = err.Error()
+	} else {
+		reply.OK = true
+		if data != nil {
+			reply.Data, _ = json.Marshal(data)
+		}
+	}
+	json.NewEncoder(conn).Encode(&reply)
+}
+
+// sendControl sends a command to the running instance.
+func sendControl(command string, args ...string) (*controlReply, error) {
+	path, err := socketPath()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("phonical is not running: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if err := json.NewEncoder(conn).Encode(&controlRequest{Command: command, Args: args}); err != nil {
+		return nil, err
+	}
+	var reply controlReply
+	if err := json.NewDecoder(conn).Decode(&reply); err != nil {
+		return nil, err
+	}
+	if !reply.OK {
+		return &reply, errors.New(reply.Error)
+	}
+	return &reply, nil
+}
+
+// forwardToRunning passes any forwardable flags set on the command line to
+// the running instance. It reports whether there were any.
+func forwardToRunning(fs *flag.FlagSet) (bool, error) {
+	forwarded := false
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		for _, name := range forwardFlags {
+			if f.Name == name && err == nil {
+				_, err = sendControl("set", name, f.Value.String())
+				forwarded = true
+			}
+		}
+	})
+	return forwarded, err
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// controlSocket gives the test its own config directory, so the control
+// socket is its own too.
+func controlSocket(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS != "linux" {
+		t.Skip("config directory is only moved with XDG_CONFIG_HOME on Linux")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path, err := socketPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSingleInstance(t *testing.T) {
+	controlSocket(t)
+	h := phonicaltest.New(t)
+	l, err := listenControl()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go serveControl(l, &instance{engine: h.Engine})
+
+	if _, err := listenControl(); !errors.Is(err, errAlreadyRunning) {
+		t.Fatalf("second instance: %v, want %v", err, errAlreadyRunning)
+	}
+	if _, err := sendControl("set", "mode", "blend"); err != nil {
+		t.Fatal(err)
+	}
+	if mode := h.Engine.Status().Mode; mode != phonical.ModeBlend.String() {
+		t.Errorf("mode %s after set, want blend", mode)
+	}
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"set", "volume", "1"}, "volume cannot be changed while running"},
+		{[]string{"set", "mode"}, "usage: set NAME VALUE"},
+		{[]string{"dance"}, `unknown command "dance"`},
+	} {
+		if _, err := sendControl(tc.args[0], tc.args[1:]...); err == nil || err.Error() != tc.want {
+			t.Errorf("%s: %v, want %q", strings.Join(tc.args, " "), err, tc.want)
+		}
+	}
+}
+
+func TestStaleControlSocket(t *testing.T) {
+	path := controlSocket(t)
+	if _, err := sendControl("ping"); err == nil || !strings.Contains(err.Error(), "not running") {
+		t.Errorf("ping with nothing running: %v", err)
+	}
+
+	// A crashed instance leaves its socket file behind
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	l, err := listenControl()
+	if err != nil {
+		t.Fatalf("taking over a stale socket: %v", err)
+	}
+	l.Close()
+}
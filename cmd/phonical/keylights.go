@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+
+	"phonical"
+)
+
+// startKeyLights connects to the OpenRGB server at addr, or the usual one
+// when addr is empty.
+func startKeyLights(addr string) (*phonical.KeyLights, error) {
+	if addr == "" {
+		addr = phonical.DefaultOpenRGBAddr
+	}
+	lights, err := phonical.NewKeyLights(addr)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Key lights: %d keys on RGB keyboards light up as they play\n", lights.Keys())
+	return lights, nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"phonical"
+)
+
+// runListen plays sound sent by phonical running elsewhere with
+// --audio-to.
+func runListen(args []string) error {
+	fs := flag.NewFlagSet("listen", flag.ExitOnError)
+	addr := fs.String("addr", ":"+phonical.DefaultAudioPort, "address to listen on")
+	fs.Parse(args)
+
+	l, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Printf("Playing sound sent to %s. Press Ctrl+C to stop\n", l.Addr())
+	return phonical.ServeAudio(ctx, l)
+}
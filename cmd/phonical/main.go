@@ -0,0 +1,354 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"phonical"
+)
+
+// commands are the subcommands accepted as the first argument.
+var commands = map[string]func(args []string) error{
+	"assess":     runAssess,
+	"debug":      runDebug,
+	"falling":    runFalling,
+	"families":   runFamilies,
+	"flashcards": runFlashcards,
+	"lesson":     runLesson,
+	"listen":     runListen,
+	"nonsense":   runNonsense,
+	"packs":      runPacks,
+	"pairs":      runPairs,
+	"preview":    runPreview,
+	"radio":      runRadio,
+	"render":     runRender,
+	"setup":      runSetup,
+	"sounds":     runSounds,
+	"spell":      runSpell,
+	"stats":      runStats,
+	"status":     runStatus,
+	"story":      runStory,
+	"targets":    runTargets,
+	"test-audio": runTestAudio,
+	"tui":        runTUI,
+	"update":     runUpdate,
+	"verify":     runVerify,
+	"version":    runVersion,
+}
+
+func usage() {
+	fmt.Println("Phonical - A phonics learning tool for kids")
+	fmt.Println("\nUsage:")
+	fmt.Printf("  %s [options]\n", filepath.Base(os.Args[0]))
+	fmt.Printf("  %s <command> [options]\n", filepath.Base(os.Args[0]))
+	fmt.Println("\nCommands:")
+	fmt.Println("  assess [-o FILE]     Assess letter sounds and decoding; FILE is .json or .csv")
+	fmt.Println("  debug dump [--json]  Print the running instance's recent key and sound events")
+	fmt.Println("  falling [--lives N]  Play the falling letters game")
+	fmt.Println("  families             Drill word families like -at and -ig (--count N)")
+	fmt.Println("  flashcards -o FILE   Print letter flashcards with the pack's words (--lang, --level N)")
+	fmt.Println("  lesson FILE          Run a lesson file")
+	fmt.Println("  listen [--addr ADDR] Play sound sent by another computer's --audio-to")
+	fmt.Println("  nonsense             Type made-up words like \"tep\" from their sounds (--rounds N)")
+	fmt.Println("  packs init DIR       Start a sound pack to record (--lang LANG, --mode MODE)")
+	fmt.Println("  packs check DIR      Show how much of a pack is recorded")
+	fmt.Println("  packs bundle DIR     Write a pack to a .phonpack file for sharing (-o FILE)")
+	fmt.Println("  packs install FILE   Install a .phonpack file, for use with --pack NAME")
+	fmt.Println("  pairs                Play the minimal-pairs listening game")
+	fmt.Println("  preview              Play every sound of the pack, naming each (--only a,b,cues)")
+	fmt.Println("  radio [--sleep D]    Play letters slowly at random, no keyboard needed (--gap D)")
+	fmt.Println("  render TEXT -o FILE  Write the sounds for TEXT to a WAV file")
+	fmt.Println("  setup                Choose settings and test sound and keyboard access")
+	fmt.Println("  sounds validate      Check that every sound in a pack loads (--pack DIR)")
+	fmt.Println("  sounds missing       List characters typed with no sound, suggesting pack entries")
+	fmt.Println("  spell --list FILE    Spelling bee with the words in FILE")
+	fmt.Println("  stats                Show practice stats")
+	fmt.Println("  stats purge          Delete a profile's transcripts (--older-than D, --all for stats too)")
+	fmt.Println("  status [--json]      Show the running instance's state; exits 1 if not running")
+	fmt.Println("  story                Type along with a short story")
+	fmt.Println("  targets [LIST]       Show or set a profile's therapy targets, e.g. s,sh (--clear)")
+	fmt.Println("  test-audio           Play a tone left, right and on both sides; show the output")
+	fmt.Println("  tui                  Watch and control the running instance live")
+	fmt.Println("  update [--check]     Install the newest release after verifying its checksum")
+	fmt.Println("  verify [--pack DIR]  Check sound files against their recorded checksums (--write to record)")
+	fmt.Println("  version [--json]     Show version and build details")
+	fmt.Println("\nOptions:")
+	fmt.Println("  -v, --verbose        Show verbose output")
+	fmt.Println("  --queue-size N       Number of sounds that may wait to play (default 100)")
+	fmt.Println("  --trace-size N       Recent events kept for \"phonical debug dump\" (default 500)")
+	fmt.Println("  --overflow POLICY    What to do when the queue is full: drop-newest,")
+	fmt.Println("                       drop-oldest or coalesce-duplicates (default drop-newest)")
+	fmt.Println("  --coalesce WINDOW    Play repeated presses of a letter within WINDOW once,")
+	fmt.Println("                       e.g. 400ms (default off)")
+	fmt.Println("  --announce-repeats   Say how many times a coalesced letter was pressed")
+	fmt.Println("  --key-click          Play a soft click under every key press")
+	fmt.Println("  --volume N           Letter sound volume from 0 to 100 (default 100)")
+	fmt.Println("  --click-volume V     Key click volume from 0 to 1 (default 0.15)")
+	fmt.Println("  --max-sound-length D Fade out letter sounds longer than D, such as 600ms")
+	fmt.Println("  --crossfade D        Overlap queued letter sounds by D when typing fast")
+	fmt.Println("  --output-latency D   Compensate for headphones that play sound D late, such")
+	fmt.Println("                       as Bluetooth (try 200ms)")
+	fmt.Println("  --audio-to HOST      Send sound to \"phonical listen\" on HOST instead of")
+	fmt.Println("                       playing it here")
+	fmt.Println("  --dry-run            Print each sound instead of playing it")
+	fmt.Println("  --language LANG      Built-in sounds to use (default \"en\")")
+	fmt.Println("  --language-key LANGS Let F10 switch between the sounds and LANGS, a comma-")
+	fmt.Println("                       separated list of languages or pack directories")
+	fmt.Println("  --second-language L  Also play letters in language (or pack directory) L")
+	fmt.Println("  --bilingual SCHEME   When the second language plays: shift (letters typed")
+	fmt.Println("                       with Shift), alternate or both (default shift)")
+	fmt.Println("  --kana SCRIPT        Read letters as romaji and play the kana they spell,")
+	fmt.Println("                       in hiragana or katakana")
+	fmt.Println("  --pinyin             Play toned pinyin syllables typed with a tone number")
+	fmt.Println("                       (ma1 to ma4)")
+	fmt.Println("  --pack DIR           Use the sound pack in DIR, or the installed pack DIR,")
+	fmt.Println("                       instead of the built-in sounds")
+	fmt.Println("  --layer DIRS         Layer the packs in DIRS, comma-separated, over the sounds;")
+	fmt.Println("                       each sound comes from the last pack that has it")
+	fmt.Println("  --cache-sounds       Keep decoded sounds on disk so later starts are faster")
+	fmt.Println("  --mono               Keep sounds in mono, halving the memory they use")
+	fmt.Println("  --cues               Play a soft cue for space and Enter")
+	fmt.Println("  --time-of-day        Say good morning or good evening, and play timed clips")
+	fmt.Println("  --theme NAME         Layer a theme's cues and encouragement over the pack;")
+	fmt.Println("                       auto picks the installed theme in season")
+	fmt.Println("  --announce-removals  Say which letter Backspace took away")
+	fmt.Println("  --name-keys          Name navigation keys (up, down, tab, escape...)")
+	fmt.Println("  --rhymes             Suggest rhyming words after a completed word")
+	fmt.Println("  --rhyme-interval D   Least time between rhyme suggestions (default 30s)")
+	fmt.Println("  --mode MODE          What to play after each word: letters (nothing extra),")
+	fmt.Println("                       blend, onset-rime, syllables or boxes (default letters)")
+	fmt.Println("  --box-gap D          Pause after each sound in boxes mode (default 700ms)")
+	fmt.Println("  --echo               Quietly sound out and blend each word in any mode")
+	fmt.Println("  --echo-volume V      Word echo volume from 0 to 1 (default 0.4)")
+	fmt.Println("  --claps              Clap between syllables in syllables mode")
+	fmt.Println("  --voice VOICE        Letter voice: normal, chipmunk, giant or robot")
+	fmt.Println("  --voice-key          Let F9 switch voices while typing")
+	fmt.Println("  --slow-key MOD       Play a letter in slow motion while MOD (shift, ctrl, alt or")
+	fmt.Println("                       meta) is held")
+	fmt.Println("  --notify             Show pausing, resuming and switching sounds as desktop")
+	fmt.Println("                       notifications")
+	fmt.Println("  --help-key           Let F1 say which hotkeys are set up and the current mode")
+	fmt.Println("  --pan                Play each letter from where its key is, left to right")
+	fmt.Println("  --visual             Flash each letter and caption spoken words in the terminal")
+	fmt.Println("  --silent             Turn audio off and show everything visually")
+	fmt.Println("  --ignore-dnd         Keep playing while Do Not Disturb or Focus is on")
+	fmt.Println("  --pause-on-calls     Go quiet while the microphone is in use, e.g. in a video call")
+	fmt.Println("  --focus LETTERS      Play only these letters in full; others get a quiet tick")
+	fmt.Println("  --focus-rotate D     Pick a new letter of the day every D (e.g. 24h), favouring")
+	fmt.Println("                       letters that are rarely typed")
+	fmt.Println("  --focus-pool LETTERS Letters to rotate through (default all)")
+	fmt.Println("  --adaptive           Emphasize letters that need practice and ask the child")
+	fmt.Println("                       to find them now and then")
+	fmt.Println("  --prompt-every D     Time between find-the-letter prompts (default 5m, 0 off)")
+	fmt.Println("  --pop-quiz D         Now and then, about every D, ask for the letter that")
+	fmt.Println("                       makes a sound (default off)")
+	fmt.Println("  --curriculum NAME    Follow a teaching order: jolly-phonics or letters-and-sounds")
+	fmt.Println("  --level N            Curriculum level reached; later letters get a quiet tick")
+	fmt.Println("                       (default 1)")
+	fmt.Println("  --celebrate          Celebrate the first time each letter is pressed")
+	fmt.Println("  --dead-keys          Combine accents typed with dead keys (´ then e is é)")
+	fmt.Println("  --emoji              Play fun sounds for emoji (🐶 the dog says woof)")
+	fmt.Println("  --finger-hints KEYS  Say which finger and row types each of KEYS (or \"all\")")
+	fmt.Println("  --speed-feedback D   Praise typing-speed milestones, at most every D (e.g. 2m)")
+	fmt.Println("  --input SOURCE       Where keys come from: hook (system-wide, the default)")
+	fmt.Println("                       or stdin (typed or piped text)")
+	fmt.Println("  --record FILE        Save every key press to FILE for --replay")
+	fmt.Println("  --replay FILE        Play back a recorded session instead of listening")
+	fmt.Println("  --check-updates      Look for a new release once a day")
+	fmt.Println("  --metrics ADDR       Serve Prometheus metrics at http://ADDR/metrics")
+	fmt.Println("  --captions ADDR      Serve live captions at http://ADDR/ for an OBS browser source")
+	fmt.Println("  --captions-file FILE Keep FILE holding the current caption, for an OBS text source")
+	fmt.Println("  --overlay ADDR       Show each letter large in a window pinned to a corner of")
+	fmt.Println("                       the screen; open http://ADDR/ to place it")
+	fmt.Println("  --openrgb ADDR       Light each key on RGB keyboards as its sound plays, through")
+	fmt.Println("                       the OpenRGB SDK server at ADDR (e.g. localhost:6742)")
+	fmt.Println("  --companion ADDR     Take key presses from a tablet app over a WebSocket at ADDR")
+	fmt.Println("  --companion-token T  Token the app must give (default a new one each run)")
+	fmt.Println("  --audio-retry D      First wait before restarting audio after the device fails,")
+	fmt.Println("                       doubling each attempt; 0 disables (default 500ms)")
+	fmt.Println("  --audio-retry-max D  Longest wait between audio restarts (default 30s)")
+	fmt.Println("  --hook-retry D       First wait before starting the keyboard hook again when it")
+	fmt.Println("                       fails, doubling each attempt; 0 gives up (default 2s)")
+	fmt.Println("  --hook-retry-max D   Longest wait between keyboard hook attempts (default 1m)")
+	fmt.Println("  --unknown MODE       For characters without a sound: silent, click, say (their")
+	fmt.Println("                       name) or log (for sounds missing) (default silent)")
+	fmt.Println("  --watchdog D         Restart audio when letters are typed for D without a")
+	fmt.Println("                       sound playing; 0 disables (default 15s)")
+	fmt.Println("  --profile NAME       Keep separate stats for each child (default \"default\")")
+	fmt.Println("  --profile-key NAMES  Let F11 switch to the profiles NAMES, comma-separated")
+	fmt.Println("  --guest              Record no stats or progress, for visitors")
+	fmt.Println("  --guest-key          Let F12 turn guest mode on and off")
+	fmt.Println("  --stats FILE         Where to keep practice stats")
+	fmt.Println("  --targets            Play the profile's therapy targets slower and clearer")
+	fmt.Println("  --transcript         Keep a timestamped list of the letters typed each session,")
+	fmt.Println("                       beside the stats, for speech-therapy review")
+	fmt.Println("  --transcript-keep D  Delete transcripts older than D; 0 keeps them (default 720h)")
+	fmt.Println("  --crash-reports      Save a report if Phonical crashes, with nothing typed in it")
+	fmt.Println("  --crash-report-url URL")
+	fmt.Println("                       Also send crash reports to URL, such as a school's own server")
+	fmt.Println("  -h, --help           Show this help message")
+	fmt.Println("\nPress ESC or Ctrl+C to exit")
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			if err := cmd(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+
+	if len(os.Args) == 1 && needsSetup() {
+		if err := runSetup(nil); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var flags engineFlags
+	flag.Usage = usage
+	flags.register(flag.CommandLine)
+	if err := flags.parse(flag.CommandLine, os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+	if flags.crashes && os.Getenv(crashChildEnv) == "" {
+		if code := superviseCrashes(flags.crashURL); code >= 0 {
+			os.Exit(code)
+		}
+	}
+
+	control, err := listenControl()
+	if errors.Is(err, errAlreadyRunning) {
+		forwarded, err := forwardToRunning(flag.CommandLine)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if forwarded {
+			fmt.Println("Updated the running Phonical.")
+			return
+		}
+		log.Fatal("Phonical is already running. Stop it first, or pass --mode or --voice to change its settings.")
+	} else if err != nil {
+		log.Fatal("Failed to claim control socket: ", err)
+	}
+	defer control.Close()
+
+	opts, stats, recording, err := flags.options()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if isTerminal(os.Stdout) {
+		opts = append(opts, phonical.WithPreloadProgress(showPreload))
+	}
+	opts = append(opts, phonical.WithAudioFallback(func(err error) {
+		log.Printf("Warning: %v. Carrying on without sound.", err)
+	}))
+	sinks, companion, err := startSinks(flags.sinkConfigs())
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts = append(opts, sinks)
+
+	fmt.Println("Phonical - Phonics Learning Tool")
+	fmt.Println("System-wide phonics - works across all applications!")
+	fmt.Println("Press Ctrl+C to exit")
+	fmt.Println("\nNote: You may need to grant Accessibility permissions in:")
+	fmt.Println("System Preferences → Security & Privacy → Privacy → Accessibility")
+
+	engine, err := phonical.New(opts...)
+	if err != nil {
+		log.Fatal("Failed to create engine: ", err)
+	}
+
+	// Initialize speaker and preload sounds before listening
+	if err := engine.Start(); err != nil {
+		flags.notifyFailure("Sound could not start: " + err.Error())
+		log.Fatal("Failed to initialize audio:", err)
+	}
+
+	stopOnSignal(engine)
+	go serveControl(control, &instance{engine: engine, profile: flags.profile})
+	serveMetrics(flags.metrics, engine)
+	if companion != nil {
+		go engine.RunSource(context.Background(), companion)
+	}
+
+	if flags.updates {
+		go checkForUpdates()
+	}
+	if flags.latency == 0 && flags.audioTo == "" {
+		suggestLatency()
+	}
+
+	switch {
+	case flags.replay != "":
+		fmt.Printf("\nReplaying %s...\n", flags.replay)
+	case flags.input == "stdin":
+		fmt.Println("\nReading keys from standard input...")
+	default:
+		fmt.Println("\nListening for keystrokes system-wide...")
+	}
+
+	if err := flags.run(engine); err != nil {
+		flags.notifyFailure("Phonical stopped: " + err.Error())
+		log.Fatal(err)
+	}
+	engine.Stop()
+	if recording != nil {
+		if err := recording.Close(); err != nil {
+			log.Printf("Failed to save the recording: %v", err)
+		}
+	}
+	// A guest session leaves the stats file alone, unless F12 could have
+	// turned guest mode off part way through
+	if flags.guest && !flags.guestKey {
+		return
+	}
+	if err := stats.Save(); err != nil {
+		log.Printf("Failed to save stats: %v", err)
+	}
+}
+
+// notifyFailure shows why Phonical is stopping as a desktop notification
+// when --notify is set, since it may be running without a terminal.
+func (f *engineFlags) notifyFailure(message string) {
+	if f.notify {
+		phonical.SystemNotifier{}.Notify("Phonical", message)
+	}
+}
+
+// showPreload keeps a line updated with how many sounds have loaded.
+func showPreload(done, total int) {
+	fmt.Printf("\rLoading sounds... %d/%d", done, total)
+	if done == total {
+		fmt.Println()
+	}
+}
+
+// suggestLatency mentions --output-latency when sound is going to
+// Bluetooth headphones.
+func suggestLatency() {
+	if name, ok, err := phonical.BluetoothOutput(); err == nil && ok {
+		fmt.Printf("Sound is going to Bluetooth (%s). If letters sound late, try --output-latency %s\n",
+			name, phonical.SuggestedBluetoothLatency)
+	}
+}
+
+// stopOnSignal stops engine on Ctrl+C or SIGTERM.
+func stopOnSignal(engine *phonical.Engine) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		fmt.Println("\nExiting Phonical...")
+		engine.Stop()
+	}()
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"phonical"
+)
+
+// serveMetrics exposes the engine's Prometheus metrics at addr, if set.
+func serveMetrics(addr string, engine *phonical.Engine) {
+	if addr == "" {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", engine.MetricsHandler())
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Printf("Metrics server stopped: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"phonical"
+)
+
+// startOverlay serves the overlay at sc's address, keeping the placement
+// and look chosen on its page in the settings file.
+func startOverlay(sc phonical.SinkConfig) *phonical.Overlay {
+	overlay := phonical.NewOverlay(sc.OverlaySettings, func(settings phonical.OverlaySettings) error {
+		return saveOverlaySettings(sc.Address, settings)
+	})
+	go func() {
+		if err := http.ListenAndServe(sc.Address, overlay); err != nil {
+			log.Printf("Overlay server stopped: %v", err)
+		}
+	}()
+	fmt.Printf("Overlay: open http://%s/ to set it up\n", sc.Address)
+	return overlay
+}
+
+// saveOverlaySettings records settings in the overlay's [[sink]] entry of
+// the settings file, adding one served at addr when the file has none.
+func saveOverlaySettings(addr string, settings phonical.OverlaySettings) error {
+	path, err := phonical.DefaultConfigPath()
+	if err != nil {
+		return err
+	}
+	cfg, err := phonical.LoadConfig(path)
+	if errors.Is(err, os.ErrNotExist) {
+		cfg = &phonical.Config{}
+	} else if err != nil {
+		return err
+	}
+	for i := range cfg.Sinks {
+		if cfg.Sinks[i].Type == phonical.SinkOverlay {
+			cfg.Sinks[i].OverlaySettings = settings
+			return cfg.Save(path)
+		}
+	}
+	cfg.Sinks = append(cfg.Sinks, phonical.SinkConfig{Type: phonical.SinkOverlay, Address: addr, OverlaySettings: settings})
+	return cfg.Save(path)
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"phonical"
+)
+
+// packsCommands are the subcommands of `phonical packs`.
+var packsCommands = map[string]func(args []string) error{
+	"bundle":  runPacksBundle,
+	"check":   runPacksCheck,
+	"init":    runPacksInit,
+	"install": runPacksInstall,
+}
+
+func runPacks(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: phonical packs init|check|bundle DIR or phonical packs install FILE")
+	}
+	cmd, ok := packsCommands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown packs command %q", args[0])
+	}
+	return cmd(args[1:])
+}
+
+// templatePack finds the built-in sound group for lang and, if given,
+// mode, such as "phonics", to base a new pack on.
+func templatePack(lang, mode string) (*phonical.Pack, error) {
+	for _, name := range phonical.EmbeddedPacks() {
+		if strings.HasPrefix(name, lang+"/") && (mode == "" || strings.HasSuffix(name, "/"+mode)) {
+			return phonical.EmbeddedPack(name)
+		}
+	}
+	if mode != "" {
+		return nil, fmt.Errorf("no built-in sounds for language %q and mode %q (have %s)", lang, mode, strings.Join(phonical.EmbeddedPacks(), ", "))
+	}
+	return nil, fmt.Errorf("no built-in sounds for language %q", lang)
+}
+
+// runPacksInit starts a pack with a manifest naming every sound to record.
+func runPacksInit(args []string) error {
+	fs := flag.NewFlagSet("packs init", flag.ExitOnError)
+	lang := fs.String("lang", "en", "language whose letters the pack records")
+	mode := fs.String("mode", "", "built-in sound group to follow, such as phonics")
+	// Options may come before or after the directory
+	fs.Parse(args)
+	if fs.NArg() < 1 {
+		return errors.New("usage: phonical packs init DIR [--lang LANG] [--mode MODE]")
+	}
+	dir := fs.Arg(0)
+	fs.Parse(fs.Args()[1:])
+	if fs.NArg() != 0 {
+		return errors.New("usage: phonical packs init DIR [--lang LANG] [--mode MODE]")
+	}
+
+	template, err := templatePack(*lang, *mode)
+	if err != nil {
+		return err
+	}
+	if err := phonical.ScaffoldPack(dir, template); err != nil {
+		return err
+	}
+	pack, err := phonical.LoadPack(dir)
+	if err != nil {
+		return err
+	}
+	_, total := pack.Completion()
+	fmt.Printf("Started %s with %d sounds to record, based on %s.\n", dir, total, template.Name)
+	fmt.Printf("Run \"phonical packs check %s\" to see how far along it is.\n", dir)
+	return nil
+}
+
+// runPacksCheck shows how much of a pack is recorded and what is left.
+func runPacksCheck(args []string) error {
+	fs := flag.NewFlagSet("packs check", flag.ExitOnError)
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("usage: phonical packs check DIR")
+	}
+	dir := fs.Arg(0)
+
+	pack, err := phonical.LoadPack(dir)
+	if err != nil {
+		return err
+	}
+	recorded, total := pack.Completion()
+	percent := 100
+	if total > 0 {
+		percent = recorded * 100 / total
+	}
+	fmt.Printf("%s: %d of %d sounds recorded (%d%%)\n", pack.Name, recorded, total, percent)
+	var todo, bad []string
+	for _, p := range pack.Validate() {
+		if errors.Is(p.Err, phonical.ErrSoundMissing) {
+			todo = append(todo, fmt.Sprintf("%s (%s)", p.Entry, p.File))
+		} else {
+			bad = append(bad, fmt.Sprintf("%s (%s): %v", p.Entry, p.File, p.Err))
+		}
+	}
+	if len(todo) > 0 {
+		fmt.Println("Still to record:")
+		for _, t := range todo {
+			fmt.Printf("  %s\n", t)
+		}
+	}
+	if len(bad) > 0 {
+		fmt.Println("Can't be played:")
+		for _, b := range bad {
+			fmt.Printf("  %s\n", b)
+		}
+	}
+	return nil
+}
+
+// runPacksBundle writes a pack out as a .phonpack archive for sharing.
+func runPacksBundle(args []string) error {
+	fs := flag.NewFlagSet("packs bundle", flag.ExitOnError)
+	out := fs.String("o", "", "archive to write (default DIR"+phonical.PackArchiveExt+")")
+	fs.Parse(args)
+	if fs.NArg() < 1 {
+		return errors.New("usage: phonical packs bundle DIR [-o FILE]")
+	}
+	dir := fs.Arg(0)
+	fs.Parse(fs.Args()[1:])
+	if fs.NArg() != 0 {
+		return errors.New("usage: phonical packs bundle DIR [-o FILE]")
+	}
+	if *out == "" {
+		*out = filepath.Clean(dir) + phonical.PackArchiveExt
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := phonical.BundlePack(f, dir); err != nil {
+		f.Close()
+		os.Remove(*out)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %s\n", *out)
+	return nil
+}
+
+// runPacksInstall unpacks a .phonpack archive into the installed packs.
+func runPacksInstall(args []string) error {
+	fs := flag.NewFlagSet("packs install", flag.ExitOnError)
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errors.New("usage: phonical packs install FILE" + phonical.PackArchiveExt)
+	}
+	pack, err := phonical.InstallPack(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	name := strings.TrimSuffix(filepath.Base(fs.Arg(0)), filepath.Ext(fs.Arg(0)))
+	fmt.Printf("Installed %s; use it with --pack %s\n", pack.Name, name)
+	return nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"phonical"
+)
+
+// runPreview plays every sound of the pack in use, naming each, so a pack
+// can be checked by ear.
+func runPreview(args []string) error {
+	fs := flag.NewFlagSet("preview", flag.ExitOnError)
+	var flags engineFlags
+	flags.register(fs)
+	only := fs.String("only", "", "comma-separated letters, tables or entries to play, such as a,b,cues")
+	gap := fs.Duration("gap", phonical.DefaultPreviewGap, "pause between sounds")
+	if err := flags.parse(fs, args); err != nil {
+		return err
+	}
+
+	opts, _, recording, err := flags.options()
+	if err != nil {
+		return err
+	}
+	if recording != nil {
+		defer recording.Close()
+	}
+	engine, err := phonical.New(opts...)
+	if err != nil {
+		return err
+	}
+	if err := engine.Start(); err != nil {
+		return fmt.Errorf("failed to initialize audio: %w", err)
+	}
+	stopOnSignal(engine)
+	defer engine.Stop()
+
+	var names []string
+	if *only != "" {
+		for _, name := range strings.Split(*only, ",") {
+			names = append(names, strings.TrimSpace(name))
+		}
+	}
+	fmt.Println("Previewing sounds. Press Ctrl+C to stop")
+	err = engine.RunActivity(phonical.Preview(names, *gap, func(entry phonical.PreviewEntry) {
+		fmt.Printf("  %-16s %s\n", entry.Entry, entry.File)
+	}))
+	if errors.Is(err, phonical.ErrStopped) {
+		return nil
+	}
+	return err
+}
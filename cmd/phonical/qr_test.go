@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReedSolomon(t *testing.T) {
+	// The version 1-M example from the QR code standard
+	data := []byte{0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11}
+	want := []byte{0xA5, 0x24, 0xD4, 0xC1, 0xED, 0x36, 0xC7, 0x87, 0x2C, 0x55}
+	if got := rsRemainder(data, rsDivisor(10)); !bytes.Equal(got, want) {
+		t.Errorf("error correction % X, want % X", got, want)
+	}
+}
+
+func TestEncodeQR(t *testing.T) {
+	// Version 1-M holds 14 bytes
+	for _, tt := range []struct {
+		text string
+		size int
+	}{
+		{strings.Repeat("x", 14), 21},
+		{strings.Repeat("x", 15), 25},
+		{"http://192.168.1.20:8765/?token=0123456789abcdef", 33},
+	} {
+		q, err := encodeQR(tt.text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q.size != tt.size {
+			t.Errorf("%d bytes made a code %d modules wide, want %d", len(tt.text), q.size, tt.size)
+		}
+
+		// Finder patterns in three corners, with the dark module beside
+		// the bottom one
+		for _, c := range [][2]int{{0, 0}, {q.size - 7, 0}, {0, q.size - 7}} {
+			for i := 0; i < 7; i++ {
+				if !q.modules[c[1]][c[0]+i] || !q.modules[c[1]+6][c[0]+i] || !q.modules[c[1]+3][c[0]+3] || q.modules[c[1]+1][c[0]+1] {
+					t.Errorf("no finder pattern at %v", c)
+					break
+				}
+			}
+		}
+		if !q.modules[q.size-8][8] {
+			t.Error("dark module missing")
+		}
+
+		// Both copies of the format information are a valid level M
+		// format for one of the masks
+		var first, second int
+		for i := 0; i <= 5; i++ {
+			first |= bit(q.modules[i][8]) << i
+		}
+		first |= bit(q.modules[7][8])<<6 | bit(q.modules[8][8])<<7 | bit(q.modules[8][7])<<8
+		for i := 9; i < 15; i++ {
+			first |= bit(q.modules[8][14-i]) << i
+		}
+		for i := 0; i < 8; i++ {
+			second |= bit(q.modules[8][q.size-1-i]) << i
+		}
+		for i := 8; i < 15; i++ {
+			second |= bit(q.modules[q.size-15+i][8]) << i
+		}
+		valid := map[int]bool{0x5412: true, 0x5125: true, 0x5E7C: true, 0x5B4B: true, 0x45F9: true, 0x40CE: true, 0x4F97: true, 0x4AA0: true}
+		if !valid[first] || first != second {
+			t.Errorf("format information %015b and %015b, want the same level M format", first, second)
+		}
+	}
+
+	if _, err := encodeQR(strings.Repeat("x", 300)); err == nil {
+		t.Error("encoded text too long for version 10")
+	}
+}
+
+func bit(dark bool) int {
+	if dark {
+		return 1
+	}
+	return 0
+}
+
+func TestPrintQR(t *testing.T) {
+	q, err := encodeQR("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	printQR(&out, q)
+	// Two rows of modules to a line, with a quiet border of two
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 13 {
+		t.Errorf("printed %d lines, want 13", len(lines))
+	}
+	if n := strings.Count(lines[0], "▀"); n != 25 {
+		t.Errorf("line is %d cells wide, want 25", n)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"phonical"
+)
+
+// runRadio plays letters slowly at random for listening to, without the
+// keyboard, until the sleep timer runs out or it is stopped.
+func runRadio(args []string) error {
+	fs := flag.NewFlagSet("radio", flag.ExitOnError)
+	var flags engineFlags
+	flags.register(fs)
+	gap := fs.Duration("gap", phonical.DefaultRadioGap, "pause between letters")
+	sleep := fs.Duration("sleep", 0, "stop after this long (default play until stopped)")
+	if err := flags.parse(fs, args); err != nil {
+		return err
+	}
+
+	opts, _, recording, err := flags.options()
+	if err != nil {
+		return err
+	}
+	if recording != nil {
+		defer recording.Close()
+	}
+	engine, err := phonical.New(opts...)
+	if err != nil {
+		return err
+	}
+	if err := engine.Start(); err != nil {
+		return fmt.Errorf("failed to initialize audio: %w", err)
+	}
+	stopOnSignal(engine)
+	defer engine.Stop()
+
+	if *sleep > 0 {
+		fmt.Printf("Letter radio for %s. Press Ctrl+C to stop\n", *sleep)
+	} else {
+		fmt.Println("Letter radio. Press Ctrl+C to stop")
+	}
+	err = engine.RunActivity(phonical.LetterRadio(*gap, *sleep, func(l phonical.RadioLetter) {
+		if l.Word != "" {
+			fmt.Printf("  %c  %s\n", l.Letter, l.Word)
+		} else {
+			fmt.Printf("  %c\n", l.Letter)
+		}
+	}))
+	if errors.Is(err, phonical.ErrStopped) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"phonical"
+)
+
+// runRender writes the sounds for some text to a WAV file instead of
+// playing them.
+func runRender(args []string) error {
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	var flags engineFlags
+	flags.register(fs)
+	out := fs.String("o", "", "WAV file to write")
+	if err := flags.parse(fs, args); err != nil {
+		return err
+	}
+	// Options may also follow the text: phonical render "cat sat" -o out.wav
+	if fs.NArg() < 1 {
+		return errors.New("usage: phonical render [options] TEXT -o FILE.wav")
+	}
+	text := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 || *out == "" {
+		return errors.New("usage: phonical render [options] TEXT -o FILE.wav")
+	}
+	if !strings.EqualFold(filepath.Ext(*out), ".wav") {
+		return fmt.Errorf("%s: only WAV output is supported", *out)
+	}
+
+	opts, _, recording, err := flags.options()
+	if err != nil {
+		return err
+	}
+	if recording != nil {
+		defer recording.Close()
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := phonical.Render(f, text, opts...); err != nil {
+		f.Close()
+		os.Remove(*out)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %s\n", *out)
+	return nil
+}
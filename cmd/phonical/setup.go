@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"phonical"
+)
+
+// prompter asks questions on the terminal.
+type prompter struct {
+	in *bufio.Reader
+}
+
+// ask prints question and returns the answer, or def when it is blank.
+func (p *prompter) ask(question, def string) string {
+	if def != "" {
+		fmt.Printf("%s [%s]: ", question, def)
+	} else {
+		fmt.Printf("%s: ", question)
+	}
+	line, _ := p.in.ReadString('\n')
+	if line = strings.TrimSpace(line); line != "" {
+		return line
+	}
+	return def
+}
+
+// choose asks until the answer is one of options.
+func (p *prompter) choose(question string, options []string, def string) string {
+	for {
+		answer := strings.ToLower(p.ask(fmt.Sprintf("%s (%s)", question, strings.Join(options, ", ")), def))
+		for _, o := range options {
+			if answer == o {
+				return o
+			}
+		}
+		fmt.Printf("  Please choose one of: %s\n", strings.Join(options, ", "))
+	}
+}
+
+// yes asks a yes/no question.
+func (p *prompter) yes(question string, def bool) bool {
+	d := "y"
+	if !def {
+		d = "n"
+	}
+	return strings.HasPrefix(strings.ToLower(p.ask(question+" (y/n)", d)), "y")
+}
+
+// number asks until the answer is a whole number from min to max.
+func (p *prompter) number(question string, min, max, def int) int {
+	for {
+		n, err := strconv.Atoi(p.ask(question, strconv.Itoa(def)))
+		if err == nil && n >= min && n <= max {
+			return n
+		}
+		fmt.Printf("  Please enter a number from %d to %d\n", min, max)
+	}
+}
+
+// isTerminal reports whether f is an interactive terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// needsSetup reports whether this looks like the first run: no settings
+// file yet, and someone at the terminal to answer questions.
+func needsSetup() bool {
+	path, err := phonical.DefaultConfigPath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return errors.Is(err, os.ErrNotExist) && isTerminal(os.Stdin)
+}
+
+// runSetup walks through the main settings, writes the settings file and
+// checks that sound and keyboard access work.
+func runSetup(args []string) error {
+	fs := flag.NewFlagSet("setup", flag.ExitOnError)
+	fs.Parse(args)
+
+	path, err := phonical.DefaultConfigPath()
+	if err != nil {
+		return err
+	}
+	p := &prompter{in: bufio.NewReader(os.Stdin)}
+	// Running setup again starts from the saved settings, keeping those it
+	// doesn't ask about
+	cfg := &phonical.Config{Language: "en"}
+	if old, err := phonical.LoadConfig(path); err == nil {
+		cfg = old
+	}
+
+	fmt.Println("Welcome to Phonical! A few questions to get started.")
+	fmt.Println("Press Enter to accept the suggestion in brackets.")
+	askSetup(p, cfg)
+
+	if err := cfg.Save(path); err != nil {
+		return err
+	}
+	fmt.Printf("\nSaved settings to %s\n", path)
+
+	return checkSetup(p)
+}
+
+// askSetup asks the setup questions, suggesting the answers already in cfg
+// and changing only what they cover.
+func askSetup(p *prompter, cfg *phonical.Config) {
+	if langs := phonical.Languages(); len(langs) > 1 {
+		cfg.Language = p.choose("\nLanguage", langs, or(cfg.Language, "en"))
+	} else {
+		fmt.Println("\nLanguage: English")
+	}
+	fmt.Println("The built-in sounds are British English. For another accent, record")
+	fmt.Println("a sound pack and enter its folder here.")
+	question := "Sound pack folder (blank for built-in)"
+	if cfg.Pack != "" {
+		question = "Sound pack folder (\"none\" for built-in)"
+	}
+	for {
+		if cfg.Pack = p.ask(question, cfg.Pack); cfg.Pack == "none" {
+			cfg.Pack = ""
+		}
+		if cfg.Pack == "" {
+			break
+		}
+		if _, err := phonical.LoadPack(cfg.Pack); err != nil {
+			fmt.Printf("  %v\n", err)
+			cfg.Pack = ""
+			continue
+		}
+		break
+	}
+
+	fmt.Println("\nAfter each word, Phonical can sound it out:")
+	fmt.Println("  letters     nothing extra, just each letter as it is typed")
+	fmt.Println("  blend       each sound, then the whole word")
+	fmt.Println("  onset-rime  c … at … cat")
+	fmt.Println("  syllables   longer words one syllable at a time")
+	fmt.Println("  boxes       each sound after a tick, evenly paced, then the word")
+	cfg.Mode = p.choose("Mode", []string{"letters", "blend", "onset-rime", "syllables", "boxes"}, or(cfg.Mode, "letters"))
+
+	names := append([]string{"none"}, phonical.Curricula()...)
+	c := p.choose("\nFollow a school phonics programme?", names, or(cfg.Curriculum, "none"))
+	if c == "none" {
+		cfg.Curriculum, cfg.Level = "", 0
+	} else {
+		cfg.Curriculum = c
+		cfg.Level = p.number("Which level has your child reached?", 1, 10, max(cfg.Level, 1))
+	}
+
+	volume := 80
+	if cfg.Volume != nil {
+		volume = *cfg.Volume
+	}
+	volume = p.number("\nVolume, 0 to 100", 0, 100, volume)
+	cfg.Volume = &volume
+	cfg.KeyClick = p.yes("Play a soft click with every key?", cfg.KeyClick)
+	fmt.Println("\nIf Phonical crashes it can save a report for its developers. Reports")
+	fmt.Println("say where in Phonical it crashed and which system it runs on, never")
+	fmt.Println("anything typed.")
+	cfg.CrashReports = p.yes("Save crash reports?", cfg.CrashReports)
+}
+
+// or returns s, or def when s is empty.
+func or(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// checkSetup plays a test sound and waits for a key through the system-wide
+// hook, explaining what to do when either fails.
+func checkSetup(p *prompter) error {
+	var flags engineFlags
+	fs := flag.NewFlagSet("setup", flag.ContinueOnError)
+	flags.register(fs)
+	if err := flags.parse(fs, nil); err != nil {
+		return err
+	}
+	opts, _, recording, err := flags.options()
+	if err != nil {
+		return err
+	}
+	if recording != nil {
+		defer recording.Close()
+	}
+	engine, err := phonical.New(opts...)
+	if err != nil {
+		return err
+	}
+	defer engine.Stop()
+
+	fmt.Println("\nTesting sound...")
+	if err := engine.Start(); err != nil {
+		fmt.Printf("Sound could not start: %v\n", err)
+		return nil
+	}
+	engine.RunActivity(phonical.ActivityFunc(func(t *phonical.Tutor) error {
+		t.Play('a')
+		t.Say("a is for apple")
+		return nil
+	}))
+	if !p.yes("Did you hear \"a is for apple\"?", true) {
+		fmt.Println("Check the volume and that the right speakers are selected, then run")
+		fmt.Println("\"phonical setup\" again.")
+	}
+
+	if phonical.Headless() {
+		fmt.Println("\nThere is no display here, so Phonical will read keys typed into this")
+		fmt.Println("terminal instead of listening system-wide.")
+		return nil
+	}
+	fmt.Println("\nTesting keyboard access: press any letter key within 10 seconds.")
+	ctx, cancel := context.WithCancel(context.Background())
+	go engine.RunContext(ctx)
+	pressed := false
+	engine.RunActivity(phonical.ActivityFunc(func(t *phonical.Tutor) error {
+		_, pressed = t.NextKey(10 * time.Second)
+		return nil
+	}))
+	cancel()
+	if pressed {
+		fmt.Println("Keyboard access works. All set!")
+		return nil
+	}
+	fmt.Println("No key press arrived.")
+	switch runtime.GOOS {
+	case "darwin":
+		fmt.Println("Allow your terminal under System Settings → Privacy & Security →")
+		fmt.Println("Accessibility and Input Monitoring, then try again.")
+	case "linux":
+		fmt.Println("Phonical needs an X11 session; see Linux Permissions in the README.")
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"phonical"
+)
+
+func TestPrompter(t *testing.T) {
+	// Answers out of range are asked again, and blank ones take the
+	// suggestion
+	p := &prompter{in: bufio.NewReader(strings.NewReader("shout\nBlend\n11\n3\n\nno\n"))}
+	if got := p.choose("Mode", []string{"letters", "blend"}, "letters"); got != "blend" {
+		t.Errorf("chose %q, want blend", got)
+	}
+	if got := p.number("Level", 1, 10, 1); got != 3 {
+		t.Errorf("number %d, want 3", got)
+	}
+	if got := p.yes("Click?", true); !got {
+		t.Error("blank answer to a yes/no question did not take the suggested yes")
+	}
+	if got := p.yes("Crash reports?", true); got {
+		t.Error("no taken as yes")
+	}
+}
+
+func TestSetupKeepsSettings(t *testing.T) {
+	volume := 40
+	saved := &phonical.Config{
+		Language:      "en",
+		Mode:          "blend",
+		Voice:         "chipmunk",
+		Volume:        &volume,
+		KeyClick:      true,
+		Curriculum:    phonical.Curricula()[0],
+		Level:         3,
+		Profile:       "sam",
+		Notifications: true,
+		CrashReports:  true,
+		Sinks:         []phonical.SinkConfig{{Type: "captions", Address: "localhost:8765"}},
+	}
+	path := filepath.Join(t.TempDir(), phonical.ConfigName)
+	if err := saved.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := phonical.LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Accepting every suggestion changes nothing, including the settings
+	// setup doesn't ask about
+	askSetup(&prompter{in: bufio.NewReader(strings.NewReader(strings.Repeat("\n", 10)))}, cfg)
+	if !reflect.DeepEqual(cfg, saved) {
+		t.Errorf("setup changed the settings to %+v, want %+v", cfg, saved)
+	}
+
+	// Answers replace only what they cover
+	answers := "\nletters\nnone\n90\nn\n\n"
+	if len(phonical.Languages()) > 1 {
+		answers = "\n" + answers
+	}
+	askSetup(&prompter{in: bufio.NewReader(strings.NewReader(answers))}, cfg)
+	want := *saved
+	want.Mode, want.Curriculum, want.Level, want.KeyClick = "letters", "", 0, false
+	ninety := 90
+	want.Volume = &ninety
+	if !reflect.DeepEqual(cfg, &want) {
+		t.Errorf("answered settings %+v, want %+v", cfg, &want)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"phonical"
+)
+
+// sinkConfigs returns the feedback channels to start, in order: those in
+// the settings file, with the command-line options filling in or adding
+// to them, and the stats and the sound first and last unless the file
+// places them.
+func (f *engineFlags) sinkConfigs() []phonical.SinkConfig {
+	sinks := append([]phonical.SinkConfig(nil), f.sinks...)
+	set := func(sc phonical.SinkConfig) {
+		for i := range sinks {
+			if sinks[i].Type != sc.Type {
+				continue
+			}
+			if sc.Address != "" {
+				sinks[i].Address = sc.Address
+			}
+			if sc.File != "" {
+				sinks[i].File = sc.File
+			}
+			if sc.Token != "" {
+				sinks[i].Token = sc.Token
+			}
+			sinks[i].Disabled = false
+			return
+		}
+		sinks = append(sinks, sc)
+	}
+	if f.captions != "" || f.capFile != "" {
+		set(phonical.SinkConfig{Type: phonical.SinkCaptions, Address: f.captions, File: f.capFile})
+	}
+	if f.companion != "" {
+		set(phonical.SinkConfig{Type: phonical.SinkCompanion, Address: f.companion, Token: f.compToken})
+	}
+	if f.openrgb != "" {
+		set(phonical.SinkConfig{Type: phonical.SinkOpenRGB, Address: f.openrgb})
+	}
+	if f.overlay != "" {
+		set(phonical.SinkConfig{Type: phonical.SinkOverlay, Address: f.overlay})
+	}
+	placed := func(typ string) bool {
+		for _, sc := range sinks {
+			if sc.Type == typ {
+				return true
+			}
+		}
+		return false
+	}
+	if !placed(phonical.SinkStats) {
+		sinks = append([]phonical.SinkConfig{{Type: phonical.SinkStats}}, sinks...)
+	}
+	if !placed(phonical.SinkAudio) {
+		sinks = append(sinks, phonical.SinkConfig{Type: phonical.SinkAudio})
+	}
+	return sinks
+}
+
+// startSinks starts the feedback channels that are on, returning the
+// engine option that sets them and the companion server, if there is one,
+// since it is also where keys come from.
+func startSinks(configs []phonical.SinkConfig) (phonical.Option, *phonical.Companion, error) {
+	var sinks []phonical.EventSink
+	var companion *phonical.Companion
+	for _, sc := range configs {
+		if sc.Disabled {
+			continue
+		}
+		var sink phonical.EventSink
+		switch sc.Type {
+		case phonical.SinkAudio:
+			sink = phonical.AudioOutput()
+		case phonical.SinkStats:
+			sink = phonical.StatsOutput()
+		case phonical.SinkCaptions:
+			sink = startCaptions(sc.Address, sc.File)
+		case phonical.SinkCompanion:
+			c, err := startCompanion(sc.Address, sc.Token)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to start companion server: %w", err)
+			}
+			companion, sink = c, c
+		case phonical.SinkOpenRGB:
+			lights, err := startKeyLights(sc.Address)
+			if err != nil {
+				log.Printf("Warning: %v. Carrying on without key lights.", err)
+				continue
+			}
+			sink = lights
+		case phonical.SinkOverlay:
+			sink = startOverlay(sc)
+		default:
+			return nil, nil, fmt.Errorf("unknown sink type %q", sc.Type)
+		}
+		sinks = append(sinks, sink)
+	}
+	return phonical.WithSinks(sinks...), companion, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"phonical"
+)
+
+// soundsCommands are the subcommands of `phonical sounds`.
+var soundsCommands = map[string]func(args []string) error{
+	"validate": runSoundsValidate,
+	"missing":  runSoundsMissing,
+}
+
+func runSounds(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: phonical sounds validate|missing [--pack DIR]")
+	}
+	cmd, ok := soundsCommands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown sounds command %q", args[0])
+	}
+	return cmd(args[1:])
+}
+
+// runSoundsValidate decodes every sound in a pack and lists the bad ones.
+func runSoundsValidate(args []string) error {
+	fs := flag.NewFlagSet("sounds validate", flag.ExitOnError)
+	dir := fs.String("pack", "", "pack directory to check (default the built-in sounds)")
+	fs.Parse(args)
+
+	pack := phonical.DefaultPack()
+	if *dir != "" {
+		var err error
+		if pack, err = phonical.LoadPack(*dir); err != nil {
+			return err
+		}
+	}
+
+	problems := pack.Validate()
+	for _, p := range problems {
+		fmt.Printf("%s (%s): %v\n", p.Entry, p.File, p.Err)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d bad sounds in %s", len(problems), pack.Name)
+	}
+	fmt.Printf("All sounds in %s are OK\n", pack.Name)
+	return nil
+}
+
+// runSoundsMissing lists the characters typed with --unknown log that the
+// pack had no sound for, suggesting a line of pack.toml for each.
+func runSoundsMissing(args []string) error {
+	fs := flag.NewFlagSet("sounds missing", flag.ExitOnError)
+	var flags engineFlags
+	fs.StringVar(&flags.statsPath, "stats", "", "stats file to read")
+	fs.StringVar(&flags.profile, "profile", phonical.DefaultProfile, "profile to show")
+	dir := fs.String("pack", "", "pack to suggest sounds for (default the built-in sounds)")
+	if err := flags.parse(fs, args); err != nil {
+		return err
+	}
+
+	pack := phonical.DefaultPack()
+	if *dir != "" {
+		var err error
+		if pack, err = openPack(*dir); err != nil {
+			return err
+		}
+	}
+	store, err := flags.openStats()
+	if err != nil {
+		return err
+	}
+	stats := store.Snapshot()
+	missing := stats.MissingChars()
+	if len(missing) == 0 {
+		fmt.Println("No characters without a sound have been typed. Run Phonical with")
+		fmt.Println("--unknown log to keep track of them.")
+		return nil
+	}
+	fmt.Printf("Typed without a sound in %s, with how often:\n", pack.Name)
+	for _, m := range missing {
+		fmt.Printf("  %-4s %-20s %d\n", string(m.Char), phonical.SymbolName(m.Char), m.Count)
+	}
+	fmt.Printf("\nTo give them sounds, add these to [letters] in %s and record the files:\n", phonical.ManifestName)
+	for _, m := range missing {
+		fmt.Printf("  %q = %q\n", string(m.Char), pack.SuggestSound(m.Char))
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"phonical"
+)
+
+// instanceStatus is what `phonical status` reports.
+type instanceStatus struct {
+	Running bool   `json:"running"`
+	PID     int    `json:"pid,omitempty"`
+	Profile string `json:"profile,omitempty"`
+	// UpdateAvailable is a newer release found by the last update check.
+	UpdateAvailable string `json:"update_available,omitempty"`
+	*phonical.Status
+}
+
+// controlStatus answers the status control command.
+func controlStatus(inst *instance, _ []string) (any, error) {
+	st := inst.engine.Status()
+	profile := inst.profile
+	if p := inst.engine.Profile(); p != "" {
+		profile = p
+	}
+	return &instanceStatus{Running: true, PID: os.Getpid(), Profile: profile, Status: &st}, nil
+}
+
+// runStatus reports on the running instance. It exits with status 1 when
+// phonical is not running, so scripts can test for it.
+func runStatus(args []string) error {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "")
+	fs.Parse(args)
+
+	st := instanceStatus{}
+	if reply, err := sendControl("status"); err == nil {
+		if err := json.Unmarshal(reply.Data, &st); err != nil {
+			return err
+		}
+	}
+
+	st.UpdateAvailable = availableUpdate()
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(&st); err != nil {
+			return err
+		}
+	} else if st.Running {
+		fmt.Printf("Running (pid %d) since %s\n", st.PID, st.Started.Format("15:04"))
+		fmt.Printf("  Profile:     %s\n", st.Profile)
+		fmt.Printf("  Pack:        %s\n", st.Pack)
+		fmt.Printf("  Mode:        %s\n", st.Mode)
+		fmt.Printf("  Voice:       %s\n", st.Voice)
+		fmt.Printf("  Volume:      %.0f%%\n", st.Volume*100)
+		fmt.Printf("  Key click:   %g\n", st.ClickVolume)
+		fmt.Printf("  Muted:       %t (do not disturb %t, on a call %t)\n", st.Muted, st.DoNotDisturb, st.OnCall)
+		if st.Guest {
+			fmt.Println("  Guest:       on, nothing is being recorded")
+		}
+		fmt.Printf("  Queue:       %d waiting\n", st.QueueDepth)
+		fmt.Printf("  Cache:       %d sounds, %.1f MB\n", st.CachedSounds, float64(st.CacheBytes)/(1<<20))
+		fmt.Printf("  Keys:        %d handled\n", st.KeysHandled)
+		fmt.Printf("  Speed:       %.0f letters a minute\n", st.TypingSpeed)
+		fmt.Printf("  Permission:  %s\n", st.Permission)
+		if len(st.BadSounds) > 0 {
+			fmt.Printf("  Bad sounds:  %s (run \"phonical sounds validate\")\n", strings.Join(st.BadSounds, ", "))
+		}
+	} else {
+		fmt.Println("Not running")
+	}
+	if st.UpdateAvailable != "" && !*asJSON {
+		fmt.Printf("\nPhonical %s is available; run \"phonical update\" to install it.\n", st.UpdateAvailable)
+	}
+
+	if !st.Running {
+		os.Exit(1)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"phonical/phonicaltest"
+)
+
+func TestStatusOverControl(t *testing.T) {
+	controlSocket(t)
+	h := phonicaltest.New(t)
+	l, err := listenControl()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go serveControl(l, &instance{engine: h.Engine, profile: "sam"})
+	h.Type("ab")
+
+	reply, err := sendControl("status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st instanceStatus
+	if err := json.Unmarshal(reply.Data, &st); err != nil {
+		t.Fatal(err)
+	}
+	if !st.Running || st.PID != os.Getpid() || st.Profile != "sam" || st.Status == nil || st.KeysHandled != 2 {
+		t.Errorf("status %s, want running as sam with 2 keys handled", reply.Data)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"phonical"
+)
+
+// runTargets shows or sets a profile's speech-therapy targets, played
+// slower and clearer with --targets.
+func runTargets(args []string) error {
+	fs := flag.NewFlagSet("targets", flag.ExitOnError)
+	var flags engineFlags
+	fs.StringVar(&flags.statsPath, "stats", "", "stats file the targets are kept beside")
+	fs.StringVar(&flags.profile, "profile", phonical.DefaultProfile, "profile to show or set")
+	clear := fs.Bool("clear", false, "remove all targets")
+	if err := flags.parse(fs, args); err != nil {
+		return err
+	}
+
+	statsPath, err := flags.statsFile()
+	if err != nil {
+		return err
+	}
+	path := phonical.TargetsPath(statsPath)
+	if *clear || fs.NArg() > 0 {
+		var units []string
+		for _, arg := range fs.Args() {
+			for _, unit := range strings.Split(arg, ",") {
+				if unit = strings.ToLower(strings.TrimSpace(unit)); unit != "" {
+					units = append(units, unit)
+				}
+			}
+		}
+		if err := phonical.SaveTargets(path, units); err != nil {
+			return err
+		}
+		if len(units) == 0 {
+			fmt.Println("Targets cleared")
+		} else {
+			fmt.Printf("Targets: %s\n", strings.Join(units, " "))
+		}
+		return nil
+	}
+
+	units, err := phonical.LoadTargets(path)
+	if err != nil {
+		return err
+	}
+	if len(units) == 0 {
+		fmt.Printf("No targets set; add some with: phonical targets --profile %s s,sh\n", flags.profile)
+		return nil
+	}
+	store, err := phonical.OpenStats(statsPath)
+	if err != nil {
+		return err
+	}
+	stats := store.Snapshot()
+	fmt.Printf("Targets in %s, with how often each has been heard:\n", path)
+	for _, ex := range stats.TargetExposures(units) {
+		fmt.Printf("  %-4s %d\n", ex.Unit, ex.Count)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"phonical"
+)
+
+// runTestAudio plays a tone on each channel and reports the audio setup,
+// to tell sound problems apart from keyboard hook problems.
+func runTestAudio(args []string) error {
+	fs := flag.NewFlagSet("test-audio", flag.ExitOnError)
+	var flags engineFlags
+	flags.register(fs)
+	if err := flags.parse(fs, args); err != nil {
+		return err
+	}
+
+	opts, _, recording, err := flags.options()
+	if err != nil {
+		return err
+	}
+	if recording != nil {
+		defer recording.Close()
+	}
+	engine, err := phonical.New(opts...)
+	if err != nil {
+		return err
+	}
+	if err := engine.Start(); err != nil {
+		fmt.Println("The sound output could not start, so the problem is with audio, not the keyboard hook.")
+		return fmt.Errorf("failed to initialize audio: %w", err)
+	}
+	stopOnSignal(engine)
+	defer engine.Stop()
+
+	info := engine.AudioInfo()
+	fmt.Printf("Output:      %s\n", info.Output)
+	fmt.Printf("Sample rate: %d Hz, %d channels\n", info.SampleRate, info.Channels)
+	if info.Buffer > 0 {
+		fmt.Printf("Buffer:      %s\n", info.Buffer)
+	}
+	if info.Output == "speaker" {
+		if device, err := phonical.OutputDevice(); err == nil {
+			fmt.Printf("Device:      %s\n", device)
+		} else {
+			fmt.Printf("Device:      unknown (%v)\n", err)
+		}
+	}
+	fmt.Println()
+
+	err = engine.RunActivity(phonical.TestAudio(func(channel string) {
+		fmt.Printf("Playing a tone on %s...\n", map[string]string{
+			phonical.ChannelLeft:  "the left channel",
+			phonical.ChannelRight: "the right channel",
+			phonical.ChannelBoth:  "both channels",
+		}[channel])
+	}))
+	if errors.Is(err, phonical.ErrStopped) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println("\nIf you heard all three tones, sound works; if letters still don't play")
+	fmt.Println("while typing, check keyboard access with \"phonical setup\".")
+	return nil
+}
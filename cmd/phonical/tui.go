@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// tuiRefresh is how often the dashboard asks the running instance for its
+// status.
+const tuiRefresh = 200 * time.Millisecond
+
+// meterWidth is the number of cells in the dashboard's bar graphs.
+const meterWidth = 30
+
+var (
+	tuiModes  = []string{"letters", "blend", "onset-rime", "syllables", "boxes"}
+	tuiVoices = []string{"normal", "chipmunk", "giant", "robot"}
+)
+
+// rawTerminal switches the terminal to unbuffered, unechoed input so single
+// key presses arrive at once, returning a function that restores it. Where
+// stty is unavailable keys arrive after Enter instead.
+func rawTerminal() func() {
+	if runtime.GOOS == "windows" || !isTerminal(os.Stdin) {
+		return func() {}
+	}
+	stty := func(args ...string) ([]byte, error) {
+		cmd := exec.Command("stty", args...)
+		cmd.Stdin = os.Stdin
+		return cmd.Output()
+	}
+	saved, err := stty("-g")
+	if err != nil {
+		return func() {}
+	}
+	if _, err := stty("-icanon", "-echo", "min", "1"); err != nil {
+		return func() {}
+	}
+	return func() { stty(strings.TrimSpace(string(saved))) }
+}
+
+// nextName returns the entry after current in names, wrapping around.
+func nextName(names []string, current string) string {
+	for i, name := range names {
+		if name == current {
+			return names[(i+1)%len(names)]
+		}
+	}
+	return names[0]
+}
+
+// meterBar draws fraction, from 0 to 1, as a meterWidth-cell bar graph.
+func meterBar(fraction float64) string {
+	n := int(fraction*meterWidth + 0.5)
+	n = max(0, min(n, meterWidth))
+	return strings.Repeat("█", n) + strings.Repeat("░", meterWidth-n)
+}
+
+// drawDashboard writes one frame of the dashboard to w.
+func drawDashboard(w io.Writer, st *instanceStatus, message string) {
+	// Home the cursor and clear, so each frame replaces the last
+	fmt.Fprint(w, "\x1b[H\x1b[2J")
+	fmt.Fprintf(w, "Phonical  (pid %d, since %s)\n\n", st.PID, st.Started.Format("15:04"))
+	profile := st.Profile
+	if st.Guest {
+		profile += " (guest mode: not recording)"
+	}
+	fmt.Fprintf(w, "  Profile:  %s\n", profile)
+	fmt.Fprintf(w, "  Pack:     %s\n", st.Pack)
+	fmt.Fprintf(w, "  Mode:     %s\n", st.Mode)
+	fmt.Fprintf(w, "  Voice:    %s\n\n", st.Voice)
+	fmt.Fprintf(w, "  Typing:   %s_\n", st.Word)
+	fmt.Fprintf(w, "  Last:     %s\n", st.LastWord)
+	fmt.Fprintf(w, "  Keys:     %d (%.0f letters a minute)\n\n", st.KeysHandled, st.TypingSpeed)
+	fmt.Fprintf(w, "  Queue:    %s %d waiting\n", meterBar(float64(st.QueueDepth)/meterWidth), st.QueueDepth)
+	fmt.Fprintf(w, "  Level:    %s\n", meterBar(st.Level))
+	fmt.Fprintf(w, "  Volume:   %s %.0f%%\n", meterBar(st.Volume), st.Volume*100)
+	muted := "no"
+	switch {
+	case st.DoNotDisturb:
+		muted = "yes (do not disturb)"
+	case st.OnCall:
+		muted = "yes (on a call)"
+	case st.Muted:
+		muted = "yes"
+	}
+	fmt.Fprintf(w, "  Muted:    %s\n\n", muted)
+	fmt.Fprintln(w, "  [m] mode  [v] voice  [q] quit")
+	if message != "" {
+		fmt.Fprintf(w, "\n  %s\n", message)
+	}
+}
+
+// dashboardKey carries out the control for key, returning a message to
+// show or an empty string.
+func dashboardKey(key byte, st *instanceStatus) string {
+	var err error
+	switch key {
+	case 'm':
+		_, err = sendControl("set", "mode", nextName(tuiModes, st.Mode))
+	case 'v':
+		_, err = sendControl("set", "voice", nextName(tuiVoices, st.Voice))
+	default:
+		return ""
+	}
+	if err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// runTUI shows a live dashboard of the running instance, with single-key
+// controls for its mode and voice. It redraws with plain ANSI escapes
+// rather than bubbletea: one screen of text refreshed five times a second
+// doesn't need a framework, and bubbletea would bring in a dozen modules,
+// more than the rest of Phonical's dependencies together, for every build.
+func runTUI(args []string) error {
+	fs := flag.NewFlagSet("tui", flag.ExitOnError)
+	fs.Parse(args)
+
+	if _, err := sendControl("status"); err != nil {
+		return err
+	}
+
+	restore := rawTerminal()
+	defer restore()
+	// Hide the cursor while drawing and show it again on the way out
+	fmt.Print("\x1b[?25l")
+	defer fmt.Print("\x1b[?25h\n")
+
+	keys := make(chan byte)
+	go func() {
+		in := bufio.NewReader(os.Stdin)
+		for {
+			b, err := in.ReadByte()
+			if err != nil {
+				close(keys)
+				return
+			}
+			keys <- b
+		}
+	}()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(tuiRefresh)
+	defer ticker.Stop()
+	var st instanceStatus
+	message := ""
+	for {
+		reply, err := sendControl("status")
+		if err != nil {
+			return err
+		}
+		st = instanceStatus{}
+		if err := json.Unmarshal(reply.Data, &st); err != nil {
+			return err
+		}
+		drawDashboard(os.Stdout, &st, message)
+
+		select {
+		case key, ok := <-keys:
+			if !ok || key == 'q' || key == 0x1b {
+				return nil
+			}
+			if key != '\n' {
+				message = dashboardKey(key, &st)
+			}
+		case <-interrupt:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"phonical/phonicaltest"
+)
+
+func TestDashboard(t *testing.T) {
+	controlSocket(t)
+	h := phonicaltest.New(t)
+	l, err := listenControl()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go serveControl(l, &instance{engine: h.Engine, profile: "sam"})
+	h.Type("cat{space}do")
+
+	reply, err := controlStatus(&instance{engine: h.Engine, profile: "sam"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := reply.(*instanceStatus)
+	var buf bytes.Buffer
+	drawDashboard(&buf, st, "")
+	for _, want := range []string{
+		"  Profile:  sam\n",
+		"  Mode:     letters\n",
+		"  Typing:   do_\n",
+		"  Last:     cat\n",
+		"  Keys:     6 ",
+		"  Muted:    no\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("dashboard missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	// m moves the running instance on to the next mode
+	if msg := dashboardKey('m', st); msg != "" {
+		t.Fatal(msg)
+	}
+	if mode := h.Engine.Status().Mode; mode != "blend" {
+		t.Errorf("mode %s after m, want blend", mode)
+	}
+}
+
+func TestMeterBar(t *testing.T) {
+	for _, tc := range []struct {
+		fraction float64
+		full     int
+	}{{0, 0}, {0.5, 15}, {1, 30}, {2, 30}, {-1, 0}} {
+		bar := meterBar(tc.fraction)
+		if got := strings.Count(bar, "█"); got != tc.full || strings.Count(bar, "░") != meterWidth-tc.full {
+			t.Errorf("meterBar(%g) = %s, want %d of %d cells full", tc.fraction, bar, tc.full, meterWidth)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"phonical"
+)
+
+// releasesURL is the GitHub API endpoint for the newest release.
+const releasesURL = "https://api.github.com/repos/carldaws/phonical/releases/latest"
+
+// checksumsAsset is the release file listing each binary's SHA-256.
+const checksumsAsset = "checksums.txt"
+
+// updateCheckInterval is how often --check-updates looks for a release.
+const updateCheckInterval = 24 * time.Hour
+
+type release struct {
+	TagName string `json:"tag_name"`
+	Assets  []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// updateNotice records the last update check, so status can mention a new
+// version without going online.
+type updateNotice struct {
+	Checked time.Time `json:"checked"`
+	Latest  string    `json:"latest"`
+}
+
+var httpClient = &http.Client{Timeout: 2 * time.Minute}
+
+// latestRelease asks GitHub for the newest release.
+func latestRelease() (*release, error) {
+	resp, err := httpClient.Get(releasesURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("checking for updates: %s", resp.Status)
+	}
+	var rel release
+	if err := json.NewDecoder(resp.Body).Decode(&rel); err != nil {
+		return nil, err
+	}
+	return &rel, nil
+}
+
+// asset returns the download URL of the named release file.
+func (r *release) asset(name string) (string, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a.URL, true
+		}
+	}
+	return "", false
+}
+
+// newerVersion reports whether latest is a later version than current.
+// Development builds are never updated automatically.
+func newerVersion(latest, current string) bool {
+	l, ok1 := parseVersion(latest)
+	c, ok2 := parseVersion(current)
+	if !ok1 || !ok2 {
+		return false
+	}
+	for i := range l {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	return false
+}
+
+// parseVersion splits "v1.2.3" into its numbers.
+func parseVersion(v string) ([3]int, bool) {
+	var out [3]int
+	v, _, _ = strings.Cut(strings.TrimPrefix(v, "v"), "-")
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return out, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return out, false
+		}
+		out[i] = n
+	}
+	return out, true
+}
+
+// binaryAsset is the name of this platform's binary in a release.
+func binaryAsset() string {
+	name := fmt.Sprintf("phonical_%s_%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+func download(url string) ([]byte, error) {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// verifyChecksum checks data against its entry in a checksums file of
+// "<sha256>  <name>" lines.
+func verifyChecksum(data, checksums []byte, name string) error {
+	sum := sha256.Sum256(data)
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == name {
+			if fields[0] != hex.EncodeToString(sum[:]) {
+				return fmt.Errorf("%s does not match its checksum; not updating", name)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not listed in %s", name, checksumsAsset)
+}
+
+// replaceExecutable swaps the running binary for data. The new file is
+// written next to the old one so the final rename can't cross devices.
+func replaceExecutable(data []byte) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return err
+	}
+	tmp := exe + ".new"
+	if err := os.WriteFile(tmp, data, 0o755); err != nil {
+		return err
+	}
+	if runtime.GOOS == "windows" {
+		// A running executable can be renamed but not overwritten
+		old := exe + ".old"
+		os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+	}
+	return os.Rename(tmp, exe)
+}
+
+func noticePath() (string, error) {
+	dir, err := phonical.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "update.json"), nil
+}
+
+func saveNotice(n updateNotice) error {
+	path, err := noticePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(&n)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func loadNotice() updateNotice {
+	var n updateNotice
+	if path, err := noticePath(); err == nil {
+		if data, err := os.ReadFile(path); err == nil {
+			json.Unmarshal(data, &n)
+		}
+	}
+	return n
+}
+
+// availableUpdate returns the newer version found by the last check, if
+// any.
+func availableUpdate() string {
+	if n := loadNotice(); newerVersion(n.Latest, version) {
+		return n.Latest
+	}
+	return ""
+}
+
+// checkForUpdates looks for a new release if the last check is older than
+// updateCheckInterval, recording the result for status.
+func checkForUpdates() {
+	if time.Since(loadNotice().Checked) < updateCheckInterval {
+		return
+	}
+	rel, err := latestRelease()
+	if err != nil {
+		return
+	}
+	saveNotice(updateNotice{Checked: time.Now(), Latest: rel.TagName})
+	if newerVersion(rel.TagName, version) {
+		fmt.Printf("Phonical %s is available; run \"phonical update\" to install it.\n", rel.TagName)
+	}
+}
+
+// runUpdate replaces the binary with the newest release after checking
+// its checksum.
+func runUpdate(args []string) error {
+	fs := flag.NewFlagSet("update", flag.ExitOnError)
+	checkOnly := fs.Bool("check", false, "only report whether an update is available")
+	fs.Parse(args)
+
+	rel, err := latestRelease()
+	if err != nil {
+		return err
+	}
+	saveNotice(updateNotice{Checked: time.Now(), Latest: rel.TagName})
+	if !newerVersion(rel.TagName, version) {
+		fmt.Printf("Phonical %s is up to date.\n", version)
+		return nil
+	}
+	if *checkOnly {
+		fmt.Printf("Phonical %s is available (you have %s).\n", rel.TagName, version)
+		return nil
+	}
+
+	name := binaryAsset()
+	binURL, ok := rel.asset(name)
+	if !ok {
+		return fmt.Errorf("release %s has no build for %s/%s", rel.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+	sumURL, ok := rel.asset(checksumsAsset)
+	if !ok {
+		return errors.New("release has no checksums; not updating")
+	}
+
+	fmt.Printf("Downloading Phonical %s...\n", rel.TagName)
+	data, err := download(binURL)
+	if err != nil {
+		return err
+	}
+	checksums, err := download(sumURL)
+	if err != nil {
+		return err
+	}
+	if err := verifyChecksum(data, checksums, name); err != nil {
+		return err
+	}
+	if err := replaceExecutable(data); err != nil {
+		return fmt.Errorf("installing update: %w", err)
+	}
+	fmt.Printf("Updated to %s. Restart Phonical to use it.\n", rel.TagName)
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestNewerVersion(t *testing.T) {
+	for _, tc := range []struct {
+		latest, current string
+		want            bool
+	}{
+		{"v1.2.4", "v1.2.3", true},
+		{"v1.10.0", "v1.9.9", true},
+		{"v2.0.0", "1.9.0", true},
+		{"v1.2.3", "v1.2.3", false},
+		{"v1.2.2", "v1.2.3", false},
+		{"v1.3.0", "v1.2.3-4-gabcdef", true},
+		// Development builds are left alone
+		{"v9.0.0", "dev", false},
+		{"nightly", "v1.0.0", false},
+	} {
+		if got := newerVersion(tc.latest, tc.current); got != tc.want {
+			t.Errorf("newerVersion(%q, %q) = %v, want %v", tc.latest, tc.current, got, tc.want)
+		}
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("new phonical")
+	sum := sha256.Sum256(data)
+	checksums := []byte(hex.EncodeToString(sum[:]) + "  phonical_linux_amd64\n" +
+		strings.Repeat("0", 64) + "  phonical_darwin_arm64\n")
+
+	if err := verifyChecksum(data, checksums, "phonical_linux_amd64"); err != nil {
+		t.Errorf("matching binary: %v", err)
+	}
+	if err := verifyChecksum(data, checksums, "phonical_darwin_arm64"); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("tampered binary: %v", err)
+	}
+	if err := verifyChecksum(data, checksums, "phonical_windows_amd64.exe"); err == nil || !strings.Contains(err.Error(), "not listed") {
+		t.Errorf("unlisted binary: %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"phonical"
+)
+
+// runVerify checks the built-in sounds, and the configured or given pack,
+// against their recorded checksums.
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	dir := fs.String("pack", "", "pack directory to check as well as the built-in sounds")
+	write := fs.Bool("write", false, "record checksums for the --pack directory instead of checking them")
+	if err := parseWithConfig(fs, args); err != nil {
+		return err
+	}
+
+	if *write {
+		if *dir == "" {
+			return errors.New("--write needs --pack DIR")
+		}
+		if err := phonical.WriteChecksums(*dir); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %s in %s\n", phonical.ChecksumsName, *dir)
+		return nil
+	}
+
+	packs := []*phonical.Pack{}
+	if phonical.HasBuiltinSounds() {
+		packs = append(packs, phonical.DefaultPack())
+	}
+	if *dir != "" {
+		pack, err := phonical.LoadPack(*dir)
+		if err != nil {
+			return err
+		}
+		packs = append(packs, pack)
+	}
+
+	bad := 0
+	for _, pack := range packs {
+		problems, err := pack.Verify()
+		if errors.Is(err, phonical.ErrNoChecksums) {
+			fmt.Printf("%s: no checksums recorded; run \"phonical verify --write --pack DIR\"\n", pack.Name)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", pack.Name, err)
+		}
+		for _, p := range problems {
+			fmt.Printf("%s: %s: %s\n", pack.Name, p.File, p.Problem)
+		}
+		if len(problems) == 0 {
+			fmt.Printf("%s: OK\n", pack.Name)
+		}
+		bad += len(problems)
+	}
+	if bad > 0 {
+		return fmt.Errorf("%d damaged files; reinstall Phonical or the pack", bad)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+
+	"phonical"
+)
+
+// Build metadata, set by packagers with
+//
+//	go build -ldflags "-X main.version=v1.2.3 -X main.commit=abc123 -X main.date=2024-01-02" ./cmd/phonical
+var (
+	version = "dev"
+	commit  = ""
+	date    = ""
+)
+
+// buildInfo describes this binary for `phonical version`.
+type buildInfo struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit,omitempty"`
+	Date    string `json:"date,omitempty"`
+	Go      string `json:"go"`
+	OS      string `json:"os"`
+	Arch    string `json:"arch"`
+	// Sounds is "builtin", or "lite" when the binary has no embedded sounds.
+	Sounds string `json:"sounds"`
+}
+
+func currentBuild() buildInfo {
+	b := buildInfo{
+		Version: version,
+		Commit:  commit,
+		Date:    date,
+		Go:      runtime.Version(),
+		OS:      runtime.GOOS,
+		Arch:    runtime.GOARCH,
+		Sounds:  "builtin",
+	}
+	if !phonical.HasBuiltinSounds() {
+		b.Sounds = "lite"
+	}
+	// Fall back to the VCS details the go tool records
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			switch {
+			case s.Key == "vcs.revision" && b.Commit == "":
+				b.Commit = s.Value
+			case s.Key == "vcs.time" && b.Date == "":
+				b.Date = s.Value
+			}
+		}
+	}
+	return b
+}
+
+func runVersion(args []string) error {
+	fs := flag.NewFlagSet("version", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "")
+	fs.Parse(args)
+
+	b := currentBuild()
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(&b)
+	}
+	fmt.Printf("phonical %s (%s/%s, %s, %s sounds)\n", b.Version, b.OS, b.Arch, b.Go, b.Sounds)
+	if b.Commit != "" {
+		fmt.Printf("commit %s %s\n", b.Commit, b.Date)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"runtime"
+	"testing"
+
+	"phonical"
+)
+
+func TestCurrentBuild(t *testing.T) {
+	defer func(v, c, d string) { version, commit, date = v, c, d }(version, commit, date)
+	version, commit, date = "v1.2.3", "abc123", "2024-01-02"
+
+	// Packagers' values win over what the go tool recorded
+	b := currentBuild()
+	want := buildInfo{
+		Version: "v1.2.3", Commit: "abc123", Date: "2024-01-02",
+		Go: runtime.Version(), OS: runtime.GOOS, Arch: runtime.GOARCH,
+		Sounds: "builtin",
+	}
+	if !phonical.HasBuiltinSounds() {
+		want.Sounds = "lite"
+	}
+	if b != want {
+		t.Errorf("build %+v, want %+v", b, want)
+	}
+}
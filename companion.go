@@ -0,0 +1,221 @@
+package phonical
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"unicode/utf8"
+)
+
+// CompanionPath is where Companion accepts WebSocket connections.
+const CompanionPath = "/companion"
+
+// CompanionVersion is the version of the companion protocol, sent in the
+// hello message so apps can tell what to expect.
+const CompanionVersion = 1
+
+// companionMessage is a message of the companion protocol, in either
+// direction.
+type companionMessage struct {
+	Type string `json:"type"`
+	// Version is set in hello.
+	Version int `json:"version,omitempty"`
+	// Char and Key are set in key: the character typed, or the name of a
+	// key without one.
+	Char string `json:"char,omitempty"`
+	Key  string `json:"key,omitempty"`
+	// Letter and Text are set in play.
+	Letter string `json:"letter,omitempty"`
+	Text   string `json:"text,omitempty"`
+	// Error is set in error.
+	Error string `json:"error,omitempty"`
+}
+
+// Companion lets a touch device, such as a tablet keyboard app, be the
+// child's keyboard while the engine handles the curriculum and sound. It is
+// an http.Handler taking WebSocket connections at CompanionPath, and a
+// KeySource for RunSource delivering the keys they send. Add it with
+// WithSink too to tell the apps what is playing.
+//
+// Apps connect to ws://HOST/companion?token=TOKEN and are greeted with
+// {"type":"hello","version":1}. They send {"type":"key","char":"a"} for a
+// character or {"type":"key","key":"backspace"} for a key named as by
+// KeyNamed, and receive {"type":"play","letter":"a"} or
+// {"type":"play","text":"cat"} as each sound starts. A message the
+// engine can't use is answered with {"type":"error","error":"..."}.
+type Companion struct {
+	token   string
+	keys    chan Key
+	updates chan []byte
+	done    chan struct{}
+	once    sync.Once
+	stop    sync.Once
+
+	mu      sync.Mutex
+	clients map[*wsConn]bool
+}
+
+// NewCompanion returns a companion server that only accepts apps giving
+// token. An empty token accepts any app, for trusted networks.
+func NewCompanion(token string) *Companion {
+	return &Companion{
+		token:   token,
+		keys:    make(chan Key, 64),
+		updates: make(chan []byte, 64),
+		done:    make(chan struct{}),
+		clients: make(map[*wsConn]bool),
+	}
+}
+
+// Close stops sending play messages and taking keys, and closes the
+// connected apps' connections.
+func (c *Companion) Close() error {
+	c.stop.Do(func() { close(c.done) })
+	c.mu.Lock()
+	clients := c.clients
+	c.clients = make(map[*wsConn]bool)
+	c.mu.Unlock()
+	for ws := range clients {
+		ws.Close()
+	}
+	return nil
+}
+
+// NewCompanionToken returns a random token for NewCompanion.
+func NewCompanionToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Keys returns the keys sent by connected apps. It is never closed.
+func (c *Companion) Keys() <-chan Key {
+	return c.keys
+}
+
+// Played tells the connected apps that a sound has started.
+func (c *Companion) Played(ev PlayEvent) {
+	msg := companionMessage{Type: "play", Text: ev.Text}
+	if ev.Letter != 0 {
+		msg.Letter = string(ev.Letter)
+	} else if ev.Text == "" {
+		return
+	}
+	data, _ := json.Marshal(&msg)
+	c.once.Do(func() { go c.publish() })
+	// Drop the message rather than hold up playback for a slow tablet
+	select {
+	case <-c.done:
+	case c.updates <- data:
+	default:
+	}
+}
+
+// publish sends each play message to the connected apps, off the player's
+// goroutine.
+func (c *Companion) publish() {
+	for {
+		var data []byte
+		select {
+		case <-c.done:
+			return
+		case data = <-c.updates:
+		}
+		c.mu.Lock()
+		clients := make([]*wsConn, 0, len(c.clients))
+		for ws := range c.clients {
+			clients = append(clients, ws)
+		}
+		c.mu.Unlock()
+		for _, ws := range clients {
+			if err := ws.WriteText(data); err != nil {
+				ws.Close()
+			}
+		}
+	}
+}
+
+// ServeHTTP accepts an app's connection and feeds its keys to Keys until
+// it disconnects.
+func (c *Companion) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != CompanionPath {
+		http.NotFound(w, r)
+		return
+	}
+	token := r.URL.Query().Get("token")
+	if c.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(c.token)) != 1 {
+		http.Error(w, "wrong or missing token", http.StatusUnauthorized)
+		return
+	}
+	ws, err := upgradeWebSocket(w, r)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+	c.mu.Lock()
+	select {
+	case <-c.done:
+		c.mu.Unlock()
+		return
+	default:
+		c.clients[ws] = true
+	}
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.clients, ws)
+		c.mu.Unlock()
+	}()
+
+	reply := func(msg companionMessage) error {
+		data, _ := json.Marshal(&msg)
+		return ws.WriteText(data)
+	}
+	if reply(companionMessage{Type: "hello", Version: CompanionVersion}) != nil {
+		return
+	}
+	for {
+		data, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg companionMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			reply(companionMessage{Type: "error", Error: "not JSON: " + err.Error()})
+			continue
+		}
+		if msg.Type != "key" {
+			reply(companionMessage{Type: "error", Error: "unknown message type " + msg.Type})
+			continue
+		}
+		key, ok := companionKey(msg)
+		if !ok {
+			reply(companionMessage{Type: "error", Error: "key needs one character in char or a key name in key"})
+			continue
+		}
+		// The request's context isn't cancelled once the connection is
+		// taken over, so only Close frees a handler nobody is reading from
+		select {
+		case c.keys <- key:
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// companionKey converts a key message to the press it stands for.
+func companionKey(msg companionMessage) (Key, bool) {
+	if msg.Key != "" {
+		return KeyNamed(msg.Key)
+	}
+	r, size := utf8.DecodeRuneInString(msg.Char)
+	if r == utf8.RuneError || size != len(msg.Char) {
+		return Key{}, false
+	}
+	return charKey(r), true
+}
@@ -0,0 +1,69 @@
+package phonical_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestCompanionKeys(t *testing.T) {
+	companion := phonical.NewCompanion("secret")
+	h := phonicaltest.New(t, phonical.WithSink(companion))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go h.Engine.RunSource(ctx, companion)
+	srv := httptest.NewServer(companion)
+	defer srv.Close()
+
+	if resp, err := http.Get(srv.URL + phonical.CompanionPath + "?token=wrong"); err != nil {
+		t.Fatal(err)
+	} else if resp.Body.Close(); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("wrong token: status %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+
+	app := dialWebSocket(t, srv, phonical.CompanionPath+"?token=secret")
+	if msg := app.read(t); msg != `{"type":"hello","version":1}` {
+		t.Errorf("greeted with %s", msg)
+	}
+	app.send(t, `{"type":"key","char":"a"}`)
+	h.Expect("a.wav")
+	if msg := app.read(t); msg != `{"type":"play","letter":"a"}` {
+		t.Errorf("told %s, want a playing", msg)
+	}
+	app.send(t, `{"type":"key","char":"ab"}`)
+	if msg := app.read(t); msg != `{"type":"error","error":"key needs one character in char or a key name in key"}` {
+		t.Errorf("answered %s, want an error", msg)
+	}
+}
+
+func TestCompanionCloseFreesHandlers(t *testing.T) {
+	companion := phonical.NewCompanion("")
+	returned := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		companion.ServeHTTP(w, r)
+		close(returned)
+	}))
+	defer srv.Close()
+
+	// Nobody reads the keys, so the handler ends up waiting to pass one on
+	app := dialWebSocket(t, srv, phonical.CompanionPath)
+	app.read(t)
+	for i := 0; i < 100; i++ {
+		app.send(t, `{"type":"key","char":"a"}`)
+	}
+	if err := companion.Close(); err != nil {
+		t.Fatal(err)
+	}
+	<-returned
+	if !app.closed() {
+		t.Error("app still connected after Close")
+	}
+	// Sounds after closing go nowhere rather than blocking
+	for i := 0; i < 100; i++ {
+		companion.Played(phonical.PlayEvent{Letter: 'b'})
+	}
+}
@@ -0,0 +1,73 @@
+package phonical
+
+// deadKeys maps the characters hooks report for dead keys, both spacing
+// and combining forms, to the accent they add.
+var deadKeys = map[rune]rune{
+	'´': '´', '\u0301': '´',
+	'`': '`', '\u0300': '`',
+	'^': '^', '\u0302': '^',
+	'¨': '¨', '\u0308': '¨',
+	'~': '~', '\u0303': '~',
+	'¸': '¸', '\u0327': '¸',
+}
+
+// compositions lists the accented letters each accent forms.
+var compositions = map[rune]map[rune]rune{
+	'´': {'a': 'á', 'e': 'é', 'i': 'í', 'o': 'ó', 'u': 'ú', 'y': 'ý'},
+	'`': {'a': 'à', 'e': 'è', 'i': 'ì', 'o': 'ò', 'u': 'ù'},
+	'^': {'a': 'â', 'e': 'ê', 'i': 'î', 'o': 'ô', 'u': 'û'},
+	'¨': {'a': 'ä', 'e': 'ë', 'i': 'ï', 'o': 'ö', 'u': 'ü', 'y': 'ÿ'},
+	'~': {'a': 'ã', 'n': 'ñ', 'o': 'õ'},
+	'¸': {'c': 'ç'},
+}
+
+// baseLetters maps each accented letter back to its plain letter.
+var baseLetters = map[rune]rune{}
+
+func init() {
+	for _, letters := range compositions {
+		for base, composed := range letters {
+			baseLetters[composed] = base
+		}
+	}
+}
+
+// WithDeadKeys waits after a dead key (´ ` ^ ¨ ~ ¸) for the letter it
+// accents, so ´ then e plays é rather than reacting to the accent on its
+// own. Only enable it for layouts with dead keys: on others ^ followed by
+// a would be read as â.
+func WithDeadKeys(enabled bool) Option {
+	return func(e *Engine) {
+		e.deadKeys = enabled
+	}
+}
+
+// composer is the dead-key state machine.
+type composer struct {
+	pending rune
+}
+
+// feed takes the next typed character and returns the character to act on,
+// or false while an accent is waiting for its letter.
+func (c *composer) feed(r rune) (rune, bool) {
+	if c.pending == 0 {
+		if accent, ok := deadKeys[r]; ok {
+			c.pending = accent
+			return 0, false
+		}
+		return r, true
+	}
+
+	accent := c.pending
+	c.pending = 0
+	if composed, ok := compositions[accent][r]; ok {
+		return composed, true
+	}
+	if r == ' ' {
+		// Accent then space types the accent itself
+		return accent, true
+	}
+	// Either the system already composed the letter or the pair doesn't
+	// combine; go with what was typed
+	return r, true
+}
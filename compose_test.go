@@ -0,0 +1,20 @@
+package phonical_test
+
+import (
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestDeadKeys(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithDeadKeys(true))
+	// Each accent waits for its letter, which plays as the plain letter
+	// when the pack has no recording of the accented one; an accent on a
+	// letter it can't combine with leaves the letter alone
+	h.Type("caf´e^x")
+	h.Expect("c.wav", "a.wav", "f.wav", "e.wav", "x.wav")
+	if word := h.Engine.Status().Word; word != "caféx" {
+		t.Errorf("word %q, want caféx", word)
+	}
+}
@@ -0,0 +1,129 @@
+package phonical
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ConfigName is the name of the settings file in ConfigDir.
+const ConfigName = "config.toml"
+
+// Config is the saved settings file, written by the setup wizard:
+//
+//	language = "en"
+//	pack = ""
+//	mode = "blend"
+//	volume = 80
+//	key_click = true
+//	curriculum = "jolly-phonics"
+//	level = 2
+//	notifications = true
+//	crash_reports = true
+//
+//	[[sink]]
+//	type = "openrgb"
+//
+// Empty or missing values keep the usual defaults. Command-line options
+// override the file.
+type Config struct {
+	Language string `toml:"language,omitempty"`
+	// Pack is a sound pack directory; empty uses the built-in sounds.
+	Pack  string `toml:"pack,omitempty"`
+	Mode  string `toml:"mode,omitempty"`
+	Voice string `toml:"voice,omitempty"`
+	// Volume is from 0 to 100.
+	Volume     *int   `toml:"volume,omitempty"`
+	KeyClick   bool   `toml:"key_click,omitempty"`
+	Curriculum string `toml:"curriculum,omitempty"`
+	Level      int    `toml:"level,omitempty"`
+	Profile    string `toml:"profile,omitempty"`
+	// Notifications shows pausing, resuming and switching sounds as
+	// desktop notifications.
+	Notifications bool `toml:"notifications,omitempty"`
+	// CrashReports saves a report when Phonical crashes. It is off unless
+	// chosen.
+	CrashReports bool `toml:"crash_reports,omitempty"`
+	// Sinks are the feedback channels told about each sound, in order.
+	Sinks []SinkConfig `toml:"sink,omitempty"`
+}
+
+// DefaultConfigPath returns where the settings file is kept.
+func DefaultConfigPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ConfigName), nil
+}
+
+// LoadConfig reads and checks the settings at path.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Config
+	s, err := decodeSchema(path, string(data), &c)
+	if err != nil {
+		return nil, err
+	}
+	c.check(s)
+	if err := s.err(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *Config) check(s *schema) {
+	if c.Language != "" && builtinSounds && !slices.Contains(Languages(), c.Language) {
+		s.errorf(toml.Key{"language"}, "language %q is not available (want %s)", c.Language, strings.Join(Languages(), ", "))
+	}
+	if c.Mode != "" {
+		if _, err := ParseMode(c.Mode); err != nil {
+			s.errorf(toml.Key{"mode"}, "%v", err)
+		}
+	}
+	if c.Voice != "" {
+		if _, err := ParseVoice(c.Voice); err != nil {
+			s.errorf(toml.Key{"voice"}, "%v", err)
+		}
+	}
+	if c.Volume != nil && (*c.Volume < 0 || *c.Volume > 100) {
+		s.errorf(toml.Key{"volume"}, "volume must be 0–100, got %d", *c.Volume)
+	}
+	if c.Curriculum != "" {
+		if _, err := LoadCurriculum(c.Curriculum); err != nil {
+			s.errorf(toml.Key{"curriculum"}, "%v", err)
+		}
+	}
+	if c.Level < 0 {
+		s.errorf(toml.Key{"level"}, "level must be at least 1, got %d", c.Level)
+	}
+	on := map[string]bool{}
+	for i, sc := range c.Sinks {
+		if err := sc.check(); err != nil {
+			s.tableErrorf("sink", i, "sink %d: %v", i+1, err)
+		} else if sc.unique() && !sc.Disabled && on[sc.Type] {
+			s.tableErrorf("sink", i, "sink %d: a second %s; only one can be on", i+1, sc.Type)
+		}
+		on[sc.Type] = on[sc.Type] || !sc.Disabled
+	}
+}
+
+// Save writes the settings to path.
+func (c *Config) Save(path string) error {
+	var buf bytes.Buffer
+	buf.WriteString("# Phonical settings. Command-line options override these.\n")
+	if err := toml.NewEncoder(&buf).Encode(c); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
@@ -0,0 +1,66 @@
+package phonical_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"phonical"
+)
+
+func TestConfigRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "phonical", phonical.ConfigName)
+	volume := 0
+	want := &phonical.Config{
+		Language:   "en",
+		Mode:       "blend",
+		Volume:     &volume,
+		KeyClick:   true,
+		Curriculum: "jolly-phonics",
+		Level:      2,
+	}
+	if err := want.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := phonical.LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A volume of 0 is kept rather than taken as unset
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loaded %+v, want %+v", got, want)
+	}
+}
+
+func TestConfigChecked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), phonical.ConfigName)
+	config := "mode = \"shout\"\nvolume = 120\ncurriculum = \"montessori\"\nlevel = -1\n"
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := phonical.LoadConfig(path)
+	if err == nil {
+		t.Fatal("bad settings accepted")
+	}
+	for _, want := range []string{":1: unknown mode", ":2: volume must be 0–100", ":3: unknown curriculum", ":4: level must be at least 1"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestConfigLanguageBuiltIn(t *testing.T) {
+	if !phonical.HasBuiltinSounds() {
+		t.Skip("lite build")
+	}
+	path := filepath.Join(t.TempDir(), phonical.ConfigName)
+	if err := os.WriteFile(path, []byte("language = \"xx\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := phonical.LoadConfig(path)
+	if err == nil || !strings.Contains(err.Error(), `language "xx" is not available (want en)`) {
+		t.Errorf("loading an unknown language: %v", err)
+	}
+}
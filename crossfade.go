@@ -0,0 +1,44 @@
+package phonical
+
+import (
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// WithCrossfade overlaps consecutive queued letter sounds by d when
+// typing outpaces playback: the end of each sound fades out on the mixer
+// while the next fades in, so fast typing sounds smooth rather than
+// choppy. Zero, the default, plays each sound after the last.
+func WithCrossfade(d time.Duration) Option {
+	return func(e *Engine) {
+		e.crossfade = d
+	}
+}
+
+// crossfadeNext reports whether the next waiting sound can fade in over
+// the end of the one playing now.
+func (q *soundQueue) crossfadeNext() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.items) == 0 {
+		return false
+	}
+	next := q.items[0]
+	return next.say == "" && next.pause == 0 && next.buffer == nil
+}
+
+// fadeIn ramps the first fade samples of streamer up from silence.
+func fadeIn(streamer beep.Streamer, fade int) beep.Streamer {
+	pos := 0
+	return beep.StreamerFunc(func(samples [][2]float64) (int, bool) {
+		filled, ok := streamer.Stream(samples)
+		for i := 0; i < filled && pos+i < fade; i++ {
+			g := float64(pos+i) / float64(fade)
+			samples[i][0] *= g
+			samples[i][1] *= g
+		}
+		pos += filled
+		return filled, ok
+	})
+}
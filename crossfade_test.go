@@ -0,0 +1,52 @@
+package phonical_test
+
+import (
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestCrossfade(t *testing.T) {
+	if !phonical.HasBuiltinSounds() {
+		t.Skip("lite build")
+	}
+	h := phonicaltest.New(t, phonical.WithCrossfade(50*time.Millisecond))
+	// b and c queue up behind a; b's last 50ms moves to the mixer for c to
+	// fade in over, and c, with nothing behind it, plays in full
+	h.Sink.Hold()
+	h.Type("a")
+	h.Sink.WaitFor(1, time.Second)
+	h.Type("bc")
+	h.Sink.Release()
+	h.Expect("a.wav", "b.wav", "c.wav")
+
+	if layers := h.Sink.Layers(); layers != 1 {
+		t.Errorf("%d tails on the mixer, want 1", layers)
+	}
+	played := h.Sink.Playbacks()
+	first := make([][2]float64, 1)
+	played[2].Streamer.Stream(first)
+	if first[0] != [2]float64{} {
+		t.Errorf("c starts at %v, want silence", first[0])
+	}
+	for i, want := range []int{19425, 12009 - 2205, 8124 - 1} {
+		if got := samples(played[i].Streamer); got != want {
+			t.Errorf("%s played %d samples, want %d", played[i].Name, got, want)
+		}
+	}
+}
+
+func TestNoCrossfadeByDefault(t *testing.T) {
+	h := phonicaltest.New(t)
+	h.Sink.Hold()
+	h.Type("a")
+	h.Sink.WaitFor(1, time.Second)
+	h.Type("bc")
+	h.Sink.Release()
+	h.Expect("a.wav", "b.wav", "c.wav")
+	if layers := h.Sink.Layers(); layers != 0 {
+		t.Errorf("%d tails on the mixer, want none", layers)
+	}
+}
@@ -0,0 +1,227 @@
+package phonical
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ReviewState is a letter's place in the spaced-repetition schedule. Each
+// successful review moves it up a box, doubling the time until it is due
+// again; a missed prompt sends it back to the first box.
+type ReviewState struct {
+	Box int       `json:"box"`
+	Due time.Time `json:"due"`
+}
+
+// reviewIntervals are how long a letter rests in each box.
+var reviewIntervals = []time.Duration{
+	time.Hour,
+	4 * time.Hour,
+	24 * time.Hour,
+	3 * 24 * time.Hour,
+	7 * 24 * time.Hour,
+	14 * 24 * time.Hour,
+}
+
+// Adaptive configures practice that adapts to the stats.
+type Adaptive struct {
+	// Emphasize is how many of the letters most in need of practice play
+	// with emphasis (the phoneme twice).
+	Emphasize int
+	// PromptEvery is how often the child is asked to find one of those
+	// letters between words. Zero disables prompts.
+	PromptEvery time.Duration
+}
+
+// DefaultAdaptive emphasizes three letters and prompts every five minutes.
+var DefaultAdaptive = Adaptive{Emphasize: 3, PromptEvery: 5 * time.Minute}
+
+// promptTimeout is how long a find-the-letter prompt waits for an answer.
+const promptTimeout = 15 * time.Second
+
+// review records whether letter was recalled, moving it through the boxes.
+func (st *Stats) review(letter rune, recalled bool, now time.Time) {
+	if st.Reviews == nil {
+		st.Reviews = make(map[string]*ReviewState)
+	}
+	rs := st.Reviews[string(letter)]
+	if rs == nil {
+		rs = &ReviewState{}
+		st.Reviews[string(letter)] = rs
+	}
+	if recalled {
+		if rs.Box < len(reviewIntervals)-1 {
+			rs.Box++
+		}
+	} else {
+		rs.Box = 0
+	}
+	rs.Due = now.Add(reviewIntervals[rs.Box])
+}
+
+// exposed counts a normal press of letter as a review when it is due.
+func (st *Stats) exposed(letter rune, now time.Time) {
+	if rs := st.Reviews[string(letter)]; rs == nil || !now.Before(rs.Due) {
+		st.review(letter, true, now)
+	}
+}
+
+// priority scores how much letter needs practice: higher when it is
+// overdue for review, rarely typed compared with the rest of pool, or often
+// confused in listening games.
+func (st *Stats) priority(letter rune, pool []rune, now time.Time) float64 {
+	total := 0
+	for _, r := range pool {
+		total += st.Letters[string(r)]
+	}
+	mean := float64(total) / float64(len(pool))
+	score := (mean + 1) / float64(st.Letters[string(letter)]+1)
+
+	rs := st.Reviews[string(letter)]
+	switch {
+	case rs == nil:
+		score += 1
+	case !now.Before(rs.Due):
+		overdue := now.Sub(rs.Due).Hours() / 24
+		if overdue > 2 {
+			overdue = 2
+		}
+		score += 1 + overdue
+	}
+
+	for key, count := range st.Confusions {
+		if []rune(key)[0] == letter {
+			score += 0.5 * float64(count)
+		}
+	}
+	return score
+}
+
+// WeakestLetters returns the n letters in pool most in need of practice.
+func (st *Stats) WeakestLetters(pool []rune, n int, now time.Time) []rune {
+	if len(pool) == 0 {
+		return nil
+	}
+	ranked := append([]rune(nil), pool...)
+	scores := make(map[rune]float64, len(ranked))
+	for _, r := range ranked {
+		scores[r] = st.priority(r, pool, now)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if scores[ranked[i]] != scores[ranked[j]] {
+			return scores[ranked[i]] > scores[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// adaptive tracks emphasized letters and find-the-letter prompts.
+type adaptive struct {
+	cfg        Adaptive
+	emphasized map[rune]bool
+	lastPrompt time.Time
+	target     rune
+	deadline   time.Time
+}
+
+// refreshEmphasis recomputes which letters are emphasized. The caller must
+// hold e.mu.
+func (e *Engine) refreshEmphasis(now time.Time) {
+	if e.adaptive == nil || e.stats == nil {
+		return
+	}
+	st := e.stats.Snapshot()
+	e.adaptive.emphasized = make(map[rune]bool)
+	for _, r := range st.WeakestLetters(e.packLetters(), e.adaptive.cfg.Emphasize, now) {
+		e.adaptive.emphasized[r] = true
+	}
+}
+
+// packLetters lists the letters the pack can play, limited to those taught
+// so far when a curriculum is in use.
+func (e *Engine) packLetters() []rune {
+	pack := e.pack.Load()
+	letters := make([]rune, 0, len(pack.Letters))
+	for r := range pack.Letters {
+		if e.active == nil || e.active[r] {
+			letters = append(letters, r)
+		}
+	}
+	return letters
+}
+
+// adaptLetter updates the review schedule for a typed letter and reports
+// whether it should be emphasized.
+func (e *Engine) adaptLetter(letter rune, now time.Time) bool {
+	stats := e.recordingStats()
+	if e.adaptive == nil || stats == nil {
+		return false
+	}
+
+	e.mu.Lock()
+	a := e.adaptive
+	answered := a.target == letter && now.Before(a.deadline)
+	if answered {
+		a.target = 0
+	}
+	emphasize := a.emphasized[letter]
+	e.mu.Unlock()
+
+	stats.Update(func(st *Stats) {
+		if answered {
+			st.review(letter, true, now)
+		} else {
+			st.exposed(letter, now)
+		}
+	})
+	if answered {
+		e.praise(fmt.Sprintf("Well done, you found %c!", letter))
+	}
+	return emphasize
+}
+
+// maybePrompt asks the child to find a letter that needs practice when a
+// prompt is due. It runs between words so it never interrupts one.
+func (e *Engine) maybePrompt(now time.Time) {
+	stats := e.recordingStats()
+	if e.adaptive == nil || stats == nil || e.adaptive.cfg.PromptEvery <= 0 {
+		return
+	}
+
+	e.mu.Lock()
+	a := e.adaptive
+	if a.target != 0 && !now.Before(a.deadline) {
+		// The last prompt went unanswered
+		missed := a.target
+		a.target = 0
+		stats.Update(func(st *Stats) { st.review(missed, false, now) })
+	}
+	if a.target != 0 || now.Sub(a.lastPrompt) < a.cfg.PromptEvery {
+		e.mu.Unlock()
+		return
+	}
+	e.refreshEmphasis(now)
+	var target rune
+	for r := range a.emphasized {
+		if target == 0 || r < target {
+			target = r
+		}
+	}
+	if target == 0 {
+		e.mu.Unlock()
+		return
+	}
+	a.target = target
+	a.deadline = now.Add(promptTimeout)
+	a.lastPrompt = now
+	e.mu.Unlock()
+
+	e.debugf("Prompting for %c\n", target)
+	e.enqueueSay(fmt.Sprintf("Can you find the letter %c?", target))
+	e.enqueueUnit(string(target))
+}
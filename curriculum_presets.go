@@ -0,0 +1,106 @@
+package phonical
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/BurntSushi/toml"
+)
+
+//go:embed data/curricula/*.toml
+var curriculumFiles embed.FS
+
+// Curriculum is a teaching order: which letters and digraphs are taught at
+// each level, the pack variant whose sounds match the programme, and any
+// actions that go with each letter.
+type Curriculum struct {
+	Name string
+	// Variant names a [variants.<name>] table in the pack whose sounds
+	// replace the standard ones.
+	Variant string
+	Levels  []CurriculumLevel
+	// Actions describes the programme's movement for each letter.
+	Actions map[rune]string
+}
+
+// CurriculumLevel is one group of letters and digraphs.
+type CurriculumLevel struct {
+	Letters  []rune
+	Digraphs []string
+}
+
+type curriculumFile struct {
+	Name    string            `toml:"name"`
+	Variant string            `toml:"variant"`
+	Actions map[string]string `toml:"actions"`
+	Levels  []struct {
+		Letters  string   `toml:"letters"`
+		Digraphs []string `toml:"digraphs"`
+	} `toml:"level"`
+}
+
+// Curricula returns the names of the built-in curriculum presets.
+func Curricula() []string {
+	entries, _ := fs.ReadDir(curriculumFiles, "data/curricula")
+	var names []string
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".toml"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadCurriculum returns the built-in preset with the given name, such as
+// "jolly-phonics".
+func LoadCurriculum(name string) (*Curriculum, error) {
+	data, err := curriculumFiles.ReadFile(path.Join("data/curricula", name+".toml"))
+	if err != nil {
+		return nil, fmt.Errorf("unknown curriculum %q (want one of %s)", name, strings.Join(Curricula(), ", "))
+	}
+	return parseCurriculum(name+".toml", string(data))
+}
+
+func parseCurriculum(file, data string) (*Curriculum, error) {
+	var f curriculumFile
+	s, err := decodeSchema(file, data, &f)
+	if err != nil {
+		return nil, err
+	}
+	c := &Curriculum{Name: f.Name, Variant: f.Variant, Actions: make(map[rune]string)}
+	for _, l := range f.Levels {
+		c.Levels = append(c.Levels, CurriculumLevel{Letters: []rune(l.Letters), Digraphs: l.Digraphs})
+	}
+	for key, action := range f.Actions {
+		r, size := utf8.DecodeRuneInString(key)
+		if size != len(key) {
+			s.errorf(toml.Key{"actions", key}, "actions: key %q must be a single character", key)
+			continue
+		}
+		c.Actions[r] = action
+	}
+	if err := s.err(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Active returns the letters and digraphs taught up to and including level,
+// counting from 1. Levels beyond the last include everything.
+func (c *Curriculum) Active(level int) (letters map[rune]bool, digraphs []string) {
+	letters = make(map[rune]bool)
+	for i, l := range c.Levels {
+		if i >= level {
+			break
+		}
+		for _, r := range l.Letters {
+			letters[r] = true
+		}
+		digraphs = append(digraphs, l.Digraphs...)
+	}
+	return letters, digraphs
+}
@@ -0,0 +1,54 @@
+package phonical_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestCurriculumPresets(t *testing.T) {
+	if names := phonical.Curricula(); !reflect.DeepEqual(names, []string{"jolly-phonics", "letters-and-sounds"}) {
+		t.Errorf("presets %q", names)
+	}
+	for _, name := range phonical.Curricula() {
+		c, err := phonical.LoadCurriculum(name)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		// Between them the levels teach every letter
+		letters, _ := c.Active(len(c.Levels))
+		if len(letters) != 26 {
+			t.Errorf("%s teaches %d letters, want 26", name, len(letters))
+		}
+	}
+	if _, err := phonical.LoadCurriculum("montessori"); err == nil || !strings.Contains(err.Error(), "jolly-phonics") {
+		t.Errorf("unknown preset: %v, want an error listing the presets", err)
+	}
+}
+
+func TestCurriculumLevels(t *testing.T) {
+	c, err := phonical.LoadCurriculum("jolly-phonics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	letters, digraphs := c.Active(2)
+	if len(letters) != 13 || !letters['s'] || !letters['d'] || letters['g'] {
+		t.Errorf("level 2 letters %v, want satipn and ckehrmd", letters)
+	}
+	if !reflect.DeepEqual(digraphs, []string{"ck"}) {
+		t.Errorf("level 2 digraphs %q, want ck", digraphs)
+	}
+
+	// Letters not taught yet play only a tick
+	h := phonicaltest.New(t, phonical.WithCurriculum(c, 1))
+	h.Type("sg")
+	h.Expect("s.wav", "g.wav")
+	played := h.Sink.Playbacks()
+	if taught, later := samples(played[0].Streamer), samples(played[1].Streamer); later*2 > taught {
+		t.Errorf("g played %d samples against s's %d, want a short tick", later, taught)
+	}
+}
@@ -0,0 +1,56 @@
+package phonical_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// practisedStats returns stats where every letter but the ones in rare
+// has been typed often.
+func practisedStats(t *testing.T, rare string) *phonical.StatsStore {
+	t.Helper()
+	stats, err := phonical.OpenStats(filepath.Join(t.TempDir(), "stats.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats.Update(func(st *phonical.Stats) {
+		for c := 'a'; c <= 'z'; c++ {
+			st.Letters[string(c)] = 20
+		}
+		for _, c := range rare {
+			st.Letters[string(c)] = 0
+		}
+	})
+	return stats
+}
+
+func TestWeakestLetters(t *testing.T) {
+	st := practisedStats(t, "qz").Snapshot()
+	// Confusing z for s in a listening game puts it ahead of q
+	st.Confusions["z>s"] = 2
+	got := st.WeakestLetters([]rune("abcqz"), 2, time.Now())
+	if want := []rune("zq"); !reflect.DeepEqual(got, want) {
+		t.Errorf("weakest letters %q, want %q", string(got), string(want))
+	}
+}
+
+func TestAdaptivePrompts(t *testing.T) {
+	// Without the watchdog the clock only moves when the test says
+	h := phonicaltest.New(t,
+		phonical.WithStats(practisedStats(t, "z")),
+		phonical.WithAdaptive(phonical.Adaptive{Emphasize: 1, PromptEvery: time.Hour}),
+		phonical.WithWatchdog(0))
+
+	// The weakest letter plays twice, and a prompt comes between words
+	// once the hour is up
+	h.Type("za{space}{wait 1h}b{space}")
+	h.Type("z{space}")
+	h.Expect("z.wav", "z.wav", "a.wav", "b.wav",
+		"say:Can you find the letter z?", "z.wav",
+		"z.wav", "say:Well done, you found z!", "z.wav")
+}
@@ -0,0 +1,100 @@
+package phonical
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// dndPollInterval is how often the Do Not Disturb state is checked.
+const dndPollInterval = 15 * time.Second
+
+// WithDoNotDisturb mutes the engine while the operating system's Do Not
+// Disturb or Focus mode is on. Key presses are still tracked and recorded.
+func WithDoNotDisturb(enabled bool) Option {
+	return func(e *Engine) {
+		e.respectDND = enabled
+	}
+}
+
+// DoNotDisturb reports whether the operating system's Do Not Disturb or
+// Focus mode is on. It is supported on macOS and on Linux desktops using
+// GNOME notifications.
+func DoNotDisturb() (bool, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return macFocusActive()
+	case "linux":
+		out, err := exec.Command("gsettings", "get", "org.gnome.desktop.notifications", "show-banners").Output()
+		if err != nil {
+			return false, err
+		}
+		return strings.TrimSpace(string(out)) == "false", nil
+	default:
+		return false, errors.New("do not disturb detection is not supported on " + runtime.GOOS)
+	}
+}
+
+// macFocusActive reads the Focus assertions store, which lists the active
+// Focus modes.
+func macFocusActive() (bool, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false, err
+	}
+	data, err := os.ReadFile(filepath.Join(home, "Library", "DoNotDisturb", "DB", "Assertions.json"))
+	if err != nil {
+		return false, err
+	}
+	var db struct {
+		Data []struct {
+			StoreAssertionRecords []json.RawMessage `json:"storeAssertionRecords"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &db); err != nil {
+		return false, err
+	}
+	for _, d := range db.Data {
+		if len(d.StoreAssertionRecords) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// watchState polls check every interval until the engine stops, storing
+// the result in state. It gives up if check fails, since the platform is
+// unlikely to start supporting it later.
+func (e *Engine) watchState(name string, interval time.Duration, check func() (bool, error), state *atomic.Bool) {
+	for {
+		on, err := check()
+		if err != nil {
+			e.logf("Checking %s: %v", name, err)
+			return
+		}
+		if state.Swap(on) != on {
+			e.debugf("%s: %t\n", name, on)
+			if on {
+				e.notify("Paused: " + name)
+			} else if !e.muted() {
+				e.notify("Resumed")
+			}
+		}
+		select {
+		case <-e.clock.After(interval):
+		case <-e.ctx.Done():
+			return
+		}
+	}
+}
+
+// muted reports whether sounds should currently be skipped.
+func (e *Engine) muted() bool {
+	return e.silent || e.dnd.Load() || e.onCall.Load()
+}
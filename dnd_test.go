@@ -0,0 +1,70 @@
+package phonical_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// fakeCommand puts a script named name that prints output first on PATH.
+func fakeCommand(t *testing.T, name, output string) {
+	t.Helper()
+	if runtime.GOOS != "linux" {
+		t.Skip("fake commands are shell scripts")
+	}
+	dir := t.TempDir()
+	script := "#!/bin/sh\necho '" + output + "'\n"
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestDoNotDisturbMutes(t *testing.T) {
+	// GNOME hides notification banners while Do Not Disturb is on
+	fakeCommand(t, "gsettings", "false")
+	if on, err := phonical.DoNotDisturb(); err != nil || !on {
+		t.Fatalf("DoNotDisturb() = %v, %v, want on", on, err)
+	}
+
+	h := phonicaltest.New(t, phonical.WithDoNotDisturb(true))
+	deadline := time.Now().Add(2 * time.Second)
+	for !h.Engine.Status().DoNotDisturb {
+		if time.Now().After(deadline) {
+			t.Fatal("engine never noticed Do Not Disturb")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	h.Type("ab")
+	h.Expect()
+	if st := h.Engine.Status(); !st.Muted || st.KeysHandled != 2 {
+		t.Errorf("status %+v, want muted with both keys handled", st)
+	}
+}
+
+func TestDoNotDisturbPolled(t *testing.T) {
+	fakeCommand(t, "gsettings", "false")
+	notifier, c := notifications()
+	h := phonicaltest.New(t, phonical.WithDoNotDisturb(true), phonical.WithWatchdog(0), phonical.WithNotifier(notifier))
+	waitNotification(t, c, "Phonical: Paused: Do Not Disturb")
+
+	// Turning Do Not Disturb off is noticed at the next check
+	fakeCommand(t, "gsettings", "true")
+	waitWaiters(t, h.Clock, 1)
+	h.Clock.Advance(14 * time.Second)
+	if !h.Engine.Status().DoNotDisturb {
+		t.Fatal("Do Not Disturb turned off before it was checked again")
+	}
+	h.Clock.Advance(time.Second)
+	waitNotification(t, c, "Phonical: Resumed")
+	if h.Engine.Status().DoNotDisturb {
+		t.Error("status still shows Do Not Disturb")
+	}
+	h.Type("a")
+	h.Expect("a.wav")
+}
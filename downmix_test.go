@@ -0,0 +1,61 @@
+package phonical_test
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/wav"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestMonoDownmix(t *testing.T) {
+	// A recording with sound only on the left
+	dir := t.TempDir()
+	f, err := os.Create(filepath.Join(dir, "a.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	left := 100
+	err = wav.Encode(f, beep.StreamerFunc(func(samples [][2]float64) (int, bool) {
+		n := min(left, len(samples))
+		for i := range samples[:n] {
+			samples[i] = [2]float64{0.5, 0}
+		}
+		left -= n
+		return n, n > 0
+	}), testFormat)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, phonical.ManifestName), []byte("name = \"Left\"\n\n[letters]\na = \"a.wav\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pack, err := phonical.LoadPack(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := map[bool][2]float64{}
+	for _, mono := range []bool{false, true} {
+		h := phonicaltest.New(t, phonical.WithPack(pack), phonical.WithMono(mono))
+		h.Type("a")
+		h.Expect("a.wav")
+		buf := make([][2]float64, 1)
+		h.Sink.Playbacks()[0].Streamer.Stream(buf)
+		first[mono] = buf[0]
+	}
+	// Mono plays the average of the channels on both sides
+	stereo, mono := first[false], first[true]
+	if stereo[0] == 0 || stereo[1] != 0 {
+		t.Fatalf("stereo played %v, want only the left", stereo)
+	}
+	if mono[0] != mono[1] || math.Abs(mono[0]-stereo[0]/2) > 0.001 {
+		t.Errorf("mono played %v, want half of %g on both sides", mono, stereo[0])
+	}
+}
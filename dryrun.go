@@ -0,0 +1,53 @@
+package phonical
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/faiface/beep"
+)
+
+// WithDryRun runs the engine without sound, writing a line to w for each
+// sound it would play and each phrase it would say instead. It is for
+// checking letter mappings, digraphs and app filters in silence.
+func WithDryRun(w io.Writer) Option {
+	return func(e *Engine) {
+		d := &dryRun{w: w}
+		e.sink = d
+		e.announcer = AnnouncerFunc(d.say)
+	}
+}
+
+// dryRun is an AudioSink and Announcer that only reports what it is given.
+type dryRun struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (d *dryRun) printf(format string, args ...interface{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	fmt.Fprintf(d.w, format, args...)
+}
+
+func (d *dryRun) say(text string) error {
+	d.printf("Would say %q\n", text)
+	return nil
+}
+
+func (d *dryRun) Init() error { return nil }
+
+func (d *dryRun) Play(_ context.Context, p Playback) error {
+	if p.Letter != 0 {
+		d.printf("Would play %s for %c\n", p.Name, p.Letter)
+	} else {
+		d.printf("Would play %s\n", p.Name)
+	}
+	return nil
+}
+
+func (d *dryRun) Layer(beep.Streamer) {}
+
+func (d *dryRun) Close() {}
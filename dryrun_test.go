@@ -0,0 +1,42 @@
+package phonical_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"phonical"
+)
+
+func TestDryRun(t *testing.T) {
+	if !phonical.HasBuiltinSounds() {
+		t.Skip("lite build")
+	}
+	var out syncBuffer
+	engine, err := phonical.New(phonical.WithDryRun(&out), phonical.WithMode(phonical.ModeBlend),
+		phonical.WithDoNotDisturb(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer engine.Stop()
+	for _, c := range "at " {
+		engine.HandleKey(phonical.Key{Char: c})
+	}
+
+	want := "Would play a.wav for a\n" +
+		"Would play t.wav for t\n" +
+		"Would play a.wav for a\n" +
+		"Would play t.wav for t\n" +
+		"Would play blend:at\n" +
+		"Would say \"at\"\n"
+	deadline := time.Now().Add(2 * time.Second)
+	for out.String() != want {
+		if time.Now().After(deadline) || !strings.HasPrefix(want, out.String()) {
+			t.Fatalf("dry run printed:\n%s\nwant:\n%s", out.String(), want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
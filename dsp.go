@@ -0,0 +1,182 @@
+package phonical
+
+import (
+	"math"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// Blending settings. Phoneme clips are recorded to be heard on their own,
+// so each is trimmed and shortened before they are overlapped.
+const (
+	blendOverlap   = 60 * time.Millisecond
+	blendMaxLength = 220 * time.Millisecond
+	blendFade      = 25 * time.Millisecond
+	silenceLevel   = 0.02
+)
+
+// pcm reads buffer into memory, resampled to the output rate when needed.
+func pcm(buffer *beep.Buffer) [][2]float64 {
+	var streamer beep.Streamer = buffer.Streamer(0, buffer.Len())
+	if rate := buffer.Format().SampleRate; rate != outputFormat.SampleRate {
+		streamer = beep.Resample(4, rate, outputFormat.SampleRate, streamer)
+	}
+	var out [][2]float64
+	chunk := make([][2]float64, 512)
+	for {
+		n, ok := streamer.Stream(chunk)
+		out = append(out, chunk[:n]...)
+		if !ok {
+			return out
+		}
+	}
+}
+
+// pcmBuffer wraps samples in a buffer at the output format.
+func pcmBuffer(samples [][2]float64) *beep.Buffer {
+	buffer := beep.NewBuffer(outputFormat)
+	buffer.Append(samplesStreamer(samples))
+	return buffer
+}
+
+// trimSilence removes leading and trailing samples quieter than level.
+func trimSilence(samples [][2]float64, level float64) [][2]float64 {
+	loud := func(s [2]float64) bool {
+		return math.Abs(s[0]) > level || math.Abs(s[1]) > level
+	}
+	start, end := 0, len(samples)
+	for start < end && !loud(samples[start]) {
+		start++
+	}
+	for end > start && !loud(samples[end-1]) {
+		end--
+	}
+	return samples[start:end]
+}
+
+// fade applies linear ramps of in samples at the start and out samples at
+// the end, in place.
+func fade(samples [][2]float64, in, out int) {
+	for i := 0; i < in && i < len(samples); i++ {
+		g := float64(i) / float64(in)
+		samples[i][0] *= g
+		samples[i][1] *= g
+	}
+	for i := 0; i < out && i < len(samples); i++ {
+		g := float64(i) / float64(out)
+		j := len(samples) - 1 - i
+		samples[j][0] *= g
+		samples[j][1] *= g
+	}
+}
+
+// overlapAdd joins clips so each starts overlap samples before the previous
+// one ends, summing the overlapping parts.
+func overlapAdd(clips [][][2]float64, overlap int) [][2]float64 {
+	var out [][2]float64
+	for _, clip := range clips {
+		start := len(out) - overlap
+		if start < 0 {
+			start = 0
+		}
+		for i, s := range clip {
+			if start+i < len(out) {
+				out[start+i][0] += s[0]
+				out[start+i][1] += s[1]
+			} else {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+// blend time-compresses and crossfades phoneme clips into one buffer that
+// sounds like a word rather than a row of separate sounds.
+func blend(buffers []*beep.Buffer) *beep.Buffer {
+	rate := outputFormat.SampleRate
+	maxLen, fadeLen, overlap := rate.N(blendMaxLength), rate.N(blendFade), rate.N(blendOverlap)
+
+	clips := make([][][2]float64, 0, len(buffers))
+	for _, buffer := range buffers {
+		clip := trimSilence(pcm(buffer), silenceLevel)
+		if len(clip) > maxLen {
+			clip = clip[:maxLen]
+		}
+		// Copy before fading so the cached buffer's samples aren't touched
+		clip = append([][2]float64(nil), clip...)
+		fade(clip, fadeLen, overlap)
+		clips = append(clips, clip)
+	}
+	return pcmBuffer(overlapAdd(clips, overlap))
+}
+
+// Time-stretch settings: frames of about 23ms at the output rate, half
+// overlapping, each placed within stretchTolerance of its nominal position
+// where it best continues the one before.
+const (
+	stretchFrame     = 1024
+	stretchTolerance = 256
+)
+
+// timeStretch makes samples factor times longer without changing their
+// pitch, using waveform-similarity overlap-add (WSOLA) so voiced sounds
+// stay smooth rather than warbling.
+func timeStretch(samples [][2]float64, factor float64) [][2]float64 {
+	if factor == 1 || len(samples) < stretchFrame {
+		return append([][2]float64(nil), samples...)
+	}
+	hop := stretchFrame / 2
+	outLen := int(float64(len(samples)) * factor)
+	out := make([][2]float64, outLen+stretchFrame)
+	norm := make([]float64, outLen+stretchFrame)
+	window := make([]float64, stretchFrame)
+	for i := range window {
+		window[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(stretchFrame))
+	}
+
+	prev := 0
+	for k := 0; k*hop < outLen; k++ {
+		pos := int(float64(k*hop) / factor)
+		if k > 0 {
+			pos = bestOverlap(samples, prev+hop, pos, hop)
+		}
+		for i, w := range window {
+			if pos+i >= len(samples) {
+				break
+			}
+			out[k*hop+i][0] += samples[pos+i][0] * w
+			out[k*hop+i][1] += samples[pos+i][1] * w
+			norm[k*hop+i] += w
+		}
+		prev = pos
+	}
+	for i := range out {
+		if norm[i] > 1e-3 {
+			out[i][0] /= norm[i]
+			out[i][1] /= norm[i]
+		}
+	}
+	return out[:outLen]
+}
+
+// bestOverlap returns the start within stretchTolerance of nominal whose
+// first n samples best match the n starting at want, the natural
+// continuation of the previous frame.
+func bestOverlap(samples [][2]float64, want, nominal, n int) int {
+	best, bestScore := nominal, math.Inf(-1)
+	for pos := max(0, nominal-stretchTolerance); pos <= nominal+stretchTolerance; pos++ {
+		if pos+n > len(samples) || want+n > len(samples) {
+			break
+		}
+		score := 0.0
+		for i := 0; i < n; i++ {
+			score += (samples[pos+i][0] + samples[pos+i][1]) * (samples[want+i][0] + samples[want+i][1])
+		}
+		if score > bestScore {
+			best, bestScore = pos, score
+		}
+	}
+	return best
+}
@@ -0,0 +1,28 @@
+package phonical_test
+
+import (
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestBlendRunsPhonemesTogether(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithMode(phonical.ModeBlend))
+	h.Type("cat{space}")
+	h.Expect("c.wav", "a.wav", "t.wav",
+		"c.wav", "a.wav", "t.wav", "blend:cat", "say:cat")
+
+	// Each phoneme is cut short and overlaps the next, so the blend is
+	// quicker than the three played one after another, but not by more
+	// than their longest allowed lengths
+	played := h.Sink.Playbacks()
+	apart := 0
+	for _, p := range played[:3] {
+		apart += samples(p.Streamer)
+	}
+	blended := samples(played[6].Streamer)
+	if maxLen := 3 * 44100 * 220 / 1000; blended == 0 || blended >= apart || blended > maxLen {
+		t.Errorf("blend is %d samples, want fewer than %d apart and at most %d", blended, apart, maxLen)
+	}
+}
@@ -0,0 +1,47 @@
+package phonical
+
+import "fmt"
+
+// DefaultEchoVolume is the word echo level used when none is given, quiet
+// enough to stay in the background of ordinary typing.
+const DefaultEchoVolume = 0.4
+
+// WithWordEcho quietly echoes each completed word, whatever the mode: its
+// sounds one at a time and then blended, at volume between 0 and 1
+// relative to the letters. It builds the segment-and-blend habit during
+// normal typing. A volume of 0, the default, disables it.
+func WithWordEcho(volume float64) Option {
+	return func(e *Engine) {
+		e.echoVolume = volume
+	}
+}
+
+func (e *Engine) checkEcho() error {
+	if e.echoVolume < 0 || e.echoVolume > 1 {
+		return fmt.Errorf("echo volume must be between 0 and 1, got %g", e.echoVolume)
+	}
+	return nil
+}
+
+// echoWord queues the quiet segmented-then-blended echo of word. ModeBlend
+// already sounds the word out, so it is skipped there.
+func (e *Engine) echoWord(word string) {
+	e.mu.Lock()
+	mode := e.mode
+	e.mu.Unlock()
+	if e.echoVolume == 0 || mode == ModeBlend {
+		return
+	}
+
+	e.enqueuePause(segmentGap)
+	for _, g := range e.pack.Load().segment(word) {
+		for _, item := range e.unitSounds(g) {
+			item.volume = e.echoVolume
+			e.enqueue(item)
+		}
+	}
+	e.enqueuePause(segmentGap)
+	if buffer := e.blendWord(word); buffer != nil {
+		e.enqueue(&queuedSound{sound: "echo:" + word, buffer: buffer, volume: e.echoVolume})
+	}
+}
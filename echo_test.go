@@ -0,0 +1,52 @@
+package phonical_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/faiface/beep"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// peak returns the loudest sample left in s.
+func peak(s beep.Streamer) float64 {
+	buf := make([][2]float64, 512)
+	max := 0.0
+	for {
+		n, ok := s.Stream(buf)
+		for _, sample := range buf[:n] {
+			max = math.Max(max, math.Max(math.Abs(sample[0]), math.Abs(sample[1])))
+		}
+		if !ok {
+			return max
+		}
+	}
+}
+
+func TestWordEcho(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithWordEcho(0.5))
+	h.Type("at{space}")
+	h.Expect("a.wav", "t.wav", "a.wav", "t.wav", "echo:at")
+
+	// The echo plays at half the letters' volume
+	played := h.Sink.Playbacks()
+	typed, echoed := peak(played[0].Streamer), peak(played[2].Streamer)
+	if math.Abs(echoed-typed/2) > 0.001 {
+		t.Errorf("echoed a peaks at %g, want half of %g", echoed, typed)
+	}
+}
+
+func TestWordEchoSkippedInBlendMode(t *testing.T) {
+	// Blend mode already sounds the word out
+	h := phonicaltest.New(t, phonical.WithWordEcho(0.5), phonical.WithMode(phonical.ModeBlend))
+	h.Type("at{space}")
+	h.Expect("a.wav", "t.wav", "a.wav", "t.wav", "blend:at", "say:at")
+}
+
+func TestWordEchoVolumeChecked(t *testing.T) {
+	if _, err := phonical.New(phonical.WithWordEcho(1.5)); err == nil {
+		t.Error("echo volume above 1 accepted")
+	}
+}
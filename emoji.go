@@ -0,0 +1,55 @@
+package phonical
+
+// EmojiSound is what plays for an emoji in emoji mode: an optional
+// recording followed by an optional spoken phrase.
+type EmojiSound struct {
+	Sound string `toml:"sound,omitempty"`
+	Say   string `toml:"say,omitempty"`
+}
+
+// defaultEmoji are the built-in emoji sounds, used by DefaultPack.
+var defaultEmoji = map[rune]EmojiSound{
+	'🐶': {Say: "the dog says woof"},
+	'🐱': {Say: "the cat says meow"},
+	'🐮': {Say: "the cow says moo"},
+	'🐷': {Say: "the pig says oink"},
+	'🐑': {Say: "the sheep says baa"},
+	'🦆': {Say: "the duck says quack"},
+	'🐸': {Say: "the frog says ribbit"},
+	'🦁': {Say: "the lion says roar"},
+	'🐍': {Say: "the snake says sss"},
+	'🐝': {Say: "the bee says buzz"},
+	'🚂': {Say: "the train says choo choo"},
+	'🚗': {Say: "the car says beep beep"},
+}
+
+// variationSelector follows many emoji to ask for colour presentation. It
+// carries no meaning of its own.
+const variationSelector = '\ufe0f'
+
+// WithEmoji plays a sound for emoji sent by picture and touch keyboards,
+// such as "the dog says woof" for 🐶. Packs can add or change emoji in an
+// [emoji] table.
+func WithEmoji(enabled bool) Option {
+	return func(e *Engine) {
+		e.emoji = enabled
+	}
+}
+
+// playEmoji queues the sound for char, reporting whether it is an emoji
+// the pack knows.
+func (e *Engine) playEmoji(char rune) bool {
+	sound, ok := e.pack.Load().Emoji[char]
+	if !ok {
+		return false
+	}
+	e.endWord()
+	e.debugf("Emoji %c\n", char)
+	if sound.Sound != "" {
+		e.enqueue(&queuedSound{sound: sound.Sound})
+	}
+	if sound.Say != "" {
+		e.enqueueSay(sound.Say)
+	}
+	return true
+}
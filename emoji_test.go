@@ -0,0 +1,25 @@
+package phonical_test
+
+import (
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestEmoji(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithEmoji(true))
+	// The colour variation selector after an emoji is ignored, and an
+	// emoji ends the word before it
+	h.Type("a🐶️🐮")
+	h.Expect("a.wav", "say:the dog says woof", "say:the cow says moo")
+	if st := h.Engine.Status(); st.Word != "" || st.LastWord != "a" {
+		t.Errorf("word %q after %q, want a finished", st.Word, st.LastWord)
+	}
+}
+
+func TestEmojiOff(t *testing.T) {
+	h := phonicaltest.New(t)
+	h.Type("🐶a")
+	h.Expect("a.wav")
+}
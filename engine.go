@@ -0,0 +1,1142 @@
+// Package phonical plays phonetic letter sounds as keys are typed.
+//
+// The Engine type wraps the keyboard hook, sound cache and playback queue
+// used by the phonical command so other Go programs can embed it:
+//
+//	engine, err := phonical.New(
+//		phonical.OnLetter(func(ev phonical.LetterEvent) {
+//			fmt.Printf("%c\n", ev.Letter)
+//		}),
+//	)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer engine.Stop()
+//	engine.Run()
+package phonical
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/effects"
+)
+
+// Key is a single key press delivered to the engine.
+type Key struct {
+	// Char is the character the key produced, or 0 for non-character keys.
+	Char rune
+	// Code is the hook's layout-independent keycode.
+	Code uint16
+	// Rawcode is the platform-specific keycode.
+	Rawcode uint16
+	// Mods is the modifier keys held, when the input reports them.
+	Mods Modifier
+}
+
+// LetterEvent is passed to OnLetter callbacks when a mapped letter is pressed.
+type LetterEvent struct {
+	Letter rune
+	Sound  string
+	Time   time.Time
+}
+
+// WordEvent is passed to OnWord callbacks when a word boundary follows one
+// or more letters.
+type WordEvent struct {
+	Word string
+	Time time.Time
+}
+
+// PlayEvent is passed to each EventSink as each sound starts playing.
+type PlayEvent struct {
+	// Sound is the pack file, or a label such as "cue:space".
+	Sound string
+	// Letter is the letter the sound stands for, if any.
+	Letter rune
+	// Text is the phrase being spoken, for spoken sounds.
+	Text string
+	Time time.Time
+	// Queued is when the sound was queued, such as when its key was
+	// pressed.
+	Queued time.Time
+}
+
+// SessionSummary is passed to OnSessionEnd callbacks when the engine stops.
+type SessionSummary struct {
+	Start   time.Time
+	End     time.Time
+	Letters int
+	Words   int
+}
+
+// Option configures an Engine.
+type Option func(*Engine)
+
+// WithVerbose enables diagnostic output.
+func WithVerbose(verbose bool) Option {
+	return func(e *Engine) {
+		e.verbose = verbose
+	}
+}
+
+// WithOutput sets where verbose output is written. Defaults to os.Stdout.
+func WithOutput(w io.Writer) Option {
+	return func(e *Engine) {
+		e.out = w
+	}
+}
+
+// WithErrorOutput sets where problems the user must hear about, even
+// without WithVerbose, are written. Defaults to os.Stderr.
+func WithErrorOutput(w io.Writer) Option {
+	return func(e *Engine) {
+		e.errOut = w
+	}
+}
+
+// WithQueueSize sets how many sounds may wait to be played. Defaults to
+// DefaultQueueSize.
+func WithQueueSize(size int) Option {
+	return func(e *Engine) {
+		e.queueSize = size
+	}
+}
+
+// WithOverflowPolicy sets what happens to sounds typed while the queue is
+// full. Defaults to DropNewest.
+func WithOverflowPolicy(policy OverflowPolicy) Option {
+	return func(e *Engine) {
+		e.overflow = policy
+	}
+}
+
+// WithRepeatCoalescing collapses presses of the same letter that arrive
+// within window of each other into a single playback. When announce is set
+// the number of presses is spoken afterwards ("b, three times").
+func WithRepeatCoalescing(window time.Duration, announce bool) Option {
+	return func(e *Engine) {
+		e.coalesceWindow = window
+		e.announceRepeats = announce
+	}
+}
+
+// WithAnnouncer sets how phrases are spoken. Defaults to SystemAnnouncer.
+func WithAnnouncer(a Announcer) Option {
+	return func(e *Engine) {
+		e.announcer = a
+	}
+}
+
+// WithKeyClick layers a soft percussive click under every key press,
+// including keys with no phoneme, at volume between 0 and 1. A volume of 0
+// disables the click.
+func WithKeyClick(volume float64) Option {
+	return func(e *Engine) {
+		e.clickVolume = volume
+	}
+}
+
+// WithVolume sets the level of letter sounds from 0 to 1. Spoken phrases
+// use the system voice's own volume.
+func WithVolume(volume float64) Option {
+	return func(e *Engine) {
+		e.volume = volume
+	}
+}
+
+// WithPack sets the sounds to play. Defaults to DefaultPack.
+func WithPack(p *Pack) Option {
+	return func(e *Engine) {
+		e.pack.Store(p)
+	}
+}
+
+// WithCues plays a soft non-spoken cue for space and Enter so word
+// boundaries get feedback. Packs may provide their own cue recordings.
+func WithCues(enabled bool) Option {
+	return func(e *Engine) {
+		e.cues = enabled
+	}
+}
+
+// WithRemovalAnnouncements says which letter Backspace or Delete took out
+// of the current word ("took away t").
+func WithRemovalAnnouncements(enabled bool) Option {
+	return func(e *Engine) {
+		e.announceRemovals = enabled
+	}
+}
+
+// WithNavigationNames names navigation keys ("up", "tab", "escape") as
+// they are pressed, for children learning to use a computer.
+func WithNavigationNames(enabled bool) Option {
+	return func(e *Engine) {
+		e.navigation = enabled
+	}
+}
+
+// WithRhymes says a couple of rhyming words after a completed word is
+// found in the bundled rhyme dictionary ("cat rhymes with hat and mat"),
+// no more often than once per interval.
+func WithRhymes(enabled bool, interval time.Duration) Option {
+	return func(e *Engine) {
+		e.rhymes = enabled
+		e.rhymeInterval = interval
+	}
+}
+
+// WithMode sets what is played when a word is completed. Defaults to
+// ModeLetters.
+func WithMode(m Mode) Option {
+	return func(e *Engine) {
+		e.mode = m
+	}
+}
+
+// WithSyllableClaps plays a clap between syllables in ModeSyllables instead
+// of a silent gap.
+func WithSyllableClaps(enabled bool) Option {
+	return func(e *Engine) {
+		e.claps = enabled
+	}
+}
+
+// WithStats records practice in store: letter presses during normal typing
+// and results of activities such as games.
+func WithStats(store *StatsStore) Option {
+	return func(e *Engine) {
+		e.stats = store
+	}
+}
+
+// WithFocus concentrates practice on letters: they play as usual, while
+// every other letter plays only a brief muted tick. No letters disables
+// focus mode.
+func WithFocus(letters ...rune) Option {
+	return func(e *Engine) {
+		e.focus = make(map[rune]bool, len(letters))
+		for _, r := range letters {
+			e.focus[unicode.ToLower(r)] = true
+		}
+	}
+}
+
+// WithAdaptive emphasizes the letters the stats show most need practice and
+// occasionally asks the child to find them, scheduling reviews with simple
+// spaced repetition. It requires WithStats.
+func WithAdaptive(cfg Adaptive) Option {
+	return func(e *Engine) {
+		e.adaptive = &adaptive{cfg: cfg}
+	}
+}
+
+// WithFirstLetterCelebration plays an extended version of a letter, with a
+// sparkle and its association word, the first time the profile ever
+// presses it. It requires WithStats.
+func WithFirstLetterCelebration(enabled bool) Option {
+	return func(e *Engine) {
+		e.celebrate = enabled
+	}
+}
+
+// WithCurriculum follows a teaching order: letters taught up to level play
+// in full using the curriculum's variant sounds, and later letters play only
+// a quiet tick so children meet sounds in the programme's order.
+func WithCurriculum(c *Curriculum, level int) Option {
+	return func(e *Engine) {
+		e.curriculum = c
+		e.level = level
+	}
+}
+
+// WithVoice plays letter sounds through a fun voice effect.
+func WithVoice(v Voice) Option {
+	return func(e *Engine) {
+		e.voice = v
+	}
+}
+
+// WithVoiceHotkey lets F9 switch between the voices while typing.
+func WithVoiceHotkey(enabled bool) Option {
+	return func(e *Engine) {
+		e.voiceHotkey = enabled
+	}
+}
+
+// WithKeyPanning places each letter's sound left or right to match where
+// its key sits on the keyboard, so q sounds from the left and p from the
+// right.
+func WithKeyPanning(enabled bool) Option {
+	return func(e *Engine) {
+		e.panning = enabled
+	}
+}
+
+// OnLetter registers a callback run for every mapped letter pressed.
+func OnLetter(fn func(LetterEvent)) Option {
+	return func(e *Engine) {
+		e.onLetter = append(e.onLetter, fn)
+	}
+}
+
+// OnWord registers a callback run whenever a word is completed.
+func OnWord(fn func(WordEvent)) Option {
+	return func(e *Engine) {
+		e.onWord = append(e.onWord, fn)
+	}
+}
+
+// OnPlay registers a callback run on the player as each sound starts, in
+// the order they are heard, as an EventSink. It must return quickly.
+func OnPlay(fn func(PlayEvent)) Option {
+	return WithSink(SinkFunc(fn))
+}
+
+// OnSessionEnd registers a callback run once when the engine stops.
+func OnSessionEnd(fn func(SessionSummary)) Option {
+	return func(e *Engine) {
+		e.onSessionEnd = append(e.onSessionEnd, fn)
+	}
+}
+
+// Engine listens for key presses and plays the matching phonics sounds.
+type Engine struct {
+	verbose   bool
+	out       io.Writer
+	errOut    io.Writer
+	traceSize int
+	trace     *traceRing
+
+	onLetter     []func(LetterEvent)
+	onWord       []func(WordEvent)
+	onSessionEnd []func(SessionSummary)
+	sinks        []EventSink
+	sinksPlaced  bool
+
+	queueSize        int
+	overflow         OverflowPolicy
+	coalesceWindow   time.Duration
+	announceRepeats  bool
+	announceRemovals bool
+	announcer        Announcer
+	clickVolume      float64
+	volume           float64
+	languages        []*Pack
+	second           *Pack
+	kana             string
+	pinyin           bool
+	maxLength        time.Duration
+	crossfade        time.Duration
+	outputLatency    time.Duration
+	soundCache       string
+	onPreload        func(done, total int)
+	mono             bool
+	decoding         decoding
+	helpHotkey       bool
+	notifier         Notifier
+	bilingual        Bilingual
+	cues             bool
+	stats            *StatsStore
+	focus            map[rune]bool
+	adaptive         *adaptive
+	popQuizEvery     time.Duration
+	profiles         []string
+	guestHotkey      bool
+	timeOfDay        bool
+	theme            *Pack
+	echoVolume       float64
+	nextPopQuiz      time.Time
+	celebrate        bool
+	curriculum       *Curriculum
+	level            int
+	active           map[rune]bool
+	variant          string
+	navigation       bool
+	claps            bool
+	boxGap           time.Duration
+	rhymes           bool
+	rhymeInterval    time.Duration
+	voiceHotkey      bool
+	panning          bool
+	visual           bool
+	silent           bool
+	deadKeys         bool
+	emoji            bool
+	fingerHints      map[rune]bool
+	speedInterval    time.Duration
+	respectDND       bool
+	pauseOnCalls     bool
+	reinitBackoff    time.Duration
+	maxReinitBackoff time.Duration
+	hookRetry        time.Duration
+	maxHookRetry     time.Duration
+	audioFallback    func(error)
+	watchdog         time.Duration
+	unknown          string
+	transcript       *transcript
+	targets          *targets
+	slowMod          Modifier
+
+	sink  AudioSink
+	clock Clock
+	// pack is swapped by SetPack while the engine runs.
+	pack      atomic.Pointer[Pack]
+	playQueue *soundQueue
+	// slowed holds slowed-down sounds, made as each is first needed.
+	slowed slowCache
+	// ctx is cancelled by Stop, ending the player, preloader and input.
+	ctx       context.Context
+	cancel    context.CancelFunc
+	stopOnce  sync.Once
+	startOnce sync.Once
+	startErr  error
+
+	mu        sync.Mutex
+	word      wordBuffer
+	lastWord  string
+	reopened  bool // editing lastWord again, so it isn't counted twice
+	lastRhyme time.Time
+	session   SessionSummary
+	tutor     *Tutor
+	voice     Voice
+	mode      Mode
+	composer  composer
+	alternate bool
+	romaji    romajiComposer
+	syllable  string
+	profile   string
+	speed     speedTracker
+	// interrupt cancels the sound playing now, if any.
+	interrupt context.CancelFunc
+	dnd       atomic.Bool
+	guest     atomic.Bool
+	onCall    atomic.Bool
+
+	// tailPlaying is set while the end of the last sound is still fading
+	// out on the mixer. Only the player goroutine uses it.
+	tailPlaying bool
+
+	// outputLevel holds the float64 bits of the playing sound's peak level.
+	outputLevel atomic.Uint64
+	keysHandled atomic.Int64
+	hookKeys    atomic.Int64
+	// hookFailures counts the keyboard hook's failed starts.
+	hookFailures atomic.Int64
+	// heard counts sounds that played through without failing, for the
+	// watchdog, which sets restartAudio for the player when they stop.
+	heard        atomic.Int64
+	restartAudio atomic.Bool
+	metrics      metrics
+	keyLog       *keyLog
+}
+
+// New creates an Engine configured by opts.
+func New(opts ...Option) (*Engine, error) {
+	e := &Engine{
+		out:       os.Stdout,
+		errOut:    os.Stderr,
+		queueSize: DefaultQueueSize,
+		traceSize: DefaultTraceSize,
+		boxGap:    DefaultBoxGap,
+		announcer: SystemAnnouncer{},
+		volume:    1,
+		sink:      speakerSink{buffer: speakerBuffer},
+		clock:     systemClock{},
+
+		reinitBackoff:    DefaultReinitBackoff,
+		maxReinitBackoff: DefaultMaxReinitBackoff,
+		hookRetry:        DefaultHookRetry,
+		maxHookRetry:     DefaultMaxHookRetry,
+		watchdog:         DefaultWatchdog,
+		unknown:          UnknownSilent,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	if e.traceSize < 0 {
+		return nil, fmt.Errorf("trace size must not be negative, got %d", e.traceSize)
+	}
+	if e.traceSize > 0 {
+		e.trace = newTraceRing(e.traceSize)
+	}
+	if e.pack.Load() == nil {
+		e.pack.Store(DefaultPack())
+	}
+	e.decoding.mono = e.mono
+	if e.soundCache != "" {
+		cache, err := openPCMCache(e.soundCache)
+		if err != nil {
+			return nil, fmt.Errorf("sound cache: %w", err)
+		}
+		e.decoding.disk = cache
+	}
+	if _, ok := e.sink.(speakerSink); ok && e.outputLatency > 0 {
+		e.sink = speakerSink{buffer: speakerBufferFor(e.outputLatency)}
+	}
+	if len(e.languages) > 0 {
+		e.languages = append([]*Pack{e.pack.Load()}, e.languages...)
+	}
+	e.placeSinks()
+	if e.clickVolume < 0 || e.clickVolume > 1 {
+		return nil, fmt.Errorf("key click volume must be between 0 and 1, got %g", e.clickVolume)
+	}
+	if e.volume < 0 || e.volume > 1 {
+		return nil, fmt.Errorf("volume must be between 0 and 1, got %g", e.volume)
+	}
+	if e.boxGap < 0 {
+		return nil, fmt.Errorf("box gap must not be negative, got %s", e.boxGap)
+	}
+	if e.queueSize < 1 {
+		return nil, fmt.Errorf("queue size must be at least 1, got %d", e.queueSize)
+	}
+	if err := e.checkHookRetry(); err != nil {
+		return nil, err
+	}
+	if err := e.checkUnknown(); err != nil {
+		return nil, err
+	}
+	if err := e.checkBackoff(); err != nil {
+		return nil, err
+	}
+	if err := e.checkKana(); err != nil {
+		return nil, err
+	}
+	if err := e.checkEcho(); err != nil {
+		return nil, err
+	}
+	if err := e.checkProfiles(); err != nil {
+		return nil, err
+	}
+	if err := e.checkTargets(); err != nil {
+		return nil, err
+	}
+
+	var stages []queueStage
+	if e.coalesceWindow > 0 {
+		stages = append(stages, coalesceRepeats(e.coalesceWindow))
+	}
+	if e.curriculum != nil {
+		if e.level < 1 {
+			return nil, fmt.Errorf("curriculum level must be at least 1, got %d", e.level)
+		}
+		e.active, _ = e.curriculum.Active(e.level)
+		e.variant = e.curriculum.Variant
+	}
+	e.ctx, e.cancel = context.WithCancel(context.Background())
+	e.playQueue = newSoundQueue(e.queueSize, e.overflow, stages...)
+	e.session.Start = e.clock.Now()
+	if e.adaptive != nil {
+		e.adaptive.lastPrompt = e.session.Start
+		e.refreshEmphasis(e.session.Start)
+	}
+	if e.popQuizEvery > 0 {
+		e.nextPopQuiz = e.session.Start.Add(e.popQuizGap())
+	}
+	return e, nil
+}
+
+// Start initializes audio, preloads sounds and starts the player. It is
+// called by Run; embedders feeding keys through HandleKey call it directly.
+func (e *Engine) Start() error {
+	e.startOnce.Do(func() {
+		if !e.silent {
+			if err := e.initSink(); err != nil {
+				e.startErr = err
+				return
+			}
+
+			// Preload all sounds for faster playback
+			e.preloadSounds(e.ctx)
+		}
+
+		go e.soundPlayer(e.ctx)
+		if e.watchdog > 0 && !e.silent {
+			go e.watch(e.ctx)
+		}
+		if e.respectDND {
+			go e.watchState("Do Not Disturb", dndPollInterval, DoNotDisturb, &e.dnd)
+		}
+		if e.pauseOnCalls {
+			go e.watchState("microphone in use", micPollInterval, MicrophoneInUse, &e.onCall)
+		}
+		if e.timeOfDay {
+			e.greet(e.clock.Now())
+		}
+	})
+	return e.startErr
+}
+
+// Stop ends Run and reports the session to OnSessionEnd callbacks. It is
+// safe to call more than once.
+func (e *Engine) Stop() {
+	e.stopOnce.Do(func() {
+		e.cancel()
+		e.endWord()
+
+		e.mu.Lock()
+		e.session.End = e.clock.Now()
+		summary := e.session
+		e.mu.Unlock()
+		if e.transcript != nil {
+			e.transcript.close()
+		}
+
+		for _, fn := range e.onSessionEnd {
+			fn(summary)
+		}
+		e.closeSinks()
+	})
+}
+
+// HandleKey processes a single key press as if it had been typed.
+func (e *Engine) HandleKey(key Key) {
+	e.keysHandled.Add(1)
+	if e.keyLog != nil {
+		e.keyLog.record(key)
+	}
+	slow := e.slowMotion(&key)
+	if key.Char == 0 {
+		if c, ok := fallbackChar(key); ok {
+			e.debugf("No character for keycode %#x, using %c\n", key.Code, c)
+			key.Char = c
+		}
+	}
+	if e.clickVolume > 0 && !e.muted() {
+		e.click()
+	}
+
+	if e.deadKeys && key.Char != 0 {
+		e.mu.Lock()
+		c, ok := e.composer.feed(unicode.ToLower(key.Char))
+		e.mu.Unlock()
+		if !ok {
+			e.debugf("Dead key %c, waiting for its letter\n", key.Char)
+			return
+		}
+		key.Char = c
+	}
+
+	if e.routeToActivity(key) {
+		return
+	}
+
+	if key.Code == KeyF1 && e.helpHotkey {
+		e.announceHelp()
+		return
+	}
+	if key.Code == KeyF9 && e.voiceHotkey {
+		e.nextVoice()
+		return
+	}
+	if key.Code == KeyF10 && len(e.languages) > 0 {
+		e.nextLanguage()
+		return
+	}
+	if key.Code == KeyF11 && len(e.profiles) > 0 {
+		e.nextProfile()
+		return
+	}
+	if key.Code == KeyF12 && e.guestHotkey {
+		e.SetGuest(!e.Guest())
+		return
+	}
+
+	if name, ok := navigationNames[key.Code]; ok && e.navigation {
+		e.playNavigation(name)
+	}
+
+	if e.editWord(key) {
+		return
+	}
+
+	if key.Char == 0 {
+		return
+	}
+
+	// Convert to lowercase for our map
+	shifted := unicode.IsUpper(key.Char)
+	char := unicode.ToLower(key.Char)
+	if char == variationSelector || isSilentMark(char) {
+		return
+	}
+	if e.emoji && e.playEmoji(char) {
+		return
+	}
+	if e.pinyin && e.typePinyin(char) {
+		return
+	}
+	if e.kana != "" {
+		if char >= 'a' && char <= 'z' {
+			e.typeRomaji(char)
+			return
+		}
+		e.flushRomaji()
+	}
+
+	soundFile, exists := e.pack.Load().letterSound(char, e.variant)
+	if !exists {
+		e.endWord()
+		if cue := cueFor(char); cue != "" {
+			if e.cues {
+				e.playCue(cue)
+			}
+		} else {
+			e.unknownChar(char)
+		}
+		return
+	}
+
+	now := e.clock.Now()
+	e.mu.Lock()
+	e.word.insert(char)
+	typed := e.word.beforeCursor()
+	e.session.Letters++
+	e.speed.press(now)
+	e.mu.Unlock()
+
+	unit := string(char)
+	if g, file, ok := e.pack.Load().graphemeEnding(typed); ok {
+		e.debugf("Grapheme %s completed\n", g)
+		soundFile, unit = file, strings.ToLower(g)
+	}
+	e.debugf("Key pressed: %c - Playing: %s\n", char, soundFile)
+
+	first := false
+	if stats := e.recordingStats(); stats != nil {
+		stats.Update(func(st *Stats) {
+			first = st.firstPress(char, now)
+			st.Letters[string(char)]++
+		})
+	}
+	e.transcribe(char, soundFile, now)
+
+	ev := LetterEvent{Letter: char, Sound: soundFile, Time: now}
+	for _, fn := range e.onLetter {
+		fn(ev)
+	}
+	if e.visual {
+		e.flashLetter(char)
+	}
+
+	if first && e.celebrate {
+		e.celebrateFirst(char, soundFile)
+		return
+	}
+
+	item := &queuedSound{sound: soundFile, letter: char, at: ev.Time, typed: true}
+	if e.isTarget(unit, now) {
+		item.stretch = targetStretch
+	}
+	if slow {
+		item.stretch = slowMotionStretch
+	}
+	if pan, ok := keyPan(key); ok && e.panning {
+		item.pan = pan
+	}
+	outside := len(e.focus) > 0 && !e.focus[char] || e.active != nil && !e.active[char]
+	if outside {
+		item.buffer = mutedCue()
+	}
+	second, replace, bilingual := e.secondSound(char, shifted)
+	if bilingual && !outside && replace {
+		item.sound, item.pack = second, e.second
+	}
+	e.enqueue(item)
+	if bilingual && !outside && !replace {
+		e.enqueue(&queuedSound{sound: second, letter: char, pack: e.second, pan: item.pan})
+	}
+	if e.adaptLetter(char, ev.Time) {
+		e.enqueuePause(150 * time.Millisecond)
+		e.enqueueUnit(string(char))
+	}
+	e.hintFinger(char, key.Code)
+}
+
+// enqueue adds item to the play queue, logging anything the overflow
+// policy discards.
+func (e *Engine) enqueue(item *queuedSound) {
+	if item.at.IsZero() {
+		item.at = e.clock.Now()
+	}
+	if item.pack == nil {
+		item.pack = e.pack.Load()
+	}
+	if queued, dropped := e.playQueue.push(item); !queued {
+		e.logf("Sound queue full, skipping %s", item.sound)
+		e.metrics.queueDrops.Add(1)
+	} else if dropped != "" {
+		e.logf("Sound queue full, dropped %s", dropped)
+		e.metrics.queueDrops.Add(1)
+	}
+}
+
+// editWord applies Backspace, Delete and cursor keys to the word buffer,
+// reporting whether key was an editing key.
+func (e *Engine) editWord(key Key) bool {
+	var removed rune
+	var ok bool
+	e.mu.Lock()
+	switch {
+	case key.Code == KeyBackspace || key.Char == '\b':
+		if e.word.len() == 0 && e.lastWord != "" {
+			// Backspacing over the boundary after a word resumes editing it
+			e.word.set(e.lastWord)
+			e.lastWord = ""
+			e.reopened = true
+			break
+		}
+		removed, ok = e.word.backspace()
+	case key.Code == KeyDelete || key.Char == 0x7f:
+		removed, ok = e.word.delete()
+	case key.Code == KeyLeft || key.Code == KeyRight:
+		// Arrows follow the screen, so in right-to-left text the left
+		// arrow moves on through the word
+		step := 1
+		if (key.Code == KeyLeft) != isRTL(e.word.runes) {
+			step = -1
+		}
+		e.word.move(step)
+		e.mu.Unlock()
+		return true
+	case key.Code == KeyHome:
+		e.word.move(-e.word.len())
+		e.mu.Unlock()
+		return true
+	case key.Code == KeyEnd:
+		e.word.move(e.word.len())
+		e.mu.Unlock()
+		return true
+	default:
+		e.mu.Unlock()
+		return false
+	}
+	word := e.word.String()
+	e.mu.Unlock()
+
+	// Load and queue the sounds after unlocking, so a slow cue file
+	// doesn't hold up everything else waiting on e.mu
+	if e.cues {
+		e.playCue(CueBackspace)
+	}
+	if ok {
+		e.debugf("Removed %c, word is now %q\n", removed, word)
+		if e.announceRemovals {
+			e.enqueueSay(fmt.Sprintf("took away %c", removed))
+		}
+	}
+	return true
+}
+
+// cueFor returns the cue name for a word-boundary character, if any.
+func cueFor(char rune) string {
+	switch char {
+	case ' ':
+		return CueSpace
+	case '\r', '\n':
+		return CueEnter
+	}
+	return ""
+}
+
+// playCue queues the pack's recording of cue, or the built-in one.
+func (e *Engine) playCue(cue string) {
+	buffer := builtinCue(cue)
+	for _, pack := range e.layers() {
+		file, ok := pack.Cues[cue]
+		if !ok {
+			continue
+		}
+		loaded, err := pack.loadWith(file, e.decoding)
+		if err != nil {
+			e.logf("Failed to load cue %s: %v", file, err)
+			e.metrics.decodeErrors.Add(1)
+			continue
+		}
+		buffer = loaded
+		break
+	}
+
+	e.debugf("Playing %s cue\n", cue)
+	e.enqueue(&queuedSound{sound: "cue:" + cue, buffer: buffer, cue: true})
+}
+
+// playNavigation queues the pack's recording of a navigation key name, or
+// speaks the name when the pack has none.
+func (e *Engine) playNavigation(name string) {
+	e.debugf("Navigation key: %s\n", name)
+	if file, ok := e.pack.Load().Navigation[name]; ok {
+		e.enqueue(&queuedSound{sound: file})
+		return
+	}
+	e.enqueueSay(name)
+}
+
+// endWord reports the buffered word, if any, to OnWord callbacks. A word
+// reopened with Backspace and finished again is not reported twice.
+func (e *Engine) endWord() {
+	e.mu.Lock()
+	reopened := e.reopened
+	e.reopened = false
+	if e.word.len() == 0 {
+		e.mu.Unlock()
+		return
+	}
+	ev := WordEvent{Word: e.word.String(), Time: e.clock.Now()}
+	e.lastWord = ev.Word
+	e.word.reset()
+	if !reopened {
+		e.session.Words++
+	}
+	e.mu.Unlock()
+
+	if !reopened {
+		for _, fn := range e.onWord {
+			fn(ev)
+		}
+	}
+
+	e.playWord(ev.Word)
+	e.echoWord(ev.Word)
+	if e.rhymes {
+		e.suggestRhymes(ev)
+	}
+	e.maybePrompt(ev.Time)
+	e.maybePopQuiz(ev.Time)
+	e.announceSpeed(ev.Time)
+}
+
+// suggestRhymes queues rhymes for a completed word, rate limited by the
+// rhyme interval.
+func (e *Engine) suggestRhymes(ev WordEvent) {
+	e.mu.Lock()
+	if !e.lastRhyme.IsZero() && ev.Time.Sub(e.lastRhyme) < e.rhymeInterval {
+		e.mu.Unlock()
+		return
+	}
+	rhymes := rhymesFor(ev.Word, 2)
+	if len(rhymes) == 0 {
+		e.mu.Unlock()
+		return
+	}
+	e.lastRhyme = ev.Time
+	e.mu.Unlock()
+
+	phrase := rhymePhrase(ev.Word, rhymes)
+	e.debugf("Rhymes: %s\n", phrase)
+	e.enqueueSay(phrase)
+}
+
+func (e *Engine) soundPlayer(ctx context.Context) {
+	for {
+		select {
+		case <-e.playQueue.pending:
+			if item, ok := e.playQueue.pop(); ok {
+				itemCtx, cancel := context.WithCancel(ctx)
+				e.mu.Lock()
+				e.interrupt = cancel
+				e.mu.Unlock()
+
+				e.playItem(itemCtx, item)
+
+				e.mu.Lock()
+				e.interrupt = nil
+				e.mu.Unlock()
+				cancel()
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Interrupt cuts off the sound playing now and drops those waiting, for
+// pausing or switching profiles without stopping the engine.
+func (e *Engine) Interrupt() {
+	e.playQueue.clear()
+	e.mu.Lock()
+	if e.interrupt != nil {
+		e.interrupt()
+	}
+	e.mu.Unlock()
+}
+
+func (e *Engine) playItem(ctx context.Context, item *queuedSound) {
+	if e.restartAudio.Swap(false) {
+		e.recoverSpeaker(ctx, errSilent)
+	}
+	if e.visual && item.say != "" {
+		e.caption(item.say)
+	}
+	if item.pause > 0 {
+		e.sleep(ctx, item.pause)
+	} else {
+		<-e.tellSinks(ctx, item)
+	}
+
+	count := e.playQueue.finish(item)
+	if count > 1 {
+		e.debugf("Coalesced %d presses of %c\n", count, item.letter)
+		if e.announceRepeats {
+			e.say(ctx, repeatPhrase(item.letter, count))
+		}
+	}
+}
+
+// playAudio plays item, or waits as long as a phrase would take when
+// muted.
+func (e *Engine) playAudio(ctx context.Context, item *queuedSound) {
+	switch {
+	case e.muted():
+		if item.say != "" {
+			e.sleep(ctx, silentSayTime)
+		}
+	case item.say != "":
+		e.say(ctx, item.say)
+	case item.buffer != nil:
+		e.playVoiced(ctx, item, item.buffer)
+	default:
+		e.playSound(ctx, item)
+	}
+}
+
+// click plays the key-click layer.
+func (e *Engine) click() {
+	if err := e.sink.Init(); err != nil {
+		e.logf("Failed to initialize speaker: %v", err)
+		return
+	}
+	click := keyClick()
+	e.sink.Layer(&effects.Gain{Streamer: click.Streamer(0, click.Len()), Gain: e.clickVolume - 1})
+}
+
+// say speaks text through the configured announcer, cutting it short when
+// ctx is cancelled if the announcer allows.
+func (e *Engine) say(ctx context.Context, text string) {
+	if e.announcer == nil || ctx.Err() != nil {
+		return
+	}
+	var err error
+	if a, ok := e.announcer.(ContextAnnouncer); ok {
+		err = a.SayContext(ctx, text)
+	} else {
+		err = e.announcer.Say(text)
+	}
+	if err != nil && ctx.Err() == nil {
+		e.logf("Failed to announce %q: %v", text, err)
+	} else if err == nil {
+		e.heard.Add(1)
+	}
+}
+
+func (e *Engine) playSound(ctx context.Context, item *queuedSound) {
+	buffer, err := item.pack.loadWith(item.sound, e.decoding)
+	if err != nil {
+		e.logf("Failed to load sound %s: %v", item.sound, err)
+		e.metrics.decodeErrors.Add(1)
+		// Something still plays so the child isn't left wondering
+		buffer = fallbackTone()
+	}
+
+	if err := e.sink.Init(); err != nil {
+		e.logf("Failed to initialize speaker: %v", err)
+		e.recoverSpeaker(ctx, err)
+		return
+	}
+
+	if item.stretch > 1 && buffer != fallbackTone() {
+		buffer = e.slowed.get(buffer, item.stretch)
+	}
+	e.playVoiced(ctx, item, buffer)
+}
+
+// playVoiced plays buffer for item through the current voice effect,
+// panned by item.pan. When crossfading, the end of a letter sound is left
+// fading out on the mixer as soon as the next one is waiting.
+func (e *Engine) playVoiced(ctx context.Context, item *queuedSound, buffer *beep.Buffer) {
+	rate := buffer.Format().SampleRate
+	n := buffer.Len()
+	var streamer, tail beep.Streamer
+	if item.buffer == nil {
+		n = e.soundLength(item, buffer)
+		fade := rate.N(e.crossfade)
+		if fade > 0 && n > 2*fade && e.playQueue.crossfadeNext() {
+			tail = fadeOut(buffer.Streamer(n-fade, n), fade, fade)
+			n -= fade
+		}
+	}
+	streamer = buffer.Streamer(0, n)
+	if tail == nil && n < buffer.Len() {
+		streamer = fadeOut(streamer, n, rate.N(limitFade))
+	}
+	if e.tailPlaying && item.buffer == nil {
+		streamer = fadeIn(streamer, rate.N(e.crossfade))
+	}
+	e.tailPlaying = false
+
+	e.tracef("Sound %s started", item.sound)
+	meter := &levelMeter{Streamer: e.voiced(item, streamer), level: &e.outputLevel}
+	err := e.sink.Play(ctx, Playback{Name: item.sound, Letter: item.letter, Streamer: meter})
+	e.outputLevel.Store(0)
+	if err != nil && ctx.Err() == nil {
+		e.recoverSpeaker(ctx, err)
+		return
+	}
+	if err == nil && buffer != fallbackTone() {
+		e.heard.Add(1)
+	}
+	if tail != nil && ctx.Err() == nil {
+		e.sink.Layer(e.voiced(item, tail))
+		e.tailPlaying = true
+	}
+}
+
+// voiced applies the current voice, item's pan and volume and the engine's
+// volume to streamer.
+func (e *Engine) voiced(item *queuedSound, streamer beep.Streamer) beep.Streamer {
+	streamer = e.currentVoice().apply(streamer)
+	if item.pan != 0 {
+		streamer = &effects.Pan{Streamer: streamer, Pan: item.pan}
+	}
+	volume := e.volume
+	if item.volume > 0 {
+		volume *= item.volume
+	}
+	if volume < 1 {
+		streamer = &effects.Gain{Streamer: streamer, Gain: volume - 1}
+	}
+	return streamer
+}
+
+// sleep waits for d on the engine's clock, or until ctx is cancelled.
+func (e *Engine) sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-e.clock.After(d):
+	case <-ctx.Done():
+	}
+}
+
+// debugf writes verbose progress output and records it for Trace.
+func (e *Engine) debugf(format string, args ...interface{}) {
+	e.tracef(format, args...)
+	if e.verbose {
+		fmt.Fprintf(e.out, format, args...)
+	}
+}
+
+// logf logs a verbose-only error and records it for Trace.
+func (e *Engine) logf(format string, args ...interface{}) {
+	e.tracef(format, args...)
+	if e.verbose {
+		log.Printf(format, args...)
+	}
+}
@@ -0,0 +1,20 @@
+package phonical_test
+
+import (
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestWordBoundaryCues(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithCues(true))
+	h.Type("a b\n")
+	h.Expect("a.wav", "cue:space", "b.wav", "cue:enter")
+}
+
+func TestNoCuesByDefault(t *testing.T) {
+	h := phonicaltest.New(t)
+	h.Type("a b\n")
+	h.Expect("a.wav", "b.wav")
+}
@@ -0,0 +1,222 @@
+package phonical
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// EventSink is a feedback channel told about each sound as it starts
+// playing, such as live captions, a companion app or keyboard lights. The
+// engine runs its sinks in the order they were added, on the player, so
+// they must return quickly. A sink that is also an io.Closer is closed when
+// the engine stops.
+//
+// The engine's own outputs are sinks too, AudioOutput and StatsOutput.
+// Unless WithSinks places them, the stats come before the sinks added with
+// WithSink and the sound after them.
+type EventSink interface {
+	Played(ev PlayEvent)
+}
+
+// builtinSink stands for one of the engine's own outputs among its sinks.
+type builtinSink int
+
+const (
+	audioOutput builtinSink = iota
+	statsOutput
+)
+
+// Played does nothing; the engine runs its outputs itself.
+func (builtinSink) Played(PlayEvent) {}
+
+// AudioOutput is the sink that plays each sound. The player waits for the
+// sound to finish once every sink has been told about it. Leaving it out
+// of WithSinks plays nothing, like WithSilent.
+func AudioOutput() EventSink {
+	return audioOutput
+}
+
+// StatsOutput is the sink that counts the sounds played and how long each
+// waited to start, as reported by WriteMetrics.
+func StatsOutput() EventSink {
+	return statsOutput
+}
+
+// SinkFunc lets an ordinary function be an EventSink.
+type SinkFunc func(PlayEvent)
+
+// Played calls f.
+func (f SinkFunc) Played(ev PlayEvent) {
+	f(ev)
+}
+
+// WithSink adds sink to the end of the engine's sinks.
+func WithSink(sink EventSink) Option {
+	return func(e *Engine) {
+		e.sinks = append(e.sinks, sink)
+	}
+}
+
+// WithSinks replaces the engine's sinks, including its own outputs, with
+// sinks in order: AudioOutput and StatsOutput play and count sounds where
+// they are listed, and an output left out is turned off.
+func WithSinks(sinks ...EventSink) Option {
+	return func(e *Engine) {
+		e.sinks = sinks
+		e.sinksPlaced = true
+	}
+}
+
+// placeSinks puts the engine's own outputs around the sinks added with
+// WithSink, unless WithSinks placed them.
+func (e *Engine) placeSinks() {
+	if !e.sinksPlaced {
+		e.sinks = append(append([]EventSink{statsOutput}, e.sinks...), audioOutput)
+	}
+}
+
+// tellSinks runs item through the engine's sinks in order and returns a
+// channel closed once its sound, started by AudioOutput, has finished.
+func (e *Engine) tellSinks(ctx context.Context, item *queuedSound) <-chan struct{} {
+	done := make(chan struct{})
+	playing := false
+	ev := PlayEvent{Sound: item.sound, Letter: item.letter, Text: item.say, Time: e.clock.Now(), Queued: item.at}
+	for _, sink := range e.sinks {
+		switch sink {
+		case audioOutput:
+			if !playing {
+				playing = true
+				go func() {
+					defer close(done)
+					e.playAudio(ctx, item)
+				}()
+			}
+		case statsOutput:
+			e.observeLatency(item)
+			e.metrics.soundsPlayed.Add(1)
+		default:
+			sink.Played(ev)
+		}
+	}
+	if !playing {
+		close(done)
+	}
+	return done
+}
+
+// closeSinks closes the sinks that need it once the engine stops.
+func (e *Engine) closeSinks() {
+	for _, sink := range e.sinks {
+		if c, ok := sink.(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				e.logf("Closing %T: %v", sink, err)
+			}
+		}
+	}
+}
+
+// Sink types for SinkConfig. SinkAudio and SinkStats are the engine's own
+// outputs, AudioOutput and StatsOutput.
+const (
+	SinkAudio     = "audio"
+	SinkCaptions  = "captions"
+	SinkCompanion = "companion"
+	SinkOpenRGB   = "openrgb"
+	SinkOverlay   = "overlay"
+	SinkStats     = "stats"
+)
+
+// SinkTypes lists the sink types the settings file can turn on.
+func SinkTypes() []string {
+	return []string{SinkAudio, SinkCaptions, SinkCompanion, SinkOpenRGB, SinkOverlay, SinkStats}
+}
+
+// SinkConfig is one [[sink]] entry of the settings file, turning on a
+// feedback channel. Sinks are told about each sound in the order listed;
+// unless listed, the stats come first and the sound itself last:
+//
+//	[[sink]]
+//	type = "stats"
+//	disabled = true
+//
+//	[[sink]]
+//	type = "captions"
+//	address = "localhost:8765"
+//	file = "/home/me/captions.txt"
+//
+//	[[sink]]
+//	type = "openrgb"
+//
+//	[[sink]]
+//	type = "overlay"
+//	address = "localhost:8766"
+//	monitor = 2
+//	corner = "top-right"
+//	theme = "high-contrast"
+//	font = "opendyslexic"
+type SinkConfig struct {
+	// Type is one of SinkTypes.
+	Type string `toml:"type"`
+	// Address is where captions, the companion server or the overlay are
+	// served, or the OpenRGB server to connect to (default
+	// DefaultOpenRGBAddr).
+	Address string `toml:"address,omitempty"`
+	// File is kept holding the current caption.
+	File string `toml:"file,omitempty"`
+	// Token is the one the companion app must give; empty makes a new one
+	// each run.
+	Token string `toml:"token,omitempty"`
+	// Disabled turns the sink off while keeping its settings.
+	Disabled bool `toml:"disabled,omitempty"`
+	// OverlaySettings places the overlay and sets its look.
+	OverlaySettings
+}
+
+// check reports what is wrong with sc, if anything.
+func (sc SinkConfig) check() error {
+	switch sc.Type {
+	case SinkCaptions:
+		if sc.Address == "" && sc.File == "" {
+			return fmt.Errorf("captions need an address or a file")
+		}
+	case SinkCompanion:
+		if sc.Address == "" {
+			return fmt.Errorf("the companion server needs an address")
+		}
+	case SinkOverlay:
+		if sc.Address == "" {
+			return fmt.Errorf("the overlay needs an address")
+		}
+		if err := sc.OverlaySettings.check(); err != nil {
+			return err
+		}
+	case SinkAudio, SinkStats:
+		if sc.Address != "" {
+			return fmt.Errorf("address is not used by %s", sc.Type)
+		}
+	case SinkOpenRGB:
+	case "":
+		return fmt.Errorf("missing type (want %s)", strings.Join(SinkTypes(), ", "))
+	default:
+		return fmt.Errorf("unknown type %q (want %s)", sc.Type, strings.Join(SinkTypes(), ", "))
+	}
+	if sc.File != "" && sc.Type != SinkCaptions {
+		return fmt.Errorf("file is only for captions")
+	}
+	if sc.Token != "" && sc.Type != SinkCompanion {
+		return fmt.Errorf("token is only for the companion server")
+	}
+	if sc.OverlaySettings != (OverlaySettings{}) && sc.Type != SinkOverlay {
+		return fmt.Errorf("monitor, corner, scale, theme, font, letter_size, strokes and case are only for the overlay")
+	}
+	return nil
+}
+
+// unique reports whether at most one sink of sc's type may be on: the
+// companion server, which is also where keys come from, and the engine's
+// own outputs.
+func (sc SinkConfig) unique() bool {
+	return sc.Type == SinkCompanion || sc.Type == SinkAudio || sc.Type == SinkStats
+}
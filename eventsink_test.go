@@ -0,0 +1,99 @@
+package phonical_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// recorder is an EventSink noting the sounds it is told about.
+type recorder struct {
+	mu     sync.Mutex
+	sounds []string
+}
+
+func (r *recorder) Played(ev phonical.PlayEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sounds = append(r.sounds, ev.Sound)
+}
+
+func (r *recorder) Sounds() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.sounds...)
+}
+
+func TestSinksInOrder(t *testing.T) {
+	var order []string
+	var mu sync.Mutex
+	note := func(name string) phonical.EventSink {
+		return phonical.SinkFunc(func(ev phonical.PlayEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name+":"+ev.Sound)
+		})
+	}
+	h := phonicaltest.New(t, phonical.WithSinks(note("first"), phonical.AudioOutput(), note("second")))
+	h.Type("ab")
+	h.Expect("a.wav", "b.wav")
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"first:a.wav", "second:a.wav", "first:b.wav", "second:b.wav"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("sinks told %q, want %q", order, want)
+	}
+}
+
+func TestSinksWithoutAudio(t *testing.T) {
+	rec := &recorder{}
+	h := phonicaltest.New(t, phonical.WithSinks(rec))
+	h.Type("ab")
+	deadline := time.Now().Add(2 * time.Second)
+	for len(rec.Sounds()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	h.Expect()
+	if want := []string{"a.wav", "b.wav"}; !reflect.DeepEqual(rec.Sounds(), want) {
+		t.Errorf("sink told %q, want %q", rec.Sounds(), want)
+	}
+
+	// Without StatsOutput nothing is counted
+	var metrics strings.Builder
+	h.Engine.WriteMetrics(&metrics)
+	if !strings.Contains(metrics.String(), "phonical_sounds_played_total 0\n") {
+		t.Errorf("sounds counted without the stats sink:\n%s", metrics.String())
+	}
+}
+
+func TestSinkConfigDuplicates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	config := `[[sink]]
+type = "companion"
+address = "localhost:9001"
+
+[[sink]]
+type = "companion"
+address = "localhost:9002"
+disabled = true
+
+[[sink]]
+type = "companion"
+address = "localhost:9003"
+`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := phonical.LoadConfig(path)
+	if err == nil || !strings.Contains(err.Error(), "sink 3: a second companion") {
+		t.Errorf("LoadConfig = %v, want the third sink refused", err)
+	}
+}
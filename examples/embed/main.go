@@ -0,0 +1,43 @@
+// Command embed shows how to embed the phonics engine in another program.
+// It prints each letter and completed word as the built-in sounds play, and
+// a short summary when interrupted.
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"phonical"
+)
+
+func main() {
+	engine, err := phonical.New(
+		phonical.OnLetter(func(ev phonical.LetterEvent) {
+			fmt.Printf("letter: %c\n", ev.Letter)
+		}),
+		phonical.OnWord(func(ev phonical.WordEvent) {
+			fmt.Printf("word: %s\n", ev.Word)
+		}),
+		phonical.OnSessionEnd(func(s phonical.SessionSummary) {
+			fmt.Printf("typed %d letters and %d words in %s\n",
+				s.Letters, s.Words, s.End.Sub(s.Start).Round(time.Second))
+		}),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt)
+	go func() {
+		<-sigChan
+		engine.Stop()
+	}()
+
+	if err := engine.Run(); err != nil {
+		log.Fatal(err)
+	}
+}
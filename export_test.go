@@ -0,0 +1,15 @@
+//go:build !noinput
+
+package phonical
+
+import (
+	"context"
+
+	hook "github.com/robotn/gohook"
+)
+
+// HookEvents runs the hook's event loop on events, so tests can replay
+// what the hook reports.
+func (e *Engine) HookEvents(ctx context.Context, events <-chan hook.Event) error {
+	return e.hookEvents(ctx, events)
+}
@@ -0,0 +1,130 @@
+package phonical
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// FallingLettersGame is the FallingLetters entry in Stats.HighScores.
+const FallingLettersGame = "falling-letters"
+
+// highScoreLimit is how many scores each game's table keeps.
+const highScoreLimit = 10
+
+const (
+	// fallTime is how long a letter takes to land at level one.
+	fallTime = 5 * time.Second
+	// fallSpeedup is the fraction of fall time kept at each new level.
+	fallSpeedup = 0.85
+	// minFallTime is the fastest letters ever fall.
+	minFallTime = 1200 * time.Millisecond
+	// lettersPerLevel is how many catches take the game up a level.
+	lettersPerLevel = 10
+)
+
+// HighScore is one entry of a game's high-score table.
+type HighScore struct {
+	Score int       `json:"score"`
+	Level int       `json:"level"`
+	Date  time.Time `json:"date"`
+}
+
+// GameResult is how a game went.
+type GameResult struct {
+	Score int
+	Level int
+	// Rank is the score's place in the high-score table, from 1, or 0
+	// when it didn't make the table or there are no stats.
+	Rank int
+}
+
+// FallingLetters returns an audio-only game. Letter sounds are called out
+// one at a time and each must be pressed before it lands; letters fall
+// faster every level. A wrong key or a landed letter costs one of the
+// lives, and a catch scores the current level in points. The result is
+// passed to done and kept in the engine's high-score table.
+func FallingLetters(lives int, done func(GameResult)) Activity {
+	return ActivityFunc(func(t *Tutor) error {
+		letters := t.e.packLetters()
+		if len(letters) == 0 {
+			return fmt.Errorf("sound pack has no letters")
+		}
+
+		result := GameResult{Level: 1}
+		fall := fallTime
+		caught := 0
+		t.Say(fmt.Sprintf("Catch the falling letters! Press each one before it lands. You have %s lives.", numberWord(lives)))
+		t.Pause(time.Second)
+		for lives > 0 {
+			letter := letters[rand.Intn(len(letters))]
+			t.Play(letter)
+
+			pressed, ok := t.NextKey(fall)
+			if t.Stopped() {
+				return ErrStopped
+			}
+			if ok && pressed == letter {
+				result.Score += result.Level
+				caught++
+				if caught%lettersPerLevel == 0 {
+					result.Level++
+					fall = max(time.Duration(float64(fall)*fallSpeedup), minFallTime)
+					t.Say(fmt.Sprintf("Level %s!", numberWord(result.Level)))
+				}
+				continue
+			}
+
+			lives--
+			if ok {
+				t.Say(fmt.Sprintf("Oops, that was %c.", letter))
+			} else {
+				t.Say(fmt.Sprintf("Splat! That was %c.", letter))
+			}
+			switch {
+			case lives == 1:
+				t.Say("one life left.")
+			case lives > 1:
+				t.Say(fmt.Sprintf("%s lives left.", numberWord(lives)))
+			}
+			t.Pause(500 * time.Millisecond)
+		}
+
+		t.Say(fmt.Sprintf("Game over. You scored %s.", numberWord(result.Score)))
+		if stats := t.Stats(); stats != nil {
+			now := t.e.clock.Now()
+			stats.Update(func(st *Stats) {
+				result.Rank = st.recordHighScore(FallingLettersGame, HighScore{Score: result.Score, Level: result.Level, Date: now})
+			})
+		}
+		if result.Rank == 1 && result.Score > 0 {
+			t.Say("That's a new high score!")
+		}
+		if done != nil {
+			done(result)
+		}
+		return nil
+	})
+}
+
+// recordHighScore adds hs to game's table, keeping the best
+// highScoreLimit, and returns its rank from 1, or 0 if it didn't place.
+func (st *Stats) recordHighScore(game string, hs HighScore) int {
+	if st.HighScores == nil {
+		st.HighScores = make(map[string][]HighScore)
+	}
+	table := append(st.HighScores[game], hs)
+	// Stable, so an equal earlier score keeps its place
+	sort.SliceStable(table, func(i, j int) bool { return table[i].Score > table[j].Score })
+	if len(table) > highScoreLimit {
+		table = table[:highScoreLimit]
+	}
+	st.HighScores[game] = table
+	for i, entry := range table {
+		if entry == hs {
+			return i + 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,58 @@
+package phonical_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestFallingLetters(t *testing.T) {
+	stats, err := phonical.OpenStats(filepath.Join(t.TempDir(), "stats.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := phonicaltest.New(t, phonical.WithStats(stats), phonical.WithWatchdog(0))
+	results := make(chan phonical.GameResult, 1)
+	done := runActivity(h, phonical.FallingLetters(2, func(r phonical.GameResult) { results <- r }))
+
+	// Catch the first letter, press the wrong key for the second and let
+	// the third land
+	played := waitPlayed(t, h, 2)
+	answer(h, letterOf(played[1]))
+	second := letterOf(waitPlayed(t, h, 3)[2])
+	wrong := "z"
+	if second == "z" {
+		wrong = "y"
+	}
+	answer(h, wrong)
+	third := letterOf(waitPlayed(t, h, 6)[5])
+	played = waitPlayed(t, h, 9)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"say:Catch the falling letters! Press each one before it lands. You have two lives.",
+		played[1],
+		second + ".wav",
+		"say:Oops, that was " + second + ".",
+		"say:one life left.",
+		third + ".wav",
+		"say:Splat! That was " + third + ".",
+		"say:Game over. You scored one.",
+		"say:That's a new high score!",
+	}
+	for i := range want {
+		if played[i] != want[i] {
+			t.Errorf("sound %d is %q, want %q", i, played[i], want[i])
+		}
+	}
+	if r := <-results; r != (phonical.GameResult{Score: 1, Level: 1, Rank: 1}) {
+		t.Errorf("result %+v, want a score of one ranked first", r)
+	}
+	if table := stats.Snapshot().HighScores[phonical.FallingLettersGame]; len(table) != 1 || table[0].Score != 1 {
+		t.Errorf("high scores %+v, want the one game", table)
+	}
+}
@@ -0,0 +1,133 @@
+package phonical
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// familyMembers is the most words a family drill asks for.
+const familyMembers = 4
+
+// taughtFamilies returns the CVC word families whose words can be spelled
+// with the letters taught so far, each with only those decodable members.
+// Without a curriculum every CVC family in the rhyme dictionary counts.
+func (e *Engine) taughtFamilies() []wordFamily {
+	loadFamilies()
+	taught := make(map[rune]bool)
+	for _, r := range e.packLetters() {
+		taught[r] = true
+	}
+	decodable := func(s string) bool {
+		for _, r := range s {
+			if !taught[r] {
+				return false
+			}
+		}
+		return true
+	}
+
+	var out []wordFamily
+	for _, f := range families {
+		if len(f.rime) != 2 || !decodable(f.rime) {
+			continue
+		}
+		var words []string
+		for _, w := range f.words {
+			if len(w) == 3 && decodable(w) {
+				words = append(words, w)
+			}
+		}
+		if len(words) >= 2 {
+			out = append(out, wordFamily{rime: f.rime, words: words})
+		}
+	}
+	return out
+}
+
+// WordFamilyDrill returns an activity that drills the given number of CVC
+// word families (-at, -an, -ig), chosen from those the curriculum has
+// taught the letters for, least mastered first. For each family it sounds
+// out the rime, then asks for each member in turn. A family counts as
+// mastered after it has been drilled without a mistake three times in a
+// row; progress is kept in the engine's stats and the result passed to
+// done.
+func WordFamilyDrill(count int, done func(SpellingResult)) Activity {
+	return ActivityFunc(func(t *Tutor) error {
+		fams := t.e.taughtFamilies()
+		if len(fams) == 0 {
+			return fmt.Errorf("not enough letters taught yet for word families")
+		}
+		fams = familyOrder(t.Stats(), fams)
+		if len(fams) > count {
+			fams = fams[:count]
+		}
+
+		var result SpellingResult
+		for _, f := range fams {
+			t.Say(fmt.Sprintf("The %s family.", f.rime))
+			t.SoundOut(f.rime)
+			t.Pause(segmentGap)
+
+			words := f.words
+			if len(words) > familyMembers {
+				words = words[:familyMembers]
+			}
+			perfect := true
+			for _, word := range words {
+				result.Total++
+				t.Say(fmt.Sprintf("Type %s.", word))
+				typed, err := readSpelling(t)
+				if err != nil {
+					return err
+				}
+				if typed == word {
+					result.Correct++
+					t.Say("Yes!")
+				} else {
+					perfect = false
+					t.Say(fmt.Sprintf("That one was %s.", strings.Join(strings.Split(word, ""), " ")))
+				}
+			}
+
+			if stats := t.Stats(); stats != nil {
+				now := t.e.clock.Now()
+				stats.Update(func(st *Stats) { st.recordFamily(f.rime, perfect, now) })
+			}
+			t.Pause(500 * time.Millisecond)
+		}
+
+		t.Say(fmt.Sprintf("You got %s out of %s.", numberWord(result.Correct), numberWord(result.Total)))
+		if done != nil {
+			done(result)
+		}
+		return nil
+	})
+}
+
+// familyOrder sorts fams so those furthest from mastery come first,
+// keeping dictionary order among equals.
+func familyOrder(stats *StatsStore, fams []wordFamily) []wordFamily {
+	if stats == nil {
+		return fams
+	}
+	st := stats.Snapshot()
+	fams = append([]wordFamily(nil), fams...)
+	sort.SliceStable(fams, func(i, j int) bool {
+		return familyStreak(st, fams[i].rime) < familyStreak(st, fams[j].rime)
+	})
+	return fams
+}
+
+func familyStreak(st Stats, rime string) int {
+	if m := st.Families[rime]; m != nil {
+		return m.Streak
+	}
+	return 0
+}
+
+// recordFamily updates a family's mastery after a drill.
+func (st *Stats) recordFamily(rime string, perfect bool, now time.Time) {
+	st.Families = recordMastery(st.Families, rime, perfect, now)
+}
@@ -0,0 +1,55 @@
+package phonical_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestWordFamilyDrill(t *testing.T) {
+	stats, err := phonical.OpenStats(filepath.Join(t.TempDir(), "stats.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// With only a, t, c and h taught, the -at family is cat and hat
+	pack := letterPack(t, map[string]string{"a": "a", "t": "t", "c": "c", "h": "h"})
+	h := phonicaltest.New(t, phonical.WithPack(pack), phonical.WithStats(stats), phonical.WithWatchdog(0))
+	results := make(chan phonical.SpellingResult, 1)
+	done := runActivity(h, phonical.WordFamilyDrill(3, func(r phonical.SpellingResult) { results <- r }))
+
+	waitPlayed(t, h, 5)
+	n := spell(t, h, "cat", 5)
+	waitPlayed(t, h, n+2)
+	n = spell(t, h, "cat", n+2)
+	played := waitPlayed(t, h, n+2)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"say:The at family.", "a.wav", "t.wav", "blend:at",
+		"say:Type cat.", "c.wav", "a.wav", "t.wav", "say:Yes!",
+		"say:Type hat.", "c.wav", "a.wav", "t.wav", "say:That one was h a t.",
+		"say:You got one out of two.",
+	}
+	if !reflect.DeepEqual(played, want) {
+		t.Errorf("played %q, want %q", played, want)
+	}
+	if r := <-results; r != (phonical.SpellingResult{Correct: 1, Total: 2}) {
+		t.Errorf("result %+v, want one of two", r)
+	}
+	if m := stats.Snapshot().Families["at"]; m == nil || m.Attempts != 1 || m.Streak != 0 {
+		t.Errorf("mastery of the at family %+v, want one attempt and no streak", m)
+	}
+}
+
+func TestWordFamilyDrillNeedsLetters(t *testing.T) {
+	pack := letterPack(t, map[string]string{"a": "a", "t": "t"})
+	h := phonicaltest.New(t, phonical.WithPack(pack), phonical.WithWatchdog(0))
+	if err := h.Engine.RunActivity(phonical.WordFamilyDrill(1, nil)); err == nil {
+		t.Error("drilled word families with only a and t taught")
+	}
+}
@@ -0,0 +1,56 @@
+package phonical
+
+import "strings"
+
+// fingers names the finger for each column of the keyboard rows in
+// touch typing.
+var fingers = []string{
+	"left little finger", "left ring finger", "left middle finger", "left pointer", "left pointer",
+	"right pointer", "right pointer", "right middle finger", "right ring finger", "right little finger",
+}
+
+// fingerHint describes which finger and row type a key, e.g. "left
+// pointer, home row" for f. It goes by the keycode, falling back to the
+// character's place on a US layout when there is none.
+func fingerHint(code uint16, char rune) (string, bool) {
+	for _, row := range keyRows {
+		if code >= row.first && int(code-row.first) < len(row.chars) {
+			return fingers[code-row.first] + ", " + row.name, true
+		}
+	}
+	for _, row := range keyRows {
+		if i := strings.IndexRune(row.chars, char); i >= 0 {
+			return fingers[i] + ", " + row.name, true
+		}
+	}
+	return "", false
+}
+
+// WithFingerHints says which finger and row to use after each of the
+// given keys is typed, as a first step towards touch typing. "all" gives
+// hints for every letter.
+func WithFingerHints(keys string) Option {
+	return func(e *Engine) {
+		if keys == "" {
+			e.fingerHints = nil
+			return
+		}
+		e.fingerHints = make(map[rune]bool)
+		if keys == "all" {
+			keys = "abcdefghijklmnopqrstuvwxyz"
+		}
+		for _, r := range strings.ToLower(keys) {
+			e.fingerHints[r] = true
+		}
+	}
+}
+
+// hintFinger queues the finger hint for a typed letter, if it has one.
+func (e *Engine) hintFinger(char rune, code uint16) {
+	if !e.fingerHints[char] {
+		return
+	}
+	if hint, ok := fingerHint(code, char); ok {
+		e.enqueueSay(hint)
+	}
+}
@@ -0,0 +1,18 @@
+package phonical_test
+
+import (
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestFingerHints(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithFingerHints("FJ"))
+	// The keycode wins over the character, so a layout that puts j where
+	// QWERTY has y still names the key's position
+	h.Type("fa")
+	h.Source.Send(phonical.Key{Char: 'j', Code: 0x0015})
+	h.Expect("f.wav", "say:left pointer, home row", "a.wav",
+		"j.wav", "say:right pointer, top row")
+}
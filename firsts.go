@@ -0,0 +1,57 @@
+package phonical
+
+import (
+	"fmt"
+	"time"
+)
+
+// associationWords are the built-in "a is for apple" words.
+var associationWords = map[rune]string{
+	'a': "apple", 'b': "ball", 'c': "cat", 'd': "dog", 'e': "egg",
+	'f': "fish", 'g': "goat", 'h': "hat", 'i': "insect", 'j': "jam",
+	'k': "kite", 'l': "lion", 'm': "moon", 'n': "nest", 'o': "octopus",
+	'p': "pig", 'q': "queen", 'r': "rabbit", 's': "sun", 't': "tiger",
+	'u': "umbrella", 'v': "van", 'w': "web", 'x': "fox", 'y': "yo-yo",
+	'z': "zebra",
+}
+
+// firstPress reports whether letter has never been pressed before by this
+// profile, marking it seen. Letters counted before seen-tracking existed
+// are treated as seen.
+func (st *Stats) firstPress(letter rune, now time.Time) bool {
+	if st.Seen == nil {
+		st.Seen = make(map[string]time.Time)
+	}
+	key := string(letter)
+	if _, ok := st.Seen[key]; ok {
+		return false
+	}
+	st.Seen[key] = now
+	return st.Letters[key] == 0
+}
+
+// celebrateFirst queues the extended first-time version of letter: a
+// sparkle, then the pack's intro clip, or the phoneme followed by its
+// association word.
+func (e *Engine) celebrateFirst(letter rune, soundFile string) {
+	e.debugf("First time pressing %c!\n", letter)
+	e.enqueue(&queuedSound{sound: "sparkle", buffer: sparkle()})
+
+	if file, ok := e.pack.Load().Intros[letter]; ok {
+		e.enqueue(&queuedSound{sound: file, letter: letter})
+		return
+	}
+
+	e.enqueue(&queuedSound{sound: soundFile, letter: letter})
+	word, ok := e.pack.Load().Words[letter]
+	if !ok {
+		word = associationWords[letter]
+	}
+	if word != "" {
+		e.enqueuePause(200 * time.Millisecond)
+		e.enqueueSay(fmt.Sprintf("%c is for %s!", letter, word))
+	}
+	if e.curriculum != nil && e.curriculum.Actions[letter] != "" {
+		e.enqueueSay(e.curriculum.Actions[letter])
+	}
+}
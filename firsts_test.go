@@ -0,0 +1,25 @@
+package phonical_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestFirstLetterCelebration(t *testing.T) {
+	stats, err := phonical.OpenStats(filepath.Join(t.TempDir(), "stats.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// b was typed before first presses were tracked
+	stats.Update(func(st *phonical.Stats) { st.Letters["b"] = 3 })
+	h := phonicaltest.New(t, phonical.WithStats(stats), phonical.WithFirstLetterCelebration(true))
+
+	h.Type("aab")
+	h.Expect("sparkle", "a.wav", "say:a is for apple!", "a.wav", "b.wav")
+	if _, ok := stats.Snapshot().Seen["a"]; !ok {
+		t.Error("a not recorded as seen")
+	}
+}
@@ -0,0 +1,301 @@
+package phonical
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" // pictures may be JPEG
+	_ "image/png"  // or PNG
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Flashcard is one printable card: a letter or digraph with its word and,
+// when the pack has one, a picture of it.
+type Flashcard struct {
+	Letter string
+	Word   string
+	// Picture is the pack file of the picture, if any.
+	Picture string
+}
+
+// Flashcards returns cards for the letters and digraphs taught up to level
+// of c, in teaching order, with the words and pictures of p, so cards
+// printed for practice away from the screen match what the child hears.
+// Without a curriculum every letter of p is included, in alphabetical
+// order.
+func Flashcards(p *Pack, c *Curriculum, level int) []Flashcard {
+	var units []string
+	if c == nil {
+		letters := make([]rune, 0, len(p.Letters))
+		for r := range p.Letters {
+			letters = append(letters, r)
+		}
+		sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+		for _, r := range letters {
+			units = append(units, string(r))
+		}
+	} else {
+		for i, l := range c.Levels {
+			if i >= level {
+				break
+			}
+			for _, r := range l.Letters {
+				units = append(units, string(r))
+			}
+			units = append(units, l.Digraphs...)
+		}
+	}
+
+	cards := make([]Flashcard, 0, len(units))
+	for _, unit := range units {
+		card := Flashcard{Letter: unit}
+		if r := []rune(unit); len(r) == 1 {
+			card.Word, card.Picture = p.Words[r[0]], p.Pictures[r[0]]
+			if card.Word == "" {
+				card.Word = associationWords[r[0]]
+			}
+		}
+		cards = append(cards, card)
+	}
+	return cards
+}
+
+// Flashcard page layout, in points on A4 paper: two columns of three
+// cards, separated by dashed lines to cut along.
+const (
+	pageWidth     = 595.0
+	pageHeight    = 842.0
+	cardColumns   = 2
+	cardRows      = 3
+	cardWidth     = pageWidth / cardColumns
+	cardHeight    = pageHeight / cardRows
+	letterSize    = 96.0
+	wordSize      = 30.0
+	pictureWidth  = 200.0
+	pictureHeight = 85.0
+)
+
+// WriteFlashcardsPDF writes cards to w as a PDF to print on A4 paper, six
+// to a page. Each shows its letter in capital and small form, the
+// picture, if any, and the word. The built-in PDF fonts only cover Western
+// European letters, so cards for other scripts are refused.
+func WriteFlashcardsPDF(w io.Writer, p *Pack, cards []Flashcard) error {
+	if len(cards) == 0 {
+		return fmt.Errorf("no flashcards to write")
+	}
+	doc := &pdfWriter{}
+	// 1 is the catalog, 2 the page tree and 3 the font; pages follow
+	doc.reserve(3)
+	var pages []int
+	perPage := cardColumns * cardRows
+	for start := 0; start < len(cards); start += perPage {
+		page := cards[start:min(start+perPage, len(cards))]
+		var content bytes.Buffer
+		images := map[string]int{}
+		content.WriteString("0.6 G 0.5 w [6 4] 0 d\n")
+		for i := 1; i < cardColumns; i++ {
+			x := float64(i) * cardWidth
+			fmt.Fprintf(&content, "%.2f 0 m %.2f %.2f l S\n", x, x, pageHeight)
+		}
+		for i := 1; i < cardRows; i++ {
+			y := float64(i) * cardHeight
+			fmt.Fprintf(&content, "0 %.2f m %.2f %.2f l S\n", y, pageWidth, y)
+		}
+		content.WriteString("[] 0 d 0 g\n")
+		for i, card := range page {
+			x := float64(i%cardColumns) * cardWidth
+			y := pageHeight - float64(i/cardColumns+1)*cardHeight
+			if err := doc.card(&content, images, p, card, x, y); err != nil {
+				return err
+			}
+		}
+		stream := doc.add(pdfStream("", content.Bytes()))
+		var xobjects strings.Builder
+		for name, obj := range images {
+			fmt.Fprintf(&xobjects, " /%s %d 0 R", name, obj)
+		}
+		pages = append(pages, doc.add(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R >> /XObject <<%s >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, xobjects.String(), stream)))
+	}
+
+	kids := make([]string, len(pages))
+	for i, page := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", page)
+	}
+	doc.set(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	doc.set(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	doc.set(3, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	return doc.writeTo(w)
+}
+
+// card draws one card with its bottom-left corner at x, y.
+func (doc *pdfWriter) card(content *bytes.Buffer, images map[string]int, p *Pack, card Flashcard, x, y float64) error {
+	letters := card.Letter
+	if r := []rune(card.Letter); len(r) == 1 && unicode.ToUpper(r[0]) != r[0] {
+		letters = string(unicode.ToUpper(r[0])) + card.Letter
+	}
+	if err := centredText(content, letters, letterSize, x+cardWidth/2, y+cardHeight-110); err != nil {
+		return err
+	}
+
+	if card.Picture != "" {
+		img, err := p.picture(card.Picture)
+		if err != nil {
+			return fmt.Errorf("picture for %s: %w", card.Letter, err)
+		}
+		name := fmt.Sprintf("Im%d", len(images)+1)
+		images[name] = doc.add(pdfImage(img))
+		// Fit the picture in its box, keeping its shape
+		b := img.Bounds()
+		scale := min(pictureWidth/float64(b.Dx()), pictureHeight/float64(b.Dy()))
+		w, h := float64(b.Dx())*scale, float64(b.Dy())*scale
+		fmt.Fprintf(content, "q %.2f 0 0 %.2f %.2f %.2f cm /%s Do Q\n",
+			w, h, x+(cardWidth-w)/2, y+60+(pictureHeight-h)/2, name)
+	}
+
+	if card.Word != "" {
+		return centredText(content, card.Word, wordSize, x+cardWidth/2, y+28)
+	}
+	return nil
+}
+
+// picture decodes the pack's picture file.
+func (p *Pack) picture(name string) (image.Image, error) {
+	file, err := p.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return img, nil
+}
+
+// centredText draws text centred on x with its baseline at y.
+func centredText(content *bytes.Buffer, text string, size, x, y float64) error {
+	encoded, width, err := winAnsi(text)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(content, "BT /F1 %.0f Tf %.2f %.2f Td (%s) Tj ET\n", size, x-width*size/2000, y, encoded)
+	return nil
+}
+
+// winAnsi encodes text for a PDF string in the built-in fonts'
+// WinAnsiEncoding, returning its width in thousandths of the font size.
+func winAnsi(text string) (string, float64, error) {
+	var b strings.Builder
+	width := 0.0
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= ' ' && r <= '~':
+			b.WriteRune(r)
+		case r >= 0xA0 && r <= 0xFF:
+			// Latin-1 letters have the same codes in WinAnsiEncoding
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			return "", 0, fmt.Errorf("can't print %q on flashcards: only Western European letters are supported", r)
+		}
+		width += helveticaBoldWidth(r)
+	}
+	return b.String(), width, nil
+}
+
+// helveticaBoldWidths are the widths of the printable ASCII characters in
+// Helvetica-Bold, from space to tilde, in thousandths of the font size.
+var helveticaBoldWidths = [...]float64{
+	278, 333, 474, 556, 556, 889, 722, 278, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	278, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
+
+func helveticaBoldWidth(r rune) float64 {
+	if r >= ' ' && r <= '~' {
+		return helveticaBoldWidths[r-' ']
+	}
+	// Accented letters are about as wide as average letters
+	return 611
+}
+
+// pdfImage converts img to an 8-bit RGB image object, with any
+// transparency shown against white paper.
+func pdfImage(img image.Image) string {
+	b := img.Bounds()
+	pixels := make([]byte, 0, 3*b.Dx()*b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			over := func(v uint8) byte {
+				return byte((int(v)*int(c.A) + 255*(255-int(c.A))) / 255)
+			}
+			pixels = append(pixels, over(c.R), over(c.G), over(c.B))
+		}
+	}
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	zw.Write(pixels)
+	zw.Close()
+	return pdfStream(fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode",
+		b.Dx(), b.Dy()), z.Bytes())
+}
+
+// pdfStream returns a stream object with the given dictionary entries.
+func pdfStream(dict string, data []byte) string {
+	if dict != "" {
+		dict += " "
+	}
+	return fmt.Sprintf("<< %s/Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+}
+
+// pdfWriter collects a PDF's objects, numbered from 1, and writes them
+// with their cross-reference table.
+type pdfWriter struct {
+	objects []string
+}
+
+// reserve makes room for n objects to be set later.
+func (doc *pdfWriter) reserve(n int) {
+	doc.objects = append(doc.objects, make([]string, n)...)
+}
+
+func (doc *pdfWriter) set(n int, obj string) {
+	doc.objects[n-1] = obj
+}
+
+// add appends obj, returning its number.
+func (doc *pdfWriter) add(obj string) int {
+	doc.objects = append(doc.objects, obj)
+	return len(doc.objects)
+}
+
+func (doc *pdfWriter) writeTo(w io.Writer) error {
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(doc.objects))
+	for i, obj := range doc.objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(doc.objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(doc.objects)+1, xref)
+	_, err := w.Write(out.Bytes())
+	return err
+}
@@ -0,0 +1,74 @@
+package phonical_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"phonical"
+)
+
+func TestFlashcards(t *testing.T) {
+	curriculum, err := phonical.LoadCurriculum("letters-and-sounds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var letters []string
+	for _, card := range phonical.Flashcards(phonical.DefaultPack(), curriculum, 2) {
+		letters = append(letters, card.Letter)
+		if card.Word == "" {
+			t.Errorf("card for %s has no word", card.Letter)
+		}
+	}
+	if want := []string{"s", "a", "t", "p", "i", "n", "m", "d"}; !reflect.DeepEqual(letters, want) {
+		t.Errorf("cards for %q, want %q in teaching order", letters, want)
+	}
+}
+
+func TestWriteFlashcardsPDF(t *testing.T) {
+	if !phonical.HasBuiltinSounds() {
+		t.Skip("lite build")
+	}
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	wav, err := os.ReadFile(filepath.Join("sounds", phonical.DefaultSounds, "a.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pack, err := phonical.LoadPack(writePack(t, "name = 'Pictures'\n[letters]\na = 'a.wav'\n[pictures]\na = 'apple.png'\n",
+		map[string][]byte{"a.wav": wav, "apple.png": img.Bytes()}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cards := phonical.Flashcards(pack, nil, 0)
+	if want := []phonical.Flashcard{{Letter: "a", Word: "apple", Picture: "apple.png"}}; !reflect.DeepEqual(cards, want) {
+		t.Fatalf("cards %+v, want %+v", cards, want)
+	}
+
+	// Seven cards take two pages of six
+	cards = append(cards, phonical.Flashcards(phonical.DefaultPack(), nil, 0)[:6]...)
+	var pdf bytes.Buffer
+	if err := phonical.WriteFlashcardsPDF(&pdf, pack, cards); err != nil {
+		t.Fatal(err)
+	}
+	out := pdf.String()
+	if !strings.HasPrefix(out, "%PDF-") || !strings.HasSuffix(strings.TrimSpace(out), "%%EOF") {
+		t.Errorf("not a PDF: %.20q...", out)
+	}
+	for _, want := range []string{"/Count 2", "/Subtype /Image"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("PDF lacks %q", want)
+		}
+	}
+
+	if err := phonical.WriteFlashcardsPDF(&pdf, pack, []phonical.Flashcard{{Letter: "ש"}}); err == nil {
+		t.Error("wrote a card in a script the PDF font lacks")
+	}
+}
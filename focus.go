@@ -0,0 +1,64 @@
+package phonical
+
+import (
+	"sort"
+	"time"
+)
+
+// FocusState is the current letter of the day, kept in Stats so rotation
+// survives restarts.
+type FocusState struct {
+	Letter string    `json:"letter"`
+	Since  time.Time `json:"since"`
+}
+
+// FocusSchedule rotates the focus letter through Pool every Period.
+type FocusSchedule struct {
+	Period time.Duration
+	Pool   []rune
+}
+
+// ChooseFocus returns the focus letter for now. The letter chosen at the
+// last rotation is kept until its period ends; then the least-practised
+// letter in the pool takes over, so rotation favours letters the child
+// rarely types. The choice is recorded in store.
+func (s FocusSchedule) ChooseFocus(store *StatsStore, now time.Time) rune {
+	var chosen rune
+	store.Update(func(st *Stats) {
+		if st.Focus != nil && st.Focus.Letter != "" && now.Sub(st.Focus.Since) < s.Period {
+			chosen = []rune(st.Focus.Letter)[0]
+			return
+		}
+
+		previous := ""
+		if st.Focus != nil {
+			previous = st.Focus.Letter
+		}
+		pool := append([]rune(nil), s.Pool...)
+		sort.Slice(pool, func(i, j int) bool { return pool[i] < pool[j] })
+		for _, r := range pool {
+			if string(r) == previous && len(pool) > 1 {
+				continue
+			}
+			if chosen == 0 || st.Letters[string(r)] < st.Letters[string(chosen)] {
+				chosen = r
+			}
+		}
+		st.Focus = &FocusState{Letter: string(chosen), Since: now}
+	})
+	return chosen
+}
+
+// CurrentFocus returns the letter chosen at the last rotation without
+// rotating it or recording anything, for guest sessions, which must leave
+// the child's progression alone. It reports false before the first
+// rotation.
+func (s FocusSchedule) CurrentFocus(store *StatsStore) (rune, bool) {
+	var current rune
+	store.Update(func(st *Stats) {
+		if st.Focus != nil && st.Focus.Letter != "" {
+			current = []rune(st.Focus.Letter)[0]
+		}
+	})
+	return current, current != 0
+}
@@ -0,0 +1,69 @@
+package phonical_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestFocusRotation(t *testing.T) {
+	stats, err := phonical.OpenStats(filepath.Join(t.TempDir(), "stats.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+	stats.Update(func(st *phonical.Stats) {
+		st.Letters["a"], st.Letters["b"], st.Letters["c"] = 1, 5, 2
+		st.Focus = &phonical.FocusState{Letter: "a", Since: now.Add(-48 * time.Hour)}
+	})
+	schedule := phonical.FocusSchedule{Period: 24 * time.Hour, Pool: []rune("abc")}
+
+	// A guest sees the current letter even though its day is over, and
+	// the rotation stays put
+	if letter, ok := schedule.CurrentFocus(stats); !ok || letter != 'a' {
+		t.Errorf("current focus %q, %v, want a", letter, ok)
+	}
+	if focus := stats.Snapshot().Focus; focus.Letter != "a" || !focus.Since.Equal(now.Add(-48*time.Hour)) {
+		t.Errorf("current focus changed the stats to %+v", focus)
+	}
+
+	// Rotating moves on to the least-practised letter other than a
+	if letter := schedule.ChooseFocus(stats, now); letter != 'c' {
+		t.Errorf("rotated to %q, want c", letter)
+	}
+	if letter := schedule.ChooseFocus(stats, now.Add(time.Hour)); letter != 'c' {
+		t.Errorf("rotated again within the day to %q, want c", letter)
+	}
+	if focus := stats.Snapshot().Focus; focus.Letter != "c" || !focus.Since.Equal(now) {
+		t.Errorf("recorded focus %+v, want c since %v", focus, now)
+	}
+}
+
+func TestCurrentFocusBeforeRotation(t *testing.T) {
+	stats, err := phonical.OpenStats(filepath.Join(t.TempDir(), "stats.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule := phonical.FocusSchedule{Period: 24 * time.Hour, Pool: []rune("abc")}
+	if letter, ok := schedule.CurrentFocus(stats); ok {
+		t.Errorf("current focus %q before any rotation", letter)
+	}
+	if focus := stats.Snapshot().Focus; focus != nil {
+		t.Errorf("current focus recorded %+v", focus)
+	}
+}
+
+func TestFocusMutesOtherLetters(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithFocus('A'))
+	h.Type("ab")
+	h.Expect("a.wav", "b.wav")
+
+	// b plays only the brief tick, well short of its recording
+	played := h.Sink.Playbacks()
+	if focus, other := samples(played[0].Streamer), samples(played[1].Streamer); other*2 > focus {
+		t.Errorf("b played %d samples against a's %d, want a short tick", other, focus)
+	}
+}
@@ -3,6 +3,7 @@ module phonical
 go 1.21
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/faiface/beep v1.1.0
 	github.com/hajimehoshi/oto v0.7.1
 	github.com/robotn/gohook v0.31.3
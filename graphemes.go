@@ -0,0 +1,56 @@
+package phonical
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// splitGraphemes moves the multi-letter keys of a [letters] table, such as
+// Welsh "ll" or "dd", into their own map so single letters can still be
+// read by runeTable.
+func splitGraphemes(entries map[string]string) (letters, graphemes map[string]string) {
+	letters = make(map[string]string, len(entries))
+	graphemes = map[string]string{}
+	for key, file := range entries {
+		if utf8.RuneCountInString(key) > 1 {
+			graphemes[strings.ToLower(key)] = file
+		} else {
+			letters[key] = file
+		}
+	}
+	return letters, graphemes
+}
+
+// graphemeList returns the pack's multi-letter graphemes, longest first.
+func (p *Pack) graphemeList() []string {
+	list := make([]string, 0, len(p.Graphemes))
+	for g := range p.Graphemes {
+		list = append(list, g)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if len(list[i]) != len(list[j]) {
+			return len(list[i]) > len(list[j])
+		}
+		return list[i] < list[j]
+	})
+	return list
+}
+
+// graphemeEnding returns the sound for the longest of the pack's graphemes
+// that text ends with, so typing the second l of "ll" plays the whole
+// grapheme.
+func (p *Pack) graphemeEnding(text string) (grapheme, file string, ok bool) {
+	for _, g := range p.graphemeList() {
+		if strings.HasSuffix(text, g) {
+			return g, p.Graphemes[g], true
+		}
+	}
+	return "", "", false
+}
+
+// segment splits word into graphemes, grouping the pack's own multi-letter
+// graphemes before the built-in digraphs.
+func (p *Pack) segment(word string) []string {
+	return segmentWith(word, p.graphemeList())
+}
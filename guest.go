@@ -0,0 +1,46 @@
+package phonical
+
+// WithGuest starts the engine in guest mode: letters play as usual but
+// nothing is recorded in the stats and nothing advances, for when a
+// visitor or an adult borrows the computer.
+func WithGuest(on bool) Option {
+	return func(e *Engine) {
+		e.guest.Store(on)
+	}
+}
+
+// WithGuestHotkey lets F12 turn guest mode on and off.
+func WithGuestHotkey(enabled bool) Option {
+	return func(e *Engine) {
+		e.guestHotkey = enabled
+	}
+}
+
+// Guest reports whether guest mode is on.
+func (e *Engine) Guest() bool {
+	return e.guest.Load()
+}
+
+// SetGuest turns guest mode on or off while the engine runs and announces
+// the change.
+func (e *Engine) SetGuest(on bool) {
+	if e.guest.Swap(on) == on {
+		return
+	}
+	msg := "Guest mode off"
+	if on {
+		msg = "Guest mode on"
+	}
+	e.debugf("%s\n", msg)
+	e.enqueueSay(msg + ".")
+	e.notify(msg)
+}
+
+// recordingStats returns the stats store to record progress in, or nil in
+// guest mode or when there is none.
+func (e *Engine) recordingStats() *StatsStore {
+	if e.guest.Load() {
+		return nil
+	}
+	return e.stats
+}
@@ -0,0 +1,67 @@
+package phonical
+
+import (
+	"context"
+	"errors"
+	"os"
+	"runtime"
+
+	"github.com/faiface/beep"
+)
+
+// ErrNoAudioDevice is returned, wrapped, by Start when the sound device
+// can't be opened, as over SSH or on a CI machine without one.
+var ErrNoAudioDevice = errors.New("phonical: no audio device")
+
+// Headless reports whether there is no display for the keyboard hook to
+// listen on, as over SSH or in CI. Run then returns ErrNoInputHook at once
+// rather than retrying.
+func Headless() bool {
+	switch runtime.GOOS {
+	case "darwin", "windows":
+		return false
+	}
+	return os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == ""
+}
+
+// WithAudioFallback lets the engine carry on in silence when Start can't
+// open the sound device, calling warn with the error, which wraps
+// ErrNoAudioDevice. Without it Start returns the error.
+func WithAudioFallback(warn func(err error)) Option {
+	return func(e *Engine) {
+		e.audioFallback = warn
+	}
+}
+
+// initSink opens the sound device for Start, falling back to silentSink
+// when there is none and WithAudioFallback is set.
+func (e *Engine) initSink() error {
+	err := e.sink.Init()
+	if err == nil || e.audioFallback == nil || !errors.Is(err, ErrNoAudioDevice) {
+		return err
+	}
+	e.logf("%v; carrying on without sound", err)
+	e.audioFallback(err)
+	e.sink = silentSink{}
+	return nil
+}
+
+// silentSink stands in for a missing sound device. It reads each sound
+// through without waiting, so level meters and captions still see it.
+type silentSink struct{}
+
+func (silentSink) Init() error { return nil }
+
+func (silentSink) Play(ctx context.Context, p Playback) error {
+	samples := make([][2]float64, 512)
+	for ctx.Err() == nil {
+		if n, ok := p.Streamer.Stream(samples); !ok || n == 0 {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+func (silentSink) Layer(beep.Streamer) {}
+
+func (silentSink) Close() {}
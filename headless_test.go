@@ -0,0 +1,68 @@
+package phonical_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// deviceless is a sound device that isn't there.
+type deviceless struct {
+	*phonicaltest.Sink
+}
+
+func (deviceless) Init() error {
+	return fmt.Errorf("%w: no default output", phonical.ErrNoAudioDevice)
+}
+
+func TestAudioFallback(t *testing.T) {
+	var warned error
+	engine, err := phonical.New(
+		phonical.WithAudioSink(deviceless{phonicaltest.NewSink()}),
+		phonical.WithClock(phonicaltest.NewClock(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))),
+		phonical.WithDoNotDisturb(false),
+		phonical.WithAudioFallback(func(err error) { warned = err }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatalf("start without a device: %v", err)
+	}
+	t.Cleanup(engine.Stop)
+	if !errors.Is(warned, phonical.ErrNoAudioDevice) {
+		t.Errorf("warned %v, want no audio device", warned)
+	}
+	if out := engine.AudioInfo().Output; out != "none" {
+		t.Errorf("output %q, want none", out)
+	}
+
+	// Without the fallback the missing device stops it starting
+	engine, err = phonical.New(phonical.WithAudioSink(deviceless{phonicaltest.NewSink()}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Start(); !errors.Is(err, phonical.ErrNoAudioDevice) {
+		t.Errorf("start returned %v, want no audio device", err)
+	}
+}
+
+func TestHeadless(t *testing.T) {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("always has a display")
+	}
+	t.Setenv("DISPLAY", "")
+	t.Setenv("WAYLAND_DISPLAY", "")
+	if !phonical.Headless() {
+		t.Error("not headless without a display")
+	}
+	t.Setenv("DISPLAY", ":0")
+	if phonical.Headless() {
+		t.Error("headless with an X display")
+	}
+}
@@ -0,0 +1,51 @@
+package phonical
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithHelpHotkey makes F1 speak and print the hotkeys in use and the
+// current mode, voice and sounds, for anyone who has forgotten how to
+// change them and can't see the terminal or the docs.
+func WithHelpHotkey(on bool) Option {
+	return func(e *Engine) {
+		e.helpHotkey = on
+	}
+}
+
+// helpLines describes the current settings and hotkeys, one sentence
+// each.
+func (e *Engine) helpLines() []string {
+	e.mu.Lock()
+	mode, voice := e.mode, e.voice
+	e.mu.Unlock()
+
+	lines := []string{
+		fmt.Sprintf("Mode: %s.", strings.ReplaceAll(mode.String(), "-", " ")),
+		fmt.Sprintf("Voice: %s.", voice),
+		fmt.Sprintf("Sounds: %s.", e.pack.Load().Name),
+	}
+	if e.voiceHotkey {
+		lines = append(lines, "F9 changes the voice.")
+	}
+	if len(e.languages) > 0 {
+		lines = append(lines, "F10 switches language.")
+	}
+	if profile := e.Profile(); len(e.profiles) > 0 {
+		lines = append(lines, fmt.Sprintf("F11 switches profile; this is %s's.", profile))
+	}
+	if e.guestHotkey {
+		lines = append(lines, "F12 turns guest mode on and off.")
+	}
+	return append(lines, "F1 repeats this help.")
+}
+
+// announceHelp prints and speaks the help.
+func (e *Engine) announceHelp() {
+	lines := e.helpLines()
+	for _, line := range lines {
+		fmt.Fprintln(e.out, line)
+	}
+	e.enqueueSay(strings.Join(lines, " "))
+}
@@ -0,0 +1,137 @@
+//go:build !noinput
+
+package phonical
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	hook "github.com/robotn/gohook"
+)
+
+// HasHook reports whether this build can listen to the keyboard
+// system-wide. Builds with the noinput tag leave the hook out.
+func HasHook() bool {
+	return true
+}
+
+// Run starts the system-wide keyboard hook and feeds key presses to the
+// engine until Stop is called.
+func (e *Engine) Run() error {
+	return e.RunContext(context.Background())
+}
+
+// RunContext is like Run but also returns, leaving the engine running, once
+// ctx is cancelled, so the hook can be stopped and started again on its own.
+// When the hook fails to start it explains the likely cause and tries
+// again, backing off as set by WithHookRetry. Without a display to listen
+// on it returns ErrNoInputHook at once.
+func (e *Engine) RunContext(ctx context.Context) error {
+	if err := e.Start(); err != nil {
+		return err
+	}
+	if Headless() {
+		return fmt.Errorf("%w: %s", ErrNoInputHook, hookFailureHint())
+	}
+
+	backoff := e.hookRetry
+	for attempt := 1; ; attempt++ {
+		err := e.runHook(ctx)
+		if !errors.Is(err, ErrNoInputHook) {
+			return err
+		}
+		if e.hookRetry <= 0 {
+			e.hookFailed(attempt, 0)
+			return hookError()
+		}
+		e.hookFailed(attempt, backoff)
+		select {
+		case <-e.clock.After(backoff):
+		case <-e.ctx.Done():
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if backoff *= 2; backoff > e.maxHookRetry {
+			backoff = e.maxHookRetry
+		}
+	}
+}
+
+// typedWait is how long a key press waits for the character it types
+// before being handled as a key with no character. The hook reports the
+// character straight after the press, so this only delays keys such as
+// F1 or the arrows, and only when no other event comes first.
+const typedWait = 30 * time.Millisecond
+
+// runHook runs the keyboard hook once, returning ErrNoInputHook if it
+// doesn't report that it started within hookStartTimeout or is disabled
+// afterwards.
+func (e *Engine) runHook(ctx context.Context) error {
+	evChan := hook.Start()
+	defer hook.End()
+	return e.hookEvents(ctx, evChan)
+}
+
+// hookEvents handles the hook's events until it stops. The hook reports a
+// key twice: pressed (KeyHold), with its keycodes, then typed (KeyDown),
+// with the character but no keycode, leaving out the second for keys such
+// as F1 that type nothing. The two are merged into one Key.
+func (e *Engine) hookEvents(ctx context.Context, evChan <-chan hook.Event) error {
+	started := e.clock.After(hookStartTimeout)
+	var pressed *hook.Event
+	var waiting <-chan time.Time
+	flush := func() {
+		if pressed != nil {
+			e.debugf("Non-character key: rawcode=%d\n", pressed.Rawcode)
+			e.hookKey(Key{Code: pressed.Keycode, Rawcode: pressed.Rawcode, Mods: Modifier(pressed.Mask)})
+			pressed, waiting = nil, nil
+		}
+	}
+	for {
+		select {
+		case ev := <-evChan:
+			e.debugf("Event: Kind=%d, Rawcode=%d, Keychar=%d, Keycode=%d\n", ev.Kind, ev.Rawcode, ev.Keychar, ev.Keycode)
+			switch ev.Kind {
+			case hook.HookEnabled:
+				e.debugf("Keyboard hook started\n")
+				started = nil
+			case hook.HookDisabled:
+				return ErrNoInputHook
+			case hook.KeyHold:
+				started = nil
+				flush()
+				pressed, waiting = &ev, e.clock.After(typedWait)
+			case hook.KeyDown:
+				started = nil
+				key := Key{Rawcode: ev.Rawcode, Mods: Modifier(ev.Mask)}
+				if pressed != nil {
+					key = Key{Code: pressed.Keycode, Rawcode: pressed.Rawcode, Mods: Modifier(pressed.Mask)}
+					pressed, waiting = nil, nil
+				}
+				if ev.Keychar != 0 && ev.Keychar != hook.CharUndefined {
+					key.Char = ev.Keychar
+				}
+				e.hookKey(key)
+			case hook.KeyUp:
+				flush()
+			}
+		case <-waiting:
+			flush()
+		case <-started:
+			return ErrNoInputHook
+		case <-e.ctx.Done():
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// hookKey handles a key press reported by the hook.
+func (e *Engine) hookKey(key Key) {
+	e.hookKeys.Add(1)
+	e.HandleKey(key)
+}
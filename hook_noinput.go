@@ -0,0 +1,25 @@
+//go:build noinput
+
+package phonical
+
+import (
+	"context"
+	"fmt"
+)
+
+// HasHook reports whether this build can listen to the keyboard
+// system-wide. Builds with the noinput tag leave the hook out.
+func HasHook() bool {
+	return false
+}
+
+// Run is unavailable in noinput builds; use RunReader or Replay instead.
+func (e *Engine) Run() error {
+	return e.RunContext(context.Background())
+}
+
+// RunContext is unavailable in noinput builds; use RunReaderContext or
+// ReplayContext instead.
+func (e *Engine) RunContext(ctx context.Context) error {
+	return fmt.Errorf("%w: built without it (noinput); use RunReader or Replay", ErrNoInputHook)
+}
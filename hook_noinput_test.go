@@ -0,0 +1,21 @@
+//go:build noinput
+
+package phonical_test
+
+import (
+	"errors"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestNoInputBuild(t *testing.T) {
+	if phonical.HasHook() {
+		t.Error("noinput build reports a hook")
+	}
+	h := phonicaltest.New(t)
+	if err := h.Engine.Run(); !errors.Is(err, phonical.ErrNoInputHook) {
+		t.Errorf("Run() = %v, want %v", err, phonical.ErrNoInputHook)
+	}
+}
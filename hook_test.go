@@ -0,0 +1,246 @@
+//go:build !noinput
+
+package phonical_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	hook "github.com/robotn/gohook"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// Events as the hook reports them on X11, where Rawcode is the keysym.
+var (
+	hookEnabled = hook.Event{Kind: hook.HookEnabled}
+	pressC      = hook.Event{Kind: hook.KeyHold, Keycode: 0x2E, Rawcode: 0x63}
+	typedC      = hook.Event{Kind: hook.KeyDown, Rawcode: 0x63, Keychar: 'c'}
+	releaseC    = hook.Event{Kind: hook.KeyUp, Keycode: 0x2E, Rawcode: 0x63}
+	pressShift  = hook.Event{Kind: hook.KeyHold, Keycode: 0x2A, Rawcode: 0xFFE1, Mask: 1}
+	pressA      = hook.Event{Kind: hook.KeyHold, Keycode: 0x1E, Rawcode: 0x41, Mask: 1}
+	typedA      = hook.Event{Kind: hook.KeyDown, Rawcode: 0x41, Keychar: 'A', Mask: 1}
+	releaseA    = hook.Event{Kind: hook.KeyUp, Keycode: 0x1E, Rawcode: 0x41, Mask: 1}
+	pressLeft   = hook.Event{Kind: hook.KeyHold, Keycode: phonical.KeyLeft, Rawcode: 0xFF51}
+	releaseLeft = hook.Event{Kind: hook.KeyUp, Keycode: phonical.KeyLeft, Rawcode: 0xFF51}
+	pressF12    = hook.Event{Kind: hook.KeyHold, Keycode: phonical.KeyF12, Rawcode: 0xFFC9}
+)
+
+// replayHook feeds events through the engine's hook loop, returning once
+// it has handled that many key presses.
+func replayHook(t *testing.T, h *phonicaltest.Harness, keys int64, events ...hook.Event) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan hook.Event, len(events))
+	for _, ev := range events {
+		ch <- ev
+	}
+	done := make(chan error)
+	go func() { done <- h.Engine.HookEvents(ctx, ch) }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for h.Engine.Status().KeysHandled < keys {
+		if time.Now().After(deadline) {
+			t.Fatalf("handled %d of %d keys", h.Engine.Status().KeysHandled, keys)
+		}
+		// Lets a key with no character stop waiting for one
+		h.Clock.Advance(10 * time.Millisecond)
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("hook loop returned %v", err)
+	}
+}
+
+// loggedKey is a key press as WithKeyLog writes it.
+type loggedKey struct {
+	Char string `json:"char"`
+	Code uint16 `json:"code"`
+	Raw  uint16 `json:"raw"`
+	Mods uint16 `json:"mods"`
+}
+
+func readKeyLog(t *testing.T, log *bytes.Buffer) []loggedKey {
+	t.Helper()
+	var keys []loggedKey
+	dec := json.NewDecoder(log)
+	for dec.More() {
+		var k loggedKey
+		if err := dec.Decode(&k); err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func TestHookMergesPressAndTyped(t *testing.T) {
+	var log bytes.Buffer
+	h := phonicaltest.New(t, phonical.WithKeyLog(&log))
+	replayHook(t, h, 4, hookEnabled,
+		pressC, typedC, releaseC,
+		pressShift, pressA, typedA, releaseA,
+		pressLeft, releaseLeft)
+
+	want := []loggedKey{
+		{Char: "c", Code: 0x2E, Raw: 0x63},
+		{Code: 0x2A, Raw: 0xFFE1, Mods: 1},
+		{Char: "A", Code: 0x1E, Raw: 0x41, Mods: 1},
+		{Code: phonical.KeyLeft, Raw: 0xFF51},
+	}
+	if got := readKeyLog(t, &log); !reflect.DeepEqual(got, want) {
+		t.Errorf("keys %+v, want %+v", got, want)
+	}
+	h.Expect("c.wav", "a.wav")
+}
+
+func TestHookKeyWithoutRelease(t *testing.T) {
+	// F12 held down: nothing follows the press, so only the wait for a
+	// character it will never type lets it through
+	var log bytes.Buffer
+	h := phonicaltest.New(t, phonical.WithKeyLog(&log))
+	replayHook(t, h, 1, hookEnabled, pressF12)
+
+	want := []loggedKey{{Code: phonical.KeyF12, Raw: 0xFFC9}}
+	if got := readKeyLog(t, &log); !reflect.DeepEqual(got, want) {
+		t.Errorf("keys %+v, want %+v", got, want)
+	}
+}
+
+func TestHookGuestHotkey(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithGuestHotkey(true))
+	replayHook(t, h, 1, hookEnabled, pressF12)
+	h.Expect("say:Guest mode on.")
+	if !h.Engine.Guest() {
+		t.Error("F12 didn't turn guest mode on")
+	}
+}
+
+func TestHookNavigationKeys(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithNavigationNames(true))
+	replayHook(t, h, 1, hookEnabled, pressLeft, releaseLeft)
+	h.Expect("say:left")
+}
+
+func TestHookKeyPanning(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithKeyPanning(true))
+	replayHook(t, h, 1, hookEnabled,
+		hook.Event{Kind: hook.KeyHold, Keycode: 0x10, Rawcode: 0x71},
+		hook.Event{Kind: hook.KeyDown, Rawcode: 0x71, Keychar: 'q'})
+	h.Expect("q.wav")
+	// Hard left leaves the right channel silent
+	var left, right float64
+	samples := make([][2]float64, 512)
+	streamer := h.Sink.Playbacks()[0].Streamer
+	for {
+		n, ok := streamer.Stream(samples)
+		for _, s := range samples[:n] {
+			left += math.Abs(s[0])
+			right += math.Abs(s[1])
+		}
+		if !ok {
+			break
+		}
+	}
+	if left == 0 || right != 0 {
+		t.Errorf("q played at left %g, right %g, want only left", left, right)
+	}
+}
+
+func TestHookHelpHotkey(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithHelpHotkey(true))
+	replayHook(t, h, 1, hookEnabled,
+		hook.Event{Kind: hook.KeyHold, Keycode: phonical.KeyF1, Rawcode: 0xFFBE})
+	played, _ := h.Sink.WaitFor(1, time.Second)
+	if len(played) != 1 || !strings.HasPrefix(played[0], "say:Mode:") || !strings.HasSuffix(played[0], "F1 repeats this help.") {
+		t.Errorf("F1 played %q, want the help", played)
+	}
+}
+
+func TestHookVoiceHotkey(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithVoiceHotkey(true))
+	replayHook(t, h, 1, hookEnabled,
+		hook.Event{Kind: hook.KeyHold, Keycode: phonical.KeyF9, Rawcode: 0xFFC6},
+		hook.Event{Kind: hook.KeyUp, Keycode: phonical.KeyF9, Rawcode: 0xFFC6})
+	h.Expect("say:chipmunk voice")
+}
+
+func TestHookEditingKeys(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithRemovalAnnouncements(true))
+	press := func(code, raw uint16) hook.Event {
+		return hook.Event{Kind: hook.KeyHold, Keycode: code, Rawcode: raw}
+	}
+	replayHook(t, h, 8, hookEnabled,
+		pressC, typedC, releaseC,
+		press(0x1E, 0x61), hook.Event{Kind: hook.KeyDown, Rawcode: 0x61, Keychar: 'a'},
+		press(0x14, 0x74), hook.Event{Kind: hook.KeyDown, Rawcode: 0x74, Keychar: 't'},
+		press(phonical.KeyHome, 0xFF50),
+		press(phonical.KeyDelete, 0xFFFF),
+		press(phonical.KeyEnd, 0xFF57),
+		press(phonical.KeyLeft, 0xFF51),
+		press(phonical.KeyBackspace, 0xFF08), hook.Event{Kind: hook.KeyDown, Rawcode: 0xFF08, Keychar: '\b'})
+	h.Expect("c.wav", "a.wav", "t.wav", "say:took away c", "say:took away a")
+	if word := h.Engine.Status().Word; word != "t" {
+		t.Errorf("word %q, want t", word)
+	}
+}
+
+func TestHookFailsToStart(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithWatchdog(0))
+	// A hook that never reports starting has failed after a few seconds
+	done := make(chan error, 1)
+	go func() { done <- h.Engine.HookEvents(context.Background(), make(chan hook.Event)) }()
+	waitWaiters(t, h.Clock, 1)
+	h.Clock.Advance(3 * time.Second)
+	if err := <-done; !errors.Is(err, phonical.ErrNoInputHook) {
+		t.Errorf("hook that never started returned %v", err)
+	}
+
+	// So has one that is disabled once running
+	ch := make(chan hook.Event, 2)
+	ch <- hookEnabled
+	ch <- hook.Event{Kind: hook.HookDisabled}
+	if err := h.Engine.HookEvents(context.Background(), ch); !errors.Is(err, phonical.ErrNoInputHook) {
+		t.Errorf("disabled hook returned %v", err)
+	}
+}
+
+func TestHookRetryChecked(t *testing.T) {
+	if _, err := phonical.New(phonical.WithHookRetry(time.Minute, time.Second)); err == nil {
+		t.Error("accepted a maximum hook retry shorter than the first")
+	}
+	if _, err := phonical.New(phonical.WithHookRetry(0, 0)); err != nil {
+		t.Errorf("turning hook retries off failed: %v", err)
+	}
+}
+
+func TestHookFailureShown(t *testing.T) {
+	t.Setenv("DISPLAY", ":0")
+	var errOut syncBuffer
+	h := phonicaltest.New(t, phonical.WithWatchdog(0), phonical.WithErrorOutput(&errOut),
+		phonical.WithHookRetry(time.Minute, time.Minute))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- h.Engine.RunContext(ctx) }()
+
+	// Without WithVerbose the user is still told why keys go unheard, with
+	// a hint at what to do
+	waitOutput(t, h, &errOut, "\n")
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run returned %v", err)
+	}
+	prefix := phonical.ErrNoInputHook.Error() + ". "
+	if got := errOut.String(); !strings.HasPrefix(got, prefix) || len(got) <= len(prefix)+1 {
+		t.Errorf("hook failure shown as %q, want %q and a hint", got, prefix)
+	}
+}
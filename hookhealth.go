@@ -0,0 +1,115 @@
+package phonical
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+)
+
+// Default backoff between attempts to start the keyboard hook after it
+// fails to start.
+const (
+	DefaultHookRetry    = 2 * time.Second
+	DefaultMaxHookRetry = time.Minute
+)
+
+// hookStartTimeout is how long the keyboard hook has to report that it is
+// running before it is taken to have failed.
+const hookStartTimeout = 3 * time.Second
+
+// ErrNoInputHook is returned, wrapped, by Run when the keyboard hook can't
+// listen system-wide: there is no display, the build leaves it out, or it
+// fails to start and WithHookRetry turns retrying off.
+var ErrNoInputHook = errors.New("phonical: keyboard hook unavailable")
+
+// ErrPermissionDenied matches errors caused by a missing permission: the
+// keyboard hook on macOS without Accessibility and Input Monitoring, or a
+// sound file Phonical isn't allowed to read.
+var ErrPermissionDenied = errors.New("phonical: permission denied")
+
+// deniedError marks an error as caused by a missing permission, so it
+// matches ErrPermissionDenied as well as what it wraps.
+type deniedError struct {
+	err error
+}
+
+// denied marks err as caused by a missing permission.
+func denied(err error) error {
+	return deniedError{err: err}
+}
+
+func (d deniedError) Error() string        { return d.err.Error() }
+func (d deniedError) Unwrap() error        { return d.err }
+func (d deniedError) Is(target error) bool { return target == ErrPermissionDenied }
+
+// hookError is the error Run returns for a keyboard hook that failed to
+// start. On macOS that comes down to a missing permission.
+func hookError() error {
+	err := fmt.Errorf("%w: %s", ErrNoInputHook, hookFailureHint())
+	if runtime.GOOS == "darwin" {
+		return denied(err)
+	}
+	return err
+}
+
+// WithHookRetry sets the backoff used to start the keyboard hook again
+// when it fails, as it does without permission or in a Wayland session: the
+// first retry waits min, doubling up to max. A min of 0 makes Run return
+// the error instead. Defaults to DefaultHookRetry and DefaultMaxHookRetry.
+func WithHookRetry(min, max time.Duration) Option {
+	return func(e *Engine) {
+		e.hookRetry = min
+		e.maxHookRetry = max
+	}
+}
+
+// checkHookRetry validates the hook retry settings for New.
+func (e *Engine) checkHookRetry() error {
+	if e.hookRetry > 0 && e.maxHookRetry < e.hookRetry {
+		return fmt.Errorf("maximum hook retry %s is shorter than the first %s", e.maxHookRetry, e.hookRetry)
+	}
+	return nil
+}
+
+// hookFailureHint explains the likely reason the keyboard hook failed on
+// this platform.
+func hookFailureHint() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "macOS is blocking keyboard access. Allow your terminal under System Settings → " +
+			"Privacy & Security → Accessibility and Input Monitoring."
+	case "linux":
+		switch {
+		case os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") != "":
+			return "This is a Wayland session, which doesn't let programs hear keys system-wide. " +
+				"Log in with an X11 session, or use --input stdin."
+		case os.Getenv("DISPLAY") == "":
+			return "There is no X display, as over SSH or on a machine without a screen. " +
+				"Use --input stdin to type into Phonical directly."
+		}
+		return "The X server refused the keyboard hook; check that its XRecord extension is enabled."
+	case "windows":
+		return "Windows refused the keyboard hook, as it can in remote and restricted sessions."
+	}
+	return "The keyboard hook is not supported here; use --input stdin."
+}
+
+// hookFailed reports a failed keyboard hook the first time it happens.
+// While retrying it is written out even without WithVerbose, as nothing
+// else tells the user why their keys go unheard; without retries Run
+// returns it instead.
+func (e *Engine) hookFailed(attempt int, retry time.Duration) {
+	e.hookFailures.Add(1)
+	if attempt == 1 {
+		e.logf("%v. %s", ErrNoInputHook, hookFailureHint())
+		if retry > 0 && !e.verbose {
+			fmt.Fprintf(e.errOut, "%v. %s\n", ErrNoInputHook, hookFailureHint())
+		}
+		e.notify("Phonical can't hear the keyboard: " + hookFailureHint())
+	}
+	if retry > 0 {
+		e.debugf("Starting the keyboard hook again in %s\n", retry)
+	}
+}
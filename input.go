@@ -0,0 +1,185 @@
+package phonical
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// WithKeyLog records every key press to w as JSON lines, one per key with
+// its delay since the first, so a session can be played back with Replay.
+func WithKeyLog(w io.Writer) Option {
+	return func(e *Engine) {
+		e.keyLog = &keyLog{enc: json.NewEncoder(w)}
+	}
+}
+
+// loggedKey is one line of a key log.
+type loggedKey struct {
+	// Offset is milliseconds since the first key.
+	Offset  int64  `json:"t"`
+	Char    string `json:"char,omitempty"`
+	Code    uint16 `json:"code,omitempty"`
+	Rawcode uint16 `json:"raw,omitempty"`
+	Mods    uint16 `json:"mods,omitempty"`
+}
+
+type keyLog struct {
+	mu    sync.Mutex
+	enc   *json.Encoder
+	start time.Time
+}
+
+func (l *keyLog) record(key Key) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if l.start.IsZero() {
+		l.start = now
+	}
+	entry := loggedKey{Offset: now.Sub(l.start).Milliseconds(), Code: key.Code, Rawcode: key.Rawcode, Mods: uint16(key.Mods)}
+	if key.Char != 0 {
+		entry.Char = string(key.Char)
+	}
+	l.enc.Encode(&entry)
+}
+
+// RunReader feeds the characters read from r to the engine as if they were
+// typed, until r ends or Stop is called. It works without the system-wide
+// hook, for piped input and builds with the noinput tag.
+func (e *Engine) RunReader(r io.Reader) error {
+	return e.RunReaderContext(context.Background(), r)
+}
+
+// RunReaderContext is like RunReader but also returns, leaving the engine
+// running, once ctx is cancelled.
+func (e *Engine) RunReaderContext(ctx context.Context, r io.Reader) error {
+	if err := e.Start(); err != nil {
+		return err
+	}
+
+	runes := make(chan rune)
+	errc := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		br := bufio.NewReader(r)
+		for {
+			c, _, err := br.ReadRune()
+			if err != nil {
+				errc <- err
+				return
+			}
+			select {
+			case runes <- c:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case c := <-runes:
+			e.HandleKey(charKey(c))
+		case err := <-errc:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		case <-e.ctx.Done():
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// KeySource delivers key presses to RunSource, such as a scripted
+// sequence in tests or another program's input events.
+type KeySource interface {
+	// Keys returns the presses to handle. The source closes the channel
+	// when it runs out.
+	Keys() <-chan Key
+}
+
+// RunSource feeds the presses from src to the engine until src runs out,
+// ctx is cancelled or Stop is called.
+func (e *Engine) RunSource(ctx context.Context, src KeySource) error {
+	if err := e.Start(); err != nil {
+		return err
+	}
+	keys := src.Keys()
+	for {
+		select {
+		case key, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			e.HandleKey(key)
+		case <-e.ctx.Done():
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// charKey builds the Key a typed character would produce.
+func charKey(c rune) Key {
+	key := Key{Char: c}
+	switch c {
+	case '\n', '\r':
+		key.Code = KeyEnter
+	case '\b', 0x7f:
+		key = Key{Code: KeyBackspace}
+	case ' ':
+		key.Code = KeySpace
+	}
+	return key
+}
+
+// Replay feeds the key presses in a log written by WithKeyLog to the
+// engine, keeping their original timing, until the log ends or Stop is
+// called.
+func (e *Engine) Replay(r io.Reader) error {
+	return e.ReplayContext(context.Background(), r)
+}
+
+// ReplayContext is like Replay but also returns, leaving the engine
+// running, once ctx is cancelled.
+func (e *Engine) ReplayContext(ctx context.Context, r io.Reader) error {
+	if err := e.Start(); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	dec := json.NewDecoder(r)
+	for {
+		var entry loggedKey
+		if err := dec.Decode(&entry); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		select {
+		case <-time.After(time.Until(start.Add(time.Duration(entry.Offset) * time.Millisecond))):
+		case <-e.ctx.Done():
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		key := Key{Code: entry.Code, Rawcode: entry.Rawcode, Mods: Modifier(entry.Mods)}
+		key.Char, _ = utf8.DecodeRuneInString(entry.Char)
+		if key.Char == utf8.RuneError {
+			key.Char = 0
+		}
+		e.HandleKey(key)
+	}
+}
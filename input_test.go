@@ -0,0 +1,71 @@
+package phonical_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestRunReader(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithCues(true))
+	if err := h.Engine.RunReader(strings.NewReader("ab\n")); err != nil {
+		t.Fatal(err)
+	}
+	h.Expect("a.wav", "b.wav", "cue:enter")
+}
+
+func TestKeyLogReplay(t *testing.T) {
+	var log syncBuffer
+	h := phonicaltest.New(t, phonical.WithKeyLog(&log))
+	h.Type("a{code 0x2a}B")
+	h.Expect("a.wav", "b.wav")
+	if lines := strings.Count(log.String(), "\n"); lines != 3 {
+		t.Fatalf("logged %d keys, want 3:\n%s", lines, log.String())
+	}
+
+	replay := phonicaltest.New(t)
+	if err := replay.Engine.Replay(strings.NewReader(log.String())); err != nil {
+		t.Fatal(err)
+	}
+	replay.Expect("a.wav", "b.wav")
+}
+
+func TestRunReaderContextLeavesEngineRunning(t *testing.T) {
+	h := phonicaltest.New(t)
+	r, w := io.Pipe()
+	defer w.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- h.Engine.RunReaderContext(ctx, r) }()
+
+	w.Write([]byte("a"))
+	h.Expect("a.wav")
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Fatalf("RunReaderContext returned %v, want context.Canceled", err)
+	}
+	h.Type("b")
+	h.Expect("a.wav", "b.wav")
+}
+
+func TestStopCutsOffSound(t *testing.T) {
+	h := phonicaltest.New(t)
+	h.Sink.Hold()
+	h.Type("a")
+	h.Sink.WaitFor(1, time.Second)
+	h.Engine.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for len(h.Sink.Interrupted()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := h.Sink.Interrupted(); len(got) != 1 || got[0] != "a.wav" {
+		t.Errorf("interrupted %q, want [a.wav]", got)
+	}
+}
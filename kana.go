@@ -0,0 +1,192 @@
+package phonical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// romajiKana maps romaji syllables to hiragana.
+var romajiKana = map[string]string{
+	"a": "あ", "i": "い", "u": "う", "e": "え", "o": "お",
+	"ka": "か", "ki": "き", "ku": "く", "ke": "け", "ko": "こ",
+	"sa": "さ", "shi": "し", "si": "し", "su": "す", "se": "せ", "so": "そ",
+	"ta": "た", "chi": "ち", "ti": "ち", "tsu": "つ", "tu": "つ", "te": "て", "to": "と",
+	"na": "な", "ni": "に", "nu": "ぬ", "ne": "ね", "no": "の",
+	"ha": "は", "hi": "ひ", "fu": "ふ", "hu": "ふ", "he": "へ", "ho": "ほ",
+	"ma": "ま", "mi": "み", "mu": "む", "me": "め", "mo": "も",
+	"ya": "や", "yu": "ゆ", "yo": "よ",
+	"ra": "ら", "ri": "り", "ru": "る", "re": "れ", "ro": "ろ",
+	"wa": "わ", "wo": "を", "nn": "ん",
+	"ga": "が", "gi": "ぎ", "gu": "ぐ", "ge": "げ", "go": "ご",
+	"za": "ざ", "ji": "じ", "zi": "じ", "zu": "ず", "ze": "ぜ", "zo": "ぞ",
+	"da": "だ", "di": "ぢ", "du": "づ", "de": "で", "do": "ど",
+	"ba": "ば", "bi": "び", "bu": "ぶ", "be": "べ", "bo": "ぼ",
+	"pa": "ぱ", "pi": "ぴ", "pu": "ぷ", "pe": "ぺ", "po": "ぽ",
+	"kya": "きゃ", "kyu": "きゅ", "kyo": "きょ",
+	"sha": "しゃ", "shu": "しゅ", "sho": "しょ",
+	"cha": "ちゃ", "chu": "ちゅ", "cho": "ちょ",
+	"nya": "にゃ", "nyu": "にゅ", "nyo": "にょ",
+	"hya": "ひゃ", "hyu": "ひゅ", "hyo": "ひょ",
+	"mya": "みゃ", "myu": "みゅ", "myo": "みょ",
+	"rya": "りゃ", "ryu": "りゅ", "ryo": "りょ",
+	"gya": "ぎゃ", "gyu": "ぎゅ", "gyo": "ぎょ",
+	"ja": "じゃ", "ju": "じゅ", "jo": "じょ",
+	"bya": "びゃ", "byu": "びゅ", "byo": "びょ",
+	"pya": "ぴゃ", "pyu": "ぴゅ", "pyo": "ぴょ",
+}
+
+// romajiPrefixes holds every proper prefix of a romaji syllable, so the
+// composer knows when to keep waiting.
+var romajiPrefixes = map[string]bool{}
+
+func init() {
+	for r := range romajiKana {
+		for i := 1; i < len(r); i++ {
+			romajiPrefixes[r[:i]] = true
+		}
+	}
+}
+
+// Kana scripts accepted by WithKana.
+const (
+	Hiragana = "hiragana"
+	Katakana = "katakana"
+)
+
+// smallTsu marks a doubled consonant ("kk" in "kitte"), heard as a short
+// catch before the next syllable.
+const smallTsu = "っ"
+
+// WithKana reads typed letters as romaji and plays the kana they spell,
+// k then a playing か, for children learning kana. script is Hiragana or
+// Katakana; empty disables it. Packs provide kana sounds in their
+// [letters] table ("か" = "ka.wav"); kana without one are spoken in
+// romaji.
+func WithKana(script string) Option {
+	return func(e *Engine) {
+		e.kana = script
+	}
+}
+
+// checkKana validates the WithKana script for New.
+func (e *Engine) checkKana() error {
+	if e.kana != "" && e.kana != Hiragana && e.kana != Katakana {
+		return fmt.Errorf("unknown kana script %q (want %s or %s)", e.kana, Hiragana, Katakana)
+	}
+	return nil
+}
+
+// syllable is a kana completed by the romaji composer.
+type syllable struct {
+	kana   string
+	romaji string
+}
+
+// romajiComposer collects typed letters until they spell a syllable.
+type romajiComposer struct {
+	pending string
+}
+
+// feed takes the next letter and returns the syllables it completes.
+func (c *romajiComposer) feed(r rune) []syllable {
+	c.pending += string(r)
+	var out []syllable
+	for c.pending != "" {
+		if kana, ok := romajiKana[c.pending]; ok {
+			out = append(out, syllable{kana, c.pending})
+			c.pending = ""
+			break
+		}
+		if len(c.pending) >= 2 {
+			first, second := c.pending[0], c.pending[1]
+			if first == 'n' && !strings.ContainsRune("aiueoy", rune(second)) {
+				// n before a consonant is ん
+				out = append(out, syllable{"ん", "n"})
+				c.pending = c.pending[1:]
+				continue
+			}
+			if first == second && !strings.ContainsRune("aiueo", rune(first)) {
+				out = append(out, syllable{smallTsu, ""})
+				c.pending = c.pending[1:]
+				continue
+			}
+		}
+		if romajiPrefixes[c.pending] {
+			break
+		}
+		// Nothing starts like this; drop the first letter and try again
+		c.pending = c.pending[1:]
+	}
+	return out
+}
+
+// flush ends the word, completing a trailing n.
+func (c *romajiComposer) flush() []syllable {
+	defer func() { c.pending = "" }()
+	if c.pending == "n" {
+		return []syllable{{"ん", "n"}}
+	}
+	return nil
+}
+
+// toKatakana converts hiragana to katakana.
+func toKatakana(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'ぁ' && r <= 'ゖ' {
+			return r + 0x60
+		}
+		return r
+	}, s)
+}
+
+// typeRomaji feeds a typed letter to the romaji composer and plays any
+// kana it completes.
+func (e *Engine) typeRomaji(char rune) {
+	e.mu.Lock()
+	done := e.romaji.feed(char)
+	e.mu.Unlock()
+	e.playKana(done)
+}
+
+// flushRomaji completes any syllable still waiting at a word boundary.
+func (e *Engine) flushRomaji() {
+	e.mu.Lock()
+	done := e.romaji.flush()
+	e.mu.Unlock()
+	e.playKana(done)
+}
+
+func (e *Engine) playKana(syllables []syllable) {
+	pack := e.pack.Load()
+	for _, s := range syllables {
+		kana := s.kana
+		if e.kana == Katakana {
+			kana = toKatakana(kana)
+		}
+		e.debugf("Kana: %s (%s)\n", kana, s.romaji)
+
+		e.mu.Lock()
+		for _, r := range kana {
+			e.word.insert(r)
+		}
+		e.session.Letters++
+		e.mu.Unlock()
+
+		if e.visual {
+			e.caption(kana)
+		}
+		file, ok := pack.Graphemes[kana]
+		if !ok && len([]rune(kana)) == 1 {
+			file, ok = pack.letterSound([]rune(kana)[0], e.variant)
+		}
+		switch {
+		case ok:
+			e.enqueue(&queuedSound{sound: file, letter: []rune(kana)[0], typed: true})
+		case s.romaji == "":
+			e.enqueuePause(150 * time.Millisecond)
+		default:
+			e.enqueueSay(s.romaji)
+		}
+	}
+}
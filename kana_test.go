@@ -0,0 +1,47 @@
+package phonical_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestKana(t *testing.T) {
+	var mu sync.Mutex
+	var words []string
+	h := phonicaltest.New(t,
+		phonical.WithKana(phonical.Hiragana),
+		phonical.WithPack(letterPack(t, map[string]string{"か": "ka"})),
+		phonical.OnWord(func(ev phonical.WordEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			words = append(words, ev.Word)
+		}))
+	// Kana without a recording are spoken in romaji, the doubled t is a
+	// pause, and a trailing n completes at the space
+	h.Type("kitte{space}kan{space}")
+	h.Expect("say:ki", "say:te", "ka.wav", "say:n")
+
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"きって", "かん"}; !reflect.DeepEqual(words, want) {
+		t.Errorf("words %q, want %q", words, want)
+	}
+}
+
+func TestKatakana(t *testing.T) {
+	h := phonicaltest.New(t,
+		phonical.WithKana(phonical.Katakana),
+		phonical.WithPack(letterPack(t, map[string]string{"カ": "ka"})))
+	h.Type("ka")
+	h.Expect("ka.wav")
+}
+
+func TestKanaScriptChecked(t *testing.T) {
+	if _, err := phonical.New(phonical.WithKana("kanji")); err == nil {
+		t.Error("unknown kana script accepted")
+	}
+}
@@ -0,0 +1,156 @@
+package phonical
+
+import (
+	"runtime"
+	"unicode"
+)
+
+// Layout-independent keycodes reported in Key.Code, matching the hook's
+// virtual keycodes on every platform.
+const (
+	KeyEscape    uint16 = 0x0001
+	KeyBackspace uint16 = 0x000E
+	KeyTab       uint16 = 0x000F
+	KeyEnter     uint16 = 0x001C
+	KeySpace     uint16 = 0x0039
+	KeyInsert    uint16 = 0x0E52
+	KeyDelete    uint16 = 0x0E53
+	KeyHome      uint16 = 0x0E47
+	KeyEnd       uint16 = 0x0E4F
+	KeyPageUp    uint16 = 0x0E49
+	KeyPageDown  uint16 = 0x0E51
+	KeyUp        uint16 = 0xE048
+	KeyLeft      uint16 = 0xE04B
+	KeyRight     uint16 = 0xE04D
+	KeyDown      uint16 = 0xE050
+	KeyF1        uint16 = 0x003B
+	KeyF9        uint16 = 0x0043
+	KeyF10       uint16 = 0x0044
+	KeyF11       uint16 = 0x0057
+	KeyF12       uint16 = 0x0058
+)
+
+// navigationNames are the names spoken for navigation keys, also used as
+// keys in a pack's [navigation] table.
+var navigationNames = map[uint16]string{
+	KeyUp:       "up",
+	KeyDown:     "down",
+	KeyLeft:     "left",
+	KeyRight:    "right",
+	KeyTab:      "tab",
+	KeyEscape:   "escape",
+	KeyHome:     "home",
+	KeyEnd:      "end",
+	KeyPageUp:   "page up",
+	KeyPageDown: "page down",
+	KeyInsert:   "insert",
+	KeyDelete:   "delete",
+}
+
+// KeyNamed returns the press of the key called name: a navigation name
+// such as "page up", or "enter", "space", "backspace", "f1", "f9", "f10", "f11" or "f12".
+func KeyNamed(name string) (Key, bool) {
+	switch name {
+	case "enter":
+		return Key{Char: '\n', Code: KeyEnter}, true
+	case "space":
+		return Key{Char: ' ', Code: KeySpace}, true
+	case "backspace":
+		return Key{Code: KeyBackspace}, true
+	case "f1":
+		return Key{Code: KeyF1}, true
+	case "f9":
+		return Key{Code: KeyF9}, true
+	case "f10":
+		return Key{Code: KeyF10}, true
+	case "f11":
+		return Key{Code: KeyF11}, true
+	case "f12":
+		return Key{Code: KeyF12}, true
+	}
+	for code, n := range navigationNames {
+		if n == name {
+			return Key{Code: code}, true
+		}
+	}
+	return Key{}, false
+}
+
+// isNavigationName reports whether name is a key in navigationNames.
+func isNavigationName(name string) bool {
+	for _, n := range navigationNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// vcLetters maps the hook's layout-independent keycodes for the letter and
+// digit rows to the characters they carry on a US layout.
+var vcLetters = map[uint16]rune{}
+
+// rawLetters maps each platform's native keycodes (Key.Rawcode) to
+// characters, for events where neither Char nor Code is usable.
+var rawLetters = map[string]map[uint16]rune{
+	// macOS virtual keycodes (kVK_ANSI_*)
+	"darwin": {
+		0x00: 'a', 0x01: 's', 0x02: 'd', 0x03: 'f', 0x04: 'h', 0x05: 'g',
+		0x06: 'z', 0x07: 'x', 0x08: 'c', 0x09: 'v', 0x0B: 'b', 0x0C: 'q',
+		0x0D: 'w', 0x0E: 'e', 0x0F: 'r', 0x10: 'y', 0x11: 't', 0x12: '1',
+		0x13: '2', 0x14: '3', 0x15: '4', 0x16: '6', 0x17: '5', 0x19: '9',
+		0x1A: '7', 0x1C: '8', 0x1D: '0', 0x1F: 'o', 0x20: 'u', 0x22: 'i',
+		0x23: 'p', 0x25: 'l', 0x26: 'j', 0x28: 'k', 0x2D: 'n', 0x2E: 'm',
+	},
+	"windows": {},
+	"linux":   {},
+}
+
+// keyRows describes the physical keyboard rows with letters or digits:
+// the keycode of the first key, the characters on a US layout, and how far
+// the row is staggered right, in key widths, relative to the Q row.
+var keyRows = []struct {
+	name   string
+	first  uint16
+	chars  string
+	offset float64
+}{
+	{"number row", 0x0002, "1234567890", -0.5},
+	{"top row", 0x0010, "qwertyuiop", 0},
+	{"home row", 0x001E, "asdfghjkl;", 0.25},
+	{"bottom row", 0x002C, "zxcvbnm,./", 0.75},
+}
+
+func init() {
+	for _, row := range keyRows {
+		for i, c := range row.chars {
+			if unicode.IsLetter(c) || unicode.IsDigit(c) {
+				vcLetters[row.first+uint16(i)] = c
+			}
+		}
+	}
+	// Windows virtual-key codes and X11 keysyms use ASCII for letters and
+	// digits; X11 also sends lowercase keysyms
+	for c := 'a'; c <= 'z'; c++ {
+		rawLetters["windows"][uint16(unicode.ToUpper(c))] = c
+		rawLetters["linux"][uint16(c)] = c
+		rawLetters["linux"][uint16(unicode.ToUpper(c))] = c
+	}
+	for c := '0'; c <= '9'; c++ {
+		rawLetters["windows"][uint16(c)] = c
+		rawLetters["linux"][uint16(c)] = c
+	}
+}
+
+// fallbackChar guesses the character for a key event that arrived without
+// one, from its keycode and then its platform keycode.
+func fallbackChar(key Key) (rune, bool) {
+	if c, ok := vcLetters[key.Code]; ok {
+		return c, true
+	}
+	if key.Code == 0 && key.Rawcode != 0 {
+		c, ok := rawLetters[runtime.GOOS][key.Rawcode]
+		return c, ok
+	}
+	return 0, false
+}
@@ -0,0 +1,23 @@
+package phonical_test
+
+import (
+	"runtime"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestKeycodeFallback(t *testing.T) {
+	raw := map[string]uint16{"darwin": 0x0B, "windows": 'B', "linux": 'b'}[runtime.GOOS]
+	if raw == 0 {
+		t.Skipf("no platform keycodes known on %s", runtime.GOOS)
+	}
+	h := phonicaltest.New(t)
+	// A press without a character plays the letter on its key, found
+	// from the keycode or else the platform's own keycode; Shift has
+	// neither and stays silent
+	h.Type("{code 0x1e}{code 0x2a}")
+	h.Source.Send(phonical.Key{Rawcode: raw})
+	h.Expect("a.wav", "b.wav")
+}
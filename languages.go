@@ -0,0 +1,33 @@
+package phonical
+
+// WithLanguageHotkey lets F10 switch between the current pack and packs,
+// such as the built-in sounds of another language, announcing each by
+// name. All of them are preloaded so switching is instant.
+func WithLanguageHotkey(packs ...*Pack) Option {
+	return func(e *Engine) {
+		e.languages = packs
+	}
+}
+
+// SetPack switches to pack while the engine runs and announces its name.
+// Sounds already queued finish with the pack they were queued from.
+func (e *Engine) SetPack(pack *Pack) {
+	e.pack.Store(pack)
+	e.debugf("Pack: %s\n", pack.Name)
+	e.enqueueSay(pack.Name)
+	e.notify("Sounds: " + pack.Name)
+}
+
+// nextLanguage switches to the pack after the current one in the hotkey's
+// cycle: the pack the engine started with, then each language in turn.
+func (e *Engine) nextLanguage() {
+	current := e.pack.Load()
+	next := e.languages[0]
+	for i, pack := range e.languages {
+		if pack == current {
+			next = e.languages[(i+1)%len(e.languages)]
+			break
+		}
+	}
+	e.SetPack(next)
+}
@@ -0,0 +1,25 @@
+package phonical_test
+
+import (
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestLanguageHotkey(t *testing.T) {
+	if !phonical.HasBuiltinSounds() {
+		t.Skip("lite build")
+	}
+	second, err := phonical.EmbeddedPack(phonical.DefaultSounds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second.Name = "Second"
+	h := phonicaltest.New(t, phonical.WithLanguageHotkey(second))
+
+	h.Type("{f10}")
+	h.Expect("say:Second")
+	h.Type("{f10}")
+	h.Expect("say:Second", "say:British English")
+}
@@ -0,0 +1,116 @@
+package phonical
+
+import (
+	"io/fs"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layer combines packs into one, later packs taking priority: each sound,
+// such as the letter a or the space cue, comes from the last pack that has
+// one. A parent's recordings of a few letters can be layered over the
+// built-in sounds this way, keeping the built-in ones for the rest.
+func Layer(packs ...*Pack) *Pack {
+	if len(packs) == 1 {
+		return packs[0]
+	}
+	layered := &Pack{
+		Letters:    map[rune]string{},
+		Cues:       map[string]string{},
+		Navigation: map[string]string{},
+		Intros:     map[rune]string{},
+		Words:      map[rune]string{},
+		Pictures:   map[rune]string{},
+		Variants:   map[string]map[rune]string{},
+		Graphemes:  map[string]string{},
+		Syllables:  map[string]string{},
+		Limits:     map[string]time.Duration{},
+		Emoji:      map[rune]EmojiSound{},
+		Timed:      map[string]map[string]string{},
+	}
+	var names, ids []string
+	var layers layeredFS
+	for i, p := range packs {
+		names = append(names, p.Name)
+		ids = append(ids, p.id)
+		layers = append(layers, p.fsys)
+		// Each layer's files are found under its index, so two packs can
+		// both have an a.wav
+		file := func(name string) string {
+			return strconv.Itoa(i) + "/" + name
+		}
+
+		layerRunes(layered.Letters, p.Letters, file)
+		layerRunes(layered.Intros, p.Intros, file)
+		layerRunes(layered.Pictures, p.Pictures, file)
+		layerNames(layered.Cues, p.Cues, file)
+		layerNames(layered.Navigation, p.Navigation, file)
+		layerNames(layered.Graphemes, p.Graphemes, file)
+		layerNames(layered.Syllables, p.Syllables, file)
+		for r, word := range p.Words {
+			layered.Words[r] = word
+		}
+		for name, table := range p.Variants {
+			if layered.Variants[name] == nil {
+				layered.Variants[name] = map[rune]string{}
+			}
+			layerRunes(layered.Variants[name], table, file)
+		}
+		for part, clips := range p.Timed {
+			if layered.Timed[part] == nil {
+				layered.Timed[part] = map[string]string{}
+			}
+			layerNames(layered.Timed[part], clips, file)
+		}
+		for name, d := range p.Limits {
+			layered.Limits[file(name)] = d
+		}
+		for r, sound := range p.Emoji {
+			if sound.Sound != "" {
+				sound.Sound = file(sound.Sound)
+			}
+			layered.Emoji[r] = sound
+		}
+		if len(p.Encouragement) > 0 {
+			layered.Encouragement = nil
+			for _, name := range p.Encouragement {
+				layered.Encouragement = append(layered.Encouragement, file(name))
+			}
+		}
+		if len(p.Months) > 0 {
+			layered.Months = p.Months
+		}
+	}
+	layered.Name = strings.Join(names, " + ")
+	layered.id = strings.Join(ids, "+")
+	layered.fsys = layers
+	return layered
+}
+
+// layerRunes copies the files in src over those in dest.
+func layerRunes(dest, src map[rune]string, file func(string) string) {
+	for r, name := range src {
+		dest[r] = file(name)
+	}
+}
+
+// layerNames copies the files in src over those in dest.
+func layerNames(dest, src map[string]string, file func(string) string) {
+	for key, name := range src {
+		dest[key] = file(name)
+	}
+}
+
+// layeredFS serves the files of a layered pack, each under the index of
+// the layer it comes from.
+type layeredFS []fs.FS
+
+func (l layeredFS) Open(name string) (fs.File, error) {
+	index, rest, ok := strings.Cut(name, "/")
+	i, err := strconv.Atoi(index)
+	if !ok || err != nil || i < 0 || i >= len(l) || l[i] == nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return l[i].Open(rest)
+}
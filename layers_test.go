@@ -0,0 +1,27 @@
+package phonical_test
+
+import (
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestLayer(t *testing.T) {
+	if !phonical.HasBuiltinSounds() {
+		t.Skip("lite build")
+	}
+	mine := letterPack(t, map[string]string{"a": "mine-a"})
+	layered := phonical.Layer(phonical.DefaultPack(), mine)
+	if layered.Name != phonical.DefaultPack().Name+" + Letters" {
+		t.Errorf("layered pack is called %q", layered.Name)
+	}
+	// Each file is found under the index of the layer it comes from
+	h := phonicaltest.New(t, phonical.WithPack(layered))
+	h.Type("ab")
+	h.Expect("1/mine-a.wav", "0/b.wav")
+
+	if phonical.Layer(mine) != mine {
+		t.Error("layering one pack made a new one")
+	}
+}
@@ -0,0 +1,247 @@
+package phonical
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Lesson is a teacher-authored sequence of targets, read from a TOML file:
+//
+//	title = "Lesson 1: s a t p"
+//	pass = 0.8
+//
+//	[[target]]
+//	letter = "s"
+//
+//	[[target]]
+//	word = "sat"
+//	prompt = "Can you type sat?"
+//	tries = 3
+//
+//	[[target]]
+//	digraph = "sh"
+type Lesson struct {
+	Title string `toml:"title"`
+	// Pass is the fraction of targets that must be correct to pass.
+	// Defaults to DefaultLessonPass.
+	Pass    float64        `toml:"pass"`
+	Targets []LessonTarget `toml:"target"`
+}
+
+// LessonTarget is one thing to practise. Exactly one of Letter, Word or
+// Digraph is set.
+type LessonTarget struct {
+	Letter  string `toml:"letter"`
+	Word    string `toml:"word"`
+	Digraph string `toml:"digraph"`
+	// Prompt replaces the spoken instruction.
+	Prompt string `toml:"prompt"`
+	// Tries is how many attempts count as success. Defaults to 2.
+	Tries int `toml:"tries"`
+	// Timeout is how long to wait for each key, in seconds. Defaults to 15.
+	Timeout int `toml:"timeout"`
+}
+
+// LessonResult is the outcome of running a lesson.
+type LessonResult struct {
+	Title   string
+	Correct int
+	Total   int
+	Passed  bool
+}
+
+// DefaultLessonPass is the pass mark used when a lesson sets none.
+const DefaultLessonPass = 0.8
+
+// LoadLesson reads and checks a lesson file.
+func LoadLesson(filename string) (Lesson, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return Lesson{}, err
+	}
+	var l Lesson
+	s, err := decodeSchema(filename, string(data), &l)
+	if err != nil {
+		return Lesson{}, err
+	}
+	l.check(s)
+	if err := s.err(); err != nil {
+		return Lesson{}, err
+	}
+	return l, nil
+}
+
+func (l *Lesson) check(s *schema) {
+	if len(l.Targets) == 0 {
+		s.errorf(nil, "lesson has no targets; add a [[target]] table")
+	}
+	if l.Pass == 0 {
+		l.Pass = DefaultLessonPass
+	}
+	if l.Pass < 0 || l.Pass > 1 {
+		s.errorf(toml.Key{"pass"}, "pass must be between 0 and 1, got %g", l.Pass)
+	}
+	for i := range l.Targets {
+		tg := &l.Targets[i]
+		set := 0
+		for _, v := range []string{tg.Letter, tg.Word, tg.Digraph} {
+			if v != "" {
+				set++
+			}
+		}
+		if set != 1 {
+			s.tableErrorf("target", i, "target %d: set exactly one of letter, word or digraph", i+1)
+		}
+		if tg.Letter != "" && len([]rune(tg.Letter)) != 1 {
+			s.tableErrorf("target", i, "target %d: letter must be a single character, got %q", i+1, tg.Letter)
+		}
+		if tg.Tries < 0 || tg.Timeout < 0 {
+			s.tableErrorf("target", i, "target %d: tries and timeout must not be negative", i+1)
+		}
+		tg.Letter = strings.ToLower(tg.Letter)
+		tg.Word = strings.ToLower(tg.Word)
+		tg.Digraph = strings.ToLower(tg.Digraph)
+		if tg.Tries == 0 {
+			tg.Tries = 2
+		}
+		if tg.Timeout == 0 {
+			tg.Timeout = 15
+		}
+	}
+}
+
+// text is what the child must type for the target.
+func (tg LessonTarget) text() string {
+	return tg.Letter + tg.Word + tg.Digraph
+}
+
+// RunLesson returns an activity that works through l, prompting for each
+// target and scoring the answers. The result is passed to done and
+// recorded in the engine's stats.
+func RunLesson(l Lesson, done func(LessonResult)) Activity {
+	return ActivityFunc(func(t *Tutor) error {
+		result := LessonResult{Title: l.Title, Total: len(l.Targets)}
+		if l.Title != "" {
+			t.Say(l.Title)
+		}
+
+		for _, tg := range l.Targets {
+			ok, err := runTarget(t, tg)
+			if err != nil {
+				return err
+			}
+			if ok {
+				result.Correct++
+			}
+			t.Pause(400 * time.Millisecond)
+		}
+
+		result.Passed = float64(result.Correct) >= l.Pass*float64(result.Total)
+		t.Say(fmt.Sprintf("You got %s out of %s.", numberWord(result.Correct), numberWord(result.Total)))
+		if result.Passed {
+			t.Say("Lesson passed. Brilliant!")
+		} else {
+			t.Say("Let's practise this one again soon.")
+		}
+
+		if stats := t.Stats(); stats != nil {
+			stats.Update(func(st *Stats) { st.recordLesson(result) })
+		}
+		if done != nil {
+			done(result)
+		}
+		return nil
+	})
+}
+
+// runTarget prompts for tg and reports whether it was typed correctly
+// within the allowed tries.
+func runTarget(t *Tutor, tg LessonTarget) (bool, error) {
+	want := tg.text()
+	timeout := time.Duration(tg.Timeout) * time.Second
+
+	for try := 0; try < tg.Tries; try++ {
+		if try == 0 {
+			promptTarget(t, tg)
+		} else {
+			t.Say("Try again.")
+			t.PlayGrapheme(want)
+		}
+
+		typed := ""
+		for len([]rune(typed)) < len([]rune(want)) {
+			r, ok := t.NextKey(timeout)
+			if t.Stopped() {
+				return false, ErrStopped
+			}
+			if !ok {
+				break
+			}
+			t.Play(r)
+			typed += string(r)
+		}
+		if typed == want {
+			t.Say("Yes!")
+			return true, nil
+		}
+	}
+
+	t.Say(fmt.Sprintf("That one was %s.", strings.Join(strings.Split(want, ""), " ")))
+	return false, nil
+}
+
+// promptTarget gives the spoken instruction for tg.
+func promptTarget(t *Tutor, tg LessonTarget) {
+	switch {
+	case tg.Prompt != "":
+		t.Say(tg.Prompt)
+	case tg.Letter != "":
+		t.Say("Press the letter that makes this sound.")
+	case tg.Digraph != "":
+		t.Say("Type the two letters that make this sound.")
+	default:
+		t.Say("Type the word " + tg.Word + ".")
+		return
+	}
+	t.PlayGrapheme(tg.text())
+}
+
+// PlayGrapheme plays a letter's phoneme, or speaks a longer grapheme such
+// as a digraph.
+func (t *Tutor) PlayGrapheme(g string) {
+	if r := []rune(g); len(r) == 1 {
+		t.Play(r[0])
+		return
+	}
+	t.Say(g)
+}
+
+// recordLesson keeps the best score and pass state for a lesson.
+func (st *Stats) recordLesson(r LessonResult) {
+	if st.Lessons == nil {
+		st.Lessons = make(map[string]*LessonRecord)
+	}
+	rec := st.Lessons[r.Title]
+	if rec == nil {
+		rec = &LessonRecord{}
+		st.Lessons[r.Title] = rec
+	}
+	rec.Attempts++
+	if r.Correct > rec.Best {
+		rec.Best = r.Correct
+	}
+	rec.Total = r.Total
+	rec.Passed = rec.Passed || r.Passed
+}
+
+// LessonRecord is a lesson's history in Stats.
+type LessonRecord struct {
+	Attempts int  `json:"attempts"`
+	Best     int  `json:"best"`
+	Total    int  `json:"total"`
+	Passed   bool `json:"passed"`
+}
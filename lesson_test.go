@@ -0,0 +1,76 @@
+package phonical_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// writeLesson writes a lesson file and loads it.
+func writeLesson(t *testing.T, text string) (phonical.Lesson, error) {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "lesson.toml")
+	if err := os.WriteFile(file, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return phonical.LoadLesson(file)
+}
+
+func TestLoadLessonErrors(t *testing.T) {
+	for text, want := range map[string]string{
+		`title = "Empty"`:                                  "no targets",
+		"pass = 2\n[[target]]\nletter = \"s\"":             "pass must be between 0 and 1",
+		"[[target]]\nletter = \"s\"\nword = \"sat\"":       "set exactly one of letter, word or digraph",
+		"[[target]]\nletter = \"sh\"":                      "letter must be a single character",
+		"[[target]]\nletter = \"s\"\ntries = -1":           "must not be negative",
+		"[[target]]\nletter = \"s\"\n[[target]]\nword = 1": "word",
+	} {
+		if _, err := writeLesson(t, text); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("lesson %q: error %v, want one mentioning %q", text, err, want)
+		}
+	}
+}
+
+func TestRunLesson(t *testing.T) {
+	lesson, err := writeLesson(t, `
+title = "S and at"
+
+[[target]]
+letter = "S"
+prompt = "Find s."
+tries = 1
+
+[[target]]
+word = "at"
+prompt = "Type at."
+tries = 1
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := phonicaltest.New(t)
+	var result phonical.LessonResult
+	done := runActivity(h, phonical.RunLesson(lesson, func(r phonical.LessonResult) { result = r }))
+
+	waitPlayed(t, h, 3)
+	answer(h, "s")
+	waitPlayed(t, h, 7)
+	answer(h, "a")
+	waitPlayed(t, h, 8)
+	answer(h, "x")
+	waitPlayed(t, h, 11)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	h.Expect("say:S and at",
+		"say:Find s.", "s.wav", "s.wav", "say:Yes!",
+		"say:Type at.", "say:at", "a.wav", "x.wav", "say:That one was a t.",
+		"say:You got one out of two.", "say:Let's practise this one again soon.")
+	if want := (phonical.LessonResult{Title: "S and at", Correct: 1, Total: 2}); result != want {
+		t.Errorf("result %+v, want %+v", result, want)
+	}
+}
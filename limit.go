@@ -0,0 +1,56 @@
+package phonical
+
+import (
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// limitFade is how long a sound cut short by WithMaxSoundLength takes to
+// fade out.
+const limitFade = 40 * time.Millisecond
+
+// WithMaxSoundLength cuts letter and cue recordings off after d, fading
+// them out, so long recordings don't back up the queue during fast
+// typing. Packs can set a different limit for a file in their [limits]
+// table. Zero, the default, plays recordings in full.
+func WithMaxSoundLength(d time.Duration) Option {
+	return func(e *Engine) {
+		e.maxLength = d
+	}
+}
+
+// soundLength returns how many of buffer's samples to play for item.
+func (e *Engine) soundLength(item *queuedSound, buffer *beep.Buffer) int {
+	if item.stretch > 1 {
+		// The model pronunciation is meant to be heard in full
+		return buffer.Len()
+	}
+	limit := e.maxLength
+	if item.pack != nil {
+		if d, ok := item.pack.Limits[item.sound]; ok {
+			limit = d
+		}
+	}
+	if limit <= 0 {
+		return buffer.Len()
+	}
+	return min(buffer.Len(), buffer.Format().SampleRate.N(limit))
+}
+
+// fadeOut ramps the last fade of streamer's n samples down to silence.
+func fadeOut(streamer beep.Streamer, n, fade int) beep.Streamer {
+	pos := 0
+	return beep.StreamerFunc(func(samples [][2]float64) (int, bool) {
+		filled, ok := streamer.Stream(samples)
+		for i := 0; i < filled; i++ {
+			if left := n - pos - i; left < fade {
+				g := float64(left) / float64(fade)
+				samples[i][0] *= g
+				samples[i][1] *= g
+			}
+		}
+		pos += filled
+		return filled, ok
+	})
+}
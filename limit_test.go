@@ -0,0 +1,76 @@
+package phonical_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// limitPack writes a pack of the English a and b with the given [limits]
+// table.
+func limitPack(t *testing.T, limits string) (*phonical.Pack, error) {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{"a.wav", "b.wav"} {
+		data, err := os.ReadFile(filepath.Join("sounds", phonical.DefaultSounds, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	manifest := "name = \"Limits\"\n\n[letters]\na = \"a.wav\"\nb = \"b.wav\"\n\n[limits]\n" + limits
+	if err := os.WriteFile(filepath.Join(dir, phonical.ManifestName), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return phonical.LoadPack(dir)
+}
+
+func TestMaxSoundLength(t *testing.T) {
+	if !phonical.HasBuiltinSounds() {
+		t.Skip("lite build")
+	}
+	// a plays in full and b is cut at 50ms rather than the default 100ms
+	pack, err := limitPack(t, "\"a.wav\" = 0\n\"b.wav\" = 50\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		pack *phonical.Pack
+		want []int
+	}{
+		{phonical.DefaultPack(), []int{4410, 4410}},
+		{pack, []int{19425, 2205}},
+	} {
+		h := phonicaltest.New(t, phonical.WithPack(tt.pack), phonical.WithMaxSoundLength(100*time.Millisecond))
+		h.Type("ab")
+		h.Expect("a.wav", "b.wav")
+		for i, p := range h.Sink.Playbacks() {
+			if got := samples(p.Streamer); got != tt.want[i] {
+				t.Errorf("%s: %s played %d samples, want %d", tt.pack.Name, p.Name, got, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestMaxSoundLengthFades(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithMaxSoundLength(100*time.Millisecond))
+	h.Type("a")
+	h.Expect("a.wav")
+	buf := make([][2]float64, 4410)
+	n, _ := h.Sink.Playbacks()[0].Streamer.Stream(buf)
+	if last := buf[n-1]; last[0] > 0.001 || last[0] < -0.001 {
+		t.Errorf("cut-off sound ends at %g, want silence", last[0])
+	}
+}
+
+func TestLimitsChecked(t *testing.T) {
+	if _, err := limitPack(t, "\"a.wav\" = -5\n"); err == nil {
+		t.Error("negative limit accepted")
+	}
+}
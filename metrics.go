@@ -0,0 +1,105 @@
+package phonical
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the playback latency
+// histogram: the time from a key press to its sound starting.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// metrics counts what the engine has done, for the Prometheus endpoint.
+type metrics struct {
+	soundsPlayed atomic.Int64
+	queueDrops   atomic.Int64
+	decodeErrors atomic.Int64
+	// speakerRestarts counts recoveries after the audio device failed.
+	speakerRestarts atomic.Int64
+	latency         histogram
+
+	// watchdogTrips counts times letters were typed but nothing played.
+	watchdogTrips atomic.Int64
+}
+
+// histogram is a minimal cumulative histogram in the Prometheus style.
+type histogram struct {
+	mu     sync.Mutex
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func (h *histogram) observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.counts == nil {
+		h.counts = make([]uint64, len(latencyBuckets))
+	}
+	for i, bound := range latencyBuckets {
+		if v <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+// observeLatency records how long item waited before playing.
+func (e *Engine) observeLatency(item *queuedSound) {
+	if !item.at.IsZero() {
+		e.metrics.latency.observe(e.clock.Now().Sub(item.at).Seconds())
+	}
+}
+
+// WriteMetrics writes the engine's counters in the Prometheus text format.
+func (e *Engine) WriteMetrics(w io.Writer) error {
+	counters := []struct {
+		name, help string
+		value      int64
+	}{
+		{"phonical_keypresses_total", "Key presses handled.", e.keysHandled.Load()},
+		{"phonical_sounds_played_total", "Sounds played, including spoken phrases.", e.metrics.soundsPlayed.Load()},
+		{"phonical_queue_drops_total", "Sounds dropped because the queue was full.", e.metrics.queueDrops.Load()},
+		{"phonical_decode_errors_total", "Sounds that failed to load or decode.", e.metrics.decodeErrors.Load()},
+		{"phonical_speaker_restarts_total", "Times audio was restarted after the device failed.", e.metrics.speakerRestarts.Load()},
+		{"phonical_watchdog_trips_total", "Times letters were typed but no sound played.", e.metrics.watchdogTrips.Load()},
+	}
+	for _, c := range counters {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", c.name, c.help, c.name, c.name, c.value); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "# HELP phonical_queue_depth Sounds waiting to play.\n# TYPE phonical_queue_depth gauge\nphonical_queue_depth %d\n", e.playQueue.len()); err != nil {
+		return err
+	}
+
+	h := &e.metrics.latency
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	const name = "phonical_playback_latency_seconds"
+	fmt.Fprintf(w, "# HELP %s Time from a key press to its sound starting.\n# TYPE %s histogram\n", name, name)
+	for i, bound := range latencyBuckets {
+		var n uint64
+		if h.counts != nil {
+			n = h.counts[i]
+		}
+		fmt.Fprintf(w, "%s_bucket{le=\"%g\"} %d\n", name, bound, n)
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, h.count)
+	_, err := fmt.Fprintf(w, "%s_sum %g\n%s_count %d\n", name, h.sum, name, h.count)
+	return err
+}
+
+// MetricsHandler serves WriteMetrics over HTTP, for mounting at /metrics.
+func (e *Engine) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if err := e.WriteMetrics(w); err != nil {
+			e.logf("Writing metrics: %v", err)
+		}
+	})
+}
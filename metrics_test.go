@@ -0,0 +1,41 @@
+package phonical_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestMetrics(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithQueueSize(1))
+	// b waits 30ms behind a, and c finds the queue full
+	h.Sink.Hold()
+	h.Type("a")
+	h.Sink.WaitFor(1, time.Second)
+	h.Type("bc{wait 30ms}")
+	h.Sink.Release()
+	h.Expect("a.wav", "b.wav")
+
+	var buf bytes.Buffer
+	if err := h.Engine.WriteMetrics(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"phonical_keypresses_total 3\n",
+		"phonical_sounds_played_total 2\n",
+		"phonical_queue_drops_total 1\n",
+		"phonical_queue_depth 0\n",
+		"# TYPE phonical_playback_latency_seconds histogram\n",
+		`phonical_playback_latency_seconds_bucket{le="0.025"} 1` + "\n",
+		`phonical_playback_latency_seconds_bucket{le="0.05"} 2` + "\n",
+		"phonical_playback_latency_seconds_count 2\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("metrics missing %q:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package phonical
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// micPollInterval is how often the microphone is checked; short so sounds
+// stop soon after a call starts.
+const micPollInterval = 3 * time.Second
+
+// WithCallPause mutes the engine while the microphone is in use, so
+// phonemes don't leak into video calls, and resumes when it is released.
+func WithCallPause(enabled bool) Option {
+	return func(e *Engine) {
+		e.pauseOnCalls = enabled
+	}
+}
+
+// MicrophoneInUse reports whether any program is recording from a
+// microphone. It is supported on Linux (ALSA, including PipeWire and
+// PulseAudio on top of it) and Windows.
+func MicrophoneInUse() (bool, error) {
+	switch runtime.GOOS {
+	case "linux":
+		// Capture substreams report "state: RUNNING" while recording
+		paths, err := filepath.Glob("/proc/asound/card*/pcm*c/sub*/status")
+		if err != nil {
+			return false, err
+		}
+		if len(paths) == 0 {
+			return false, errors.New("no capture devices found in /proc/asound")
+		}
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err == nil && strings.Contains(string(data), "state: RUNNING") {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "windows":
+		// Apps using the microphone have LastUsedTimeStop of 0 in the
+		// capability consent store
+		key := `HKCU\Software\Microsoft\Windows\CurrentVersion\CapabilityAccessManager\ConsentStore\microphone`
+		out, err := exec.Command("reg", "query", key, "/s", "/v", "LastUsedTimeStop").Output()
+		if err != nil {
+			return false, err
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 3 && fields[0] == "LastUsedTimeStop" && fields[2] == "0x0" {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		return false, errors.New("microphone detection is not supported on " + runtime.GOOS)
+	}
+}
+
+// OnCall reports whether the engine is paused because the microphone is in
+// use.
+func (e *Engine) OnCall() bool {
+	return e.onCall.Load()
+}
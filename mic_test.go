@@ -0,0 +1,24 @@
+package phonical_test
+
+import (
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestCallPauseOffCall(t *testing.T) {
+	if on, _ := phonical.MicrophoneInUse(); on {
+		t.Skip("the microphone is in use")
+	}
+	// Without a call, or where the microphone can't be checked, sounds
+	// carry on
+	h := phonicaltest.New(t, phonical.WithCallPause(true))
+	h.Type("a")
+	h.Expect("a.wav")
+	time.Sleep(20 * time.Millisecond)
+	if h.Engine.OnCall() || h.Engine.Status().Muted {
+		t.Error("paused for a call with the microphone free")
+	}
+}
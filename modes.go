@@ -0,0 +1,203 @@
+package phonical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// Mode selects what is played when a word is completed. Letters always
+// play as they are typed.
+type Mode int
+
+const (
+	// ModeLetters plays nothing extra when a word is completed.
+	ModeLetters Mode = iota
+	// ModeBlend sounds out each phoneme of the word, runs the phonemes
+	// together and then says the whole word.
+	ModeBlend
+	// ModeOnsetRime says the onset and rime separately and then the whole
+	// word ("c … at … cat"), for word-family curricula.
+	ModeOnsetRime
+	// ModeSyllables says multi-syllable words one syllable at a time with
+	// short gaps, optionally clapping between them, then the whole word.
+	ModeSyllables
+	// ModeBoxes paces the word's phonemes evenly, each after a soft tick
+	// like a finger tapping an Elkonin box, then says the whole word.
+	ModeBoxes
+)
+
+var modeNames = map[Mode]string{
+	ModeLetters:   "letters",
+	ModeBlend:     "blend",
+	ModeOnsetRime: "onset-rime",
+	ModeSyllables: "syllables",
+	ModeBoxes:     "boxes",
+}
+
+func (m Mode) String() string {
+	if name, ok := modeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+// ParseMode converts a mode name such as "onset-rime" into a Mode.
+func ParseMode(name string) (Mode, error) {
+	for m, n := range modeNames {
+		if strings.EqualFold(n, name) {
+			return m, nil
+		}
+	}
+	return ModeLetters, fmt.Errorf("unknown mode %q (want letters, blend, onset-rime, syllables or boxes)", name)
+}
+
+// SetMode changes what is played after each word while the engine runs.
+func (e *Engine) SetMode(m Mode) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.mode = m
+}
+
+// segmentGap is the pause between the parts of a sounded-out word.
+const segmentGap = 350 * time.Millisecond
+
+// DefaultBoxGap is the pause after each phoneme in ModeBoxes.
+const DefaultBoxGap = 700 * time.Millisecond
+
+// WithBoxGap sets the pause after each phoneme in ModeBoxes. Longer gaps
+// give time to point at each box. Defaults to DefaultBoxGap.
+func WithBoxGap(d time.Duration) Option {
+	return func(e *Engine) {
+		e.boxGap = d
+	}
+}
+
+// playWord queues the completed word according to the engine's mode.
+func (e *Engine) playWord(word string) {
+	e.mu.Lock()
+	mode := e.mode
+	e.mu.Unlock()
+
+	switch mode {
+	case ModeBlend:
+		e.enqueuePause(segmentGap)
+		for _, g := range e.pack.Load().segment(word) {
+			e.enqueueUnit(g)
+		}
+		e.enqueuePause(segmentGap)
+		if buffer := e.blendWord(word); buffer != nil {
+			e.enqueue(&queuedSound{sound: "blend:" + word, buffer: buffer})
+			e.enqueuePause(segmentGap)
+		}
+		e.enqueueSay(word)
+	case ModeOnsetRime:
+		onset, rime := onsetRime(word)
+		e.enqueuePause(segmentGap)
+		if onset != "" {
+			e.enqueueUnit(onset)
+			e.enqueuePause(segmentGap)
+		}
+		if rime != "" {
+			e.enqueueSay(rime)
+			e.enqueuePause(segmentGap)
+		}
+		e.enqueueSay(word)
+	case ModeSyllables:
+		parts := syllables(word)
+		if len(parts) < 2 {
+			return
+		}
+		e.enqueuePause(segmentGap)
+		for i, part := range parts {
+			if i > 0 {
+				if e.claps {
+					e.enqueue(&queuedSound{sound: "clap", buffer: clap()})
+				} else {
+					e.enqueuePause(segmentGap / 2)
+				}
+			}
+			e.enqueueSay(part)
+		}
+		e.enqueuePause(segmentGap)
+		e.enqueueSay(word)
+	case ModeBoxes:
+		e.enqueuePause(segmentGap)
+		for _, g := range e.pack.Load().segment(word) {
+			e.enqueue(&queuedSound{sound: "tick", buffer: boxTick()})
+			e.enqueueUnit(g)
+			e.enqueuePause(e.boxGap)
+		}
+		e.enqueueSay(word)
+	}
+}
+
+// enqueueUnit queues a grapheme or onset, using the pack's letter sounds
+// so "ch" is heard as phonemes rather than spelled out by the announcer.
+func (e *Engine) enqueueUnit(unit string) {
+	for _, item := range e.unitSounds(unit) {
+		e.enqueue(item)
+	}
+}
+
+// unitSounds returns the sounds enqueueUnit would queue for unit.
+func (e *Engine) unitSounds(unit string) []*queuedSound {
+	pack := e.pack.Load()
+	if file, ok := pack.Graphemes[unit]; ok {
+		return []*queuedSound{{sound: file, pack: pack}}
+	}
+	var items []*queuedSound
+	for _, r := range unit {
+		if file, ok := pack.letterSound(r, e.variant); ok {
+			items = append(items, &queuedSound{sound: file, letter: r})
+		}
+	}
+	return items
+}
+
+// blendWord crossfades the pack's phonemes for word into one sound, or
+// returns nil when a letter has no sound.
+func (e *Engine) blendWord(word string) *beep.Buffer {
+	pack := e.pack.Load()
+	var files []string
+	for _, g := range pack.segment(word) {
+		if file, ok := pack.Graphemes[g]; ok {
+			files = append(files, file)
+			continue
+		}
+		for _, r := range g {
+			file, ok := pack.letterSound(r, e.variant)
+			if !ok {
+				return nil
+			}
+			files = append(files, file)
+		}
+	}
+
+	var buffers []*beep.Buffer
+	for _, file := range files {
+		buffer, err := pack.loadWith(file, e.decoding)
+		if err != nil {
+			e.logf("Failed to load sound %s: %v", file, err)
+			e.metrics.decodeErrors.Add(1)
+			return nil
+		}
+		buffers = append(buffers, buffer)
+	}
+	if len(buffers) < 2 {
+		return nil
+	}
+	return blend(buffers)
+}
+
+// enqueueSay queues text for the announcer.
+func (e *Engine) enqueueSay(text string) {
+	e.enqueue(&queuedSound{sound: "say:" + text, say: text})
+}
+
+// enqueuePause queues a silence of length d.
+func (e *Engine) enqueuePause(d time.Duration) {
+	e.enqueue(&queuedSound{sound: "pause", pause: d})
+}
@@ -0,0 +1,60 @@
+package phonical_test
+
+import (
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestOnsetRime(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithMode(phonical.ModeOnsetRime))
+	h.Type("cat{space}")
+	h.Expect("c.wav", "a.wav", "t.wav", "c.wav", "say:at", "say:cat")
+}
+
+func TestSyllableClaps(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithMode(phonical.ModeSyllables), phonical.WithSyllableClaps(true))
+	// A word of one syllable is left as typed
+	h.Type("cat{space}rabbit{space}")
+	h.Expect("c.wav", "a.wav", "t.wav",
+		"r.wav", "a.wav", "b.wav", "b.wav", "i.wav", "t.wav",
+		"say:rab", "clap", "say:bit", "say:rabbit")
+}
+
+func TestSyllables(t *testing.T) {
+	for word, parts := range map[string][]string{
+		"robot":  {"ro", "bot"},
+		"table":  {"ta", "ble"},
+		"father": {"fa", "ther"},
+	} {
+		h := phonicaltest.New(t, phonical.WithMode(phonical.ModeSyllables))
+		h.Type(word + "{space}")
+		var want []string
+		for _, c := range word {
+			want = append(want, string(c)+".wav")
+		}
+		for _, part := range parts {
+			want = append(want, "say:"+part)
+		}
+		h.Expect(append(want, "say:"+word)...)
+	}
+}
+
+func TestBoxes(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithMode(phonical.ModeBoxes), phonical.WithBoxGap(time.Second))
+	start := h.Clock.Now()
+	h.Type("cat{space}")
+	h.Expect("c.wav", "a.wav", "t.wav",
+		"tick", "c.wav", "tick", "a.wav", "tick", "t.wav", "say:cat")
+	if elapsed := h.Clock.Now().Sub(start); elapsed < 3*time.Second {
+		t.Errorf("boxes took %s, want a one second gap after each sound", elapsed)
+	}
+}
+
+func TestBoxGapChecked(t *testing.T) {
+	if _, err := phonical.New(phonical.WithBoxGap(-time.Second)); err == nil {
+		t.Error("accepted a negative box gap")
+	}
+}
@@ -0,0 +1,24 @@
+package phonical
+
+import "github.com/faiface/beep"
+
+// WithMono downmixes every sound to mono as it is loaded, halving the
+// memory decoded sounds take, for devices such as laptop speakers where
+// stereo adds nothing. Panning still works.
+func WithMono(on bool) Option {
+	return func(e *Engine) {
+		e.mono = on
+	}
+}
+
+// downmix returns buffer with its channels averaged into one.
+func downmix(buffer *beep.Buffer) *beep.Buffer {
+	format := buffer.Format()
+	if format.NumChannels == 1 {
+		return buffer
+	}
+	format.NumChannels = 1
+	mono := beep.NewBuffer(format)
+	mono.Append(buffer.Streamer(0, buffer.Len()))
+	return mono
+}
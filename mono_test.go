@@ -0,0 +1,30 @@
+package phonical
+
+import "testing"
+
+func TestMonoPerEngine(t *testing.T) {
+	if !HasBuiltinSounds() {
+		t.Skip("lite build")
+	}
+	mono, err := New(WithMono(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stereo, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each engine decodes the same recording its own way, whichever is first
+	for _, tc := range []struct {
+		e        *Engine
+		channels int
+	}{{mono, 1}, {stereo, 2}, {mono, 1}} {
+		buffer, err := tc.e.pack.Load().loadWith("a.wav", tc.e.decoding)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := buffer.Format().NumChannels; got != tc.channels {
+			t.Errorf("decoded with %d channels, want %d", got, tc.channels)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package phonical
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/speaker"
+)
+
+// DefaultAudioPort is the TCP port the network sink and ServeAudio use
+// when none is given.
+const DefaultAudioPort = "7390"
+
+// audioHandshake opens a network audio stream, naming the protocol version
+// and sample rate of the frames that follow.
+const audioHandshake = "PHONICAL-AUDIO 1 44100\n"
+
+// netTimeout bounds connecting and each write to the receiver.
+const netTimeout = 5 * time.Second
+
+var errNotConnected = errors.New("not connected to audio receiver")
+
+// NetSink is an AudioSink that sends sound over TCP to another machine
+// running ServeAudio, so Phonical can run on a locked-down lab computer
+// while the sound plays at the teacher's station. Each sound is sent as it
+// starts and Play waits out its length, so the queue keeps its usual
+// timing.
+type NetSink struct {
+	addr string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewNetSink returns a sink sending to addr, "host" or "host:port". It
+// connects on Init.
+func NewNetSink(addr string) *NetSink {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, DefaultAudioPort)
+	}
+	return &NetSink{addr: addr}
+}
+
+// Init connects to the receiver if not already connected.
+func (s *NetSink) Init() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		return nil
+	}
+	conn, err := net.DialTimeout("tcp", s.addr, netTimeout)
+	if err != nil {
+		return fmt.Errorf("connecting to audio receiver: %w", err)
+	}
+	conn.SetWriteDeadline(time.Now().Add(netTimeout))
+	if _, err := io.WriteString(conn, audioHandshake); err != nil {
+		conn.Close()
+		return fmt.Errorf("connecting to audio receiver: %w", err)
+	}
+	s.conn = conn
+	return nil
+}
+
+// Play sends p and waits for as long as it takes to play. If ctx is
+// cancelled first, the receiver is told to cut the sound off.
+func (s *NetSink) Play(ctx context.Context, p Playback) error {
+	n, err := s.send(p.Streamer)
+	if err != nil {
+		return err
+	}
+	select {
+	case <-time.After(outputFormat.SampleRate.D(n)):
+		return nil
+	case <-ctx.Done():
+		s.send(nil)
+		return ctx.Err()
+	}
+}
+
+// Layer sends a sound to be mixed into whatever is playing at the
+// receiver. Errors are left for the next Play to report.
+func (s *NetSink) Layer(streamer beep.Streamer) {
+	s.send(streamer)
+}
+
+// Close drops the connection.
+func (s *NetSink) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// send writes streamer as one frame: its length in samples as a big-endian
+// uint32, then 16-bit little-endian stereo samples. An empty frame stops
+// whatever the receiver is playing. It returns the number of samples sent.
+func (s *NetSink) send(streamer beep.Streamer) (int, error) {
+	var samples [][2]float64
+	if streamer != nil {
+		samples = readAll(streamer)
+	}
+	frame := make([]byte, 4+4*len(samples))
+	binary.BigEndian.PutUint32(frame, uint32(len(samples)))
+	for i, sample := range samples {
+		binary.LittleEndian.PutUint16(frame[4+4*i:], uint16(toInt16(sample[0])))
+		binary.LittleEndian.PutUint16(frame[6+4*i:], uint16(toInt16(sample[1])))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return 0, errNotConnected
+	}
+	s.conn.SetWriteDeadline(time.Now().Add(netTimeout))
+	if _, err := s.conn.Write(frame); err != nil {
+		s.conn.Close()
+		s.conn = nil
+		return 0, err
+	}
+	return len(samples), nil
+}
+
+// ServeAudio plays the sound sent by network sinks connecting to l on this
+// machine's speaker, until ctx is cancelled.
+func ServeAudio(ctx context.Context, l net.Listener) error {
+	if err := initSpeaker(speakerBuffer); err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go serveAudioConn(conn)
+	}
+}
+
+func serveAudioConn(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	if line, err := r.ReadString('\n'); err != nil || line != audioHandshake {
+		return
+	}
+	// A frame longer than a minute means the stream is garbled
+	maxFrame := uint32(outputFormat.SampleRate.N(time.Minute))
+	header := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return
+		}
+		n := binary.BigEndian.Uint32(header)
+		if n == 0 {
+			speaker.Clear()
+			continue
+		}
+		if n > maxFrame {
+			return
+		}
+		data := make([]byte, 4*n)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return
+		}
+		samples := make([][2]float64, n)
+		for i := range samples {
+			samples[i][0] = float64(int16(binary.LittleEndian.Uint16(data[4*i:]))) / math.MaxInt16
+			samples[i][1] = float64(int16(binary.LittleEndian.Uint16(data[4*i+2:]))) / math.MaxInt16
+		}
+		buffer := pcmBuffer(samples)
+		speaker.Play(buffer.Streamer(0, buffer.Len()))
+	}
+}
+
+// readAll reads streamer to the end.
+func readAll(streamer beep.Streamer) [][2]float64 {
+	var out [][2]float64
+	chunk := make([][2]float64, 512)
+	for {
+		n, ok := streamer.Stream(chunk)
+		out = append(out, chunk[:n]...)
+		if !ok {
+			return out
+		}
+	}
+}
+
+// toInt16 converts a sample from -1..1 to 16-bit PCM.
+func toInt16(v float64) int16 {
+	return int16(math.Max(-1, math.Min(1, v)) * math.MaxInt16)
+}
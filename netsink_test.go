@@ -0,0 +1,96 @@
+package phonical_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/faiface/beep"
+
+	"phonical"
+)
+
+// constant returns a streamer of n samples at v in both channels.
+func constant(n int, v float64) beep.Streamer {
+	return beep.StreamerFunc(func(samples [][2]float64) (int, bool) {
+		if n == 0 {
+			return 0, false
+		}
+		filled := min(n, len(samples))
+		for i := range samples[:filled] {
+			samples[i] = [2]float64{v, v}
+		}
+		n -= filled
+		return filled, true
+	})
+}
+
+func TestNetSink(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	conns := make(chan net.Conn, 1)
+	go func() {
+		if conn, err := l.Accept(); err == nil {
+			conns <- conn
+		}
+	}()
+
+	sink := phonical.NewNetSink(l.Addr().String())
+	defer sink.Close()
+	if err := sink.Play(context.Background(), phonical.Playback{Streamer: constant(1, 0)}); err == nil {
+		t.Error("played before connecting")
+	}
+	if err := sink.Init(); err != nil {
+		t.Fatal(err)
+	}
+	conn := <-conns
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	r := bufio.NewReader(conn)
+	if line, err := r.ReadString('\n'); err != nil || line != "PHONICAL-AUDIO 1 44100\n" {
+		t.Fatalf("handshake %q, %v", line, err)
+	}
+
+	// 10ms at half volume, sent whole and waited out
+	start := time.Now()
+	if err := sink.Play(context.Background(), phonical.Playback{Streamer: constant(441, 0.5)}); err != nil {
+		t.Fatal(err)
+	}
+	if waited := time.Since(start); waited < 10*time.Millisecond {
+		t.Errorf("Play returned after %s, want the sound's 10ms", waited)
+	}
+	frame := make([]byte, 4+4*441)
+	if _, err := io.ReadFull(r, frame); err != nil {
+		t.Fatal(err)
+	}
+	if n := binary.BigEndian.Uint32(frame); n != 441 {
+		t.Errorf("frame of %d samples, want 441", n)
+	}
+	if left, right := int16(binary.LittleEndian.Uint16(frame[4:])), int16(binary.LittleEndian.Uint16(frame[6:])); left != 16383 || right != 16383 {
+		t.Errorf("first sample %d, %d, want 16383 in both", left, right)
+	}
+
+	// Cutting a sound off sends an empty frame to stop the receiver
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := sink.Play(ctx, phonical.Playback{Streamer: constant(44100, 0.5)}); err == nil {
+		t.Error("cancelled Play reported success")
+	}
+	if _, err := r.Discard(4 + 4*44100); err != nil {
+		t.Fatal(err)
+	}
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		t.Fatal(err)
+	}
+	if n := binary.BigEndian.Uint32(header); n != 0 {
+		t.Errorf("stop frame of %d samples, want 0", n)
+	}
+}
@@ -0,0 +1,141 @@
+package phonical
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// commonWords are real three-letter words beyond the rhyme dictionary,
+// kept out of the nonsense words.
+var commonWords = strings.Fields(`
+	bus cab cob cod dam dim fix fox gap gas gum hem him hum jab jig job jot
+	kin lab lad leg mix mob mom mud mum nab nag nib nod nun pal peg pod pub
+	pug pun pup rib rim rod rot sag sap sob sod son sum tab tax tot vat wax
+	web wit yak yap yes yum nan nip pip sis tat tit bum fag git nob pis poo
+	wee dot gal lit mat nit pin sin tan tin wed`)
+
+const (
+	// vowels are the short vowels at the middle of a nonsense word.
+	vowels = "aeiou"
+	// noOnset are consonants that don't start a decodable nonsense word.
+	noOnset = "qx"
+	// noCoda are consonants that don't end one: they are silent, spelled
+	// differently or never end English words.
+	noCoda = "chjqrvwy"
+)
+
+// nonsenseTries is how many candidates NonsenseWords draws for each word
+// before giving up on finding a new one.
+const nonsenseTries = 20
+
+// NonsenseWords returns up to n different consonant-vowel-consonant
+// nonsense words made only from letters, such as "tep" or "mib", for
+// decoding practice. Words the rhyme dictionary or a short list of common
+// words knows to be real are left out.
+func NonsenseWords(letters []rune, n int) []string {
+	var onsets, middles, codas []rune
+	for _, r := range letters {
+		switch {
+		case strings.ContainsRune(vowels, r):
+			middles = append(middles, r)
+		case r >= 'a' && r <= 'z':
+			if !strings.ContainsRune(noOnset, r) {
+				onsets = append(onsets, r)
+			}
+			if !strings.ContainsRune(noCoda, r) {
+				codas = append(codas, r)
+			}
+		}
+	}
+	if len(onsets) == 0 || len(middles) == 0 || len(codas) == 0 {
+		return nil
+	}
+
+	loadFamilies()
+	used := make(map[string]bool)
+	for _, w := range commonWords {
+		used[w] = true
+	}
+	var words []string
+	for tries := 0; len(words) < n && tries < n*nonsenseTries; tries++ {
+		w := string([]rune{
+			onsets[rand.Intn(len(onsets))],
+			middles[rand.Intn(len(middles))],
+			codas[rand.Intn(len(codas))],
+		})
+		if used[w] || familyOf[w] != nil {
+			continue
+		}
+		used[w] = true
+		words = append(words, w)
+	}
+	return words
+}
+
+// NonsenseWordCheck returns a decoding check of the given number of
+// nonsense words, made from the letters taught so far. Each is played as
+// blended phonemes and the child types it, ending with space or Enter.
+// Results are added to the engine's stats and passed to done.
+func NonsenseWordCheck(rounds int, done func(SpellingResult)) Activity {
+	return ActivityFunc(func(t *Tutor) error {
+		words := NonsenseWords(t.e.packLetters(), rounds)
+		if len(words) == 0 {
+			return fmt.Errorf("not enough letters taught yet for nonsense words")
+		}
+
+		result := SpellingResult{Total: len(words)}
+		t.Say("Alien words! Listen to each made-up word, then type it and press space.")
+		for _, word := range words {
+			correct := false
+			for try := 0; try < spellingTries && !correct; try++ {
+				if try > 0 {
+					t.Say("Listen again.")
+				}
+				t.playBlend(word)
+				typed, err := readSpelling(t)
+				if err != nil {
+					return err
+				}
+				correct = typed == word
+			}
+
+			if stats := t.Stats(); stats != nil {
+				stats.Update(func(st *Stats) { st.Nonsense.record(correct) })
+			}
+			if correct {
+				result.Correct++
+				t.Say("Yes!")
+			} else {
+				t.Say(fmt.Sprintf("That one was %s.", strings.Join(strings.Split(word, ""), " ")))
+				t.playBlend(word)
+			}
+			t.Pause(500 * time.Millisecond)
+		}
+
+		t.Say(fmt.Sprintf("You got %s out of %s.", numberWord(result.Correct), numberWord(result.Total)))
+		if done != nil {
+			done(result)
+		}
+		return nil
+	})
+}
+
+// playBlend plays word's phonemes run together, falling back to sounding
+// it out when a letter has no sound.
+func (t *Tutor) playBlend(word string) {
+	if buffer := t.e.blendWord(word); buffer != nil {
+		t.e.playAndWait(&queuedSound{sound: "blend:" + word, buffer: buffer})
+		return
+	}
+	t.SoundOut(word)
+}
+
+// record counts an attempt.
+func (s *Score) record(correct bool) {
+	s.Attempts++
+	if correct {
+		s.Correct++
+	}
+}
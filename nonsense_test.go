@@ -0,0 +1,65 @@
+package phonical_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestNonsenseWords(t *testing.T) {
+	real := map[string]bool{"sat": true, "pin": true, "tin": true, "pit": true, "sit": true, "tap": true, "nap": true, "pat": true, "tan": true, "pan": true}
+	words := phonical.NonsenseWords([]rune("satpin"), 10)
+	if len(words) == 0 {
+		t.Fatal("no nonsense words from s, a, t, p, i and n")
+	}
+	seen := map[string]bool{}
+	for _, w := range words {
+		if len(w) != 3 || !strings.ContainsAny(w[1:2], "ai") || strings.Trim(w, "satpin") != "" {
+			t.Errorf("%q is not consonant-vowel-consonant from the letters", w)
+		}
+		if real[w] || seen[w] {
+			t.Errorf("%q is a real word or repeated", w)
+		}
+		seen[w] = true
+	}
+	if words := phonical.NonsenseWords([]rune("st"), 5); words != nil {
+		t.Errorf("words %q without a vowel", words)
+	}
+}
+
+func TestNonsenseWordCheck(t *testing.T) {
+	stats, err := phonical.OpenStats(filepath.Join(t.TempDir(), "stats.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pack := letterPack(t, map[string]string{"s": "s", "a": "a", "t": "t", "i": "i", "p": "p"})
+	h := phonicaltest.New(t, phonical.WithPack(pack), phonical.WithStats(stats), phonical.WithWatchdog(0))
+	results := make(chan phonical.SpellingResult, 1)
+	done := runActivity(h, phonical.NonsenseWordCheck(1, func(r phonical.SpellingResult) { results <- r }))
+
+	word := strings.TrimPrefix(waitPlayed(t, h, 2)[1], "blend:")
+	n := spell(t, h, word, 2)
+	played := waitPlayed(t, h, n+2)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"say:Alien words! Listen to each made-up word, then type it and press space.", "blend:" + word}
+	for _, c := range word {
+		want = append(want, string(c)+".wav")
+	}
+	want = append(want, "say:Yes!", "say:You got one out of one.")
+	if !reflect.DeepEqual(played, want) {
+		t.Errorf("played %q, want %q", played, want)
+	}
+	if r := <-results; r != (phonical.SpellingResult{Correct: 1, Total: 1}) {
+		t.Errorf("result %+v, want one of one", r)
+	}
+	if score := stats.Snapshot().Nonsense; score.Attempts != 1 || score.Correct != 1 {
+		t.Errorf("nonsense score %+v, want one of one", score)
+	}
+}
@@ -0,0 +1,60 @@
+package phonical
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Notifier shows desktop notifications, so changes made with hotkeys or
+// by the system (pausing for Do Not Disturb, switching sounds) are visible
+// on screen.
+type Notifier interface {
+	Notify(title, message string) error
+}
+
+// NotifierFunc adapts an ordinary function to the Notifier interface.
+type NotifierFunc func(title, message string) error
+
+// Notify calls f(title, message).
+func (f NotifierFunc) Notify(title, message string) error {
+	return f(title, message)
+}
+
+// SystemNotifier shows notifications with the platform's own tools:
+// osascript on macOS, notify-send on Linux and a tray balloon on Windows.
+type SystemNotifier struct{}
+
+// Notify shows message under title.
+func (SystemNotifier) Notify(title, message string) error {
+	cmd, err := notifyCommand(runtime.GOOS, title, message)
+	if err != nil {
+		return err
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("showing notification: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// WithNotifier shows state changes, such as pausing for Do Not Disturb or
+// switching sounds, as desktop notifications through n. Nil, the default,
+// shows none.
+func WithNotifier(n Notifier) Option {
+	return func(e *Engine) {
+		e.notifier = n
+	}
+}
+
+// notify shows message in the background, so a slow notification command
+// never holds up key handling.
+func (e *Engine) notify(message string) {
+	if e.notifier == nil {
+		return
+	}
+	go func() {
+		if err := e.notifier.Notify("Phonical", message); err != nil {
+			e.debugf("Notification failed: %v\n", err)
+		}
+	}()
+}
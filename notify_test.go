@@ -0,0 +1,45 @@
+package phonical_test
+
+import (
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// notifications returns a notifier sending each message to the channel.
+func notifications() (phonical.Notifier, <-chan string) {
+	c := make(chan string, 10)
+	return phonical.NotifierFunc(func(title, message string) error {
+		c <- title + ": " + message
+		return nil
+	}), c
+}
+
+func waitNotification(t *testing.T, c <-chan string, want string) {
+	t.Helper()
+	select {
+	case got := <-c:
+		if got != want {
+			t.Errorf("notified %q, want %q", got, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Errorf("no notification, want %q", want)
+	}
+}
+
+func TestNotifySwitchingSounds(t *testing.T) {
+	notifier, c := notifications()
+	h := phonicaltest.New(t, phonical.WithNotifier(notifier))
+	h.Engine.SetPack(secondPack(t))
+	h.Expect("say:Second")
+	waitNotification(t, c, "Phonical: Sounds: Second")
+}
+
+func TestNotifyDoNotDisturb(t *testing.T) {
+	fakeCommand(t, "gsettings", "false")
+	notifier, c := notifications()
+	phonicaltest.New(t, phonical.WithNotifier(notifier), phonical.WithDoNotDisturb(true))
+	waitNotification(t, c, "Phonical: Paused: Do Not Disturb")
+}
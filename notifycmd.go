@@ -0,0 +1,35 @@
+package phonical
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// balloonScript shows a Windows tray balloon. The title and message are
+// read from the environment rather than put in the script, where
+// PowerShell would run them as code.
+const balloonScript = "Add-Type -AssemblyName System.Windows.Forms; " +
+	"$n = New-Object System.Windows.Forms.NotifyIcon; " +
+	"$n.Icon = [System.Drawing.SystemIcons]::Information; $n.Visible = $true; " +
+	"$n.ShowBalloonTip(5000, $env:PHONICAL_TITLE, $env:PHONICAL_MESSAGE, 'Info'); " +
+	"Start-Sleep 5; $n.Dispose()"
+
+// notifyCommand builds the command showing message under title on goos.
+func notifyCommand(goos, title, message string) (*exec.Cmd, error) {
+	switch goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", message, title)
+		return exec.Command("osascript", "-e", script), nil
+	case "windows":
+		cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", balloonScript)
+		cmd.Env = append(os.Environ(), "PHONICAL_TITLE="+title, "PHONICAL_MESSAGE="+message)
+		return cmd, nil
+	}
+	path, err := exec.LookPath("notify-send")
+	if err != nil {
+		return nil, errors.New("no notification command found (install libnotify)")
+	}
+	return exec.Command(path, "--app-name=Phonical", title, message), nil
+}
@@ -0,0 +1,28 @@
+package phonical
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNotifyCommandWindows(t *testing.T) {
+	// A pack name from a shared file must not run as PowerShell
+	message := "Sounds: x'; Remove-Item -Recurse ~; '"
+	cmd, err := notifyCommand("windows", "Phonical", message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"powershell", "-NoProfile", "-NonInteractive", "-Command", balloonScript}
+	if !slices.Equal(cmd.Args, want) {
+		t.Errorf("command %q, want %q", cmd.Args, want)
+	}
+	for _, arg := range cmd.Args {
+		if strings.Contains(arg, "Remove-Item") {
+			t.Errorf("message in the command line: %q", arg)
+		}
+	}
+	if !slices.Contains(cmd.Env, "PHONICAL_TITLE=Phonical") || !slices.Contains(cmd.Env, "PHONICAL_MESSAGE="+message) {
+		t.Error("title and message not passed in the environment")
+	}
+}
@@ -0,0 +1,318 @@
+package phonical
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// DefaultOpenRGBAddr is where the OpenRGB SDK server listens unless told
+// otherwise.
+const DefaultOpenRGBAddr = "localhost:6742"
+
+// keyLightTime is how long a key stays lit when no other sound follows.
+const keyLightTime = 800 * time.Millisecond
+
+// OpenRGB SDK packet IDs, from its network protocol.
+const (
+	orgbControllerCount = 0
+	orgbControllerData  = 1
+	orgbClientName      = 50
+	orgbUpdateSingleLED = 1052
+	orgbCustomMode      = 1100
+)
+
+// KeyLights lights the key of each letter on RGB keyboards while its sound
+// plays, each letter in a colour of its own, through the OpenRGB SDK
+// server (enable it in OpenRGB's SDK Server tab). It is an EventSink to
+// add with WithSink, and is closed with the engine.
+type KeyLights struct {
+	conn   net.Conn
+	keys   map[rune][]orgbLED
+	events chan PlayEvent
+	done   chan struct{}
+	closed chan struct{}
+	once   sync.Once
+}
+
+// orgbLED is one LED of one OpenRGB device.
+type orgbLED struct {
+	device uint32
+	index  uint32
+}
+
+// NewKeyLights connects to the OpenRGB SDK server at addr and finds the
+// letter and digit keys of its devices. It fails when no device has any.
+func NewKeyLights(addr string) (*KeyLights, error) {
+	conn, err := net.DialTimeout("tcp", addr, 3*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to OpenRGB: %w", err)
+	}
+	k := &KeyLights{
+		conn:   conn,
+		keys:   make(map[rune][]orgbLED),
+		events: make(chan PlayEvent, 64),
+		done:   make(chan struct{}),
+		closed: make(chan struct{}),
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if err := k.findKeys(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("OpenRGB: %w", err)
+	}
+	conn.SetDeadline(time.Time{})
+	if len(k.keys) == 0 {
+		conn.Close()
+		return nil, errors.New("OpenRGB has no keyboard with per-key lighting")
+	}
+	go k.run()
+	return k, nil
+}
+
+// Keys returns how many keys can be lit.
+func (k *KeyLights) Keys() int {
+	return len(k.keys)
+}
+
+// Played lights the key of a letter starting to play.
+func (k *KeyLights) Played(ev PlayEvent) {
+	// Drop the event rather than hold up playback when behind
+	select {
+	case <-k.done:
+	case k.events <- ev:
+	default:
+	}
+}
+
+// Close turns the lit key off and disconnects.
+func (k *KeyLights) Close() error {
+	k.once.Do(func() { close(k.done) })
+	<-k.closed
+	return nil
+}
+
+// run lights keys as letters play, off the player's goroutine, turning
+// each off when the next sound starts or after keyLightTime.
+func (k *KeyLights) run() {
+	defer close(k.closed)
+	defer k.conn.Close()
+	var lit []orgbLED
+	var off <-chan time.Time
+	for {
+		select {
+		case ev := <-k.events:
+			if k.light(lit, 0, 0, 0) != nil {
+				return
+			}
+			lit, off = nil, nil
+			if leds := k.keys[unicode.ToLower(ev.Letter)]; ev.Letter != 0 && len(leds) > 0 {
+				r, g, b := letterColour(ev.Letter)
+				if k.light(leds, r, g, b) != nil {
+					return
+				}
+				lit, off = leds, time.After(keyLightTime)
+			}
+		case <-off:
+			if k.light(lit, 0, 0, 0) != nil {
+				return
+			}
+			lit, off = nil, nil
+		case <-k.done:
+			k.light(lit, 0, 0, 0)
+			return
+		}
+	}
+}
+
+// light sets leds to a colour.
+func (k *KeyLights) light(leds []orgbLED, r, g, b byte) error {
+	for _, led := range leds {
+		data := binary.LittleEndian.AppendUint32(nil, led.index)
+		data = append(data, r, g, b, 0)
+		k.conn.SetWriteDeadline(time.Now().Add(time.Second))
+		if err := k.send(led.device, orgbUpdateSingleLED, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// letterColour gives each letter its own bright colour, spread around the
+// colour wheel so neighbouring letters differ.
+func letterColour(letter rune) (r, g, b byte) {
+	hue := math.Mod(float64(unicode.ToLower(letter))*137.5, 360) / 60
+	x := 1 - math.Abs(math.Mod(hue, 2)-1)
+	var rf, gf, bf float64
+	switch int(hue) {
+	case 0:
+		rf, gf = 1, x
+	case 1:
+		rf, gf = x, 1
+	case 2:
+		gf, bf = 1, x
+	case 3:
+		gf, bf = x, 1
+	case 4:
+		rf, bf = x, 1
+	default:
+		rf, bf = 1, x
+	}
+	return byte(rf * 255), byte(gf * 255), byte(bf * 255)
+}
+
+// findKeys asks the server for its devices and notes the LEDs named for
+// letter and digit keys, such as "Key: A", switching those devices to
+// direct control.
+func (k *KeyLights) findKeys() error {
+	if err := k.send(0, orgbClientName, []byte("Phonical\x00")); err != nil {
+		return err
+	}
+	if err := k.send(0, orgbControllerCount, nil); err != nil {
+		return err
+	}
+	reply, err := k.receive(orgbControllerCount)
+	if err != nil {
+		return err
+	}
+	if len(reply) < 4 {
+		return errors.New("short controller count")
+	}
+	count := binary.LittleEndian.Uint32(reply)
+	for device := uint32(0); device < count; device++ {
+		if err := k.send(device, orgbControllerData, nil); err != nil {
+			return err
+		}
+		data, err := k.receive(orgbControllerData)
+		if err != nil {
+			return err
+		}
+		leds, err := parseOpenRGBLEDs(data)
+		if err != nil {
+			return fmt.Errorf("device %d: %w", device, err)
+		}
+		found := false
+		for i, name := range leds {
+			key, ok := strings.CutPrefix(name, "Key: ")
+			if r := []rune(key); ok && len(r) == 1 && (unicode.IsLetter(r[0]) || unicode.IsDigit(r[0])) {
+				letter := unicode.ToLower(r[0])
+				k.keys[letter] = append(k.keys[letter], orgbLED{device, uint32(i)})
+				found = true
+			}
+		}
+		if found {
+			if err := k.send(device, orgbCustomMode, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// send writes one packet of the OpenRGB protocol.
+func (k *KeyLights) send(device, id uint32, data []byte) error {
+	packet := make([]byte, 16, 16+len(data))
+	copy(packet, "ORGB")
+	binary.LittleEndian.PutUint32(packet[4:], device)
+	binary.LittleEndian.PutUint32(packet[8:], id)
+	binary.LittleEndian.PutUint32(packet[12:], uint32(len(data)))
+	_, err := k.conn.Write(append(packet, data...))
+	return err
+}
+
+// receive reads packets until one with id arrives, returning its data.
+// Others, such as device list updates, are skipped.
+func (k *KeyLights) receive(id uint32) ([]byte, error) {
+	for {
+		var header [16]byte
+		if _, err := io.ReadFull(k.conn, header[:]); err != nil {
+			return nil, err
+		}
+		if string(header[:4]) != "ORGB" {
+			return nil, errors.New("not an OpenRGB server")
+		}
+		size := binary.LittleEndian.Uint32(header[12:])
+		if size > 1<<20 {
+			return nil, errors.New("packet too large")
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(k.conn, data); err != nil {
+			return nil, err
+		}
+		if binary.LittleEndian.Uint32(header[8:]) == id {
+			return data, nil
+		}
+	}
+}
+
+// parseOpenRGBLEDs returns the LED names from a device description in
+// version 0 of the protocol, in LED order.
+func parseOpenRGBLEDs(data []byte) ([]string, error) {
+	r := orgbReader{data: data}
+	r.skip(8) // size and device type
+	for i := 0; i < 5; i++ {
+		r.str() // name, description, version, serial and location
+	}
+	modes := r.u16()
+	r.skip(4) // active mode
+	for i := 0; i < modes && r.err == nil; i++ {
+		r.str()
+		r.skip(9 * 4) // value, flags, speeds, colour limits, speed, direction, colour mode
+		r.skip(4 * r.u16())
+	}
+	zones := r.u16()
+	for i := 0; i < zones && r.err == nil; i++ {
+		r.str()
+		r.skip(4 * 4) // type and LED counts
+		r.skip(r.u16())
+	}
+	leds := make([]string, r.u16())
+	for i := range leds {
+		leds[i] = r.str()
+		r.skip(4) // value
+	}
+	return leds, r.err
+}
+
+// orgbReader reads the little-endian fields of an OpenRGB packet,
+// remembering the first error.
+type orgbReader struct {
+	data []byte
+	err  error
+}
+
+func (r *orgbReader) take(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n > len(r.data) {
+		r.err = errors.New("device description too short")
+		r.data = nil
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *orgbReader) skip(n int) {
+	r.take(n)
+}
+
+func (r *orgbReader) u16() int {
+	if b := r.take(2); b != nil {
+		return int(binary.LittleEndian.Uint16(b))
+	}
+	return 0
+}
+
+// str reads a string: its length, counting the trailing NUL, then its bytes.
+func (r *orgbReader) str() string {
+	return strings.TrimRight(string(r.take(r.u16())), "\x00")
+}
@@ -0,0 +1,134 @@
+package phonical_test
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// ledUpdate is a single LED change sent to the OpenRGB server.
+type ledUpdate struct {
+	index   uint32
+	r, g, b byte
+}
+
+// openRGBServer serves one keyboard whose LEDs are named leds, reporting
+// each LED change on the returned channel.
+func openRGBServer(t *testing.T, leds ...string) (string, <-chan ledUpdate) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	str := func(b []byte, s string) []byte {
+		b = binary.LittleEndian.AppendUint16(b, uint16(len(s)+1))
+		return append(append(b, s...), 0)
+	}
+	device := make([]byte, 8)
+	for _, s := range []string{"Keyboard", "", "1.0", "", ""} {
+		device = str(device, s)
+	}
+	device = binary.LittleEndian.AppendUint16(device, 0) // modes
+	device = binary.LittleEndian.AppendUint32(device, 0) // active mode
+	device = binary.LittleEndian.AppendUint16(device, 0) // zones
+	device = binary.LittleEndian.AppendUint16(device, uint16(len(leds)))
+	for _, name := range leds {
+		device = binary.LittleEndian.AppendUint32(str(device, name), 0)
+	}
+
+	updates := make(chan ledUpdate, 16)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reply := func(id uint32, data []byte) {
+			header := []byte("ORGB")
+			header = binary.LittleEndian.AppendUint32(header, 0)
+			header = binary.LittleEndian.AppendUint32(header, id)
+			header = binary.LittleEndian.AppendUint32(header, uint32(len(data)))
+			conn.Write(append(header, data...))
+		}
+		for {
+			var header [16]byte
+			if _, err := io.ReadFull(conn, header[:]); err != nil {
+				return
+			}
+			data := make([]byte, binary.LittleEndian.Uint32(header[12:]))
+			if _, err := io.ReadFull(conn, data); err != nil {
+				return
+			}
+			switch id := binary.LittleEndian.Uint32(header[8:]); id {
+			case 0:
+				reply(id, binary.LittleEndian.AppendUint32(nil, 1))
+			case 1:
+				reply(id, device)
+			case 1052:
+				updates <- ledUpdate{binary.LittleEndian.Uint32(data), data[4], data[5], data[6]}
+			}
+		}
+	}()
+	return ln.Addr().String(), updates
+}
+
+// nextUpdate returns the next LED change, failing if none comes.
+func nextUpdate(t *testing.T, updates <-chan ledUpdate) ledUpdate {
+	t.Helper()
+	select {
+	case u := <-updates:
+		return u
+	case <-time.After(2 * time.Second):
+		t.Fatal("no LED change")
+		return ledUpdate{}
+	}
+}
+
+func TestKeyLights(t *testing.T) {
+	addr, updates := openRGBServer(t, "Key: A", "Key: B", "Key: Escape")
+	lights, err := phonical.NewKeyLights(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := lights.Keys(); n != 2 {
+		t.Errorf("%d keys can be lit, want a and b", n)
+	}
+	h := phonicaltest.New(t, phonical.WithSink(lights))
+
+	h.Type("a")
+	h.Expect("a.wav")
+	a := nextUpdate(t, updates)
+	if a.index != 0 || a.r|a.g|a.b == 0 {
+		t.Errorf("typing a changed %+v, want LED 0 lit", a)
+	}
+
+	// The next letter turns the last one off and lights in its own colour
+	h.Type("b")
+	h.Expect("a.wav", "b.wav")
+	if off := nextUpdate(t, updates); off != (ledUpdate{index: 0}) {
+		t.Errorf("typing b changed %+v, want LED 0 off", off)
+	}
+	b := nextUpdate(t, updates)
+	if b.index != 1 || b.r|b.g|b.b == 0 || b == (ledUpdate{1, a.r, a.g, a.b}) {
+		t.Errorf("typing b changed %+v, want LED 1 lit unlike a's %+v", b, a)
+	}
+
+	lights.Close()
+	if off := nextUpdate(t, updates); off != (ledUpdate{index: 1}) {
+		t.Errorf("closing changed %+v, want LED 1 off", off)
+	}
+}
+
+func TestKeyLightsNeedKeys(t *testing.T) {
+	addr, _ := openRGBServer(t, "Logo", "Strip 1")
+	if _, err := phonical.NewKeyLights(addr); err == nil {
+		t.Error("connected to OpenRGB without a per-key keyboard")
+	}
+}
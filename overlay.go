@@ -0,0 +1,658 @@
+package phonical
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Overlay corners for OverlaySettings.
+const (
+	CornerTopLeft     = "top-left"
+	CornerTopRight    = "top-right"
+	CornerBottomLeft  = "bottom-left"
+	CornerBottomRight = "bottom-right"
+)
+
+// OverlayCorners lists the corners the overlay can be pinned to.
+func OverlayCorners() []string {
+	return []string{CornerTopLeft, CornerTopRight, CornerBottomLeft, CornerBottomRight}
+}
+
+// Overlay themes for OverlaySettings.
+const (
+	OverlayDark         = "dark"
+	OverlayLight        = "light"
+	OverlayHighContrast = "high-contrast"
+)
+
+// OverlayThemes lists the overlay's colour themes.
+func OverlayThemes() []string {
+	return []string{OverlayDark, OverlayLight, OverlayHighContrast}
+}
+
+// Overlay fonts for OverlaySettings. Lexend and OpenDyslexic are shown
+// when installed, falling back to the usual sans-serif font.
+const (
+	FontSans         = "sans"
+	FontLexend       = "lexend"
+	FontOpenDyslexic = "opendyslexic"
+)
+
+// OverlayFonts lists the fonts the overlay knows by name.
+func OverlayFonts() []string {
+	return []string{FontSans, FontLexend, FontOpenDyslexic}
+}
+
+// Overlay letter cases for OverlaySettings.
+const (
+	CaseLower = "lower"
+	CaseUpper = "upper"
+	CaseBoth  = "both"
+)
+
+// OverlayCases lists the forms the overlay can show letters in.
+func OverlayCases() []string {
+	return []string{CaseLower, CaseUpper, CaseBoth}
+}
+
+// The overlay's scale limits, shared by check and the launcher's slider.
+const (
+	minOverlayScale = 0.25
+	maxOverlayScale = 8.0
+)
+
+// overlayFontFiles are the font files the overlay can serve itself.
+var overlayFontFiles = []string{".ttf", ".otf", ".woff", ".woff2"}
+
+// OverlaySettings is where the overlay window goes and how it looks.
+type OverlaySettings struct {
+	// Monitor is the screen to show it on, counting from 1 in the order
+	// the browser lists them; 0 is the main screen.
+	Monitor int `toml:"monitor,omitempty" json:"monitor"`
+	// Corner is one of OverlayCorners; empty is bottom-right.
+	Corner string `toml:"corner,omitempty" json:"corner"`
+	// Scale sizes the overlay; 0 is 1.
+	Scale float64 `toml:"scale,omitempty" json:"scale"`
+	// Theme is one of OverlayThemes; empty is dark.
+	Theme string `toml:"theme,omitempty" json:"theme"`
+	// Font is one of OverlayFonts, or a .ttf, .otf, .woff or .woff2 file
+	// for fonts that aren't installed; empty is sans.
+	Font string `toml:"font,omitempty" json:"font"`
+	// LetterSize is the letter's height as a percentage of the overlay's;
+	// 0 is 75.
+	LetterSize int `toml:"letter_size,omitempty" json:"letterSize"`
+	// Strokes draws the letter stroke by stroke as its sound plays, the
+	// way it is written, for the letters in the stroke data files.
+	Strokes bool `toml:"strokes,omitempty" json:"strokes"`
+	// Case is one of OverlayCases, whatever was typed; empty is lower,
+	// since early readers learn small letters first though keyboards
+	// show capitals.
+	Case string `toml:"case,omitempty" json:"case"`
+}
+
+// withDefaults fills in the settings left at their zero values.
+func (s OverlaySettings) withDefaults() OverlaySettings {
+	if s.Corner == "" {
+		s.Corner = CornerBottomRight
+	}
+	if s.Scale == 0 {
+		s.Scale = 1
+	}
+	if s.Theme == "" {
+		s.Theme = OverlayDark
+	}
+	if s.Font == "" {
+		s.Font = FontSans
+	}
+	if s.LetterSize == 0 {
+		s.LetterSize = 75
+	}
+	if s.Case == "" {
+		s.Case = CaseLower
+	}
+	return s
+}
+
+// fontFile reports whether Font names a font file rather than a font.
+func (s OverlaySettings) fontFile() bool {
+	return slices.Contains(overlayFontFiles, strings.ToLower(filepath.Ext(s.Font)))
+}
+
+// check reports what is wrong with s, if anything.
+func (s OverlaySettings) check() error {
+	if s.Monitor < 0 {
+		return fmt.Errorf("monitor must be 0 for the main screen or a screen number from 1, got %d", s.Monitor)
+	}
+	if s.Corner != "" && !slices.Contains(OverlayCorners(), s.Corner) {
+		return fmt.Errorf("unknown corner %q (want %s)", s.Corner, strings.Join(OverlayCorners(), ", "))
+	}
+	if s.Scale != 0 && (s.Scale < minOverlayScale || s.Scale > maxOverlayScale) {
+		return fmt.Errorf("scale must be %g–%g, got %g", minOverlayScale, maxOverlayScale, s.Scale)
+	}
+	if s.Theme != "" && !slices.Contains(OverlayThemes(), s.Theme) {
+		return fmt.Errorf("unknown theme %q (want %s)", s.Theme, strings.Join(OverlayThemes(), ", "))
+	}
+	if s.Font != "" && !s.fontFile() && !slices.Contains(OverlayFonts(), s.Font) {
+		return fmt.Errorf("unknown font %q (want %s, or a font file)", s.Font, strings.Join(OverlayFonts(), ", "))
+	}
+	if s.LetterSize != 0 && (s.LetterSize < 20 || s.LetterSize > 100) {
+		return fmt.Errorf("letter size must be 20–100%%, got %d", s.LetterSize)
+	}
+	if s.Case != "" && !slices.Contains(OverlayCases(), s.Case) {
+		return fmt.Errorf("unknown case %q (want %s)", s.Case, strings.Join(OverlayCases(), ", "))
+	}
+	return nil
+}
+
+// overlayLetter is what the overlay page is sent as each letter plays.
+type overlayLetter struct {
+	// Letter is the letter in the forms shown, such as "Aa".
+	Letter string `json:"letter"`
+	// Strokes are, for each form, the SVG paths to draw it with, in order.
+	Strokes [][]string `json:"strokes,omitempty"`
+}
+
+// letterForms returns the forms of letter to show for settings.
+func (s OverlaySettings) letterForms(letter rune) []rune {
+	upper, lower := unicode.ToUpper(letter), unicode.ToLower(letter)
+	switch {
+	case s.Case == CaseUpper:
+		return []rune{upper}
+	case s.Case == CaseBoth && upper != lower:
+		return []rune{upper, lower}
+	default:
+		return []rune{lower}
+	}
+}
+
+// Overlay shows the letter playing now, large, in a small window pinned to
+// a corner of the child's screen, so it can be seen while the sound is
+// heard. It is an EventSink to add with WithSink, and an http.Handler: its
+// page at / picks the screen, corner, size and look and opens the overlay
+// window, which shows /letter, fed over a WebSocket at /overlay. /letter
+// can also be used directly as an OBS browser source. It is closed with
+// the engine.
+type Overlay struct {
+	save    func(OverlaySettings) error
+	token   string
+	updates chan []byte
+	done    chan struct{}
+	once    sync.Once
+	stop    sync.Once
+
+	mu       sync.Mutex
+	settings OverlaySettings
+	clients  map[*wsConn]bool
+}
+
+// NewOverlay returns an overlay with the given settings. When the page
+// changes them, save is called so the choice can be kept for next time; it
+// may be nil.
+func NewOverlay(settings OverlaySettings, save func(OverlaySettings) error) *Overlay {
+	// Only the overlay's own page, which other sites can't read, knows the
+	// token that changing the settings takes
+	token, _ := NewCompanionToken()
+	return &Overlay{
+		save:     save,
+		token:    token,
+		settings: settings.withDefaults(),
+		updates:  make(chan []byte, 64),
+		done:     make(chan struct{}),
+		clients:  make(map[*wsConn]bool),
+	}
+}
+
+// Close stops sending letters and closes the open overlay windows'
+// connections.
+func (o *Overlay) Close() error {
+	o.stop.Do(func() { close(o.done) })
+	o.mu.Lock()
+	clients := o.clients
+	o.clients = make(map[*wsConn]bool)
+	o.mu.Unlock()
+	for ws := range clients {
+		ws.Close()
+	}
+	return nil
+}
+
+// Settings returns where the overlay is placed and how it looks.
+func (o *Overlay) Settings() OverlaySettings {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.settings
+}
+
+// Played shows a letter starting to play.
+func (o *Overlay) Played(ev PlayEvent) {
+	if ev.Letter == 0 {
+		return
+	}
+	o.once.Do(func() { go o.publish() })
+	settings := o.Settings()
+	forms := settings.letterForms(ev.Letter)
+	msg := overlayLetter{Letter: string(forms)}
+	if settings.Strokes {
+		for _, r := range forms {
+			strokes := letterStrokes(r)
+			if strokes == nil {
+				// Draw every form or none, so they match
+				msg.Strokes = nil
+				break
+			}
+			msg.Strokes = append(msg.Strokes, strokes)
+		}
+	}
+	data, _ := json.Marshal(&msg)
+	// Drop the letter rather than hold up playback when behind
+	select {
+	case <-o.done:
+	case o.updates <- data:
+	default:
+	}
+}
+
+// publish sends each letter to the open overlay windows, off the player's
+// goroutine.
+func (o *Overlay) publish() {
+	for {
+		var data []byte
+		select {
+		case <-o.done:
+			return
+		case data = <-o.updates:
+		}
+		o.mu.Lock()
+		clients := make([]*wsConn, 0, len(o.clients))
+		for ws := range o.clients {
+			clients = append(clients, ws)
+		}
+		o.mu.Unlock()
+		for _, ws := range clients {
+			if err := ws.WriteText(data); err != nil {
+				o.drop(ws)
+			}
+		}
+	}
+}
+
+func (o *Overlay) drop(ws *wsConn) {
+	o.mu.Lock()
+	delete(o.clients, ws)
+	o.mu.Unlock()
+	ws.Close()
+}
+
+// ServeHTTP serves the overlay's pages, its feed, its settings and its
+// font file, if it has one.
+func (o *Overlay) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !localHost(r.Host) {
+		// A site rebinding its own name to this machine would otherwise be
+		// the same origin as the overlay
+		http.Error(w, "open the overlay at localhost or an IP address", http.StatusForbidden)
+		return
+	}
+	switch r.URL.Path {
+	case "/":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		page := strings.NewReplacer(
+			"{{token}}", o.token,
+			"{{minScale}}", fmt.Sprint(minOverlayScale),
+			"{{maxScale}}", fmt.Sprint(maxOverlayScale),
+		).Replace(overlayLauncherPage)
+		w.Write([]byte(page))
+	case "/letter":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(overlayLetterPage))
+	case "/settings":
+		o.serveSettings(w, r)
+	case "/font":
+		if s := o.Settings(); s.fontFile() {
+			http.ServeFile(w, r, s.Font)
+			return
+		}
+		http.NotFound(w, r)
+	case "/overlay":
+		ws, err := upgradeWebSocket(w, r)
+		if err != nil {
+			return
+		}
+		o.mu.Lock()
+		select {
+		case <-o.done:
+			o.mu.Unlock()
+			ws.Close()
+			return
+		default:
+			o.clients[ws] = true
+		}
+		o.mu.Unlock()
+		// Read until the window closes; it sends nothing itself
+		for {
+			if _, err := ws.ReadMessage(); err != nil {
+				o.drop(ws)
+				return
+			}
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveSettings returns the settings as JSON, or changes them to the ones
+// posted.
+func (o *Overlay) serveSettings(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(o.Settings())
+	case http.MethodPost:
+		token := r.Header.Get(overlayTokenHeader)
+		if !sameOrigin(r) || o.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(o.token)) != 1 {
+			http.Error(w, "settings can only be changed from the overlay's page", http.StatusForbidden)
+			return
+		}
+		var settings OverlaySettings
+		if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := settings.check(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		settings = settings.withDefaults()
+		o.mu.Lock()
+		// The page may keep the font file it was given, but not name
+		// another, which anyone able to reach it could then read
+		if settings.fontFile() && settings.Font != o.settings.Font {
+			o.mu.Unlock()
+			http.Error(w, "font files can only be set in the settings file", http.StatusForbidden)
+			return
+		}
+		o.settings = settings
+		o.mu.Unlock()
+		if o.save != nil {
+			if err := o.save(settings); err != nil {
+				http.Error(w, "saving the settings: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// overlayTokenHeader carries the token the launcher page was given when it
+// posts the settings.
+const overlayTokenHeader = "X-Phonical-Token"
+
+// localHost reports whether host, from a request, names this machine or
+// an address rather than a site.
+func localHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	return strings.EqualFold(host, "localhost") || net.ParseIP(host) != nil
+}
+
+// overlayLauncherPage chooses the overlay's placement and look and opens
+// it. Browsers with the Window Management API (Chrome and Edge) can list
+// the screens and open the window on any of them; others use the one the
+// page is on.
+var overlayLauncherPage = strings.TrimSpace(`
+<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Phonical overlay</title>
+<meta name="token" content="{{token}}">
+<style>
+  body { font: 16px sans-serif; max-width: 28em; margin: 2em auto; }
+  label { display: block; margin: 0.8em 0; }
+  select, input { font: inherit; margin-left: 0.5em; }
+  #note { color: #666; }
+</style>
+</head>
+<body>
+<h1>Phonical overlay</h1>
+<label>Screen <select id="monitor"><option value="0">Main screen</option></select></label>
+<label>Corner <select id="corner">
+  <option>top-left</option><option>top-right</option>
+  <option>bottom-left</option><option>bottom-right</option>
+</select></label>
+<label>Size <input id="scale" type="range" min="{{minScale}}" max="{{maxScale}}" step="0.25"> <span id="scaleShown"></span></label>
+<label>Theme <select id="theme">
+  <option value="dark">Dark</option><option value="light">Light</option>
+  <option value="high-contrast">High contrast</option>
+</select></label>
+<label>Font <select id="font">
+  <option value="sans">Sans-serif</option><option value="lexend">Lexend</option>
+  <option value="opendyslexic">OpenDyslexic</option>
+</select></label>
+<label>Letter size <input id="letterSize" type="range" min="20" max="100" step="5"> <span id="letterSizeShown"></span></label>
+<label>Show letters <select id="case">
+  <option value="lower">small (a)</option><option value="upper">capital (A)</option>
+  <option value="both">both (Aa)</option>
+</select></label>
+<label><input id="strokes" type="checkbox"> Draw each letter the way it is written</label>
+<button id="show">Show overlay</button>
+<p id="note"></p>
+<script>
+const base = 200, margin = 24;
+const $ = (id) => document.getElementById(id);
+let details = null;
+
+async function findScreens() {
+  if (!("getScreenDetails" in window)) {
+    $("note").textContent = "This browser can only open the overlay on the screen this page is on.";
+    return;
+  }
+  try {
+    details = await window.getScreenDetails();
+  } catch (e) {
+    $("note").textContent = "Allow window management to choose another screen.";
+    return;
+  }
+  details.screens.forEach((s, i) => {
+    const opt = document.createElement("option");
+    opt.value = i + 1;
+    opt.textContent = (i + 1) + ": " + (s.label || s.width + "×" + s.height) + (s.isPrimary ? " (main)" : "");
+    $("monitor").append(opt);
+  });
+}
+
+function settings() {
+  return { monitor: +$("monitor").value, corner: $("corner").value, scale: +$("scale").value,
+    theme: $("theme").value, font: $("font").value, letterSize: +$("letterSize").value,
+    strokes: $("strokes").checked, case: $("case").value };
+}
+
+function shown() {
+  $("scaleShown").textContent = $("scale").value + "×";
+  $("letterSizeShown").textContent = $("letterSize").value + "%";
+}
+
+async function load() {
+  const s = await (await fetch("/settings")).json();
+  await findScreens();
+  if ($("monitor").querySelector('option[value="' + s.monitor + '"]')) $("monitor").value = s.monitor;
+  if (!$("font").querySelector('option[value="' + s.font + '"]')) {
+    const opt = document.createElement("option");
+    opt.value = s.font;
+    opt.textContent = "Font file";
+    $("font").append(opt);
+  }
+  for (const id of ["corner", "scale", "theme", "font", "letterSize", "case"]) $(id).value = s[id];
+  $("strokes").checked = s.strokes;
+  shown();
+}
+
+async function show() {
+  const s = settings();
+  const saved = await fetch("/settings", { method: "POST", body: JSON.stringify(s),
+    headers: { "X-Phonical-Token": document.querySelector('meta[name="token"]').content } });
+  $("note").textContent = saved.ok ? "" : await saved.text();
+  let area = { left: screen.availLeft || 0, top: screen.availTop || 0, width: screen.availWidth, height: screen.availHeight };
+  if (details) {
+    const found = s.monitor > 0 ? details.screens[s.monitor - 1] : details.screens.find((d) => d.isPrimary);
+    if (found) area = { left: found.availLeft, top: found.availTop, width: found.availWidth, height: found.availHeight };
+  }
+  const size = Math.round(base * s.scale);
+  const left = area.left + (s.corner.endsWith("left") ? margin : area.width - size - margin);
+  const top = area.top + (s.corner.startsWith("top") ? margin : area.height - size - margin);
+  window.open("/letter?window=1", "phonical-overlay",
+    "popup,width=" + size + ",height=" + size + ",left=" + left + ",top=" + top);
+}
+
+$("scale").oninput = $("letterSize").oninput = shown;
+$("show").onclick = show;
+load();
+</script>
+</body>
+</html>
+`)
+
+// overlayLetterPage shows each letter as it plays, in the look chosen,
+// drawing its strokes one after another when it is sent them. In the
+// overlay window it fills the window; as a browser source, ?corner= and
+// ?scale= place it.
+var overlayLetterPage = strings.TrimSpace(`
+<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Phonical</title>
+<style>
+  @font-face { font-family: "Phonical font file"; src: url("/font"); }
+  html, body { margin: 0; background: transparent; overflow: hidden; }
+  #box { position: fixed; display: flex; align-items: center; justify-content: center;
+    box-sizing: border-box; width: 200px; height: 200px; margin: 24px; border-radius: 24px;
+    line-height: 1; opacity: 0; transition: opacity 0.3s; }
+  #box.on { opacity: 1; }
+  .window #box { inset: 0; width: auto; height: auto; margin: 0; border-radius: 0; opacity: 1; }
+  .dark #box { background: rgba(0, 0, 0, 0.7); color: #fff; }
+  .light #box { background: rgba(255, 255, 255, 0.92); color: #222; box-shadow: 0 2px 12px rgba(0, 0, 0, 0.3); }
+  .high-contrast #box { background: #000; color: #ff0; border: solid #fff; }
+  .sans #box { font-family: sans-serif; font-weight: bold; }
+  .lexend #box { font-family: Lexend, "Lexend Deca", sans-serif; font-weight: 600; }
+  .opendyslexic #box { font-family: OpenDyslexic, "Open Dyslexic", sans-serif; }
+  .file #box { font-family: "Phonical font file", sans-serif; }
+  svg { overflow: visible; }
+  svg .guide { stroke: currentColor; stroke-width: 1; stroke-dasharray: 3 3; opacity: 0.35; }
+  svg .stroke { fill: none; stroke: currentColor; stroke-width: 9; stroke-linecap: round;
+    stroke-linejoin: round; stroke-dasharray: 1; visibility: hidden;
+    animation: draw linear forwards; }
+  @keyframes draw {
+    from { stroke-dashoffset: 1; visibility: visible; }
+    to { stroke-dashoffset: 0; visibility: visible; }
+  }
+</style>
+</head>
+<body>
+<div id="box"></div>
+<script>
+const params = new URLSearchParams(location.search);
+const box = document.getElementById("box");
+const svgNS = "http://www.w3.org/2000/svg";
+// Strokes are drawn at this many units of the 100-unit box a second
+const strokeSpeed = 160;
+let hide, letterSize = 75, scale = 1;
+
+async function setUp() {
+  const s = await (await fetch("/settings")).json();
+  scale = +params.get("scale") || s.scale;
+  letterSize = s.letterSize;
+  const corner = params.get("corner") || s.corner;
+  const font = ["sans", "lexend", "opendyslexic"].includes(s.font) ? s.font : "file";
+  document.body.className = s.theme + " " + font;
+  box.style.borderWidth = 6 * scale + "px";
+  if (params.get("window")) {
+    document.body.classList.add("window");
+  } else {
+    box.style.width = box.style.height = 200 * scale + "px";
+    box.style[corner.startsWith("top") ? "top" : "bottom"] = "0";
+    box.style[corner.endsWith("left") ? "left" : "right"] = "0";
+  }
+}
+
+// draw shows each form of the letter stroke by stroke, one after another,
+// returning how long it takes.
+function draw(forms) {
+  // Two forms share the box, a little smaller
+  const size = 2 * letterSize * scale * (forms.length > 1 ? 0.6 : 1);
+  box.textContent = "";
+  let start = 0;
+  for (const strokes of forms) {
+    start = drawForm(strokes, size, start);
+  }
+  return start * 1000;
+}
+
+// drawForm adds one form of the letter, its strokes starting at start
+// seconds, returning when they finish.
+function drawForm(strokes, size, start) {
+  const svg = document.createElementNS(svgNS, "svg");
+  svg.setAttribute("viewBox", "0 0 100 100");
+  svg.setAttribute("width", size);
+  svg.setAttribute("height", size);
+  for (const y of [42, 78]) {
+    const guide = document.createElementNS(svgNS, "line");
+    guide.setAttribute("class", "guide");
+    guide.setAttribute("x1", 5);
+    guide.setAttribute("x2", 95);
+    guide.setAttribute("y1", y);
+    guide.setAttribute("y2", y);
+    svg.append(guide);
+  }
+  box.append(svg);
+  for (const d of strokes) {
+    const path = document.createElementNS(svgNS, "path");
+    path.setAttribute("class", "stroke");
+    path.setAttribute("d", d);
+    svg.append(path);
+    const length = path.getTotalLength();
+    path.setAttribute("pathLength", 1);
+    const time = Math.max(0.15, length / strokeSpeed);
+    path.style.animationDelay = start + "s";
+    path.style.animationDuration = time + "s";
+    start += time + 0.1;
+  }
+  return start;
+}
+
+function show(msg) {
+  let drawing = 0;
+  if (msg.strokes) {
+    drawing = draw(msg.strokes);
+  } else {
+    box.textContent = msg.letter;
+    box.style.fontSize = 2 * letterSize * scale * ([...msg.letter].length > 1 ? 0.7 : 1) + "px";
+  }
+  box.classList.add("on");
+  clearTimeout(hide);
+  hide = setTimeout(() => box.classList.remove("on"), 2000 + drawing);
+}
+
+function connect() {
+  const ws = new WebSocket("ws://" + location.host + "/overlay");
+  ws.onmessage = (m) => show(JSON.parse(m.data));
+  ws.onclose = () => setTimeout(connect, 1000);
+}
+
+setUp().then(connect);
+</script>
+</body>
+</html>
+`)
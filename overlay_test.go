@@ -0,0 +1,382 @@
+package phonical_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"phonical"
+)
+
+func TestOverlaySettingsNeedToken(t *testing.T) {
+	var saved []phonical.OverlaySettings
+	overlay := phonical.NewOverlay(phonical.OverlaySettings{}, func(s phonical.OverlaySettings) error {
+		saved = append(saved, s)
+		return nil
+	})
+	srv := httptest.NewServer(overlay)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	m := regexp.MustCompile(`<meta name="token" content="([0-9a-f]+)">`).FindSubmatch(page)
+	if m == nil {
+		t.Fatal("launcher page has no token")
+	}
+	token := string(m[1])
+
+	post := func(token, origin string) int {
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/settings", strings.NewReader(`{"corner":"top-left"}`))
+		if token != "" {
+			req.Header.Set("X-Phonical-Token", token)
+		}
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	for _, tc := range []struct {
+		name, token, origin string
+		status              int
+	}{
+		{"no token", "", srv.URL, http.StatusForbidden},
+		{"wrong token", "0123", srv.URL, http.StatusForbidden},
+		{"other site", token, "http://example.com", http.StatusForbidden},
+		{"own page", token, srv.URL, http.StatusNoContent},
+	} {
+		if got := post(tc.token, tc.origin); got != tc.status {
+			t.Errorf("%s: status %d, want %d", tc.name, got, tc.status)
+		}
+	}
+	if len(saved) != 1 || saved[0].Corner != phonical.CornerTopLeft {
+		t.Errorf("saved %+v, want one change to top-left", saved)
+	}
+}
+
+func TestOverlayRefusesOtherSites(t *testing.T) {
+	srv := httptest.NewServer(phonical.NewOverlay(phonical.OverlaySettings{}, nil))
+	defer srv.Close()
+
+	// A site whose name was rebound to this machine
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/", nil)
+	req.Host = "attacker.example:8766"
+	if resp, err := http.DefaultClient.Do(req); err != nil {
+		t.Fatal(err)
+	} else if resp.Body.Close(); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("page for another host: status %d, want %d", resp.StatusCode, http.StatusForbidden)
+	}
+
+	// A WebSocket opened by another site's page
+	req, _ = http.NewRequest(http.MethodGet, srv.URL+"/overlay", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Origin", "http://example.com")
+	if resp, err := http.DefaultClient.Do(req); err != nil {
+		t.Fatal(err)
+	} else if resp.Body.Close(); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("WebSocket from another site: status %d, want %d", resp.StatusCode, http.StatusForbidden)
+	}
+}
+
+func TestOverlayCloseDropsWindows(t *testing.T) {
+	overlay := phonical.NewOverlay(phonical.OverlaySettings{}, nil)
+	srv := httptest.NewServer(overlay)
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	io.WriteString(conn, "GET /overlay HTTP/1.1\r\nHost: "+srv.Listener.Addr().String()+
+		"\r\nConnection: Upgrade\r\nUpgrade: websocket\r\nSec-WebSocket-Version: 13"+
+		"\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n")
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake: status %d", resp.StatusCode)
+	}
+
+	overlay.Played(phonical.PlayEvent{Letter: 'a'})
+	if err := overlay.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// Letters after closing go nowhere rather than blocking
+	for i := 0; i < 100; i++ {
+		overlay.Played(phonical.PlayEvent{Letter: 'b'})
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		t.Errorf("window still connected after Close: %v", err)
+	}
+}
+
+func TestOverlayScaleSliderMatchesSettings(t *testing.T) {
+	overlay := phonical.NewOverlay(phonical.OverlaySettings{}, func(phonical.OverlaySettings) error { return nil })
+	srv := httptest.NewServer(overlay)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	m := regexp.MustCompile(`<input id="scale" type="range" min="([^"]+)" max="([^"]+)"`).FindSubmatch(page)
+	if m == nil {
+		t.Fatal("launcher page has no scale slider")
+	}
+	token := regexp.MustCompile(`<meta name="token" content="([0-9a-f]+)">`).FindSubmatch(page)[1]
+
+	post := func(scale float64) int {
+		body := fmt.Sprintf(`{"scale":%g}`, scale)
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/settings", strings.NewReader(body))
+		req.Header.Set("X-Phonical-Token", string(token))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	// The slider reaches every scale the settings accept, and no further
+	lo, _ := strconv.ParseFloat(string(m[1]), 64)
+	hi, _ := strconv.ParseFloat(string(m[2]), 64)
+	for _, tc := range []struct {
+		scale float64
+		ok    bool
+	}{
+		{lo, true}, {hi, true}, {lo - 0.01, false}, {hi + 0.01, false},
+	} {
+		if got := post(tc.scale) == http.StatusNoContent; got != tc.ok {
+			t.Errorf("scale %g accepted %v, want %v (slider %g–%g)", tc.scale, got, tc.ok, lo, hi)
+		}
+	}
+}
+
+// overlayToken reads the settings token from the overlay's launcher page.
+func overlayToken(t *testing.T, srv *httptest.Server) string {
+	t.Helper()
+	resp, err := http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	m := regexp.MustCompile(`<meta name="token" content="([0-9a-f]+)">`).FindSubmatch(page)
+	if m == nil {
+		t.Fatal("launcher page has no token")
+	}
+	return string(m[1])
+}
+
+func TestOverlayLook(t *testing.T) {
+	font := filepath.Join(t.TempDir(), "Dyslexic.otf")
+	if err := os.WriteFile(font, []byte("font data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	overlay := phonical.NewOverlay(phonical.OverlaySettings{Font: font}, nil)
+	if got := overlay.Settings(); got.Theme != phonical.OverlayDark || got.Font != font || got.LetterSize != 75 {
+		t.Errorf("settings %+v, want the dark theme, the font file and letters at 75%%", got)
+	}
+	srv := httptest.NewServer(overlay)
+	defer srv.Close()
+	token := overlayToken(t, srv)
+
+	// The font file is served for the window to load
+	resp, err := http.Get(srv.URL + "/font")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(data) != "font data" {
+		t.Errorf("/font served %q", data)
+	}
+
+	post := func(body string) int {
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/settings", strings.NewReader(body))
+		req.Header.Set("X-Phonical-Token", token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	for _, tc := range []struct {
+		body   string
+		status int
+	}{
+		{`{"theme":"neon"}`, http.StatusBadRequest},
+		{`{"font":"comic"}`, http.StatusBadRequest},
+		{`{"letterSize":10}`, http.StatusBadRequest},
+		// Only the settings file may name a font file
+		{`{"font":"/etc/secret.ttf"}`, http.StatusForbidden},
+		{`{"theme":"high-contrast","font":"opendyslexic","letterSize":90}`, http.StatusNoContent},
+	} {
+		if got := post(tc.body); got != tc.status {
+			t.Errorf("posting %s: status %d, want %d", tc.body, got, tc.status)
+		}
+	}
+	got := overlay.Settings()
+	if got.Theme != phonical.OverlayHighContrast || got.Font != phonical.FontOpenDyslexic || got.LetterSize != 90 {
+		t.Errorf("settings %+v after choosing high contrast OpenDyslexic at 90%%", got)
+	}
+	if resp, err := http.Get(srv.URL + "/font"); err != nil {
+		t.Fatal(err)
+	} else if resp.Body.Close(); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("/font without a font file: status %d", resp.StatusCode)
+	}
+}
+
+// overlayWindow opens the overlay's feed as its window does.
+func overlayWindow(t *testing.T, srv *httptest.Server) *bufio.Reader {
+	t.Helper()
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	io.WriteString(conn, "GET /overlay HTTP/1.1\r\nHost: "+srv.Listener.Addr().String()+
+		"\r\nConnection: Upgrade\r\nUpgrade: websocket\r\nSec-WebSocket-Version: 13"+
+		"\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n")
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake: status %d", resp.StatusCode)
+	}
+	return r
+}
+
+// shown has the overlay play letter until its window is sent it, since
+// the window may not be listening yet, and returns what was sent.
+func shown(t *testing.T, overlay *phonical.Overlay, window *bufio.Reader, letter rune) map[string]any {
+	t.Helper()
+	sent := make(chan []byte, 1)
+	go func() {
+		var header [4]byte
+		if _, err := io.ReadFull(window, header[:2]); err != nil {
+			sent <- nil
+			return
+		}
+		n := int(header[1] & 0x7F)
+		if n == 126 {
+			io.ReadFull(window, header[2:4])
+			n = int(header[2])<<8 | int(header[3])
+		}
+		data := make([]byte, n)
+		if _, err := io.ReadFull(window, data); err != nil {
+			data = nil
+		}
+		sent <- data
+	}()
+	for {
+		overlay.Played(phonical.PlayEvent{Letter: letter})
+		select {
+		case data := <-sent:
+			var msg map[string]any
+			if err := json.Unmarshal(data, &msg); err != nil {
+				t.Fatalf("window was sent %q: %v", data, err)
+			}
+			return msg
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestOverlayStrokes(t *testing.T) {
+	for _, strokes := range []bool{false, true} {
+		overlay := phonical.NewOverlay(phonical.OverlaySettings{Strokes: strokes}, nil)
+		srv := httptest.NewServer(overlay)
+		window := overlayWindow(t, srv)
+
+		// i is written as a downstroke, then its dot
+		msg := shown(t, overlay, window, 'i')
+		var want any
+		if strokes {
+			want = []any{[]any{"M 50 42 L 50 78", "M 50 27 L 50 27.5"}}
+		}
+		if msg["letter"] != "i" || !reflect.DeepEqual(msg["strokes"], want) {
+			t.Errorf("with strokes %v the window was sent %v", strokes, msg)
+		}
+		overlay.Close()
+		srv.Close()
+	}
+}
+
+func TestOverlayCase(t *testing.T) {
+	for _, tc := range []struct {
+		letterCase string
+		typed      rune
+		want       string
+	}{
+		{"", 'A', "a"},
+		{phonical.CaseUpper, 'a', "A"},
+		{phonical.CaseBoth, 'a', "Aa"},
+		{phonical.CaseBoth, 'B', "Bb"},
+		// Letters without capitals are shown once
+		{phonical.CaseBoth, 'ß', "ß"},
+	} {
+		overlay := phonical.NewOverlay(phonical.OverlaySettings{Case: tc.letterCase, Strokes: true}, nil)
+		srv := httptest.NewServer(overlay)
+		msg := shown(t, overlay, overlayWindow(t, srv), tc.typed)
+		if msg["letter"] != tc.want {
+			t.Errorf("case %q showed %q as %v, want %q", tc.letterCase, tc.typed, msg["letter"], tc.want)
+		}
+		// Each form shown is drawn
+		if strokes, _ := msg["strokes"].([]any); tc.typed != 'ß' && len(strokes) != len([]rune(tc.want)) {
+			t.Errorf("case %q drew %d forms of %q", tc.letterCase, len(strokes), tc.want)
+		}
+		overlay.Close()
+		srv.Close()
+	}
+
+	overlay := phonical.NewOverlay(phonical.OverlaySettings{}, nil)
+	defer overlay.Close()
+	if got := overlay.Settings().Case; got != phonical.CaseLower {
+		t.Errorf("default case %q, want lower", got)
+	}
+	srv := httptest.NewServer(overlay)
+	defer srv.Close()
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/settings", strings.NewReader(`{"case":"sideways"}`))
+	req.Header.Set("X-Phonical-Token", overlayToken(t, srv))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("unknown case: status %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,523 @@
+package phonical
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/BurntSushi/toml"
+	"github.com/faiface/beep"
+)
+
+// ManifestName is the file describing a sound pack inside its directory.
+const ManifestName = "pack.toml"
+
+// Cue names recognised in a pack's [cues] table.
+const (
+	CueSpace     = "space"
+	CueEnter     = "enter"
+	CueBackspace = "backspace"
+)
+
+// Pack is a set of sounds and the keys that trigger them.
+type Pack struct {
+	Name string
+	// Author, Version and License describe a shared pack. License is an
+	// SPDX identifier such as "CC-BY-4.0", needed to bundle the pack.
+	Author  string
+	Version string
+	License string
+	// Letters maps a lowercase letter to its phoneme file.
+	Letters map[rune]string
+	// Cues maps optional non-spoken cue names, such as CueSpace, to files.
+	// Missing cues fall back to built-in synthesized sounds.
+	Cues map[string]string
+	// Navigation maps navigation key names, such as "up" or "page up", to
+	// recordings. Keys without one are spoken by the announcer.
+	Navigation map[string]string
+	// Intros maps letters to an extended first-time clip ("a is for apple").
+	Intros map[rune]string
+	// Words maps letters to their association word, spoken after the
+	// phoneme when the pack has no intro clip.
+	Words map[rune]string
+	// Pictures maps letters to a PNG or JPEG picture of their word, printed
+	// on flashcards.
+	Pictures map[rune]string
+	// Variants holds alternative letter sounds keyed by variant name, used
+	// by curricula that teach a letter differently.
+	Variants map[string]map[rune]string
+	// Graphemes maps multi-letter graphemes, such as Welsh "ll", to their
+	// sound, played when the grapheme's last letter is typed. They are
+	// written in the [letters] table alongside single letters.
+	Graphemes map[string]string
+	// Syllables maps toned pinyin syllables, such as "ma3", to their
+	// recordings, for pinyin mode. Write ü as the letter itself or v.
+	Syllables map[string]string
+	// Limits overrides WithMaxSoundLength for particular files; zero plays
+	// the file in full.
+	Limits map[string]time.Duration
+	// Emoji are the sounds for emoji in emoji mode.
+	Emoji map[rune]EmojiSound
+	// Encouragement are clips played after praise, one picked at random.
+	Encouragement []string
+	// Months are when a theme is in season, for SeasonalTheme.
+	Months []time.Month
+	// Timed maps a part of the day, such as PartMorning, to clips keyed by
+	// kind, such as ClipGreeting, used with WithTimeOfDay.
+	Timed map[string]map[string]string
+
+	id   string
+	fsys fs.FS
+
+	mu  sync.Mutex
+	bad map[string]error
+}
+
+// manifest is the on-disk form of a pack:
+//
+//	name = "My voice"
+//	author = "Grandma"
+//	version = "1.0"
+//	license = "CC-BY-4.0"
+//	encouragement = ["yay.wav", "super.wav"]
+//	months = [10] # for themes
+//
+//	[letters]
+//	a = "a.wav"
+//	ll = "ll.wav"
+//
+//	[cues]
+//	space = "space.wav"
+//
+//	[navigation]
+//	up = "up.wav"
+//	"page up" = "page-up.wav"
+//
+//	[intros]
+//	a = "a-is-for-apple.wav"
+//
+//	[words]
+//	a = "alligator"
+//
+//	[pictures]
+//	a = "alligator.png"
+//
+//	[variants.jolly]
+//	x = "x-ks.wav"
+//
+//	[syllables]
+//	ma3 = "ma3.wav"
+//
+//	[limits]
+//	"a-long.wav" = 900 # milliseconds
+//
+//	[emoji."🐶"]
+//	sound = "woof.wav"
+//	say = "the dog says woof"
+//
+//	[timed.morning]
+//	greeting = "good-morning.wav"
+//	encouragement = "early-bird.wav"
+type manifest struct {
+	Name       string                       `toml:"name"`
+	Author     string                       `toml:"author,omitempty"`
+	Version    string                       `toml:"version,omitempty"`
+	License    string                       `toml:"license,omitempty"`
+	Letters    map[string]string            `toml:"letters,omitempty"`
+	Cues       map[string]string            `toml:"cues,omitempty"`
+	Navigation map[string]string            `toml:"navigation,omitempty"`
+	Intros     map[string]string            `toml:"intros,omitempty"`
+	Words      map[string]string            `toml:"words,omitempty"`
+	Pictures   map[string]string            `toml:"pictures,omitempty"`
+	Variants   map[string]map[string]string `toml:"variants,omitempty"`
+	Emoji      map[string]EmojiSound        `toml:"emoji,omitempty"`
+	Syllables  map[string]string            `toml:"syllables,omitempty"`
+	Limits     map[string]int               `toml:"limits,omitempty"`
+	Timed      map[string]map[string]string `toml:"timed,omitempty"`
+
+	Encouragement []string `toml:"encouragement,omitempty"`
+	Months        []int    `toml:"months,omitempty"`
+}
+
+// renameFiles replaces each recording the manifest names with rename's
+// choice, for a bundle whose recordings have been converted. Pictures are
+// left alone.
+func (m *manifest) renameFiles(rename func(string) string) {
+	tables := []map[string]string{m.Letters, m.Cues, m.Navigation, m.Intros, m.Syllables}
+	for _, table := range m.Variants {
+		tables = append(tables, table)
+	}
+	for _, table := range m.Timed {
+		tables = append(tables, table)
+	}
+	for _, table := range tables {
+		for key, file := range table {
+			table[key] = rename(file)
+		}
+	}
+	for i, file := range m.Encouragement {
+		m.Encouragement[i] = rename(file)
+	}
+	for key, sound := range m.Emoji {
+		if sound.Sound != "" {
+			sound.Sound = rename(sound.Sound)
+			m.Emoji[key] = sound
+		}
+	}
+	limits := make(map[string]int, len(m.Limits))
+	for file, ms := range m.Limits {
+		limits[rename(file)] = ms
+	}
+	m.Limits = limits
+}
+
+// DefaultSounds is the embedded sound group used by DefaultPack.
+const DefaultSounds = "en/british/phonics"
+
+// DefaultPack returns the British English sounds embedded in the binary.
+// Lite builds have no embedded sounds, so its Letters are empty.
+func DefaultPack() *Pack {
+	if !builtinSounds {
+		return &Pack{
+			Name:       "British English",
+			Letters:    map[rune]string{},
+			Cues:       map[string]string{},
+			Navigation: map[string]string{},
+			Intros:     map[rune]string{},
+			Words:      associationWords,
+			Variants:   map[string]map[rune]string{},
+			Emoji:      defaultEmoji,
+			id:         "embedded",
+			fsys:       soundFiles,
+		}
+	}
+	p, err := EmbeddedPack(DefaultSounds)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// EmbeddedPacks lists the sound groups compiled into the binary, named
+// "<lang>/<voice>/<mode>" after their directory under sounds/. Adding a
+// language or voice is a matter of dropping a directory with a pack.toml
+// into sounds/ and rebuilding.
+func EmbeddedPacks() []string {
+	manifests, _ := fs.Glob(soundFiles, "sounds/*/*/*/"+ManifestName)
+	names := make([]string, len(manifests))
+	for i, m := range manifests {
+		names[i] = strings.TrimSuffix(strings.TrimPrefix(m, "sounds/"), "/"+ManifestName)
+	}
+	return names
+}
+
+// EmbeddedPack loads the built-in sound group name, as listed by
+// EmbeddedPacks. Groups without their own words use the built-in English
+// association words.
+func EmbeddedPack(name string) (*Pack, error) {
+	sub, err := fs.Sub(soundFiles, "sounds/"+name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fs.Stat(sub, ManifestName); err != nil {
+		return nil, fmt.Errorf("no built-in sounds %q (have %s)", name, strings.Join(EmbeddedPacks(), ", "))
+	}
+	p, err := loadPackFS(sub, "embedded/"+name)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.Words) == 0 {
+		p.Words = associationWords
+	}
+	return p, nil
+}
+
+// Languages lists the languages of the embedded sound groups.
+func Languages() []string {
+	var langs []string
+	for _, name := range EmbeddedPacks() {
+		lang, _, _ := strings.Cut(name, "/")
+		if len(langs) == 0 || langs[len(langs)-1] != lang {
+			langs = append(langs, lang)
+		}
+	}
+	return langs
+}
+
+// LanguagePack loads the first embedded sound group for lang.
+func LanguagePack(lang string) (*Pack, error) {
+	for _, name := range EmbeddedPacks() {
+		if strings.HasPrefix(name, lang+"/") {
+			return EmbeddedPack(name)
+		}
+	}
+	return nil, fmt.Errorf("no built-in sounds for language %q", lang)
+}
+
+// HasBuiltinSounds reports whether this build embeds the default sounds.
+func HasBuiltinSounds() bool {
+	return builtinSounds
+}
+
+// LoadPack reads the pack in dir, described by its pack.toml manifest.
+func LoadPack(dir string) (*Pack, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	return loadPackFS(os.DirFS(abs), abs)
+}
+
+func loadPackFS(fsys fs.FS, id string) (*Pack, error) {
+	data, err := fs.ReadFile(fsys, ManifestName)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", ManifestName, err)
+	}
+	var m manifest
+	s, err := decodeSchema(filepath.Join(id, ManifestName), string(data), &m)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Pack{
+		Name:       m.Name,
+		Author:     m.Author,
+		Version:    m.Version,
+		License:    m.License,
+		Letters:    make(map[rune]string, len(m.Letters)),
+		Cues:       make(map[string]string, len(m.Cues)),
+		Navigation: make(map[string]string, len(m.Navigation)),
+		Intros:     make(map[rune]string, len(m.Intros)),
+		Words:      make(map[rune]string, len(m.Words)),
+		Pictures:   make(map[rune]string, len(m.Pictures)),
+		Variants:   make(map[string]map[rune]string, len(m.Variants)),
+		Emoji:      make(map[rune]EmojiSound, len(defaultEmoji)+len(m.Emoji)),
+		id:         id,
+		fsys:       fsys,
+	}
+	if p.Name == "" {
+		p.Name = filepath.Base(id)
+	}
+	letters, graphemes := splitGraphemes(m.Letters)
+	p.Graphemes = graphemes
+	runeTable(s, toml.Key{"letters"}, letters, p.Letters)
+	runeTable(s, toml.Key{"intros"}, m.Intros, p.Intros)
+	runeTable(s, toml.Key{"words"}, m.Words, p.Words)
+	runeTable(s, toml.Key{"pictures"}, m.Pictures, p.Pictures)
+	for name, entries := range m.Variants {
+		p.Variants[name] = make(map[rune]string, len(entries))
+		runeTable(s, toml.Key{"variants", name}, entries, p.Variants[name])
+	}
+	p.Syllables = make(map[string]string, len(m.Syllables))
+	for key, file := range m.Syllables {
+		if !validSyllable(key) {
+			s.errorf(toml.Key{"syllables", key}, "syllables: key %q must be pinyin letters and a tone from 1 to 5", key)
+			continue
+		}
+		p.Syllables[strings.ReplaceAll(key, "v", "ü")] = file
+	}
+	p.Limits = make(map[string]time.Duration, len(m.Limits))
+	for file, ms := range m.Limits {
+		if ms < 0 {
+			s.errorf(toml.Key{"limits", file}, "limits: %s must be 0 or more milliseconds", file)
+			continue
+		}
+		p.Limits[file] = time.Duration(ms) * time.Millisecond
+	}
+	for r, sound := range defaultEmoji {
+		p.Emoji[r] = sound
+	}
+	for key, sound := range m.Emoji {
+		emoji := strings.TrimSuffix(key, string(variationSelector))
+		r, size := utf8.DecodeRuneInString(emoji)
+		if size != len(emoji) {
+			s.errorf(toml.Key{"emoji", key}, "emoji: key %q must be a single emoji", key)
+			continue
+		}
+		if sound.Sound == "" && sound.Say == "" {
+			s.errorf(toml.Key{"emoji", key}, "emoji %s: set sound, say or both", key)
+		}
+		p.Emoji[r] = sound
+	}
+	for name, file := range m.Cues {
+		if name != CueSpace && name != CueEnter && name != CueBackspace {
+			s.errorf(toml.Key{"cues", name}, "unknown cue %q (want space, enter or backspace)", name)
+		}
+		p.Cues[name] = file
+	}
+	for name, file := range m.Navigation {
+		if !isNavigationName(name) {
+			s.errorf(toml.Key{"navigation", name}, "unknown navigation key %q", name)
+		}
+		p.Navigation[name] = file
+	}
+	p.Encouragement = m.Encouragement
+	for _, month := range m.Months {
+		if month < 1 || month > 12 {
+			s.errorf(toml.Key{"months"}, "months: %d is not a month from 1 to 12", month)
+			continue
+		}
+		p.Months = append(p.Months, time.Month(month))
+	}
+	p.Timed = make(map[string]map[string]string, len(m.Timed))
+	for part, clips := range m.Timed {
+		p.Timed[part] = make(map[string]string, len(clips))
+		for kind, file := range clips {
+			if err := checkTimed(part, kind); err != nil {
+				s.errorf(toml.Key{"timed", part, kind}, "timed: %v", err)
+				continue
+			}
+			p.Timed[part][kind] = file
+		}
+	}
+	if err := s.err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// runeTable copies a manifest table keyed by single characters into dest,
+// lowercasing the keys as typed letters are looked up lowercased. A
+// lowercase key wins over the same letter in capitals.
+func runeTable(s *schema, table toml.Key, entries map[string]string, dest map[rune]string) {
+	for key, value := range entries {
+		r, size := utf8.DecodeRuneInString(key)
+		if size != len(key) {
+			s.errorf(append(table[:len(table):len(table)], key), "%s: key %q must be a single character", table, key)
+			continue
+		}
+		lower := unicode.ToLower(r)
+		if _, ok := entries[string(lower)]; ok && lower != r {
+			continue
+		}
+		dest[lower] = value
+	}
+}
+
+// load decodes a file from the pack, caching the result.
+// A file that fails to load is remembered as bad so it isn't retried on
+// every key press.
+func (p *Pack) load(name string) (*beep.Buffer, error) {
+	return p.loadWith(name, decoding{})
+}
+
+// loadWith is like load but decodes as dec says, for an engine's sounds.
+func (p *Pack) loadWith(name string, dec decoding) (*beep.Buffer, error) {
+	p.mu.Lock()
+	err := p.bad[name]
+	p.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	buffer, _, err := loadSound(p.fsys, p.id+"/"+name, name, dec)
+	if err != nil {
+		p.mu.Lock()
+		if p.bad == nil {
+			p.bad = make(map[string]error)
+		}
+		p.bad[name] = err
+		p.mu.Unlock()
+	}
+	return buffer, err
+}
+
+// BadSounds returns the files that have failed to load so far, sorted.
+func (p *Pack) BadSounds() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	names := make([]string, 0, len(p.bad))
+	for name := range p.bad {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SoundProblem is a pack entry whose file could not be loaded.
+type SoundProblem struct {
+	// Entry names the manifest entry, such as "letters.a".
+	Entry string
+	File  string
+	Err   error
+}
+
+// Validate loads every file the pack refers to and reports the ones that
+// are missing or can't be decoded.
+func (p *Pack) Validate() []SoundProblem {
+	var problems []SoundProblem
+	for entry, file := range p.entries() {
+		if _, err := p.load(file); err != nil {
+			problems = append(problems, SoundProblem{Entry: entry, File: file, Err: err})
+		}
+	}
+	sort.Slice(problems, func(i, j int) bool { return problems[i].Entry < problems[j].Entry })
+	return problems
+}
+
+// entries maps each manifest entry, such as "letters.a", to its file.
+func (p *Pack) entries() map[string]string {
+	entries := map[string]string{}
+	for r, file := range p.Letters {
+		entries["letters."+string(r)] = file
+	}
+	for g, file := range p.Graphemes {
+		entries["letters."+g] = file
+	}
+	for s, file := range p.Syllables {
+		entries["syllables."+s] = file
+	}
+	for r, file := range p.Intros {
+		entries["intros."+string(r)] = file
+	}
+	for name, table := range p.Variants {
+		for r, file := range table {
+			entries["variants."+name+"."+string(r)] = file
+		}
+	}
+	for name, file := range p.Cues {
+		entries["cues."+name] = file
+	}
+	for name, file := range p.Navigation {
+		entries["navigation."+name] = file
+	}
+	for part, clips := range p.Timed {
+		for kind, file := range clips {
+			entries["timed."+part+"."+kind] = file
+		}
+	}
+	for i, file := range p.Encouragement {
+		entries[fmt.Sprintf("encouragement.%d", i+1)] = file
+	}
+	for r, sound := range p.Emoji {
+		if sound.Sound != "" {
+			entries["emoji."+string(r)] = sound.Sound
+		}
+	}
+	return entries
+}
+
+// letterSound returns the file for letter, preferring the named variant.
+// Accented letters the pack has no recording for use their plain letter.
+func (p *Pack) letterSound(letter rune, variant string) (string, bool) {
+	if file, ok := p.Variants[variant][letter]; ok {
+		return file, true
+	}
+	if file, ok := p.Letters[letter]; ok {
+		return file, true
+	}
+	if base, ok := baseLetters[letter]; ok {
+		return p.letterSound(base, variant)
+	}
+	if base, ok := letterForm(letter); ok {
+		return p.letterSound(base, variant)
+	}
+	return "", false
+}
@@ -0,0 +1,149 @@
+package phonical_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestPackLettersLowercased(t *testing.T) {
+	dir := t.TempDir()
+	manifest := `name = "Capitals"
+
+[letters]
+A = "a.wav"
+C = "c-capital.wav"
+c = "c.wav"
+`
+	for _, name := range []string{"a.wav", "c.wav", "c-capital.wav"} {
+		data, err := os.ReadFile(filepath.Join("sounds", phonical.DefaultSounds, name[:1]+".wav"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, phonical.ManifestName), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pack, err := phonical.LoadPack(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for letter, want := range map[rune]string{'a': "a.wav", 'c': "c.wav"} {
+		if got := pack.Letters[letter]; got != want {
+			t.Errorf("letter %c plays %q, want %q", letter, got, want)
+		}
+	}
+	if _, ok := pack.Letters['A']; ok {
+		t.Error("capital A kept as a key of its own")
+	}
+}
+
+func TestBadSoundPlaysFallback(t *testing.T) {
+	dir := t.TempDir()
+	data, err := os.ReadFile(filepath.Join("sounds", phonical.DefaultSounds, "a.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{
+		"a.wav":               data,
+		"b.wav":               []byte("not a wave file"),
+		phonical.ManifestName: []byte("name = \"Broken\"\n\n[letters]\na = \"a.wav\"\nb = \"b.wav\"\nc = \"missing.wav\"\n"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pack, err := phonical.LoadPack(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := phonicaltest.New(t, phonical.WithPack(pack))
+	// The broken letter still makes a sound rather than going quiet
+	h.Type("abb")
+	h.Expect("a.wav", "b.wav", "b.wav")
+	// Preloading has already tried the letter nobody typed
+	if got := h.Engine.Status().BadSounds; len(got) != 2 || got[0] != "b.wav" || got[1] != "missing.wav" {
+		t.Errorf("bad sounds %q, want [b.wav missing.wav]", got)
+	}
+
+	problems := pack.Validate()
+	var entries []string
+	for _, p := range problems {
+		entries = append(entries, p.Entry+"="+p.File)
+	}
+	if len(entries) != 2 || entries[0] != "letters.b=b.wav" || entries[1] != "letters.c=missing.wav" {
+		t.Errorf("validate found %q, want [letters.b=b.wav letters.c=missing.wav]", entries)
+	}
+}
+
+func TestEmbeddedPacks(t *testing.T) {
+	if !phonical.HasBuiltinSounds() {
+		t.Skip("lite build")
+	}
+	if got := phonical.EmbeddedPacks(); !slices.Contains(got, phonical.DefaultSounds) {
+		t.Errorf("embedded packs %q lack %q", got, phonical.DefaultSounds)
+	}
+	if got := phonical.Languages(); !slices.Equal(got, []string{"en"}) {
+		t.Errorf("languages %q, want [en]", got)
+	}
+
+	pack, err := phonical.LanguagePack("en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pack.Letters) != 26 || len(pack.Words) == 0 {
+		t.Errorf("English pack has %d letters and %d words", len(pack.Letters), len(pack.Words))
+	}
+	h := phonicaltest.New(t, phonical.WithPack(pack))
+	h.Type("z")
+	h.Expect("z.wav")
+
+	if _, err := phonical.LanguagePack("xx"); err == nil {
+		t.Error("loaded sounds for a language that isn't built in")
+	}
+	if _, err := phonical.EmbeddedPack("en/british/opera"); err == nil {
+		t.Error("loaded a sound group that isn't built in")
+	}
+}
+
+func TestSoundErrors(t *testing.T) {
+	dir := writePack(t, "name = 'Broken'\n[letters]\na = 'a.wav'\nb = 'b.ogg'\nc = 'c.wav'\nd = 'd.wav'\n",
+		map[string][]byte{"b.ogg": []byte("OggS"), "c.wav": []byte("not a wav at all"), "d.wav": []byte("private")})
+	if err := os.Chmod(filepath.Join(dir, "d.wav"), 0); err != nil {
+		t.Fatal(err)
+	}
+	pack, err := phonical.LoadPack(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]error{
+		"letters.a": phonical.ErrSoundMissing,
+		"letters.b": phonical.ErrUnsupportedFormat,
+		"letters.c": phonical.ErrUnsupportedFormat,
+		"letters.d": phonical.ErrPermissionDenied,
+	}
+	if os.Geteuid() == 0 {
+		// Root reads the file anyway, and finds it isn't a recording
+		want["letters.d"] = phonical.ErrUnsupportedFormat
+	}
+	problems := pack.Validate()
+	if len(problems) != len(want) {
+		t.Fatalf("problems %+v, want one for each letter", problems)
+	}
+	for _, p := range problems {
+		if !errors.Is(p.Err, want[p.Entry]) {
+			t.Errorf("%s: %v, want %v", p.Entry, p.Err, want[p.Entry])
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package phonical
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// minimalPairs are letters whose sounds children commonly confuse.
+var minimalPairs = [][2]rune{
+	{'b', 'p'},
+	{'d', 't'},
+	{'g', 'k'},
+	{'m', 'n'},
+	{'f', 'v'},
+	{'s', 'z'},
+	{'e', 'i'},
+	{'o', 'u'},
+}
+
+// answerTimeout is how long a listening prompt waits for a key.
+const answerTimeout = 10 * time.Second
+
+// MinimalPairs returns a listening game of the given number of rounds. Each
+// round plays both sounds of a similar pair (b/p, m/n), then one of them,
+// and asks the child to press the letter they heard. Results and confusions
+// are recorded in the engine's stats.
+func MinimalPairs(rounds int) Activity {
+	return ActivityFunc(func(t *Tutor) error {
+		var pairs [][2]rune
+		for _, pair := range minimalPairs {
+			_, ok1 := t.Pack().Letters[pair[0]]
+			_, ok2 := t.Pack().Letters[pair[1]]
+			if ok1 && ok2 {
+				pairs = append(pairs, pair)
+			}
+		}
+		if len(pairs) == 0 {
+			return fmt.Errorf("sound pack has no minimal pairs")
+		}
+
+		correct := 0
+		t.Say("Listen carefully, then press the letter you hear.")
+		for round := 0; round < rounds; round++ {
+			pair := pairs[rand.Intn(len(pairs))]
+			heard := pair[rand.Intn(2)]
+			name := string(pair[0]) + "/" + string(pair[1])
+
+			t.Play(pair[0])
+			t.Pause(300 * time.Millisecond)
+			t.Play(pair[1])
+			t.Pause(700 * time.Millisecond)
+			t.Say("Which one is this?")
+			t.Play(heard)
+
+			pressed, ok := t.NextKey(answerTimeout)
+			if t.Stopped() {
+				return ErrStopped
+			}
+			if !ok {
+				t.Say(fmt.Sprintf("That was %c.", heard))
+				continue
+			}
+			if stats := t.Stats(); stats != nil {
+				stats.RecordPair(name, heard, pressed)
+			}
+			if pressed == heard {
+				correct++
+				t.Say("Yes!")
+			} else {
+				t.Say(fmt.Sprintf("That was %c.", heard))
+				t.Play(heard)
+			}
+			t.Pause(500 * time.Millisecond)
+		}
+
+		t.Say(fmt.Sprintf("You got %s out of %s.", numberWord(correct), numberWord(rounds)))
+		return nil
+	})
+}
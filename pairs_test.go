@@ -0,0 +1,48 @@
+package phonical_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestMinimalPairs(t *testing.T) {
+	stats, err := phonical.OpenStats(filepath.Join(t.TempDir(), "stats.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := phonicaltest.New(t, phonical.WithStats(stats))
+	done := runActivity(h, phonical.MinimalPairs(2))
+
+	// Each round plays the pair, asks, and plays the one to pick out
+	played := waitPlayed(t, h, 5)
+	if played[3] != "say:Which one is this?" {
+		t.Fatalf("played %q, want the pair then the question", played)
+	}
+	answer(h, letterOf(played[4]))
+	waitPlayed(t, h, 6)
+
+	played = waitPlayed(t, h, 10)
+	pair := played[6:8]
+	heard := letterOf(played[9])
+	wrong := letterOf(pair[0])
+	if wrong == heard {
+		wrong = letterOf(pair[1])
+	}
+	answer(h, wrong)
+	waitPlayed(t, h, 13)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	h.Expect(append(played, "say:That was "+heard+".", heard+".wav", "say:You got one out of two.")...)
+	if got := h.Sink.Played()[5]; got != "say:Yes!" {
+		t.Errorf("right answer met with %q", got)
+	}
+
+	st := stats.Snapshot()
+	if got := st.Confusions[heard+">"+wrong]; got != 1 {
+		t.Errorf("confusion %s>%s counted %d times, want 1", heard, wrong, got)
+	}
+}
@@ -0,0 +1,40 @@
+package phonical
+
+import "runtime"
+
+// keyColumns places each key of the letter rows, measured in key widths
+// from the left edge of the Q key.
+var keyColumns = map[uint16]float64{}
+
+// rowCodes maps the characters of the letter rows, on a US layout, to
+// their keycodes.
+var rowCodes = map[rune]uint16{}
+
+func init() {
+	for _, row := range keyRows[1:] {
+		for i, c := range row.chars {
+			keyColumns[row.first+uint16(i)] = row.offset + float64(i)
+			rowCodes[c] = row.first + uint16(i)
+		}
+	}
+}
+
+// keyPan returns the stereo position for a key, from -1 (left) to 1
+// (right), and false for keys not in the letter rows. The key is found by
+// its keycode, or by its platform keycode when the input gave no keycode.
+func keyPan(key Key) (float64, bool) {
+	code := key.Code
+	if code == 0 && key.Rawcode != 0 {
+		code = rowCodes[rawLetters[runtime.GOOS][key.Rawcode]]
+	}
+	col, ok := keyColumns[code]
+	if !ok {
+		return 0, false
+	}
+	// q sits at 0 and p at 9
+	pan := col/9*2 - 1
+	if pan > 1 {
+		pan = 1
+	}
+	return pan, true
+}
@@ -0,0 +1,194 @@
+package phonical
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/faiface/beep"
+)
+
+// pcmCacheVersion is part of every cache file name. Bump it when decoding
+// or the file layout changes, and older files are cleared out.
+const pcmCacheVersion = "v1"
+
+// pcmMagic starts every cache file.
+const pcmMagic = "PHONICALPCM\n"
+
+// WithSoundCache keeps decoded sounds in dir, so later starts read them
+// back instead of decoding every MP3 and WAV again: on slow machines with
+// large packs this cuts start-up from seconds to milliseconds. Files are
+// named by a hash of the recording, so edited recordings are decoded
+// afresh.
+func WithSoundCache(dir string) Option {
+	return func(e *Engine) {
+		e.soundCache = dir
+	}
+}
+
+// DefaultSoundCacheDir returns the user's cache directory for decoded
+// sounds.
+func DefaultSoundCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "phonical", "sounds"), nil
+}
+
+// pcmCache is a directory of decoded sounds.
+type pcmCache struct {
+	dir string
+}
+
+// openPCMCache creates dir if needed and removes files left by other cache
+// versions.
+func openPCMCache(dir string) (*pcmCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), pcmCacheVersion+"-") {
+			os.Remove(filepath.Join(dir, entry.Name()))
+		}
+	}
+	return &pcmCache{dir: dir}, nil
+}
+
+func (c *pcmCache) path(sum [sha256.Size]byte) string {
+	return filepath.Join(c.dir, pcmCacheVersion+"-"+hex.EncodeToString(sum[:])+".pcm")
+}
+
+// load returns the cached decoding of the recording with SHA-256 sum, or
+// nil if there is none.
+func (c *pcmCache) load(sum [sha256.Size]byte) *beep.Buffer {
+	f, err := os.Open(c.path(sum))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil
+	}
+	buffer, err := readPCM(bufio.NewReader(f), info.Size())
+	if err != nil {
+		return nil
+	}
+	return buffer
+}
+
+// store saves buffer as the decoding of the recording with SHA-256 sum. It
+// writes to a temporary file first so a crash never leaves a truncated
+// entry.
+func (c *pcmCache) store(sum [sha256.Size]byte, buffer *beep.Buffer) error {
+	path := c.path(sum)
+	tmp, err := os.CreateTemp(c.dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(tmp)
+	err = writePCM(w, buffer)
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// pcmHeader follows pcmMagic in a cache file.
+type pcmHeader struct {
+	SampleRate  uint32
+	NumChannels uint16
+	Precision   uint16
+	Samples     uint32
+}
+
+// writePCM writes buffer as a header and float32 stereo samples.
+func writePCM(w io.Writer, buffer *beep.Buffer) error {
+	format := buffer.Format()
+	header := pcmHeader{
+		SampleRate:  uint32(format.SampleRate),
+		NumChannels: uint16(format.NumChannels),
+		Precision:   uint16(format.Precision),
+		Samples:     uint32(buffer.Len()),
+	}
+	if _, err := io.WriteString(w, pcmMagic); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+	var sample [8]byte
+	for _, s := range readAll(buffer.Streamer(0, buffer.Len())) {
+		binary.LittleEndian.PutUint32(sample[:4], math.Float32bits(float32(s[0])))
+		binary.LittleEndian.PutUint32(sample[4:], math.Float32bits(float32(s[1])))
+		if _, err := w.Write(sample[:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var errBadPCM = errors.New("not a phonical sound cache file")
+
+// readPCM reads a file of size bytes written by writePCM.
+func readPCM(r io.Reader, size int64) (*beep.Buffer, error) {
+	magic := make([]byte, len(pcmMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != pcmMagic {
+		return nil, errBadPCM
+	}
+	var header pcmHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+	// A damaged header mustn't ask for more than the file holds
+	if 8*int64(header.Samples) > size-int64(len(pcmMagic)+binary.Size(header)) {
+		return nil, errBadPCM
+	}
+	data := make([]byte, 8*int(header.Samples))
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	samples := make([][2]float64, header.Samples)
+	for i := range samples {
+		samples[i][0] = float64(math.Float32frombits(binary.LittleEndian.Uint32(data[8*i:])))
+		samples[i][1] = float64(math.Float32frombits(binary.LittleEndian.Uint32(data[8*i+4:])))
+	}
+	buffer := beep.NewBuffer(beep.Format{
+		SampleRate:  beep.SampleRate(header.SampleRate),
+		NumChannels: int(header.NumChannels),
+		Precision:   int(header.Precision),
+	})
+	buffer.Append(samplesStreamer(samples))
+	return buffer, nil
+}
+
+// samplesStreamer streams samples once.
+func samplesStreamer(samples [][2]float64) beep.Streamer {
+	return beep.StreamerFunc(func(out [][2]float64) (int, bool) {
+		if len(samples) == 0 {
+			return 0, false
+		}
+		n := copy(out, samples)
+		samples = samples[n:]
+		return n, true
+	})
+}
@@ -0,0 +1,138 @@
+package phonical_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/wav"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+var testFormat = beep.Format{SampleRate: 44100, NumChannels: 2, Precision: 2}
+
+// writeWAV records n samples at v to path.
+func writeWAV(t *testing.T, path string, n int, v float64) []byte {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wav.Encode(f, constant(n, v), testFormat); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// cachePath is where the sound cache keeps the decoding of data.
+func cachePath(dir string, data []byte) string {
+	sum := sha256.Sum256(data)
+	return filepath.Join(dir, "v1-"+hex.EncodeToString(sum[:])+".pcm")
+}
+
+// writeCached writes a cache entry of n silent samples.
+func writeCached(t *testing.T, path string, n int) {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.WriteString("PHONICALPCM\n")
+	binary.Write(&buf, binary.LittleEndian, struct {
+		SampleRate  uint32
+		NumChannels uint16
+		Precision   uint16
+		Samples     uint32
+	}{44100, 2, 2, uint32(n)})
+	for i := 0; i < 2*n; i++ {
+		binary.Write(&buf, binary.LittleEndian, math.Float32bits(0))
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSoundCache(t *testing.T) {
+	dir, cache := t.TempDir(), t.TempDir()
+	// Recordings decoded by an earlier run are shared from memory, so each
+	// run records its own
+	v := float64(time.Now().UnixNano()%1e6) / 4e6
+	a := writeWAV(t, filepath.Join(dir, "a.wav"), 100, v)
+	b := writeWAV(t, filepath.Join(dir, "b.wav"), 200, v+0.5)
+	manifest := "name = \"Cached\"\n\n[letters]\na = \"a.wav\"\nb = \"b.wav\"\n"
+	if err := os.WriteFile(filepath.Join(dir, phonical.ManifestName), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pack, err := phonical.LoadPack(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a is already cached, shorter than its recording so the test can tell,
+	// and a file from an older cache version is cleared out
+	writeCached(t, cachePath(cache, a), 10)
+	stale := filepath.Join(cache, "v0-stale.pcm")
+	writeCached(t, stale, 10)
+
+	h := phonicaltest.New(t, phonical.WithPack(pack), phonical.WithSoundCache(cache))
+	h.Type("ab")
+	h.Expect("a.wav", "b.wav")
+	played := h.Sink.Playbacks()
+	if n := samples(played[0].Streamer); n != 10 {
+		t.Errorf("a played %d samples, want the 10 cached", n)
+	}
+	if n := samples(played[1].Streamer); n != 200 {
+		t.Errorf("b played %d samples, want 200", n)
+	}
+
+	if _, err := os.Stat(cachePath(cache, b)); err != nil {
+		t.Errorf("b not cached: %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("stale cache file kept: %v", err)
+	}
+}
+
+func TestSoundCacheCorrupt(t *testing.T) {
+	dir, cache := t.TempDir(), t.TempDir()
+	v := float64(time.Now().UnixNano()%1e6)/4e6 + 0.1
+	a := writeWAV(t, filepath.Join(dir, "a.wav"), 100, v)
+	manifest := "name = \"Cached\"\n\n[letters]\na = \"a.wav\"\n"
+	if err := os.WriteFile(filepath.Join(dir, phonical.ManifestName), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pack, err := phonical.LoadPack(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A cache entry holding 10 samples whose header claims four billion
+	path := cachePath(cache, a)
+	writeCached(t, path, 10)
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteAt([]byte{0xff, 0xff, 0xff, 0xff}, int64(len("PHONICALPCM\n")+8)); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	h := phonicaltest.New(t, phonical.WithPack(pack), phonical.WithSoundCache(cache))
+	h.Type("a")
+	h.Expect("a.wav")
+	if n := samples(h.Sink.Playbacks()[0].Streamer); n != 100 {
+		t.Errorf("a played %d samples, want the 100 recorded", n)
+	}
+}
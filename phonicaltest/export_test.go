@@ -0,0 +1,4 @@
+package phonicaltest
+
+// LitePack loads the stand-in for the built-in sounds.
+var LitePack = litePack
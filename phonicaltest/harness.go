@@ -0,0 +1,253 @@
+package phonicaltest
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"phonical"
+)
+
+// expectTimeout is how long Expect waits, in real time, for sounds to
+// arrive.
+const expectTimeout = 2 * time.Second
+
+// Source is a phonical.KeySource fed by Send.
+type Source struct {
+	keys chan phonical.Key
+}
+
+// NewSource returns a source with nothing sent yet.
+func NewSource() *Source {
+	return &Source{keys: make(chan phonical.Key)}
+}
+
+// Keys returns the channel read by Engine.RunSource.
+func (s *Source) Keys() <-chan phonical.Key {
+	return s.keys
+}
+
+// Send delivers keys in order, blocking until each has been taken.
+func (s *Source) Send(keys ...phonical.Key) {
+	for _, key := range keys {
+		s.keys <- key
+	}
+}
+
+// Close ends the source.
+func (s *Source) Close() {
+	close(s.keys)
+}
+
+// Step is one action in a script: a key press, or a wait on the virtual
+// clock when Wait is set.
+type Step struct {
+	Key  phonical.Key
+	Wait time.Duration
+}
+
+// ParseScript reads a script of typed text with braced keys and waits:
+//
+//	cat{space}
+//	{left}{backspace}
+//	b{wait 50ms}b
+//	{code 0x2a}A
+//
+// Braces hold a key name accepted by phonical.KeyNamed, "wait" and a
+// duration, or "code" and a keycode for keys with no character, such as
+// Shift. Write {{ for a literal brace.
+func ParseScript(script string) ([]Step, error) {
+	var steps []Step
+	for script != "" {
+		if strings.HasPrefix(script, "{{") {
+			steps = append(steps, Step{Key: phonical.Key{Char: '{'}})
+			script = script[2:]
+			continue
+		}
+		if script[0] != '{' {
+			c, size := utf8.DecodeRuneInString(script)
+			steps = append(steps, Step{Key: charKey(c)})
+			script = script[size:]
+			continue
+		}
+
+		end := strings.IndexByte(script, '}')
+		if end < 0 {
+			return nil, fmt.Errorf("script: unclosed { in %q", script)
+		}
+		token := script[1:end]
+		script = script[end+1:]
+		step, err := parseToken(token)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+func parseToken(token string) (Step, error) {
+	verb, arg, _ := strings.Cut(token, " ")
+	switch verb {
+	case "wait":
+		d, err := time.ParseDuration(arg)
+		if err != nil {
+			return Step{}, fmt.Errorf("script: {%s}: %w", token, err)
+		}
+		return Step{Wait: d}, nil
+	case "code":
+		var code uint16
+		if _, err := fmt.Sscan(arg, &code); err != nil {
+			return Step{}, fmt.Errorf("script: {%s}: want a keycode", token)
+		}
+		return Step{Key: phonical.Key{Code: code}}, nil
+	}
+	key, ok := phonical.KeyNamed(token)
+	if !ok {
+		return Step{}, fmt.Errorf("script: unknown key {%s}", token)
+	}
+	return Step{Key: key}, nil
+}
+
+// charKey is the press that types c.
+func charKey(c rune) phonical.Key {
+	switch c {
+	case '\n':
+		key, _ := phonical.KeyNamed("enter")
+		return key
+	case ' ':
+		key, _ := phonical.KeyNamed("space")
+		return key
+	}
+	return phonical.Key{Char: c}
+}
+
+// Harness runs an engine on a recording sink and virtual clock, fed by a
+// script:
+//
+//	h := phonicaltest.New(t, phonical.WithMode(phonical.ModeBlend))
+//	h.Type("cat{space}")
+//	h.Expect("c.wav", "a.wav", "t.wav", // as typed
+//		"c.wav", "a.wav", "t.wav", "blend:cat", "say:cat")
+type Harness struct {
+	Engine *phonical.Engine
+	Sink   *Sink
+	Clock  *Clock
+	Source *Source
+
+	t    testing.TB
+	sent int64
+}
+
+// New starts an engine configured by opts, with sound going to a Sink,
+// time kept by a Clock and phrases recorded by the sink's announcer. The
+// engine is stopped when the test ends.
+func New(t testing.TB, opts ...phonical.Option) *Harness {
+	t.Helper()
+	h := &Harness{
+		Sink:   NewSink(),
+		Clock:  NewClock(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)),
+		Source: NewSource(),
+		t:      t,
+	}
+	if !phonical.HasBuiltinSounds() {
+		opts = append([]phonical.Option{phonical.WithPack(litePack(t))}, opts...)
+	}
+	opts = append([]phonical.Option{
+		phonical.WithAnnouncer(h.Sink.Announcer()),
+		phonical.WithDoNotDisturb(false),
+		phonical.WithOutput(testWriter{t}),
+	}, opts...)
+	opts = append(opts, phonical.WithAudioSink(h.Sink), phonical.WithClock(h.Clock))
+
+	engine, err := phonical.New(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Engine = engine
+	if err := engine.Start(); err != nil {
+		t.Fatal(err)
+	}
+	go engine.RunSource(context.Background(), h.Source)
+	t.Cleanup(engine.Stop)
+	return h
+}
+
+// litePack loads the stand-in for the built-in sounds, which lite builds
+// leave out, from this package's testdata.
+func litePack(t testing.TB) *phonical.Pack {
+	t.Helper()
+	_, file, _, _ := runtime.Caller(0)
+	p, err := phonical.LoadPack(filepath.Join(filepath.Dir(file), "testdata", "pack"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// Type runs script, returning once every key in it has been handled.
+func (h *Harness) Type(script string) {
+	h.t.Helper()
+	steps, err := ParseScript(script)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	for _, step := range steps {
+		if step.Wait > 0 {
+			h.Clock.Advance(step.Wait)
+			continue
+		}
+		h.Source.Send(step.Key)
+		h.sent++
+		h.waitHandled()
+	}
+}
+
+// waitHandled blocks until the engine has handled every key sent.
+func (h *Harness) waitHandled() {
+	h.t.Helper()
+	deadline := time.Now().Add(expectTimeout)
+	for h.Engine.Status().KeysHandled < h.sent {
+		if time.Now().After(deadline) {
+			h.t.Fatalf("engine handled %d of %d keys", h.Engine.Status().KeysHandled, h.sent)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// Expect checks that exactly names have played so far, in order. Sounds
+// are named as in Playback.Name; phrases as "say:" and their text. While
+// waiting it lets virtual time run on, so pauses between sounds pass
+// instantly.
+func (h *Harness) Expect(names ...string) {
+	h.t.Helper()
+	deadline := time.Now().Add(expectTimeout)
+	for time.Now().Before(deadline) {
+		if _, ok := h.Sink.WaitFor(len(names), 5*time.Millisecond); ok {
+			break
+		}
+		h.Clock.AdvanceToNext()
+	}
+	// Give stray extra sounds a moment to show up
+	time.Sleep(20 * time.Millisecond)
+	got := h.Sink.Played()
+	if !reflect.DeepEqual(got, names) && !(len(got) == 0 && len(names) == 0) {
+		h.t.Errorf("played %q, want %q", got, names)
+	}
+}
+
+// testWriter sends verbose engine output to the test log.
+type testWriter struct {
+	t testing.TB
+}
+
+func (w testWriter) Write(p []byte) (int, error) {
+	w.t.Log(strings.TrimRight(string(p), "\n"))
+	return len(p), nil
+}
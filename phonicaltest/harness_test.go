@@ -0,0 +1,66 @@
+package phonicaltest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"phonical"
+)
+
+func TestParseScript(t *testing.T) {
+	steps, err := ParseScript("ab{space}{{{wait 50ms}{left}{code 0x2a}é\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Step{
+		{Key: phonical.Key{Char: 'a'}},
+		{Key: phonical.Key{Char: 'b'}},
+		{Key: phonical.Key{Char: ' ', Code: phonical.KeySpace}},
+		{Key: phonical.Key{Char: '{'}},
+		{Wait: 50 * time.Millisecond},
+		{Key: phonical.Key{Code: phonical.KeyLeft}},
+		{Key: phonical.Key{Code: 0x2a}},
+		{Key: phonical.Key{Char: 'é'}},
+		{Key: phonical.Key{Char: '\n', Code: phonical.KeyEnter}},
+	}
+	if !reflect.DeepEqual(steps, want) {
+		t.Errorf("steps %+v, want %+v", steps, want)
+	}
+}
+
+func TestParseScriptErrors(t *testing.T) {
+	for script, want := range map[string]string{
+		"ab{space":       "unclosed {",
+		"{wait}":         "{wait}",
+		"{wait soon}":    "{wait soon}",
+		"{code}":         "want a keycode",
+		"{code shift}":   "want a keycode",
+		"{code 0x1ffff}": "want a keycode",
+		"{f13}":          "unknown key {f13}",
+		"{}":             "unknown key {}",
+	} {
+		if _, err := ParseScript(script); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseScript(%q) = %v, want an error with %q", script, err, want)
+		}
+	}
+}
+
+func TestParseToken(t *testing.T) {
+	for token, want := range map[string]Step{
+		"wait 1s":     {Wait: time.Second},
+		"code 42":     {Key: phonical.Key{Code: 42}},
+		"code 0x0e53": {Key: phonical.Key{Code: phonical.KeyDelete}},
+		"backspace":   {Key: phonical.Key{Code: phonical.KeyBackspace}},
+		"page up":     {Key: phonical.Key{Code: phonical.KeyPageUp}},
+		"f12":         {Key: phonical.Key{Code: phonical.KeyF12}},
+	} {
+		step, err := parseToken(token)
+		if err != nil {
+			t.Errorf("parseToken(%q): %v", token, err)
+		} else if step != want {
+			t.Errorf("parseToken(%q) = %+v, want %+v", token, step, want)
+		}
+	}
+}
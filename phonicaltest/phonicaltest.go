@@ -0,0 +1,271 @@
+// Package phonicaltest provides a silent audio sink and a virtual clock for
+// testing code built on the phonical engine without sound hardware:
+//
+//	sink := phonicaltest.NewSink()
+//	clock := phonicaltest.NewClock(time.Now())
+//	engine, _ := phonical.New(phonical.WithAudioSink(sink), phonical.WithClock(clock))
+//	engine.Start()
+//	engine.HandleKey(phonical.Key{Char: 'a'})
+//	names, _ := sink.WaitFor(1, time.Second) // ["a.wav"]
+package phonicaltest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+
+	"phonical"
+)
+
+// Sink is a phonical.AudioSink that records sounds instead of playing
+// them. Its zero value is not usable; call NewSink.
+type Sink struct {
+	// InitErr, when set, is returned by Init, as if no device were found.
+	InitErr error
+
+	mu          sync.Mutex
+	played      []phonical.Playback
+	interrupted []string
+	layers      int
+	inits       int
+	held        chan struct{}
+	changed     chan struct{}
+}
+
+// NewSink returns an empty sink.
+func NewSink() *Sink {
+	return &Sink{changed: make(chan struct{})}
+}
+
+// Init counts the call and returns InitErr.
+func (s *Sink) Init() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inits++
+	return s.InitErr
+}
+
+// Play records p. While the sink is held it blocks until Release or until
+// ctx is cancelled, in which case p is also recorded as interrupted.
+func (s *Sink) Play(ctx context.Context, p phonical.Playback) error {
+	s.mu.Lock()
+	s.played = append(s.played, p)
+	held := s.held
+	s.notify()
+	s.mu.Unlock()
+
+	if held == nil {
+		return nil
+	}
+	select {
+	case <-held:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		s.interrupted = append(s.interrupted, p.Name)
+		s.notify()
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Layer counts a layered sound such as a key click.
+func (s *Sink) Layer(beep.Streamer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.layers++
+	s.notify()
+}
+
+// Announcer returns an announcer that records each phrase as a playback
+// named "say:" followed by the text, so phrases and sounds can be checked
+// in one sequence.
+func (s *Sink) Announcer() phonical.Announcer {
+	return phonical.AnnouncerFunc(func(text string) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.played = append(s.played, phonical.Playback{Name: "say:" + text})
+		s.notify()
+		return nil
+	})
+}
+
+// Close does nothing.
+func (s *Sink) Close() {}
+
+// Hold makes later calls to Play block until Release, so tests can
+// inspect or interrupt a sound mid-playback.
+func (s *Sink) Hold() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.held == nil {
+		s.held = make(chan struct{})
+	}
+}
+
+// Release lets held sounds finish.
+func (s *Sink) Release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.held != nil {
+		close(s.held)
+		s.held = nil
+	}
+}
+
+// Played returns the names of the sounds played so far, in order.
+func (s *Sink) Played() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.names()
+}
+
+// Playbacks returns the sounds played so far, in order.
+func (s *Sink) Playbacks() []phonical.Playback {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]phonical.Playback(nil), s.played...)
+}
+
+// Interrupted returns the names of sounds cut off by cancellation.
+func (s *Sink) Interrupted() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.interrupted...)
+}
+
+// Layers returns how many layered sounds, such as key clicks, were mixed
+// in.
+func (s *Sink) Layers() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.layers
+}
+
+// Inits returns how many times Init was called.
+func (s *Sink) Inits() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.inits
+}
+
+// WaitFor waits up to timeout for at least n sounds to have played and
+// returns their names, reporting false on timeout.
+func (s *Sink) WaitFor(n int, timeout time.Duration) ([]string, bool) {
+	deadline := time.After(timeout)
+	for {
+		s.mu.Lock()
+		if len(s.played) >= n {
+			names := s.names()
+			s.mu.Unlock()
+			return names, true
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-deadline:
+			return s.Played(), false
+		}
+	}
+}
+
+// names lists the played sounds. The caller must hold s.mu.
+func (s *Sink) names() []string {
+	names := make([]string, len(s.played))
+	for i, p := range s.played {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// notify wakes WaitFor. The caller must hold s.mu.
+func (s *Sink) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// Clock is a phonical.Clock that only moves when Advance is called.
+type Clock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []timer
+}
+
+type timer struct {
+	at time.Time
+	c  chan time.Time
+}
+
+// NewClock returns a clock stopped at start.
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start}
+}
+
+// Now returns the clock's current time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel that receives the time once Advance moves the
+// clock d past now.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.timers = append(c.timers, timer{at: c.now.Add(d), c: ch})
+	return ch
+}
+
+// Advance moves the clock forward by d, firing any timers that fall due.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.at.After(c.now) {
+			pending = append(pending, t)
+			continue
+		}
+		t.c <- c.now
+	}
+	c.timers = pending
+}
+
+// AdvanceToNext moves the clock to the earliest pending timer and fires
+// it, reporting false if there was none.
+func (c *Clock) AdvanceToNext() bool {
+	c.mu.Lock()
+	if len(c.timers) == 0 {
+		c.mu.Unlock()
+		return false
+	}
+	next := c.timers[0].at
+	for _, t := range c.timers[1:] {
+		if t.at.Before(next) {
+			next = t.at
+		}
+	}
+	d := next.Sub(c.now)
+	c.mu.Unlock()
+	c.Advance(d)
+	return true
+}
+
+// Waiters returns how many After channels have yet to fire, so a test can
+// tell when the engine is blocked on the clock.
+func (c *Clock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
@@ -0,0 +1,181 @@
+package phonicaltest_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// start runs an engine on sink and clock, stopping it when the test ends.
+func start(t *testing.T, sink *phonicaltest.Sink, clock *phonicaltest.Clock, opts ...phonical.Option) *phonical.Engine {
+	t.Helper()
+	opts = append([]phonical.Option{
+		phonical.WithAudioSink(sink),
+		phonical.WithClock(clock),
+		phonical.WithAnnouncer(sink.Announcer()),
+		phonical.WithDoNotDisturb(false),
+	}, opts...)
+	if !phonical.HasBuiltinSounds() {
+		opts = append([]phonical.Option{phonical.WithPack(phonicaltest.LitePack(t))}, opts...)
+	}
+	engine, err := phonical.New(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(engine.Stop)
+	return engine
+}
+
+func typeText(engine *phonical.Engine, text string) {
+	for _, c := range text {
+		engine.HandleKey(phonical.Key{Char: c})
+	}
+}
+
+func TestPlaysInOrder(t *testing.T) {
+	sink := phonicaltest.NewSink()
+	engine := start(t, sink, phonicaltest.NewClock(time.Now()))
+
+	sink.Hold()
+	typeText(engine, "cat")
+	sink.Release()
+	want := []string{"c.wav", "a.wav", "t.wav"}
+	if got, _ := sink.WaitFor(3, time.Second); !reflect.DeepEqual(got, want) {
+		t.Errorf("played %q, want %q", got, want)
+	}
+}
+
+func TestCoalescesRepeats(t *testing.T) {
+	sink := phonicaltest.NewSink()
+	clock := phonicaltest.NewClock(time.Now())
+	engine := start(t, sink, clock, phonical.WithRepeatCoalescing(200*time.Millisecond, true))
+
+	// a plays, held, while b is pressed three times in quick succession
+	sink.Hold()
+	typeText(engine, "a")
+	sink.WaitFor(1, time.Second)
+	for i := 0; i < 3; i++ {
+		typeText(engine, "b")
+		clock.Advance(50 * time.Millisecond)
+	}
+	sink.Release()
+	want := []string{"a.wav", "b.wav", "say:b, three times"}
+	if got, _ := sink.WaitFor(3, time.Second); !reflect.DeepEqual(got, want) {
+		t.Errorf("played %q, want %q", got, want)
+	}
+}
+
+func TestCoalescingSkipsDroppedPresses(t *testing.T) {
+	sink := phonicaltest.NewSink()
+	engine := start(t, sink, phonicaltest.NewClock(time.Now()),
+		phonical.WithRepeatCoalescing(200*time.Millisecond, false),
+		phonical.WithQueueSize(1))
+
+	// b finds the queue full behind c and is dropped, so the next b is
+	// played rather than folded into it
+	sink.Hold()
+	typeText(engine, "a")
+	sink.WaitFor(1, time.Second)
+	typeText(engine, "cb")
+	sink.Release()
+	sink.WaitFor(2, time.Second)
+	typeText(engine, "b")
+	want := []string{"a.wav", "c.wav", "b.wav"}
+	if got, _ := sink.WaitFor(3, time.Second); !reflect.DeepEqual(got, want) {
+		t.Errorf("played %q, want %q", got, want)
+	}
+}
+
+func TestCoalescingSkipsInterruptedPresses(t *testing.T) {
+	sink := phonicaltest.NewSink()
+	engine := start(t, sink, phonicaltest.NewClock(time.Now()),
+		phonical.WithRepeatCoalescing(200*time.Millisecond, false))
+
+	// the waiting b is dropped by the interruption, so the next b plays
+	sink.Hold()
+	typeText(engine, "ab")
+	sink.WaitFor(1, time.Second)
+	engine.Interrupt()
+	sink.Release()
+	typeText(engine, "b")
+	want := []string{"a.wav", "b.wav"}
+	if got, _ := sink.WaitFor(2, time.Second); !reflect.DeepEqual(got, want) {
+		t.Errorf("played %q, want %q", got, want)
+	}
+}
+
+func TestCoalescingStopsAtCues(t *testing.T) {
+	sink := phonicaltest.NewSink()
+	engine := start(t, sink, phonicaltest.NewClock(time.Now()),
+		phonical.WithRepeatCoalescing(200*time.Millisecond, false),
+		phonical.WithCues(true))
+
+	sink.Hold()
+	typeText(engine, "a")
+	sink.WaitFor(1, time.Second)
+	typeText(engine, "b b")
+	sink.Release()
+	want := []string{"a.wav", "b.wav", "cue:space", "b.wav"}
+	if got, _ := sink.WaitFor(4, time.Second); !reflect.DeepEqual(got, want) {
+		t.Errorf("played %q, want %q", got, want)
+	}
+}
+
+func TestInterrupt(t *testing.T) {
+	sink := phonicaltest.NewSink()
+	engine := start(t, sink, phonicaltest.NewClock(time.Now()))
+
+	sink.Hold()
+	typeText(engine, "ab")
+	sink.WaitFor(1, time.Second)
+	engine.Interrupt()
+
+	deadline := time.Now().Add(time.Second)
+	for len(sink.Interrupted()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := sink.Interrupted(); !reflect.DeepEqual(got, []string{"a.wav"}) {
+		t.Errorf("interrupted %q, want a.wav", got)
+	}
+	// b was waiting and is dropped
+	sink.Release()
+	time.Sleep(20 * time.Millisecond)
+	if got := sink.Played(); !reflect.DeepEqual(got, []string{"a.wav"}) {
+		t.Errorf("played %q after the interruption, want only a.wav", got)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	sink := phonicaltest.NewSink()
+	clock := phonicaltest.NewClock(time.Now())
+	engine := start(t, sink, clock)
+
+	// b waits 250ms of virtual time behind a
+	sink.Hold()
+	typeText(engine, "ab")
+	sink.WaitFor(1, time.Second)
+	clock.Advance(250 * time.Millisecond)
+	sink.Release()
+	sink.WaitFor(2, time.Second)
+
+	var metrics strings.Builder
+	if err := engine.WriteMetrics(&metrics); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`phonical_playback_latency_seconds_bucket{le="0.1"} 1`,
+		`phonical_playback_latency_seconds_bucket{le="0.25"} 2`,
+		"phonical_playback_latency_seconds_sum 0.25",
+	} {
+		if !strings.Contains(metrics.String(), want) {
+			t.Errorf("metrics lack %s:\n%s", want, metrics.String())
+		}
+	}
+}
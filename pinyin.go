@@ -0,0 +1,117 @@
+package phonical
+
+import (
+	"strings"
+)
+
+// WithPinyin turns on Mandarin tone practice. Letters collect into a
+// pinyin syllable and a tone number typed after it (ma1 to ma4, or 5 for
+// the neutral tone) plays the toned recording from the pack's [syllables]
+// table. Syllables without a recording are spoken with their tone mark.
+// Type v for ü.
+func WithPinyin(on bool) Option {
+	return func(e *Engine) {
+		e.pinyin = on
+	}
+}
+
+// toneMarks holds each pinyin vowel with tones one to four.
+var toneMarks = map[rune][4]rune{
+	'a': {'ā', 'á', 'ǎ', 'à'},
+	'e': {'ē', 'é', 'ě', 'è'},
+	'i': {'ī', 'í', 'ǐ', 'ì'},
+	'o': {'ō', 'ó', 'ǒ', 'ò'},
+	'u': {'ū', 'ú', 'ǔ', 'ù'},
+	'ü': {'ǖ', 'ǘ', 'ǚ', 'ǜ'},
+}
+
+// toneMarked writes syllable with the mark for tone: on a or e if there is
+// one, on the o of "ou", otherwise on the last vowel.
+func toneMarked(syllable string, tone int) string {
+	if tone < 1 || tone > 4 {
+		return syllable
+	}
+	runes := []rune(syllable)
+	at := -1
+	for i, r := range runes {
+		switch {
+		case r == 'a' || r == 'e':
+			at = i
+		case r == 'o' && i+1 < len(runes) && runes[i+1] == 'u':
+			at = i
+		case at < 0 || !strings.ContainsRune("aeo", runes[at]):
+			if _, ok := toneMarks[r]; ok {
+				at = i
+			}
+		}
+		if at >= 0 && (runes[at] == 'a' || runes[at] == 'e') {
+			break
+		}
+	}
+	if at < 0 {
+		return syllable
+	}
+	runes[at] = toneMarks[runes[at]][tone-1]
+	return string(runes)
+}
+
+// typePinyin tracks the syllable being typed in pinyin mode and plays it
+// when a tone number follows. It reports whether it handled the key;
+// letters the pack has no sound for are added to the word silently, to be
+// heard with their tone.
+func (e *Engine) typePinyin(char rune) bool {
+	if char == 'v' {
+		char = 'ü'
+	}
+	e.mu.Lock()
+	syllable := e.syllable
+	switch {
+	case char >= 'a' && char <= 'z' || char == 'ü':
+		e.syllable += string(char)
+		if _, ok := e.pack.Load().letterSound(char, e.variant); ok {
+			e.mu.Unlock()
+			return false
+		}
+		e.word.insert(char)
+		e.mu.Unlock()
+		return true
+	case char >= '1' && char <= '5' && syllable != "":
+		e.syllable = ""
+		e.mu.Unlock()
+		e.playTone(syllable, int(char-'0'))
+		return true
+	}
+	e.syllable = ""
+	e.mu.Unlock()
+	return false
+}
+
+// playTone plays a pinyin syllable in the given tone.
+func (e *Engine) playTone(syllable string, tone int) {
+	marked := toneMarked(syllable, tone)
+	e.debugf("Pinyin: %s\n", marked)
+	if e.visual {
+		e.caption(marked)
+	}
+	key := syllable + string(rune('0'+tone))
+	if file, ok := e.pack.Load().Syllables[key]; ok {
+		e.enqueue(&queuedSound{sound: file, typed: true})
+		return
+	}
+	e.enqueueSay(marked)
+}
+
+// validSyllable reports whether key is a pack [syllables] key: letters
+// followed by a tone number.
+func validSyllable(key string) bool {
+	letters := strings.TrimRight(key, "12345")
+	if letters == "" || len(key)-len(letters) != 1 {
+		return false
+	}
+	for _, r := range letters {
+		if (r < 'a' || r > 'z') && r != 'ü' {
+			return false
+		}
+	}
+	return true
+}
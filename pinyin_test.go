@@ -0,0 +1,56 @@
+package phonical_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// syllablePack writes a pack with no letters and the given [syllables]
+// table, recorded with the English a.
+func syllablePack(t *testing.T, syllables ...string) (*phonical.Pack, error) {
+	t.Helper()
+	dir := t.TempDir()
+	data, err := os.ReadFile(filepath.Join("sounds", phonical.DefaultSounds, "a.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := "name = \"Pinyin\"\n\n[syllables]\n"
+	for _, s := range syllables {
+		if err := os.WriteFile(filepath.Join(dir, s+".wav"), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		manifest += s + " = \"" + s + ".wav\"\n"
+	}
+	if err := os.WriteFile(filepath.Join(dir, phonical.ManifestName), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return phonical.LoadPack(dir)
+}
+
+func TestPinyin(t *testing.T) {
+	pack, err := syllablePack(t, "ma3", "lv4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := phonicaltest.New(t, phonical.WithPinyin(true), phonical.WithPack(pack))
+	// Recorded syllables play, with v for ü; the rest are spoken with the
+	// tone mark on a or e, the o of ou, or else the last vowel
+	h.Type("ma3 lv4 ma1 hao3 gou3 gui4 xie2 ma5")
+	h.Expect("ma3.wav", "lv4.wav", "say:mā", "say:hǎo", "say:gǒu", "say:guì", "say:xié", "say:ma")
+}
+
+func TestPinyinWithLetterSounds(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithPinyin(true))
+	h.Type("ma3")
+	h.Expect("m.wav", "a.wav", "say:mǎ")
+}
+
+func TestPinyinSyllablesChecked(t *testing.T) {
+	if _, err := syllablePack(t, "ma6"); err == nil {
+		t.Error("tone 6 accepted")
+	}
+}
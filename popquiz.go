@@ -0,0 +1,81 @@
+package phonical
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// popQuizTimeout is how long a pop quiz waits for its answer.
+const popQuizTimeout = 8 * time.Second
+
+// WithPopQuiz occasionally asks, between words, for the letter that makes
+// a sound ("Can you press the letter that says /m/?") and waits briefly
+// for the answer, so practice turns up during ordinary computer use.
+// Quizzes come about every interval, at random; 0, the default, disables
+// them.
+func WithPopQuiz(every time.Duration) Option {
+	return func(e *Engine) {
+		e.popQuizEvery = every
+	}
+}
+
+// popQuizGap returns a random time until the next pop quiz, from half to
+// one and a half times the configured interval.
+func (e *Engine) popQuizGap() time.Duration {
+	return e.popQuizEvery/2 + time.Duration(rand.Int63n(int64(e.popQuizEvery)))
+}
+
+// maybePopQuiz starts a pop quiz when one is due. Like maybePrompt it runs
+// between words, and it skips its turn while another activity is running.
+func (e *Engine) maybePopQuiz(now time.Time) {
+	if e.popQuizEvery <= 0 || e.muted() {
+		return
+	}
+
+	e.mu.Lock()
+	if now.Before(e.nextPopQuiz) || e.tutor != nil {
+		e.mu.Unlock()
+		return
+	}
+	e.nextPopQuiz = now.Add(e.popQuizGap())
+	e.mu.Unlock()
+
+	letters := e.packLetters()
+	if len(letters) == 0 {
+		return
+	}
+	target := letters[rand.Intn(len(letters))]
+	e.debugf("Pop quiz for %c\n", target)
+	go e.RunActivity(popQuiz(target))
+}
+
+// popQuiz plays target's sound and asks for its letter. Answers are
+// recorded as reviews; a quiz that goes unanswered is not held against
+// the child, who may simply have been busy.
+func popQuiz(target rune) Activity {
+	return ActivityFunc(func(t *Tutor) error {
+		t.Say("Pop quiz! Can you press the letter that says")
+		t.Play(target)
+
+		pressed, ok := t.NextKey(popQuizTimeout)
+		if t.Stopped() {
+			return ErrStopped
+		}
+		if !ok {
+			t.Say(fmt.Sprintf("It was %c.", target))
+			return nil
+		}
+		if stats := t.Stats(); stats != nil {
+			now := t.e.clock.Now()
+			stats.Update(func(st *Stats) { st.review(target, pressed == target, now) })
+		}
+		if pressed == target {
+			t.Say(fmt.Sprintf("Yes, that's %c!", target))
+		} else {
+			t.Say(fmt.Sprintf("Not quite, it was %c.", target))
+			t.Play(target)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,42 @@
+package phonical_test
+
+import (
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestPopQuiz(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithPopQuiz(time.Minute), phonical.WithWatchdog(0))
+	// Only a word ended after the first quiz falls due brings one
+	h.Type("at{space}{wait 2m}it{space}")
+	played := waitPlayed(t, h, 6)
+	want := []string{"a.wav", "t.wav", "i.wav", "t.wav", "say:Pop quiz! Can you press the letter that says"}
+	for i, name := range want {
+		if played[i] != name {
+			t.Fatalf("played %q, want %q then the quiz letter", played, want)
+		}
+	}
+	target := letterOf(played[5])
+	answer(h, target)
+	if got := waitPlayed(t, h, 7)[6]; got != "say:Yes, that's "+target+"!" {
+		t.Errorf("after the right answer said %q", got)
+	}
+
+	// The next quiz is at least half the interval away
+	h.Type("{wait 20s}on{space}")
+	h.Expect(append(played[:6:6], "say:Yes, that's "+target+"!", "o.wav", "n.wav")...)
+}
+
+func TestPopQuizUnanswered(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithPopQuiz(time.Minute), phonical.WithWatchdog(0))
+	h.Type("{wait 2m}it{space}")
+	played := waitPlayed(t, h, 4)
+	target := letterOf(played[3])
+	// Nobody answers, so after a while the quiz gives the letter
+	if got := waitPlayed(t, h, 5)[4]; got != "say:It was "+target+"." {
+		t.Errorf("unanswered quiz said %q", got)
+	}
+}
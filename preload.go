@@ -0,0 +1,118 @@
+package phonical
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// WithPreloadProgress calls fn as each sound is decoded at start-up, with
+// the number done so far and the total, for showing progress with large
+// packs. Calls are made one at a time.
+func WithPreloadProgress(fn func(done, total int)) Option {
+	return func(e *Engine) {
+		e.onPreload = fn
+	}
+}
+
+// preloadJob is one sound to decode ahead of time.
+type preloadJob struct {
+	pack *Pack
+	file string
+}
+
+// preloadSounds decodes the sounds of the current pack, and of any other
+// languages the hotkey switches to or mixes in, ahead of time. A worker
+// per CPU shares the decoding, and it gives up early if ctx is cancelled.
+func (e *Engine) preloadSounds(ctx context.Context) {
+	e.debugf("Preloading sounds...\n")
+
+	packs := append([]*Pack{e.pack.Load()}, e.languages...)
+	if e.second != nil {
+		packs = append(packs, e.second)
+	}
+	if e.theme != nil {
+		packs = append(packs, e.theme)
+	}
+	var jobs []preloadJob
+	seen := map[preloadJob]bool{}
+	for _, pack := range packs {
+		for _, file := range e.preloadFiles(pack) {
+			job := preloadJob{pack: pack, file: file}
+			if !seen[job] {
+				seen[job] = true
+				jobs = append(jobs, job)
+			}
+		}
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		done int
+	)
+	work := make(chan preloadJob)
+	for i := 0; i < min(runtime.GOMAXPROCS(0), len(jobs)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range work {
+				if _, err := job.pack.loadWith(job.file, e.decoding); err != nil {
+					e.logf("Failed to preload %s: %v", job.file, err)
+					e.metrics.decodeErrors.Add(1)
+				}
+				mu.Lock()
+				done++
+				if e.onPreload != nil {
+					e.onPreload(done, len(jobs))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+feed:
+	for _, job := range jobs {
+		select {
+		case work <- job:
+		case <-ctx.Done():
+			e.debugf("Preloading cancelled\n")
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	e.debugf("Preloaded %d sounds\n", cachedSoundCount())
+}
+
+// preloadFiles lists the sounds of pack worth decoding before the first
+// key press.
+func (e *Engine) preloadFiles(pack *Pack) []string {
+	var files []string
+	for _, soundFile := range pack.Letters {
+		files = append(files, soundFile)
+	}
+	for _, soundFile := range pack.Graphemes {
+		files = append(files, soundFile)
+	}
+	for _, soundFile := range pack.Variants[e.variant] {
+		files = append(files, soundFile)
+	}
+	for _, soundFile := range pack.Cues {
+		files = append(files, soundFile)
+	}
+	files = append(files, pack.Encouragement...)
+	if e.timeOfDay {
+		for _, clips := range pack.Timed {
+			for _, soundFile := range clips {
+				files = append(files, soundFile)
+			}
+		}
+	}
+	if e.navigation {
+		for _, soundFile := range pack.Navigation {
+			files = append(files, soundFile)
+		}
+	}
+	return files
+}
@@ -0,0 +1,25 @@
+package phonical_test
+
+import (
+	"reflect"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestPreloadProgress(t *testing.T) {
+	var calls [][2]int
+	pack := letterPack(t, map[string]string{"a": "a", "b": "b", "c": "c"})
+	second := secondPack(t)
+	// Calls come one at a time, covering the second language's sounds too
+	phonicaltest.New(t, phonical.WithPack(pack),
+		phonical.WithSecondLanguage(second, phonical.BilingualShift),
+		phonical.WithPreloadProgress(func(done, total int) {
+			calls = append(calls, [2]int{done, total})
+		}))
+	want := [][2]int{{1, 5}, {2, 5}, {3, 5}, {4, 5}, {5, 5}}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("progress %v, want %v", calls, want)
+	}
+}
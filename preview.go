@@ -0,0 +1,85 @@
+package phonical
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultPreviewGap is the pause between sounds in Preview.
+const DefaultPreviewGap = 600 * time.Millisecond
+
+// previewSections is the order Preview plays a pack's tables in.
+var previewSections = []string{"letters", "intros", "variants", "syllables", "cues", "navigation", "emoji", "timed", "encouragement"}
+
+// PreviewEntry is one sound of a pack, named by its manifest entry such
+// as "letters.a" or "cues.space".
+type PreviewEntry struct {
+	Entry string
+	File  string
+}
+
+// PreviewEntries lists the pack's sounds in the order Preview plays them:
+// letters first, then the other tables. When only is given, just the
+// entries it names are listed, by table ("cues"), entry ("cues.space") or
+// letter ("a").
+func (p *Pack) PreviewEntries(only []string) []PreviewEntry {
+	var entries []PreviewEntry
+	for entry, file := range p.entries() {
+		if len(only) == 0 || matchesPreview(entry, only) {
+			entries = append(entries, PreviewEntry{Entry: entry, File: file})
+		}
+	}
+	section := func(entry string) int {
+		table, _, _ := strings.Cut(entry, ".")
+		for i, s := range previewSections {
+			if s == table {
+				return i
+			}
+		}
+		return len(previewSections)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		si, sj := section(entries[i].Entry), section(entries[j].Entry)
+		if si != sj {
+			return si < sj
+		}
+		return entries[i].Entry < entries[j].Entry
+	})
+	return entries
+}
+
+// matchesPreview reports whether entry is one of those named by only.
+func matchesPreview(entry string, only []string) bool {
+	table, key, _ := strings.Cut(entry, ".")
+	for _, name := range only {
+		if name == entry || name == table || (table == "letters" && name == key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Preview plays the sounds of the pack in use that PreviewEntries lists
+// for only, with gap between them, calling show before each plays, so a
+// pack can be checked by ear from start to finish.
+func Preview(only []string, gap time.Duration, show func(PreviewEntry)) Activity {
+	return ActivityFunc(func(t *Tutor) error {
+		entries := t.Pack().PreviewEntries(only)
+		if len(entries) == 0 {
+			return fmt.Errorf("no sounds in %s match %s", t.Pack().Name, strings.Join(only, ","))
+		}
+		for _, entry := range entries {
+			if t.Stopped() {
+				return ErrStopped
+			}
+			if show != nil {
+				show(entry)
+			}
+			t.e.playAndWait(&queuedSound{sound: entry.File})
+			t.Pause(gap)
+		}
+		return nil
+	})
+}
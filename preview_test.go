@@ -0,0 +1,46 @@
+package phonical_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestPreview(t *testing.T) {
+	wav, err := os.ReadFile(filepath.Join("sounds", phonical.DefaultSounds, "a.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pack, err := phonical.LoadPack(writePack(t, "name = 'Mine'\n[letters]\nb = 'b.wav'\na = 'a.wav'\n[cues]\nspace = 'pop.wav'\n",
+		map[string][]byte{"a.wav": wav, "b.wav": wav, "pop.wav": wav}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := phonicaltest.New(t, phonical.WithPack(pack), phonical.WithWatchdog(0))
+	var shown []string
+	if err := h.Engine.RunActivity(phonical.Preview(nil, 0, func(e phonical.PreviewEntry) { shown = append(shown, e.Entry) })); err != nil {
+		t.Fatal(err)
+	}
+	// Letters come first, in order
+	if want := []string{"letters.a", "letters.b", "cues.space"}; !reflect.DeepEqual(shown, want) {
+		t.Errorf("showed %q, want %q", shown, want)
+	}
+	h.Expect("a.wav", "b.wav", "pop.wav")
+
+	var only []phonical.PreviewEntry
+	for _, names := range [][]string{{"cues"}, {"cues.space"}} {
+		if only = pack.PreviewEntries(names); len(only) != 1 || only[0] != (phonical.PreviewEntry{Entry: "cues.space", File: "pop.wav"}) {
+			t.Errorf("entries for %q are %+v", names, only)
+		}
+	}
+	if only = pack.PreviewEntries([]string{"b"}); len(only) != 1 || only[0].Entry != "letters.b" {
+		t.Errorf("entries for b are %+v", only)
+	}
+	if err := h.Engine.RunActivity(phonical.Preview([]string{"z"}, 0, nil)); err == nil {
+		t.Error("previewed a letter the pack doesn't have")
+	}
+}
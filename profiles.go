@@ -0,0 +1,78 @@
+package phonical
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WithProfileHotkey lets F11 switch between profiles, for children who
+// share one login. Each press saves the current child's stats, loads the
+// next child's from ProfileStatsPath and says their name, so stats stay
+// separate without restarting. It needs WithStats; the store's file
+// decides which profile is in use at first.
+func WithProfileHotkey(profiles ...string) Option {
+	return func(e *Engine) {
+		e.profiles = profiles
+	}
+}
+
+// checkProfiles validates the hotkey's profiles and finds the current one.
+func (e *Engine) checkProfiles() error {
+	if len(e.profiles) == 0 {
+		return nil
+	}
+	if e.stats == nil {
+		return errors.New("the profile hotkey needs stats")
+	}
+	for _, name := range e.profiles {
+		path, err := ProfileStatsPath(name)
+		if err != nil {
+			return err
+		}
+		if path == e.stats.path {
+			e.profile = name
+		}
+	}
+	return nil
+}
+
+// Profile returns the profile chosen with the profile hotkey, or "" when
+// the hotkey is off or the stats belong to none of its profiles.
+func (e *Engine) Profile() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.profile
+}
+
+// nextProfile switches to the profile after the current one.
+func (e *Engine) nextProfile() {
+	e.mu.Lock()
+	next := e.profiles[0]
+	for i, name := range e.profiles {
+		if name == e.profile {
+			next = e.profiles[(i+1)%len(e.profiles)]
+			break
+		}
+	}
+	e.mu.Unlock()
+
+	path, err := ProfileStatsPath(next)
+	if err == nil {
+		err = e.stats.Switch(path)
+	}
+	if err != nil {
+		e.logf("Switching to profile %s: %v", next, err)
+		e.enqueueSay("Sorry, I couldn't switch profile.")
+		return
+	}
+
+	e.mu.Lock()
+	e.profile = next
+	e.refreshEmphasis(e.clock.Now())
+	e.mu.Unlock()
+
+	e.debugf("Profile: %s\n", next)
+	e.Interrupt()
+	e.enqueueSay(fmt.Sprintf("Hello, %s!", next))
+	e.notify("Profile switched to " + next)
+}
@@ -0,0 +1,30 @@
+package phonical_test
+
+import (
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestProfileHotkey(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path, err := phonical.ProfileStatsPath("amy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err := phonical.OpenStats(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := phonicaltest.New(t, phonical.WithStats(stats), phonical.WithProfileHotkey("amy", "ben"))
+	if got := h.Engine.Profile(); got != "amy" {
+		t.Fatalf("profile %q at start, want amy", got)
+	}
+
+	h.Source.Send(phonical.Key{Code: phonical.KeyF11})
+	h.Expect("say:Hello, ben!")
+	if got := h.Engine.Profile(); got != "ben" {
+		t.Errorf("profile %q after F11, want ben", got)
+	}
+}
@@ -0,0 +1,278 @@
+package phonical
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// DefaultQueueSize is the number of sounds that may wait to be played.
+const DefaultQueueSize = 100
+
+// OverflowPolicy decides what happens when a sound arrives while the play
+// queue is full.
+type OverflowPolicy int
+
+const (
+	// DropNewest discards the incoming sound.
+	DropNewest OverflowPolicy = iota
+	// DropOldest discards the longest-waiting sound so playback stays close
+	// to what was just typed.
+	DropOldest
+	// CoalesceDuplicates collapses runs of the same waiting letter to make
+	// room, falling back to DropNewest when there are none.
+	CoalesceDuplicates
+)
+
+var overflowPolicyNames = map[OverflowPolicy]string{
+	DropNewest:         "drop-newest",
+	DropOldest:         "drop-oldest",
+	CoalesceDuplicates: "coalesce-duplicates",
+}
+
+func (p OverflowPolicy) String() string {
+	if name, ok := overflowPolicyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("OverflowPolicy(%d)", int(p))
+}
+
+// ParseOverflowPolicy converts a policy name such as "drop-oldest" into an
+// OverflowPolicy.
+func ParseOverflowPolicy(name string) (OverflowPolicy, error) {
+	for p, n := range overflowPolicyNames {
+		if strings.EqualFold(n, name) {
+			return p, nil
+		}
+	}
+	return DropNewest, fmt.Errorf("unknown overflow policy %q (want drop-newest, drop-oldest or coalesce-duplicates)", name)
+}
+
+// queuedSound is a sound waiting in, or being played from, the queue.
+type queuedSound struct {
+	sound  string
+	letter rune
+	at     time.Time
+	// buffer, when set, is played instead of loading sound from the pack.
+	buffer *beep.Buffer
+	// say, when set, is spoken by the announcer instead of playing a sound.
+	say string
+	// typed marks sounds triggered directly by a key press.
+	typed bool
+	// cue marks a cue such as the one for space or Enter, which ends a run
+	// of repeated presses.
+	cue bool
+	// pause, when set, is a silence of that length.
+	pause time.Duration
+	// pack is the pack sound is a file of, as it was when queued.
+	pack *Pack
+	// pan places the sound from -1 (left) to 1 (right).
+	pan float64
+	// volume, when set, scales the sound below the engine's volume.
+	volume float64
+	// stretch, when above 1, plays the sound that many times slower at the
+	// same pitch, as an exaggerated model.
+	stretch float64
+	// played, when set, is closed once the sound has played or been dropped.
+	played chan struct{}
+	// count is how many presses this entry stands for once coalesced.
+	count int
+	// done is set once the sound has played or been dropped, so no more
+	// presses are folded into it.
+	done bool
+}
+
+// queueStage preprocesses sounds on their way into the queue. Stages run
+// with the queue locked.
+type queueStage interface {
+	// admit may adjust the waiting entries, and reports whether next
+	// should still be queued.
+	admit(waiting []*queuedSound, next *queuedSound) bool
+	// queued is told of each sound the queue accepted.
+	queued(item *queuedSound)
+}
+
+// soundQueue is a bounded FIFO of sounds waiting to be played.
+type soundQueue struct {
+	mu      sync.Mutex
+	items   []*queuedSound
+	size    int
+	policy  OverflowPolicy
+	stages  []queueStage
+	pending chan struct{}
+}
+
+func newSoundQueue(size int, policy OverflowPolicy, stages ...queueStage) *soundQueue {
+	if size < 1 {
+		size = 1
+	}
+	return &soundQueue{
+		size:    size,
+		policy:  policy,
+		stages:  stages,
+		pending: make(chan struct{}, 1),
+	}
+}
+
+// push runs next through the queue's stages and adds it, applying the
+// overflow policy when full. It reports whether next was queued or absorbed
+// by a stage, and which sound, if any, was dropped to make room.
+func (q *soundQueue) push(next *queuedSound) (queued bool, dropped string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if next.count == 0 {
+		next.count = 1
+	}
+	for _, stage := range q.stages {
+		if !stage.admit(q.items, next) {
+			return true, ""
+		}
+	}
+
+	if len(q.items) >= q.size {
+		switch q.policy {
+		case DropOldest:
+			dropped = q.items[0].sound
+			q.items[0].retire()
+			q.items = q.items[1:]
+		case CoalesceDuplicates:
+			q.coalesce()
+			if len(q.items) >= q.size {
+				next.retire()
+				return false, next.sound
+			}
+		default:
+			next.retire()
+			return false, next.sound
+		}
+	}
+
+	q.items = append(q.items, next)
+	for _, stage := range q.stages {
+		stage.queued(next)
+	}
+	q.signal()
+	return true, dropped
+}
+
+// coalesce folds each run of the same typed letter into its first entry,
+// as for a held key, keeping the order of what was typed. Pauses, phrases
+// and other sounds are never merged. The caller must hold q.mu.
+func (q *soundQueue) coalesce() {
+	kept := q.items[:0]
+	for _, item := range q.items {
+		if n := len(kept); n > 0 && kept[n-1].repeatedBy(item) {
+			kept[n-1].count += item.count
+			item.retire()
+			continue
+		}
+		kept = append(kept, item)
+	}
+	q.items = kept
+}
+
+// repeatedBy reports whether next is another press of the letter typed for
+// item.
+func (item *queuedSound) repeatedBy(next *queuedSound) bool {
+	letters := item.typed && next.typed && item.letter != 0 && item.say == "" && next.say == "" && item.pause == 0 && next.pause == 0
+	return letters && item.sound == next.sound && item.letter == next.letter
+}
+
+// pop removes and returns the oldest sound, if any.
+func (q *soundQueue) pop() (*queuedSound, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.items) == 0 {
+		return nil, false
+	}
+	item := q.items[0]
+	q.items = q.items[1:]
+	if len(q.items) > 0 {
+		q.signal()
+	}
+	return item, true
+}
+
+// finish marks item as played and returns how many presses it stood for.
+// Presses arriving after finish are no longer folded into item.
+func (q *soundQueue) finish(item *queuedSound) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	item.retire()
+	return item.count
+}
+
+// retire marks item as done, so no more presses are folded into it, and
+// wakes anyone waiting for it to play. The caller must hold the queue's
+// lock.
+func (item *queuedSound) retire() {
+	item.done = true
+	if item.played != nil {
+		close(item.played)
+		item.played = nil
+	}
+}
+
+// clear drops every waiting sound.
+func (q *soundQueue) clear() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, item := range q.items {
+		item.retire()
+	}
+	q.items = nil
+}
+
+// len returns the number of sounds waiting.
+func (q *soundQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// signal wakes the player without blocking. The caller must hold q.mu.
+func (q *soundQueue) signal() {
+	select {
+	case q.pending <- struct{}{}:
+	default:
+	}
+}
+
+// repeatCoalescer is a queue stage that folds a typed sound repeated within
+// window of its previous press into that press, whether it is still waiting
+// or already playing, so key repeat and mashing produce a single playback.
+// A cue, such as the one for space, ends the run.
+type repeatCoalescer struct {
+	window time.Duration
+	last   *queuedSound
+}
+
+func coalesceRepeats(window time.Duration) *repeatCoalescer {
+	return &repeatCoalescer{window: window}
+}
+
+func (c *repeatCoalescer) admit(waiting []*queuedSound, next *queuedSound) bool {
+	if !next.typed {
+		if next.cue {
+			c.last = nil
+		}
+		return true
+	}
+	if last := c.last; last != nil && !last.done && last.sound == next.sound && next.at.Sub(last.at) <= c.window {
+		last.count += next.count
+		last.at = next.at
+		return false
+	}
+	return true
+}
+
+func (c *repeatCoalescer) queued(item *queuedSound) {
+	if item.typed {
+		c.last = item
+	}
+}
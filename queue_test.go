@@ -0,0 +1,60 @@
+package phonical
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func typedLetter(c rune) *queuedSound {
+	return &queuedSound{sound: string(c) + ".wav", letter: c, typed: true}
+}
+
+func waitingSounds(q *soundQueue) []string {
+	var names []string
+	for _, item := range q.items {
+		names = append(names, item.sound)
+	}
+	return names
+}
+
+func TestCoalesceKeepsOrder(t *testing.T) {
+	q := newSoundQueue(4, CoalesceDuplicates)
+	for _, c := range "catc" {
+		q.push(typedLetter(c))
+	}
+	if ok, dropped := q.push(typedLetter('s')); ok || dropped != "s.wav" {
+		t.Errorf("push to a full queue of distinct runs = %v, %q; want false, s.wav", ok, dropped)
+	}
+	if want := []string{"c.wav", "a.wav", "t.wav", "c.wav"}; !reflect.DeepEqual(waitingSounds(q), want) {
+		t.Errorf("queue %q, want %q", waitingSounds(q), want)
+	}
+}
+
+func TestCoalesceRuns(t *testing.T) {
+	q := newSoundQueue(5, CoalesceDuplicates)
+	for _, c := range "ccaaa" {
+		q.push(typedLetter(c))
+	}
+	q.push(typedLetter('t'))
+	if want := []string{"c.wav", "a.wav", "t.wav"}; !reflect.DeepEqual(waitingSounds(q), want) {
+		t.Errorf("queue %q, want %q", waitingSounds(q), want)
+	}
+	if q.items[0].count != 2 || q.items[1].count != 3 {
+		t.Errorf("counts %d and %d, want 2 and 3", q.items[0].count, q.items[1].count)
+	}
+}
+
+func TestCoalesceSkipsPausesAndPhrases(t *testing.T) {
+	q := newSoundQueue(4, CoalesceDuplicates)
+	q.push(&queuedSound{sound: "pause", pause: 100 * time.Millisecond})
+	q.push(&queuedSound{sound: "pause", pause: 100 * time.Millisecond})
+	q.push(&queuedSound{sound: "say:cat", say: "cat"})
+	q.push(&queuedSound{sound: "say:cat", say: "cat"})
+	if ok, _ := q.push(typedLetter('a')); ok {
+		t.Error("pauses or phrases were merged to make room")
+	}
+	if want := []string{"pause", "pause", "say:cat", "say:cat"}; !reflect.DeepEqual(waitingSounds(q), want) {
+		t.Errorf("queue %q, want %q", waitingSounds(q), want)
+	}
+}
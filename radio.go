@@ -0,0 +1,112 @@
+package phonical
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// DefaultRadioGap is the pause between letters on the letter radio.
+const DefaultRadioGap = 4 * time.Second
+
+// radioLevelWeight is how much more often letters of the current
+// curriculum level come up on the radio than those taught before it.
+const radioLevelWeight = 3
+
+// radioWordEvery is how many letters the radio plays between each one
+// followed by its word.
+const radioWordEvery = 3
+
+// RadioLetter is a letter the letter radio is about to play.
+type RadioLetter struct {
+	Letter rune
+	// Word is the word said after it ("a is for apple"), if any.
+	Word string
+}
+
+// LetterRadio returns an activity that plays letters slowly at random,
+// gap apart, for listening to without the keyboard. Letters of the current
+// curriculum level come up most often, and a letter is less likely to come
+// up again the more it has played, so each gets a fair share. Every few
+// letters it adds the letter's word. It stops after sleep, when sleep is
+// positive, calling show before each letter.
+func LetterRadio(gap, sleep time.Duration, show func(RadioLetter)) Activity {
+	return ActivityFunc(func(t *Tutor) error {
+		pool := t.e.packLetters()
+		if len(pool) == 0 {
+			return fmt.Errorf("sound pack has no letters")
+		}
+		current := t.e.levelLetters()
+		var timer <-chan time.Time
+		if sleep > 0 {
+			timer = t.e.clock.After(sleep)
+		}
+
+		played := make(map[rune]int)
+		for n := 1; ; n++ {
+			select {
+			case <-timer:
+				t.Say("That's all for now. Goodnight!")
+				return nil
+			default:
+			}
+			if t.Stopped() {
+				return ErrStopped
+			}
+
+			letter := radioPick(pool, current, played)
+			played[letter]++
+			item := RadioLetter{Letter: letter}
+			if n%radioWordEvery == 0 {
+				if item.Word = t.Pack().Words[letter]; item.Word == "" {
+					item.Word = associationWords[letter]
+				}
+			}
+			if show != nil {
+				show(item)
+			}
+			t.Play(letter)
+			if item.Word != "" {
+				t.Pause(300 * time.Millisecond)
+				t.Say(fmt.Sprintf("%c is for %s", letter, item.Word))
+			}
+			t.Pause(gap)
+		}
+	})
+}
+
+// radioPick chooses the next letter from pool, weighting those in current
+// and those played least.
+func radioPick(pool []rune, current map[rune]bool, played map[rune]int) rune {
+	weights := make([]float64, len(pool))
+	total := 0.0
+	for i, r := range pool {
+		w := 1.0
+		if current[r] {
+			w = radioLevelWeight
+		}
+		weights[i] = w / float64(1+played[r])
+		total += weights[i]
+	}
+	x := rand.Float64() * total
+	for i, w := range weights {
+		if x < w {
+			return pool[i]
+		}
+		x -= w
+	}
+	return pool[len(pool)-1]
+}
+
+// levelLetters returns the letters introduced at the curriculum's current
+// level, or nil without a curriculum.
+func (e *Engine) levelLetters() map[rune]bool {
+	if e.curriculum == nil || e.level > len(e.curriculum.Levels) {
+		return nil
+	}
+	letters := make(map[rune]bool)
+	for _, r := range e.curriculum.Levels[e.level-1].Letters {
+		letters[r] = true
+	}
+	return letters
+}
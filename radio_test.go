@@ -0,0 +1,40 @@
+package phonical_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestLetterRadio(t *testing.T) {
+	pack := letterPack(t, map[string]string{"a": "a"})
+	h := phonicaltest.New(t, phonical.WithPack(pack), phonical.WithWatchdog(0))
+	var shown []phonical.RadioLetter
+	start := h.Clock.Now()
+	done := runActivity(h, phonical.LetterRadio(4*time.Second, 10*time.Second, func(l phonical.RadioLetter) { shown = append(shown, l) }))
+
+	// Every third letter has its word, and the sleep timer ends it after
+	// the letter that runs past it
+	played := waitPlayed(t, h, 5)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(played[3], "say:a is for ") {
+		t.Fatalf("played %q, want the third a with its word", played)
+	}
+	word := strings.TrimPrefix(played[3], "say:a is for ")
+	want := []string{"a.wav", "a.wav", "a.wav", "say:a is for " + word, "say:That's all for now. Goodnight!"}
+	if !reflect.DeepEqual(played, want) {
+		t.Errorf("played %q, want %q", played, want)
+	}
+	if want := []phonical.RadioLetter{{Letter: 'a'}, {Letter: 'a'}, {Letter: 'a', Word: word}}; !reflect.DeepEqual(shown, want) {
+		t.Errorf("showed %+v, want %+v", shown, want)
+	}
+	if elapsed := h.Clock.Now().Sub(start); elapsed != 12300*time.Millisecond {
+		t.Errorf("radio played for %s, want 12.3s", elapsed)
+	}
+}
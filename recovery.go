@@ -0,0 +1,106 @@
+package phonical
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/speaker"
+)
+
+// Default backoff between attempts to bring the speaker back after it
+// fails.
+const (
+	DefaultReinitBackoff    = 500 * time.Millisecond
+	DefaultMaxReinitBackoff = 30 * time.Second
+)
+
+// stallTimeout is how long the speaker may go without pulling samples
+// before playback is considered stuck, as happens when the device is
+// removed or the sound server restarts.
+const stallTimeout = 2 * time.Second
+
+var errSpeakerStalled = errors.New("speaker stopped playing")
+
+// WithSpeakerRecovery sets the backoff used to re-initialize the audio
+// stack after playback fails: the first retry waits min, doubling up to
+// max. A min of 0 disables recovery, leaving audio off until restart.
+// Defaults to DefaultReinitBackoff and DefaultMaxReinitBackoff.
+func WithSpeakerRecovery(min, max time.Duration) Option {
+	return func(e *Engine) {
+		e.reinitBackoff = min
+		e.maxReinitBackoff = max
+	}
+}
+
+// watchedStreamer records when the speaker last asked for samples, and
+// ends early once ctx is cancelled.
+type watchedStreamer struct {
+	beep.Streamer
+	ctx  context.Context
+	last atomic.Int64
+}
+
+func (w *watchedStreamer) Stream(samples [][2]float64) (int, bool) {
+	w.last.Store(time.Now().UnixNano())
+	if w.ctx.Err() != nil {
+		return 0, false
+	}
+	return w.Streamer.Stream(samples)
+}
+
+func (w *watchedStreamer) idle() time.Duration {
+	return time.Since(time.Unix(0, w.last.Load()))
+}
+
+// closeSpeaker tears down the audio stack so the next initSpeaker opens
+// the device afresh.
+func closeSpeaker() {
+	speakerMutex.Lock()
+	defer speakerMutex.Unlock()
+	if speakerInitialized {
+		speaker.Clear()
+		speaker.Close()
+		speakerInitialized = false
+	}
+}
+
+// recoverSpeaker re-initializes the speaker after err, retrying with
+// exponential backoff until it works or the engine stops.
+func (e *Engine) recoverSpeaker(ctx context.Context, err error) {
+	if e.reinitBackoff <= 0 {
+		return
+	}
+	e.logf("Audio failed (%v); restarting it", err)
+	e.sink.Close()
+
+	backoff := e.reinitBackoff
+	for attempt := 1; ; attempt++ {
+		err := e.sink.Init()
+		if err == nil {
+			e.metrics.speakerRestarts.Add(1)
+			e.debugf("Audio restarted after %d attempts\n", attempt)
+			return
+		}
+		e.debugf("Restarting audio failed: %v; retrying in %s\n", err, backoff)
+		select {
+		case <-e.clock.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+		if backoff *= 2; backoff > e.maxReinitBackoff {
+			backoff = e.maxReinitBackoff
+		}
+	}
+}
+
+// checkBackoff validates the recovery settings for New.
+func (e *Engine) checkBackoff() error {
+	if e.reinitBackoff > 0 && e.maxReinitBackoff < e.reinitBackoff {
+		return fmt.Errorf("maximum speaker backoff %s is shorter than the first %s", e.maxReinitBackoff, e.reinitBackoff)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package phonical_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// flakySink fails its next Play, as when the device is unplugged, and
+// then the next missing Inits while the device is still gone.
+type flakySink struct {
+	*phonicaltest.Sink
+	missing int
+
+	mu        sync.Mutex
+	failPlay  bool
+	failInits int
+}
+
+func (s *flakySink) Init() error {
+	s.Sink.Init()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failInits > 0 {
+		s.failInits--
+		return errors.New("no such device")
+	}
+	return nil
+}
+
+func (s *flakySink) Play(ctx context.Context, p phonical.Playback) error {
+	s.Sink.Play(ctx, p)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failPlay {
+		s.failPlay = false
+		s.failInits = s.missing
+		return errors.New("device removed")
+	}
+	return nil
+}
+
+// waitWaiters waits until n timers are pending on clock.
+func waitWaiters(t *testing.T, clock *phonicaltest.Clock, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for clock.Waiters() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d timers pending, want %d", clock.Waiters(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSpeakerRecovery(t *testing.T) {
+	if !phonical.HasBuiltinSounds() {
+		t.Skip("lite build")
+	}
+	sink := &flakySink{Sink: phonicaltest.NewSink(), failPlay: true, missing: 2}
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	clock := phonicaltest.NewClock(start)
+	engine, err := phonical.New(
+		phonical.WithAudioSink(sink),
+		phonical.WithClock(clock),
+		phonical.WithAnnouncer(sink.Announcer()),
+		phonical.WithDoNotDisturb(false),
+		phonical.WithWatchdog(0),
+		phonical.WithSpeakerRecovery(100*time.Millisecond, 150*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(engine.Stop)
+
+	engine.HandleKey(phonical.Key{Char: 'a'})
+	// Two failed restarts back off 100ms, then 150ms at the cap
+	for _, wait := range []time.Duration{100 * time.Millisecond, 150 * time.Millisecond} {
+		waitWaiters(t, clock, 1)
+		before := clock.Now()
+		clock.AdvanceToNext()
+		if got := clock.Now().Sub(before); got != wait {
+			t.Errorf("backed off %s, want %s", got, wait)
+		}
+	}
+
+	engine.HandleKey(phonical.Key{Char: 'b'})
+	if names, ok := sink.WaitFor(2, time.Second); !ok {
+		t.Fatalf("played %q after the device came back", names)
+	}
+
+	var buf bytes.Buffer
+	if err := engine.WriteMetrics(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "phonical_speaker_restarts_total 1\n") {
+		t.Errorf("metrics missing one restart:\n%s", buf.String())
+	}
+}
+
+func TestSpeakerRecoveryBackoffChecked(t *testing.T) {
+	if _, err := phonical.New(phonical.WithSpeakerRecovery(time.Second, time.Millisecond)); err == nil {
+		t.Error("maximum backoff shorter than the first accepted")
+	}
+}
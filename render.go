@@ -0,0 +1,128 @@
+package phonical
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/wav"
+)
+
+// renderTail is the silence left at the end of a rendered file so it
+// doesn't stop abruptly.
+const renderTail = 250 * time.Millisecond
+
+// Render types text into an engine configured by opts and writes what it
+// would have played to w as a WAV file, without touching the speaker, for
+// teachers building worksheets and videos. The mode, voice and pack
+// options apply as usual. Spoken phrases are left out, as the system voice
+// can't be recorded.
+func Render(w io.WriteSeeker, text string, opts ...Option) error {
+	r := &renderer{start: time.Now()}
+	opts = append(opts,
+		WithAudioSink(r),
+		WithClock(r),
+		WithAnnouncer(nil),
+		WithDoNotDisturb(false),
+		WithSilent(false),
+		// The renderer's clock turns every wait into silence, so nothing
+		// may wait on it in the background
+		WithWatchdog(0),
+		// Keys arrive all at once, so nothing may be dropped for lack of room
+		WithQueueSize(max(DefaultQueueSize, 8*len(text))),
+	)
+	e, err := New(opts...)
+	if err != nil {
+		return err
+	}
+	if err := e.Start(); err != nil {
+		return err
+	}
+	for _, c := range text {
+		e.HandleKey(charKey(c))
+	}
+	e.endWord()
+	e.playAndWait(&queuedSound{pause: renderTail})
+	e.Stop()
+
+	return wav.Encode(w, r.buffer(), outputFormat)
+}
+
+// renderer is an AudioSink and Clock that records playback into memory.
+// Time only passes as sound or silence is rendered, so pauses take no
+// time and appear in the output at their full length.
+type renderer struct {
+	mu      sync.Mutex
+	start   time.Time
+	samples [][2]float64
+	// cursor is where the next sound starts; layered sounds mix in from
+	// here without moving it.
+	cursor int
+}
+
+func (r *renderer) Init() error { return nil }
+func (r *renderer) Close()      {}
+
+func (r *renderer) Play(ctx context.Context, p Playback) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cursor += r.mix(p.Streamer)
+	return ctx.Err()
+}
+
+func (r *renderer) Layer(s beep.Streamer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mix(s)
+}
+
+// mix adds s into the recording at the cursor, returning its length. The
+// caller must hold r.mu.
+func (r *renderer) mix(s beep.Streamer) int {
+	chunk := make([][2]float64, 512)
+	n := 0
+	for {
+		filled, ok := s.Stream(chunk)
+		for i := 0; i < filled; i++ {
+			at := r.cursor + n + i
+			if at == len(r.samples) {
+				r.samples = append(r.samples, [2]float64{})
+			}
+			r.samples[at][0] += chunk[i][0]
+			r.samples[at][1] += chunk[i][1]
+		}
+		n += filled
+		if !ok {
+			return n
+		}
+	}
+}
+
+func (r *renderer) Now() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.start.Add(outputFormat.SampleRate.D(r.cursor))
+}
+
+// After renders d of silence and fires at once.
+func (r *renderer) After(d time.Duration) <-chan time.Time {
+	r.mu.Lock()
+	r.cursor += outputFormat.SampleRate.N(d)
+	for len(r.samples) < r.cursor {
+		r.samples = append(r.samples, [2]float64{})
+	}
+	now := r.start.Add(outputFormat.SampleRate.D(r.cursor))
+	r.mu.Unlock()
+
+	c := make(chan time.Time, 1)
+	c <- now
+	return c
+}
+
+func (r *renderer) buffer() beep.Streamer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return samplesStreamer(r.samples)
+}
@@ -0,0 +1,51 @@
+package phonical_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/faiface/beep/wav"
+
+	"phonical"
+)
+
+func TestRender(t *testing.T) {
+	if !phonical.HasBuiltinSounds() {
+		t.Skip("lite build")
+	}
+	path := filepath.Join(t.TempDir(), "ab.wav")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := phonical.Render(f, "ab"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	f, err = os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	streamer, format, err := wav.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a and b back to back, then a quarter second of silence
+	if want := 19425 + 12009 + 11025; streamer.Len() != want || format.SampleRate != 44100 {
+		t.Errorf("rendered %d samples at %d Hz, want %d at 44100 Hz", streamer.Len(), format.SampleRate, want)
+	}
+}
+
+func TestRenderChecksOptions(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "bad.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := phonical.Render(f, "ka", phonical.WithKana("kanji")); err == nil {
+		t.Error("rendered with an unknown kana script")
+	}
+}
@@ -0,0 +1,84 @@
+package phonical
+
+import (
+	"bufio"
+	_ "embed"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRhymeInterval is the least time between rhyme suggestions.
+const DefaultRhymeInterval = 30 * time.Second
+
+//go:embed data/rhymes.txt
+var rhymeData string
+
+// wordFamily is a rime and the words that share it.
+type wordFamily struct {
+	rime  string
+	words []string
+}
+
+var (
+	families     []wordFamily
+	familyOf     map[string]*wordFamily
+	familiesOnce sync.Once
+)
+
+// loadFamilies parses the bundled rhyme dictionary.
+func loadFamilies() {
+	familiesOnce.Do(func() {
+		familyOf = make(map[string]*wordFamily)
+		scanner := bufio.NewScanner(strings.NewReader(rhymeData))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			rime, words, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			families = append(families, wordFamily{
+				rime:  strings.TrimSpace(rime),
+				words: strings.Fields(words),
+			})
+		}
+		for i := range families {
+			for _, w := range families[i].words {
+				familyOf[w] = &families[i]
+			}
+		}
+	})
+}
+
+// rhymesFor returns up to n words from the dictionary that rhyme with word,
+// in random order.
+func rhymesFor(word string, n int) []string {
+	loadFamilies()
+	family, ok := familyOf[word]
+	if !ok {
+		return nil
+	}
+
+	var others []string
+	for _, w := range family.words {
+		if w != word {
+			others = append(others, w)
+		}
+	}
+	rand.Shuffle(len(others), func(i, j int) {
+		others[i], others[j] = others[j], others[i]
+	})
+	if len(others) > n {
+		others = others[:n]
+	}
+	return others
+}
+
+// rhymePhrase describes rhymes for word, e.g. "cat rhymes with hat and mat".
+func rhymePhrase(word string, rhymes []string) string {
+	return word + " rhymes with " + strings.Join(rhymes, " and ")
+}
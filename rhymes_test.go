@@ -0,0 +1,31 @@
+package phonical_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestRhymesAfterWord(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithRhymes(true, time.Minute))
+	h.Type("cat{space}")
+	h.Sink.WaitFor(4, time.Second)
+	played := h.Sink.Played()
+	if len(played) != 4 || !regexp.MustCompile(`^say:cat rhymes with ([bfhmprs]at) and ([bfhmprs]at)$`).MatchString(played[3]) {
+		t.Fatalf("played %q, want cat's letters and two rhymes", played)
+	}
+
+	// A word without rhymes says nothing, and a second rhyme waits for the
+	// interval to pass
+	h.Type("xyz{space}dog{space}")
+	h.Type("{wait 1m}dog{space}")
+	h.Sink.WaitFor(14, time.Second)
+	time.Sleep(20 * time.Millisecond)
+	played = h.Sink.Played()
+	if len(played) != 14 || !regexp.MustCompile(`^say:dog rhymes with`).MatchString(played[13]) {
+		t.Errorf("played %q, want one rhyme for dog after the minute", played[4:])
+	}
+}
@@ -0,0 +1,73 @@
+package phonical
+
+import "unicode"
+
+// arabicForms lists, from U+FE80, each base letter of the Arabic
+// presentation forms block and how many positional forms (isolated, final,
+// initial, medial) follow for it.
+var arabicForms = []struct {
+	base  rune
+	forms int
+}{
+	{'ء', 1}, {'آ', 2}, {'أ', 2}, {'ؤ', 2}, {'إ', 2},
+	{'ئ', 4}, {'ا', 2}, {'ب', 4}, {'ة', 2}, {'ت', 4},
+	{'ث', 4}, {'ج', 4}, {'ح', 4}, {'خ', 4}, {'د', 2},
+	{'ذ', 2}, {'ر', 2}, {'ز', 2}, {'س', 4}, {'ش', 4},
+	{'ص', 4}, {'ض', 4}, {'ط', 4}, {'ظ', 4}, {'ع', 4},
+	{'غ', 4}, {'ف', 4}, {'ق', 4}, {'ك', 4}, {'ل', 4},
+	{'م', 4}, {'ن', 4}, {'ه', 4}, {'و', 2}, {'ى', 2},
+	{'ي', 4},
+}
+
+// hebrewFinals maps Hebrew final letter forms to their usual form.
+var hebrewFinals = map[rune]rune{
+	'ך': 'כ', // kaf
+	'ם': 'מ', // mem
+	'ן': 'נ', // nun
+	'ף': 'פ', // pe
+	'ץ': 'צ', // tsadi
+}
+
+// letterForm returns the base letter of an Arabic positional form or a
+// Hebrew final letter, so a pack only needs one sound per letter.
+func letterForm(r rune) (rune, bool) {
+	if base, ok := hebrewFinals[r]; ok {
+		return base, true
+	}
+	if r < '\ufe80' {
+		return 0, false
+	}
+	next := rune('\ufe80')
+	for _, f := range arabicForms {
+		if r < next+rune(f.forms) {
+			return f.base, true
+		}
+		next += rune(f.forms)
+	}
+	return 0, false
+}
+
+// isSilentMark reports whether r is a vowel point, tatweel or direction
+// mark. These change how a letter looks or where text runs, not which
+// word is being typed, so they neither play nor end the word.
+func isSilentMark(r rune) bool {
+	switch {
+	case r >= '\u064b' && r <= '\u0652', // Arabic harakat
+		r == '\u0640', // tatweel
+		r >= '\u05b0' && r <= '\u05c7' && r != '\u05be' && r != '\u05c3', // Hebrew points
+		r == '\u200e', r == '\u200f', r == '\u061c': // direction marks
+		return true
+	}
+	return false
+}
+
+// isRTL reports whether word is written right to left, judged by its
+// first letter.
+func isRTL(word []rune) bool {
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			return unicode.Is(unicode.Arabic, r) || unicode.Is(unicode.Hebrew, r)
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package phonical_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// letterPack returns a pack playing name.wav for each letter, recorded
+// with the English sound for the first letter of name.
+func letterPack(t *testing.T, letters map[string]string) *phonical.Pack {
+	t.Helper()
+	dir := t.TempDir()
+	manifest := "name = \"Letters\"\n\n[letters]\n"
+	for letter, name := range letters {
+		data, err := os.ReadFile(filepath.Join("sounds", phonical.DefaultSounds, name[:1]+".wav"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name+".wav"), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		manifest += "\"" + letter + "\" = \"" + name + ".wav\"\n"
+	}
+	if err := os.WriteFile(filepath.Join(dir, phonical.ManifestName), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pack, err := phonical.LoadPack(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pack
+}
+
+func hebrewPack(t *testing.T) *phonical.Pack {
+	return letterPack(t, map[string]string{"ש": "shin", "ל": "lamed", "ו": "vav", "מ": "mem"})
+}
+
+func TestHebrewFinalsAndPoints(t *testing.T) {
+	var mu sync.Mutex
+	var words []string
+	h := phonicaltest.New(t, phonical.WithPack(hebrewPack(t)), phonical.OnWord(func(ev phonical.WordEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		words = append(words, ev.Word)
+	}))
+	// The qamats point is silent and the final mem plays as mem
+	h.Type("שלָום{space}")
+	h.Expect("shin.wav", "lamed.wav", "vav.wav", "mem.wav")
+
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"שלום"}; !reflect.DeepEqual(words, want) {
+		t.Errorf("words %q, want %q", words, want)
+	}
+}
+
+func TestArabicPresentationForms(t *testing.T) {
+	pack := letterPack(t, map[string]string{"ب": "ba", "ي": "ya"})
+	h := phonicaltest.New(t, phonical.WithPack(pack))
+	// Isolated, final, initial and medial ba, then the last form of ya
+	h.Type("ﺏﺐﺑﺒﻴ")
+	h.Expect("ba.wav", "ba.wav", "ba.wav", "ba.wav", "ya.wav")
+}
+
+func TestArrowsFollowTheScreen(t *testing.T) {
+	for _, tt := range []struct {
+		pack   *phonical.Pack
+		script string
+		sounds []string
+		word   string
+	}{
+		// In right-to-left text the right arrow steps back over lamed
+		{hebrewPack(t), "של{right}ו{space}", []string{"shin.wav", "lamed.wav", "vav.wav"}, "שול"},
+		{letterPack(t, map[string]string{"a": "a", "b": "b", "c": "c"}), "ab{left}c{space}", []string{"a.wav", "b.wav", "c.wav"}, "acb"},
+	} {
+		var mu sync.Mutex
+		var words []string
+		h := phonicaltest.New(t, phonical.WithPack(tt.pack), phonical.OnWord(func(ev phonical.WordEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			words = append(words, ev.Word)
+		}))
+		h.Type(tt.script)
+		h.Expect(tt.sounds...)
+
+		mu.Lock()
+		if want := []string{tt.word}; !reflect.DeepEqual(words, want) {
+			t.Errorf("%s: words %q, want %q", tt.script, words, want)
+		}
+		mu.Unlock()
+	}
+}
@@ -0,0 +1,72 @@
+package phonical
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// bareKey matches the TOML keys that need no quotes.
+var bareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// tomlKey quotes key when TOML needs it to.
+func tomlKey(key string) string {
+	if bareKey.MatchString(key) {
+		return key
+	}
+	return fmt.Sprintf("%q", key)
+}
+
+// ScaffoldPack starts a new pack in dir with a manifest naming a file for
+// every letter and grapheme template has, ready for the recordings to be
+// saved under those names. It won't overwrite an existing manifest.
+func ScaffoldPack(dir string, template *Pack) error {
+	path := filepath.Join(dir, ManifestName)
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	var keys []string
+	for r := range template.Letters {
+		keys = append(keys, string(r))
+	}
+	for g := range template.Graphemes {
+		keys = append(keys, g)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "name = %q\n\n", filepath.Base(dir))
+	b.WriteString("# Record each sound and save it under the name given, as WAV or MP3\n")
+	b.WriteString("# (change the extension to match). \"phonical packs check\" shows how\n")
+	b.WriteString("# many are still to do. Sounds left out use the built-in ones when the\n")
+	b.WriteString("# pack is used with --layer.\n")
+	b.WriteString("[letters]\n")
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s = %q\n", tomlKey(key), key+".wav")
+	}
+	b.WriteString("\n# Optional: uncomment to record your own cues, used with --cues.\n")
+	b.WriteString("# [cues]\n")
+	for _, cue := range []string{CueSpace, CueEnter, CueBackspace} {
+		fmt.Fprintf(&b, "# %s = %q\n", cue, cue+".wav")
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
+
+// Completion reports how many of the sounds the pack's manifest names
+// have a recording that loads, out of the total.
+func (p *Pack) Completion() (recorded, total int) {
+	total = len(p.entries())
+	return total - len(p.Validate()), total
+}
@@ -0,0 +1,46 @@
+package phonical_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"phonical"
+)
+
+func TestScaffoldPack(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "Mum")
+	template := letterPack(t, map[string]string{"a": "a", "b": "b"})
+	if err := phonical.ScaffoldPack(dir, template); err != nil {
+		t.Fatal(err)
+	}
+	if err := phonical.ScaffoldPack(dir, template); err == nil {
+		t.Error("scaffolded over an existing manifest")
+	}
+
+	pack, err := phonical.LoadPack(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pack.Name != "Mum" || pack.Letters['a'] != "a.wav" || pack.Letters['b'] != "b.wav" {
+		t.Errorf("scaffolded %q with letters %q", pack.Name, pack.Letters)
+	}
+	if recorded, total := pack.Completion(); recorded != 0 || total != 2 {
+		t.Errorf("new pack has %d of %d recorded, want 0 of 2", recorded, total)
+	}
+
+	// Recording a file counts towards completion
+	data, err := os.ReadFile(filepath.Join("sounds", phonical.DefaultSounds, "a.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.wav"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if pack, err = phonical.LoadPack(dir); err != nil {
+		t.Fatal(err)
+	}
+	if recorded, total := pack.Completion(); recorded != 1 || total != 2 {
+		t.Errorf("pack has %d of %d recorded, want 1 of 2", recorded, total)
+	}
+}
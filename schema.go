@@ -0,0 +1,166 @@
+package phonical
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// FileError is a problem found in a configuration, pack or lesson file.
+type FileError struct {
+	File string
+	// Line is 1-based, or 0 when the problem isn't tied to one line.
+	Line int
+	Msg  string
+}
+
+func (e FileError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+	}
+	return fmt.Sprintf("%s: %s", e.File, e.Msg)
+}
+
+// FileErrors lists every problem found in a file, so they can all be fixed
+// in one go.
+type FileErrors []FileError
+
+func (errs FileErrors) Error() string {
+	lines := make([]string, len(errs))
+	for i, e := range errs {
+		lines[i] = e.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// schema decodes a TOML file strictly and collects the problems found while
+// checking it, each with the line it came from.
+type schema struct {
+	file  string
+	lines []string
+	errs  FileErrors
+}
+
+// decodeSchema decodes src into v. Syntax errors and values of the wrong
+// type are returned straight away; unknown keys are recorded so checking
+// can continue.
+func decodeSchema(file, src string, v any) (*schema, error) {
+	s := &schema{file: file, lines: strings.Split(src, "\n")}
+	md, err := toml.Decode(src, v)
+	if err != nil {
+		var perr toml.ParseError
+		if errors.As(err, &perr) {
+			return nil, FileErrors{{File: file, Line: perr.Position.Line, Msg: perr.Message}}
+		}
+		return nil, FileErrors{{File: file, Msg: err.Error()}}
+	}
+	var unknown []toml.Key
+	for _, key := range md.Undecoded() {
+		// Only report the outermost unknown key of an unknown table
+		if len(unknown) > 0 && isPrefix(unknown[len(unknown)-1], key) {
+			continue
+		}
+		unknown = append(unknown, key)
+		s.errorf(key, "unknown key %q", key.String())
+	}
+	return s, nil
+}
+
+// errorf records a problem with key.
+func (s *schema) errorf(key toml.Key, format string, args ...any) {
+	s.errs = append(s.errs, FileError{File: s.file, Line: s.line(key), Msg: fmt.Sprintf(format, args...)})
+}
+
+// tableErrorf records a problem with the n-th (0-based) [[table]] entry.
+func (s *schema) tableErrorf(table string, n int, format string, args ...any) {
+	line := 0
+	header := "[[" + table + "]]"
+	for i, l := range s.lines {
+		if strings.TrimSpace(stripComment(l)) == header {
+			if n == 0 {
+				line = i + 1
+				break
+			}
+			n--
+		}
+	}
+	s.errs = append(s.errs, FileError{File: s.file, Line: line, Msg: fmt.Sprintf(format, args...)})
+}
+
+// err returns the problems recorded, or nil.
+func (s *schema) err() error {
+	if len(s.errs) == 0 {
+		return nil
+	}
+	sort.SliceStable(s.errs, func(i, j int) bool { return s.errs[i].Line < s.errs[j].Line })
+	return s.errs
+}
+
+// line finds where key is defined by following table headers. It
+// understands the plain tables and keys used by phonical's files; anything
+// fancier gives 0.
+func (s *schema) line(key toml.Key) int {
+	var table []string
+	for i, l := range s.lines {
+		l = strings.TrimSpace(stripComment(l))
+		if strings.HasPrefix(l, "[") {
+			table = splitKey(strings.Trim(l, "[]"))
+			if equalKey(table, key) {
+				return i + 1
+			}
+			continue
+		}
+		name, _, ok := strings.Cut(l, "=")
+		if !ok {
+			continue
+		}
+		if equalKey(append(table[:len(table):len(table)], splitKey(name)...), key) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// splitKey splits a dotted TOML key, removing quotes.
+func splitKey(key string) []string {
+	var parts []string
+	for _, part := range strings.Split(key, ".") {
+		parts = append(parts, strings.Trim(strings.TrimSpace(part), `"'`))
+	}
+	return parts
+}
+
+func isPrefix(prefix, key toml.Key) bool {
+	return len(prefix) < len(key) && equalKey(prefix, key[:len(prefix)])
+}
+
+func equalKey(parts []string, key toml.Key) bool {
+	if len(parts) != len(key) {
+		return false
+	}
+	for i := range parts {
+		if parts[i] != key[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// stripComment removes a trailing # comment outside quotes.
+func stripComment(line string) string {
+	quote := rune(0)
+	for i, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+		case quote == 0 && r == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
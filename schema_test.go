@@ -0,0 +1,49 @@
+package phonical_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"phonical"
+)
+
+func TestSchemaErrorsWithLines(t *testing.T) {
+	_, err := writeLesson(t, `titel = "Lesson 1" # spelt wrong
+
+[[target]]
+letter = "s"
+word = "sat"
+
+[extra]
+colour = "red"
+size = 3
+`)
+	var errs phonical.FileErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error %v, want FileErrors", err)
+	}
+	// Every problem is listed in file order, and an unknown table is
+	// reported once rather than key by key
+	var got []string
+	for _, e := range errs {
+		got = append(got, strings.TrimPrefix(e.Error(), e.File))
+	}
+	want := []string{
+		`:1: unknown key "titel"`,
+		":3: target 1: set exactly one of letter, word or digraph",
+		`:7: unknown key "extra"`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("errors %q, want %q", got, want)
+	}
+}
+
+func TestSchemaSyntaxError(t *testing.T) {
+	_, err := writeLesson(t, "[[target]]\nletter = \"s\"\nword = \n")
+	var errs phonical.FileErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Line != 3 {
+		t.Errorf("error %v, want one on line 3", err)
+	}
+}
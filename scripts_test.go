@@ -0,0 +1,83 @@
+package phonical_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/faiface/beep"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// digraphPack is a pack with a recording of sh as well as its letters.
+func digraphPack(t *testing.T) *phonical.Pack {
+	t.Helper()
+	dir := t.TempDir()
+	manifest := "name = \"Digraphs\"\n\n[letters]\n"
+	for _, name := range []string{"s", "h", "i", "p", "sh"} {
+		data, err := os.ReadFile(filepath.Join("sounds", phonical.DefaultSounds, name[:1]+".wav"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name+".wav"), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		manifest += name + " = \"" + name + ".wav\"\n"
+	}
+	if err := os.WriteFile(filepath.Join(dir, phonical.ManifestName), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pack, err := phonical.LoadPack(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pack
+}
+
+func TestScriptDigraphs(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithPack(digraphPack(t)), phonical.WithMode(phonical.ModeBlend))
+	// Typing the h of sh plays the digraph, and blending sounds it once
+	h.Type("ship{space}")
+	h.Expect("s.wav", "sh.wav", "i.wav", "p.wav",
+		"sh.wav", "i.wav", "p.wav", "blend:ship", "say:ship")
+}
+
+func TestScriptBlend(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithMode(phonical.ModeBlend))
+	h.Type("cat{space}")
+	h.Expect("c.wav", "a.wav", "t.wav",
+		"c.wav", "a.wav", "t.wav", "blend:cat", "say:cat")
+}
+
+func TestScriptRepeats(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithRepeatCoalescing(200*time.Millisecond, true))
+	// b is pressed three times while a plays, then once more after the
+	// window has passed
+	h.Sink.Hold()
+	h.Type("a{wait 50ms}b{wait 50ms}b{wait 50ms}b{wait 300ms}b")
+	h.Sink.Release()
+	h.Expect("a.wav", "b.wav", "say:b, three times", "b.wav")
+}
+
+func TestScriptModifiers(t *testing.T) {
+	// Shift and Ctrl on their own are silent, and a capital plays its letter
+	h := phonicaltest.New(t)
+	h.Type("{code 0x2a}A{code 0x1d}")
+	h.Expect("a.wav")
+}
+
+// samples counts the samples left in s.
+func samples(s beep.Streamer) int {
+	buf := make([][2]float64, 512)
+	total := 0
+	for {
+		n, ok := s.Stream(buf)
+		total += n
+		if !ok {
+			return total
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package phonical
+
+import (
+	"context"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/speaker"
+)
+
+// Playback is one sound handed to an AudioSink.
+type Playback struct {
+	// Name identifies the sound: its pack file, or a label such as
+	// "cue:space", "blend:cat" or "clap" for generated sounds.
+	Name string
+	// Letter is the letter the sound stands for, if any.
+	Letter   rune
+	Streamer beep.Streamer
+}
+
+// AudioSink is where the engine sends sound. The default plays through the
+// speaker; tests substitute a silent sink that records what was played.
+type AudioSink interface {
+	// Init prepares the device. It is called before the first sound and
+	// again, after Close, when recovering from a failed Play.
+	Init() error
+	// Play plays p and blocks until it finishes or ctx is cancelled.
+	Play(ctx context.Context, p Playback) error
+	// Layer mixes s into whatever is playing without waiting, for key
+	// clicks.
+	Layer(s beep.Streamer)
+	// Close releases the device.
+	Close()
+}
+
+// Clock tells the time in the playback path: queue timestamps, coalescing
+// windows, pauses and latency. Tests substitute a virtual clock so they
+// don't have to wait.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// WithAudioSink sends sound to sink instead of the speaker.
+func WithAudioSink(sink AudioSink) Option {
+	return func(e *Engine) {
+		e.sink = sink
+	}
+}
+
+// WithClock sets the clock used for playback timing. Defaults to the
+// system clock.
+func WithClock(c Clock) Option {
+	return func(e *Engine) {
+		e.clock = c
+	}
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// speakerSink plays through the system audio device.
+type speakerSink struct {
+	buffer time.Duration
+}
+
+func (s speakerSink) Init() error { return initSpeaker(s.buffer) }
+
+func (speakerSink) Play(ctx context.Context, p Playback) error {
+	return playStreamer(ctx, p.Streamer)
+}
+
+func (speakerSink) Layer(s beep.Streamer) { speaker.Play(s) }
+
+func (speakerSink) Close() { closeSpeaker() }
@@ -0,0 +1,106 @@
+package phonical
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// Modifier is a set of modifier keys, as held during a key press.
+type Modifier uint16
+
+// Modifiers, each covering the left and right keys, as the keyboard hook
+// reports them.
+const (
+	ModShift Modifier = 1<<0 | 1<<4
+	ModCtrl  Modifier = 1<<1 | 1<<5
+	ModMeta  Modifier = 1<<2 | 1<<6
+	ModAlt   Modifier = 1<<3 | 1<<7
+)
+
+// modifierNames are the names ParseModifier accepts.
+var modifierNames = map[string]Modifier{
+	"shift":   ModShift,
+	"ctrl":    ModCtrl,
+	"control": ModCtrl,
+	"alt":     ModAlt,
+	"option":  ModAlt,
+	"meta":    ModMeta,
+	"cmd":     ModMeta,
+	"super":   ModMeta,
+	"win":     ModMeta,
+}
+
+// ParseModifier converts a modifier name such as "alt" into a Modifier.
+func ParseModifier(name string) (Modifier, error) {
+	if mod, ok := modifierNames[strings.ToLower(name)]; ok {
+		return mod, nil
+	}
+	return 0, fmt.Errorf("unknown modifier %q (want shift, ctrl, alt or meta)", name)
+}
+
+// slowMotionStretch is how much slower letters play in slow motion.
+const slowMotionStretch = 2.5
+
+// slowLevel is the peak slowed sounds are brought up to, so the stretched
+// parts stay easy to hear.
+const slowLevel = 0.9
+
+// WithSlowMotion plays a letter in slow motion when mod is held as it is
+// pressed: stretched to several times its length without changing pitch,
+// so a child can hear the parts of the sound. It needs the keyboard hook,
+// which reports modifiers; 0 turns it off.
+func WithSlowMotion(mod Modifier) Option {
+	return func(e *Engine) {
+		e.slowMod = mod
+	}
+}
+
+// slowMotion reports whether key was pressed with the slow-motion modifier
+// held. The modifier can change the character typed, such as Ctrl+A
+// sending a control code, so key is given the letter on its key instead.
+func (e *Engine) slowMotion(key *Key) bool {
+	if e.slowMod == 0 || key.Mods&e.slowMod == 0 {
+		return false
+	}
+	if e.slowMod != ModShift {
+		if c, ok := fallbackChar(Key{Code: key.Code, Rawcode: key.Rawcode}); ok {
+			key.Char = c
+		}
+	}
+	return true
+}
+
+// slowCache keeps the slowed version of each sound, made once.
+type slowCache struct {
+	mu      sync.Mutex
+	buffers map[slowSound]*beep.Buffer
+}
+
+type slowSound struct {
+	buffer  *beep.Buffer
+	stretch float64
+}
+
+// get returns buffer stretched by stretch and brought up in level.
+func (c *slowCache) get(buffer *beep.Buffer, stretch float64) *beep.Buffer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := slowSound{buffer, stretch}
+	if slow, ok := c.buffers[key]; ok {
+		return slow
+	}
+	samples := timeStretch(trimSilence(pcm(buffer), silenceLevel), stretch)
+	normalize(samples, slowLevel)
+	rate := outputFormat.SampleRate
+	fade(samples, rate.N(5*time.Millisecond), rate.N(blendFade))
+	slow := pcmBuffer(samples)
+	if c.buffers == nil {
+		c.buffers = make(map[slowSound]*beep.Buffer)
+	}
+	c.buffers[key] = slow
+	return slow
+}
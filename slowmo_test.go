@@ -0,0 +1,75 @@
+package phonical
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseModifier(t *testing.T) {
+	for name, want := range map[string]Modifier{
+		"shift": ModShift, "Ctrl": ModCtrl, "control": ModCtrl,
+		"ALT": ModAlt, "option": ModAlt, "cmd": ModMeta, "win": ModMeta,
+	} {
+		if got, err := ParseModifier(name); err != nil || got != want {
+			t.Errorf("ParseModifier(%q) = %v, %v, want %v", name, got, err, want)
+		}
+	}
+	for _, name := range []string{"", "hyper", "ctrl+alt"} {
+		if _, err := ParseModifier(name); err == nil {
+			t.Errorf("ParseModifier(%q) accepted", name)
+		}
+	}
+}
+
+func TestSlowMotionStretch(t *testing.T) {
+	// A fifth of a second of steady tone, with no silence to trim
+	tone := make([][2]float64, outputFormat.SampleRate.N(200e6))
+	for i := range tone {
+		s := 0.3 * math.Sin(2*math.Pi*440*float64(i)/float64(outputFormat.SampleRate))
+		tone[i] = [2]float64{s, s}
+	}
+	buffer := pcmBuffer(tone)
+
+	var cache slowCache
+	slow := cache.get(buffer, slowMotionStretch)
+	// Less the few quiet samples at either end, where the tone starts
+	// and stops at zero
+	if want := int(float64(len(tone)) * slowMotionStretch); slow.Len() > want || slow.Len() < want-20 {
+		t.Errorf("slowed to %d samples, want about %d", slow.Len(), want)
+	}
+	var peak float64
+	for _, s := range pcm(slow) {
+		peak = math.Max(peak, math.Abs(s[0]))
+	}
+	if math.Abs(peak-slowLevel) > 0.01 {
+		t.Errorf("slowed peak %.3f, want %.1f", peak, slowLevel)
+	}
+	if cache.get(buffer, slowMotionStretch) != slow {
+		t.Error("slowed the same sound twice")
+	}
+	if cache.get(buffer, 2) == slow {
+		t.Error("a different stretch gave the same sound")
+	}
+}
+
+func TestSlowMotionKeepsTheLetter(t *testing.T) {
+	for _, mod := range []Modifier{ModCtrl, ModAlt} {
+		e, err := New(WithSlowMotion(mod))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Ctrl+A types a control code and Option+A on a Mac types å, but
+		// the letter on the key is the one played
+		for _, typed := range []rune{0x01, 'å'} {
+			key := Key{Char: typed, Code: 0x1E, Mods: mod}
+			if !e.slowMotion(&key) || key.Char != 'a' {
+				t.Errorf("%q with modifier %#x: slowed %v as %q, want a slowed", typed, mod, e.slowMotion(&key), key.Char)
+			}
+		}
+		// Without the modifier the key plays as typed
+		key := Key{Char: 'a', Code: 0x1E}
+		if e.slowMotion(&key) {
+			t.Errorf("a without modifier %#x slowed", mod)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package phonical
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/mp3"
+	"github.com/faiface/beep/speaker"
+	"github.com/faiface/beep/wav"
+)
+
+// Errors for sounds that can't be loaded, returned wrapped with the file's
+// name.
+var (
+	// ErrSoundMissing is for a sound file the pack names but doesn't have.
+	ErrSoundMissing = errors.New("phonical: sound file missing")
+	// ErrUnsupportedFormat is for a sound file that isn't a WAV or MP3
+	// recording Phonical can decode.
+	ErrUnsupportedFormat = errors.New("phonical: unsupported sound format")
+)
+
+var (
+	speakerInitialized bool
+	speakerMutex       sync.Mutex
+	// soundCache maps a pack file to its decoded sound. soundsByHash holds
+	// the same buffers keyed by the recording's SHA-256, so packs,
+	// languages and profiles that share a recording share its memory.
+	soundCache      = make(map[soundKey]*beep.Buffer)
+	soundsByHash    = make(map[soundKey]*beep.Buffer)
+	soundCacheMutex sync.RWMutex
+)
+
+// soundKey identifies a decoded sound in memory: a pack file or a
+// recording's hash, and whether it was downmixed, as engines in one
+// process may differ.
+type soundKey struct {
+	file string
+	sum  [sha256.Size]byte
+	mono bool
+}
+
+// decoding is how an engine decodes sounds.
+type decoding struct {
+	// mono downmixes sounds to mono as they are decoded.
+	mono bool
+	// disk, when set, keeps decoded sounds on disk between runs.
+	disk *pcmCache
+}
+
+var outputFormat = beep.Format{
+	SampleRate:  44100,
+	NumChannels: 2,
+	Precision:   2,
+}
+
+// speakerBuffer is the usual speaker buffer, kept small for low latency.
+const speakerBuffer = time.Second / 60
+
+func initSpeaker(buffer time.Duration) error {
+	speakerMutex.Lock()
+	defer speakerMutex.Unlock()
+
+	if speakerInitialized {
+		return nil
+	}
+
+	err := speaker.Init(outputFormat.SampleRate, outputFormat.SampleRate.N(buffer))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNoAudioDevice, err)
+	}
+
+	speakerInitialized = true
+	return nil
+}
+
+// loadSound decodes soundPath from fsys as dec says, caching the buffer
+// under key. A recording already decoded under another key is reused.
+func loadSound(fsys fs.FS, key, soundPath string, dec decoding) (*beep.Buffer, beep.Format, error) {
+	fileKey := soundKey{file: key, mono: dec.mono}
+	soundCacheMutex.RLock()
+	if buffer, exists := soundCache[fileKey]; exists {
+		soundCacheMutex.RUnlock()
+		return buffer, buffer.Format(), nil
+	}
+	soundCacheMutex.RUnlock()
+
+	data, err := fs.ReadFile(fsys, soundPath)
+	if err != nil {
+		return nil, beep.Format{}, soundFileError(soundPath, err)
+	}
+	sum := sha256.Sum256(data)
+	hashKey := soundKey{sum: sum, mono: dec.mono}
+	soundCacheMutex.Lock()
+	buffer, shared := soundsByHash[hashKey]
+	if shared {
+		soundCache[fileKey] = buffer
+	}
+	soundCacheMutex.Unlock()
+	if shared {
+		return buffer, buffer.Format(), nil
+	}
+
+	cache := dec.disk
+	if cache != nil {
+		buffer = cache.load(sum)
+	}
+	if buffer == nil {
+		if buffer, err = decodeSound(soundPath, data); err != nil {
+			return nil, beep.Format{}, err
+		}
+		if cache != nil {
+			cache.store(sum, buffer)
+		}
+	}
+	if dec.mono {
+		buffer = downmix(buffer)
+	}
+
+	soundCacheMutex.Lock()
+	if first, ok := soundsByHash[hashKey]; ok {
+		// Decoded meanwhile under another key; keep one copy
+		buffer = first
+	}
+	soundsByHash[hashKey] = buffer
+	soundCache[fileKey] = buffer
+	soundCacheMutex.Unlock()
+
+	return buffer, buffer.Format(), nil
+}
+
+// decodeSound decodes the MP3 or WAV recording data.
+func decodeSound(soundPath string, data []byte) (*beep.Buffer, error) {
+	var streamer beep.StreamSeekCloser
+	var format beep.Format
+	var err error
+
+	if strings.HasSuffix(soundPath, ".mp3") {
+		streamer, format, err = mp3.Decode(io.NopCloser(bytes.NewReader(data)))
+	} else if strings.HasSuffix(soundPath, ".wav") {
+		streamer, format, err = wav.Decode(bytes.NewReader(data))
+	} else {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, soundPath)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrUnsupportedFormat, soundPath, err)
+	}
+	defer streamer.Close()
+
+	buffer := beep.NewBuffer(format)
+	buffer.Append(streamer)
+	return buffer, nil
+}
+
+// soundFileError wraps err, from reading soundPath, in the matching
+// sentinel error.
+func soundFileError(soundPath string, err error) error {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("%w: %s", ErrSoundMissing, soundPath)
+	case errors.Is(err, fs.ErrPermission):
+		return denied(fmt.Errorf("can't read %s: %w", soundPath, err))
+	}
+	return err
+}
+
+// playBuffer plays buffer on the speaker and blocks until it finishes.
+func playBuffer(ctx context.Context, buffer *beep.Buffer) error {
+	return playStreamer(ctx, buffer.Streamer(0, buffer.Len()))
+}
+
+// playStreamer plays streamer on the speaker and blocks until it finishes
+// or ctx is cancelled, returning errSpeakerStalled if the speaker stops
+// pulling samples.
+func playStreamer(ctx context.Context, streamer beep.Streamer) error {
+	done := make(chan struct{})
+	watched := &watchedStreamer{Streamer: streamer, ctx: ctx}
+	watched.last.Store(time.Now().UnixNano())
+	speaker.Play(beep.Seq(watched, beep.Callback(func() {
+		close(done)
+	})))
+
+	ticker := time.NewTicker(stallTimeout / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return ctx.Err()
+		case <-ticker.C:
+			if watched.idle() > stallTimeout {
+				return errSpeakerStalled
+			}
+		}
+	}
+}
+
+// cachedSoundCount returns the number of distinct recordings decoded.
+func cachedSoundCount() int {
+	soundCacheMutex.RLock()
+	defer soundCacheMutex.RUnlock()
+	return len(soundsByHash)
+}
+
+// cachedSoundBytes estimates the memory held by decoded sounds.
+func cachedSoundBytes() int64 {
+	soundCacheMutex.RLock()
+	defer soundCacheMutex.RUnlock()
+	var total int64
+	for _, buffer := range soundsByHash {
+		// Buffers keep samples encoded at the format's precision
+		total += int64(buffer.Len() * buffer.Format().Width())
+	}
+	return total
+}
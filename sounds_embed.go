@@ -0,0 +1,15 @@
+//go:build !lite
+
+package phonical
+
+import "embed"
+
+// soundFiles holds the built-in sound groups, sounds/<lang>/<voice>/<mode>,
+// each a pack with its own pack.toml.
+//
+//go:embed sounds
+var soundFiles embed.FS
+
+// builtinSounds reports whether the letter recordings are compiled in.
+// Build with -tags lite to leave them out and load packs instead.
+const builtinSounds = true
@@ -0,0 +1,10 @@
+//go:build lite
+
+package phonical
+
+import "embed"
+
+// soundFiles is empty in lite builds; sounds come from packs.
+var soundFiles embed.FS
+
+const builtinSounds = false
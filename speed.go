@@ -0,0 +1,97 @@
+package phonical
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// speedWindow is how far back typing speed is measured.
+	speedWindow = time.Minute
+	// speedMinSpan keeps a short burst of keys from reading as a huge speed.
+	speedMinSpan = 10 * time.Second
+	// speedStep is the gap between announced milestones, in characters per
+	// minute.
+	speedStep = 10
+)
+
+// WithSpeedFeedback announces typing-speed milestones ("forty letters a
+// minute!") between words, at most once per interval, for older children
+// using Phonical as a gentle typing tutor.
+func WithSpeedFeedback(interval time.Duration) Option {
+	return func(e *Engine) {
+		e.speedInterval = interval
+	}
+}
+
+// speedTracker measures characters per minute over a sliding window.
+type speedTracker struct {
+	presses   []time.Time
+	milestone int
+	announced time.Time
+}
+
+// press records a typed character.
+func (s *speedTracker) press(now time.Time) {
+	s.presses = append(s.presses, now)
+	cut := 0
+	for cut < len(s.presses) && now.Sub(s.presses[cut]) > speedWindow {
+		cut++
+	}
+	s.presses = s.presses[cut:]
+}
+
+// cpm returns the current characters per minute.
+func (s *speedTracker) cpm(now time.Time) float64 {
+	if len(s.presses) < 2 {
+		return 0
+	}
+	span := now.Sub(s.presses[0])
+	if span < speedMinSpan {
+		span = speedMinSpan
+	}
+	return float64(len(s.presses)) / span.Minutes()
+}
+
+// TypingSpeed returns the current typing speed in characters per minute,
+// measured over the last minute.
+func (e *Engine) TypingSpeed() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.speed.cpm(e.clock.Now())
+}
+
+// announceSpeed praises a new speed milestone, rate limited by the speed
+// interval.
+func (e *Engine) announceSpeed(now time.Time) {
+	if e.speedInterval <= 0 {
+		return
+	}
+	e.mu.Lock()
+	cpm := int(e.speed.cpm(now))
+	milestone := cpm / speedStep * speedStep
+	due := now.Sub(e.speed.announced) >= e.speedInterval
+	if milestone <= e.speed.milestone || milestone == 0 || !due {
+		e.mu.Unlock()
+		return
+	}
+	e.speed.milestone = milestone
+	e.speed.announced = now
+	e.mu.Unlock()
+
+	e.debugf("Typing speed: %d characters a minute\n", cpm)
+	e.praise(fmt.Sprintf("Great typing! %s letters a minute!", speedWords(milestone)))
+}
+
+// speedWords spells out a milestone so it is spoken naturally.
+func speedWords(n int) string {
+	tens := []string{"", "ten", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	switch {
+	case n < 100:
+		return tens[n/10]
+	case n%100 == 0:
+		return numberWord(n/100) + " hundred"
+	default:
+		return numberWord(n/100) + " hundred and " + tens[n%100/10]
+	}
+}
@@ -0,0 +1,35 @@
+package phonical_test
+
+import (
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestSpeedMilestones(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithSpeedFeedback(time.Minute))
+	// Three letters at once count as three in ten seconds: 18 a minute
+	h.Type("abc{space}")
+	// A faster word within the minute waits for the interval
+	h.Type("{wait 5s}abcd{space}")
+	h.Type("{wait 2m}abcdefgh{space}")
+
+	var want []string
+	for _, word := range []string{"abc", "abcd", "abcdefgh"} {
+		for _, c := range word {
+			want = append(want, string(c)+".wav")
+		}
+		switch word {
+		case "abc":
+			want = append(want, "say:Great typing! ten letters a minute!")
+		case "abcdefgh":
+			want = append(want, "say:Great typing! forty letters a minute!")
+		}
+	}
+	h.Expect(want...)
+	if speed := h.Engine.TypingSpeed(); speed != 48 {
+		t.Errorf("typing speed %g, want 48", speed)
+	}
+}
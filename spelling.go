@@ -0,0 +1,205 @@
+package phonical
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// spellingTimeout is how long the spelling bee waits for each letter.
+	spellingTimeout = 20 * time.Second
+	// spellingTries is how many goes the child gets at each word.
+	spellingTries = 2
+	// spellingMastery is how many correct spellings in a row master a word.
+	spellingMastery = 3
+)
+
+// WordMastery is a word's spelling history in Stats.
+type WordMastery struct {
+	Correct  int       `json:"correct"`
+	Attempts int       `json:"attempts"`
+	Streak   int       `json:"streak"`
+	Last     time.Time `json:"last"`
+}
+
+// Mastered reports whether the word has been spelled right enough times
+// in a row.
+func (m *WordMastery) Mastered() bool {
+	return m != nil && m.Streak >= spellingMastery
+}
+
+// SpellingResult is how a spelling bee went.
+type SpellingResult struct {
+	Correct int
+	Total   int
+}
+
+// ParseWordList reads one word per line, skipping blank lines and lines
+// starting with #.
+func ParseWordList(text string) []string {
+	var words []string
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, strings.ToLower(line))
+	}
+	return words
+}
+
+// LoadWordList reads a word list file in the form ParseWordList accepts.
+func LoadWordList(filename string) ([]string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	words := ParseWordList(string(data))
+	if len(words) == 0 {
+		return nil, fmt.Errorf("%s has no words", filename)
+	}
+	return words, nil
+}
+
+// SoundOut plays word one grapheme at a time and then blended, as
+// ModeBlend does after a typed word.
+func (t *Tutor) SoundOut(word string) {
+	e := t.e
+	for _, g := range e.pack.Load().segment(word) {
+		e.enqueueUnit(g)
+	}
+	e.enqueuePause(segmentGap)
+	if buffer := e.blendWord(word); buffer != nil {
+		e.playAndWait(&queuedSound{sound: "blend:" + word, buffer: buffer})
+	} else {
+		e.playAndWait(&queuedSound{sound: "pause", pause: segmentGap})
+	}
+}
+
+// SpellingBee returns an activity that speaks each word in words and has
+// the child type it, ending with space or Enter. What was typed is sounded
+// out before it is marked, so the child hears their own spelling. Words
+// not yet mastered come first, and each attempt is recorded in the
+// engine's stats. The result is passed to done.
+func SpellingBee(words []string, done func(SpellingResult)) Activity {
+	return ActivityFunc(func(t *Tutor) error {
+		words = spellingOrder(t.Stats(), words)
+		result := SpellingResult{Total: len(words)}
+		t.Say("Spelling bee! Listen to each word, type it, then press space.")
+
+		for _, word := range words {
+			correct := false
+			for try := 0; try < spellingTries && !correct; try++ {
+				if try == 0 {
+					t.Say(fmt.Sprintf("Spell %s.", word))
+				} else {
+					t.Say(fmt.Sprintf("Try again. Spell %s.", word))
+				}
+				typed, err := readSpelling(t)
+				if err != nil {
+					return err
+				}
+				if typed != "" {
+					t.SoundOut(typed)
+				}
+				correct = typed == word
+			}
+
+			if stats := t.Stats(); stats != nil {
+				now := t.e.clock.Now()
+				stats.Update(func(st *Stats) { st.recordSpelling(word, correct, now) })
+			}
+			if correct {
+				result.Correct++
+				t.Say("Correct!")
+			} else {
+				t.Say(fmt.Sprintf("%s is spelled %s.", word, strings.Join(strings.Split(word, ""), " ")))
+			}
+			t.Pause(500 * time.Millisecond)
+		}
+
+		t.Say(fmt.Sprintf("You spelled %s out of %s.", numberWord(result.Correct), numberWord(result.Total)))
+		if done != nil {
+			done(result)
+		}
+		return nil
+	})
+}
+
+// readSpelling collects letters until space, Enter or a pause, playing each
+// one as it is typed. Backspace removes the last letter.
+func readSpelling(t *Tutor) (string, error) {
+	var typed []rune
+	for {
+		r, ok := t.NextKey(spellingTimeout)
+		if t.Stopped() {
+			return "", ErrStopped
+		}
+		switch {
+		case !ok || r == ' ' || r == '\n' || r == '\r':
+			if ok && len(typed) == 0 {
+				continue
+			}
+			return string(typed), nil
+		case r == '\b' || r == 0x7f:
+			if len(typed) > 0 {
+				typed = typed[:len(typed)-1]
+			}
+		case unicode.IsLetter(r) || r == '\'':
+			t.Play(r)
+			typed = append(typed, r)
+		}
+	}
+}
+
+// spellingOrder shuffles words, putting those not yet mastered first.
+func spellingOrder(stats *StatsStore, words []string) []string {
+	words = append([]string(nil), words...)
+	rand.Shuffle(len(words), func(i, j int) { words[i], words[j] = words[j], words[i] })
+	if stats == nil {
+		return words
+	}
+	st := stats.Snapshot()
+	var fresh, mastered []string
+	for _, w := range words {
+		if st.Spelling[w].Mastered() {
+			mastered = append(mastered, w)
+		} else {
+			fresh = append(fresh, w)
+		}
+	}
+	return append(fresh, mastered...)
+}
+
+// recordSpelling updates word's mastery after an attempt.
+func (st *Stats) recordSpelling(word string, correct bool, now time.Time) {
+	st.Spelling = recordMastery(st.Spelling, word, correct, now)
+}
+
+// recordMastery updates key's entry in table after an attempt, creating
+// the table and entry as needed, and returns table.
+func recordMastery(table map[string]*WordMastery, key string, correct bool, now time.Time) map[string]*WordMastery {
+	if table == nil {
+		table = make(map[string]*WordMastery)
+	}
+	m := table[key]
+	if m == nil {
+		m = &WordMastery{}
+		table[key] = m
+	}
+	m.Attempts++
+	m.Last = now
+	if correct {
+		m.Correct++
+		m.Streak++
+	} else {
+		m.Streak = 0
+	}
+	return table
+}
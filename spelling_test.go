@@ -0,0 +1,69 @@
+package phonical_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// spell types word a letter at a time, waiting for each to be played back
+// since the bee doesn't take keys while it is talking, then presses space.
+func spell(t *testing.T, h *phonicaltest.Harness, word string, played int) int {
+	t.Helper()
+	for _, c := range word {
+		answer(h, string(c))
+		played++
+		waitPlayed(t, h, played)
+	}
+	answer(h, "{space}")
+	return played
+}
+
+func TestSpellingBee(t *testing.T) {
+	stats, err := phonical.OpenStats(filepath.Join(t.TempDir(), "stats.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := phonicaltest.New(t, phonical.WithStats(stats), phonical.WithWatchdog(0))
+	results := make(chan phonical.SpellingResult, 1)
+	done := runActivity(h, phonical.SpellingBee([]string{"at"}, func(r phonical.SpellingResult) { results <- r }))
+
+	// A wrong spelling is sounded out and gets a second try
+	waitPlayed(t, h, 2)
+	n := spell(t, h, "it", 2)
+	waitPlayed(t, h, n+4)
+	n = spell(t, h, "at", n+4)
+	played := waitPlayed(t, h, n+5)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"say:Spelling bee! Listen to each word, type it, then press space.",
+		"say:Spell at.",
+		"i.wav", "t.wav", "i.wav", "t.wav", "blend:it",
+		"say:Try again. Spell at.",
+		"a.wav", "t.wav", "a.wav", "t.wav", "blend:at",
+		"say:Correct!",
+		"say:You spelled one out of one.",
+	}
+	if !reflect.DeepEqual(played, want) {
+		t.Errorf("played %q, want %q", played, want)
+	}
+	if r := <-results; r != (phonical.SpellingResult{Correct: 1, Total: 1}) {
+		t.Errorf("result %+v, want one of one", r)
+	}
+	if m := stats.Snapshot().Spelling["at"]; m == nil || m.Attempts != 1 || m.Streak != 1 {
+		t.Errorf("mastery of at %+v, want one attempt on a streak of one", m)
+	}
+}
+
+func TestParseWordList(t *testing.T) {
+	words := phonical.ParseWordList("# Week 3\nCat\n\n  ship \n# end\n")
+	if want := []string{"cat", "ship"}; !reflect.DeepEqual(words, want) {
+		t.Errorf("words %q, want %q", words, want)
+	}
+}
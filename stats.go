@@ -0,0 +1,236 @@
+package phonical
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Stats is the persistent record of a child's practice.
+type Stats struct {
+	// Letters counts presses of each letter.
+	Letters map[string]int `json:"letters"`
+	// Confusions counts listening-game mistakes keyed "heard>pressed",
+	// e.g. "b>p" when b was played and p was pressed.
+	Confusions map[string]int `json:"confusions"`
+	// Pairs records minimal-pair rounds keyed by pair, e.g. "b/p".
+	Pairs map[string]*Score `json:"pairs"`
+	// Focus is the current letter of the day, when rotation is in use.
+	Focus *FocusState `json:"focus,omitempty"`
+	// Reviews is each letter's spaced-repetition schedule.
+	Reviews map[string]*ReviewState `json:"reviews,omitempty"`
+	// Seen records when each letter was first pressed.
+	Seen map[string]time.Time `json:"seen,omitempty"`
+	// Lessons records results keyed by lesson title.
+	Lessons map[string]*LessonRecord `json:"lessons,omitempty"`
+	// Nonsense tallies nonsense-word checks.
+	Nonsense Score `json:"nonsense"`
+	// Spelling is each spelling bee word's history.
+	Spelling map[string]*WordMastery `json:"spelling,omitempty"`
+	// Families is each word family's drill history, keyed by rime.
+	Families map[string]*WordMastery `json:"families,omitempty"`
+	// HighScores is each game's best scores, highest first.
+	HighScores map[string][]HighScore `json:"high_scores,omitempty"`
+	// Missing counts characters typed that the pack had no sound for,
+	// when WithUnknownChars is UnknownLog.
+	Missing map[string]int `json:"missing,omitempty"`
+	// Exposures counts how often each therapy target has been heard, when
+	// WithTherapyTargets is on.
+	Exposures map[string]int `json:"exposures,omitempty"`
+}
+
+// Score tallies attempts at an exercise.
+type Score struct {
+	Correct  int `json:"correct"`
+	Attempts int `json:"attempts"`
+}
+
+// Confusion is one entry of Stats.Confusions.
+type Confusion struct {
+	Heard   rune
+	Pressed rune
+	Count   int
+}
+
+// StatsStore guards a Stats value and persists it as JSON.
+type StatsStore struct {
+	path string
+
+	mu    sync.Mutex
+	stats Stats
+}
+
+// ConfigDir returns the directory phonical keeps its files in.
+func ConfigDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "phonical"), nil
+}
+
+// DefaultProfile is the profile used when none is chosen.
+const DefaultProfile = "default"
+
+// DefaultStatsPath returns where the default profile's stats are stored.
+func DefaultStatsPath() (string, error) {
+	return ProfileStatsPath(DefaultProfile)
+}
+
+// ProfileStatsPath returns where a profile's stats are stored. Each child
+// using the computer can have their own profile.
+func ProfileStatsPath(profile string) (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	if profile == "" || profile == DefaultProfile {
+		return filepath.Join(dir, "stats.json"), nil
+	}
+	if strings.ContainsAny(profile, `/\`) || profile == "." || profile == ".." {
+		return "", fmt.Errorf("invalid profile name %q", profile)
+	}
+	return filepath.Join(dir, "profiles", profile, "stats.json"), nil
+}
+
+// OpenStats loads the stats at path. A missing file gives empty stats.
+func OpenStats(path string) (*StatsStore, error) {
+	s := &StatsStore{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &s.stats); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+	}
+	s.stats.init()
+	return s, nil
+}
+
+func (st *Stats) init() {
+	if st.Letters == nil {
+		st.Letters = make(map[string]int)
+	}
+	if st.Confusions == nil {
+		st.Confusions = make(map[string]int)
+	}
+	if st.Pairs == nil {
+		st.Pairs = make(map[string]*Score)
+	}
+	if st.Reviews == nil {
+		st.Reviews = make(map[string]*ReviewState)
+	}
+}
+
+// Save writes the stats back to disk.
+func (s *StatsStore) Save() error {
+	s.mu.Lock()
+	path := s.path
+	data, err := json.MarshalIndent(&s.stats, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// Write to a temporary file first so a crash can't truncate the stats
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Update calls fn with the stats locked so it may modify them.
+func (s *StatsStore) Update(fn func(*Stats)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&s.stats)
+}
+
+// Switch saves the stats and replaces them with those at path, so one
+// store can follow whichever child is at the computer.
+func (s *StatsStore) Switch(path string) error {
+	if err := s.Save(); err != nil {
+		return err
+	}
+	next, err := OpenStats(path)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.path = next.path
+	s.stats = next.stats
+	return nil
+}
+
+// Snapshot returns a deep copy of the current stats.
+func (s *StatsStore) Snapshot() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var c Stats
+	data, _ := json.Marshal(&s.stats)
+	_ = json.Unmarshal(data, &c)
+	c.init()
+	return c
+}
+
+// RecordLetter counts a press of letter.
+func (s *StatsStore) RecordLetter(letter rune) {
+	s.Update(func(st *Stats) {
+		st.Letters[string(letter)]++
+	})
+}
+
+// RecordPair records a minimal-pair round where heard was played and
+// pressed was the answer.
+func (s *StatsStore) RecordPair(pair string, heard, pressed rune) {
+	s.Update(func(st *Stats) {
+		score := st.Pairs[pair]
+		if score == nil {
+			score = &Score{}
+			st.Pairs[pair] = score
+		}
+		score.Attempts++
+		if heard == pressed {
+			score.Correct++
+		} else {
+			st.Confusions[string(heard)+">"+string(pressed)]++
+		}
+	})
+}
+
+// TopConfusions returns the most frequent confusions, most common first.
+func (st *Stats) TopConfusions(n int) []Confusion {
+	var list []Confusion
+	for key, count := range st.Confusions {
+		runes := []rune(key)
+		if len(runes) != 3 || runes[1] != '>' {
+			continue
+		}
+		list = append(list, Confusion{Heard: runes[0], Pressed: runes[2], Count: count})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Heard < list[j].Heard
+	})
+	if len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
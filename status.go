@@ -0,0 +1,94 @@
+package phonical
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// Status is a snapshot of a running engine, for scripts and front ends.
+type Status struct {
+	Pack         string  `json:"pack"`
+	Mode         string  `json:"mode"`
+	Voice        string  `json:"voice"`
+	Volume       float64 `json:"volume"`
+	ClickVolume  float64 `json:"click_volume"`
+	Muted        bool    `json:"muted"`
+	DoNotDisturb bool    `json:"do_not_disturb"`
+	OnCall       bool    `json:"on_call"`
+	Guest        bool    `json:"guest"`
+	QueueDepth   int     `json:"queue_depth"`
+	// Level is the peak level of the sound playing now, from 0 to 1.
+	Level float64 `json:"level"`
+	// Word is the word being typed and LastWord the one before it.
+	Word         string `json:"word"`
+	LastWord     string `json:"last_word"`
+	CachedSounds int    `json:"cached_sounds"`
+	CacheBytes   int64  `json:"cache_bytes"`
+	KeysHandled  int64  `json:"keys_handled"`
+	// TypingSpeed is characters per minute over the last minute.
+	TypingSpeed float64   `json:"typing_speed"`
+	Started     time.Time `json:"started"`
+	// Permission is "granted" once a key press has arrived through the
+	// system-wide hook, "failed" while the hook won't start, as happens
+	// without permission, and "unknown" otherwise.
+	Permission string `json:"permission"`
+	// BadSounds are pack files that failed to load.
+	BadSounds []string `json:"bad_sounds,omitempty"`
+}
+
+// Status reports the engine's current state.
+func (e *Engine) Status() Status {
+	pack := e.pack.Load()
+	e.mu.Lock()
+	st := Status{
+		Pack:        pack.Name,
+		Mode:        e.mode.String(),
+		Voice:       e.voice.String(),
+		Started:     e.session.Start,
+		TypingSpeed: e.speed.cpm(e.clock.Now()),
+		Word:        e.word.String(),
+		LastWord:    e.lastWord,
+	}
+	e.mu.Unlock()
+
+	st.Volume = e.volume
+	st.ClickVolume = e.clickVolume
+	st.Muted = e.muted()
+	st.DoNotDisturb = e.dnd.Load()
+	st.OnCall = e.onCall.Load()
+	st.Guest = e.guest.Load()
+	st.QueueDepth = e.playQueue.len()
+	st.Level = math.Float64frombits(e.outputLevel.Load())
+	st.CachedSounds = cachedSoundCount()
+	st.CacheBytes = cachedSoundBytes()
+	st.KeysHandled = e.keysHandled.Load()
+	st.BadSounds = pack.BadSounds()
+	st.Permission = "unknown"
+	switch {
+	case e.hookKeys.Load() > 0:
+		st.Permission = "granted"
+	case e.hookFailures.Load() > 0:
+		st.Permission = "failed"
+	}
+	return st
+}
+
+// levelMeter passes samples through, recording the peak of each chunk in
+// level as float64 bits for Status.
+type levelMeter struct {
+	beep.Streamer
+	level *atomic.Uint64
+}
+
+func (m *levelMeter) Stream(samples [][2]float64) (int, bool) {
+	n, ok := m.Streamer.Stream(samples)
+	peak := 0.0
+	for _, s := range samples[:n] {
+		peak = math.Max(peak, math.Max(math.Abs(s[0]), math.Abs(s[1])))
+	}
+	m.level.Store(math.Float64bits(math.Min(peak, 1)))
+	return n, ok
+}
@@ -0,0 +1,46 @@
+package phonical_test
+
+import (
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestStatus(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithMode(phonical.ModeBlend))
+	start := h.Clock.Now()
+	h.Type("cat do")
+	st := h.Engine.Status()
+	if st.Word != "do" || st.LastWord != "cat" {
+		t.Errorf("word %q after %q, want do after cat", st.Word, st.LastWord)
+	}
+	if st.KeysHandled != 6 || st.Mode != "blend" || !st.Started.Equal(start) {
+		t.Errorf("status %+v, want 6 keys handled in blend mode since %v", st, start)
+	}
+	// Keys fed in directly say nothing about the hook's permission
+	if st.Permission != "unknown" || st.Muted || st.Guest {
+		t.Errorf("status %+v, want unknown permission, unmuted and not a guest", st)
+	}
+}
+
+func TestStatusLevel(t *testing.T) {
+	h := phonicaltest.New(t)
+	h.Sink.Hold()
+	h.Type("a")
+	h.Sink.WaitFor(1, time.Second)
+	// The level is the peak of what the sink last read
+	h.Sink.Playbacks()[0].Streamer.Stream(make([][2]float64, 19425))
+	if level := h.Engine.Status().Level; level <= 0 || level > 1 {
+		t.Errorf("level %g while a plays, want between 0 and 1", level)
+	}
+	h.Sink.Release()
+	deadline := time.Now().Add(time.Second)
+	for h.Engine.Status().Level != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("level kept after the sound ended")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,143 @@
+package phonical
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+//go:embed data/stories/*.txt
+var storyFiles embed.FS
+
+// Story is a short text to type along with, one sentence at a time.
+type Story struct {
+	Title     string
+	Sentences []string
+}
+
+// ParseStory reads a story from plain text. An optional first line starting
+// with "#" is the title; the rest is split into sentences at ".", "!" and
+// "?".
+func ParseStory(text string) Story {
+	var s Story
+	var body strings.Builder
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if s.Title == "" && body.Len() == 0 && strings.HasPrefix(line, "#") {
+			s.Title = strings.TrimSpace(strings.TrimPrefix(line, "#"))
+			continue
+		}
+		body.WriteString(line)
+		body.WriteByte(' ')
+	}
+
+	var sentence strings.Builder
+	for _, r := range body.String() {
+		sentence.WriteRune(r)
+		if r == '.' || r == '!' || r == '?' {
+			if t := strings.TrimSpace(sentence.String()); t != "" {
+				s.Sentences = append(s.Sentences, t)
+			}
+			sentence.Reset()
+		}
+	}
+	if t := strings.TrimSpace(sentence.String()); t != "" {
+		s.Sentences = append(s.Sentences, t)
+	}
+	return s
+}
+
+// LoadStory reads a story from a plain-text file.
+func LoadStory(filename string) (Story, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return Story{}, err
+	}
+	return ParseStory(string(data)), nil
+}
+
+// BuiltinStories returns the names of the stories embedded in the binary.
+func BuiltinStories() []string {
+	entries, _ := fs.ReadDir(storyFiles, "data/stories")
+	var names []string
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".txt"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuiltinStory returns the embedded story with the given name.
+func BuiltinStory(name string) (Story, error) {
+	data, err := storyFiles.ReadFile(path.Join("data/stories", name+".txt"))
+	if err != nil {
+		return Story{}, fmt.Errorf("no built-in story %q", name)
+	}
+	return ParseStory(string(data)), nil
+}
+
+// storyPromptTimeout is how long to wait before repeating a prompt.
+const storyPromptTimeout = 30 * time.Second
+
+// TypeAlong returns an activity that reads story aloud a sentence at a time
+// and then speaks each letter the child should type next, advancing only
+// when the right key is pressed.
+func TypeAlong(story Story) Activity {
+	return ActivityFunc(func(t *Tutor) error {
+		if story.Title != "" {
+			t.Say(story.Title)
+		}
+		for _, sentence := range story.Sentences {
+			t.Say(sentence)
+			for _, word := range strings.Fields(sentence) {
+				if err := typeWord(t, word); err != nil {
+					return err
+				}
+			}
+			t.Pause(300 * time.Millisecond)
+			t.Say(sentence)
+		}
+		t.Say("The end. Well done!")
+		return nil
+	})
+}
+
+// typeWord prompts for each letter of word, then says the word.
+func typeWord(t *Tutor, word string) error {
+	typed := false
+	for _, r := range strings.ToLower(word) {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		typed = true
+		t.Say(string(r))
+		for {
+			pressed, ok := t.NextKey(storyPromptTimeout)
+			if t.Stopped() {
+				return ErrStopped
+			}
+			if !ok {
+				t.Say(fmt.Sprintf("Press %c.", r))
+				continue
+			}
+			if pressed == r {
+				t.Play(r)
+				break
+			}
+			// Give the sound as a hint and wait again
+			t.Play(r)
+		}
+	}
+	if typed {
+		t.Say(strings.Trim(word, ".,!?;:\"'"))
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package phonical_test
+
+import (
+	"reflect"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestParseStory(t *testing.T) {
+	story := phonical.ParseStory("# The Cat\nThe cat sat.\nIt ran! Did it\nstop? Yes")
+	want := phonical.Story{Title: "The Cat", Sentences: []string{"The cat sat.", "It ran!", "Did it stop?", "Yes"}}
+	if !reflect.DeepEqual(story, want) {
+		t.Errorf("parsed %+v, want %+v", story, want)
+	}
+}
+
+func TestTypeAlong(t *testing.T) {
+	h := phonicaltest.New(t)
+	done := runActivity(h, phonical.TypeAlong(phonical.ParseStory("# Hi\nI am.")))
+
+	waitPlayed(t, h, 3)
+	answer(h, "i")
+	waitPlayed(t, h, 6)
+	// A wrong key plays the letter wanted as a hint
+	answer(h, "x")
+	waitPlayed(t, h, 7)
+	answer(h, "a")
+	waitPlayed(t, h, 9)
+	answer(h, "m")
+	waitPlayed(t, h, 13)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	h.Expect("say:Hi", "say:I am.",
+		"say:i", "i.wav", "say:I",
+		"say:a", "a.wav", "a.wav", "say:m", "m.wav", "say:am",
+		"say:I am.", "say:The end. Well done!")
+}
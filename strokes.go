@@ -0,0 +1,61 @@
+package phonical
+
+import (
+	"bufio"
+	"embed"
+	"io/fs"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// strokeFiles hold how letters are written, one file per script.
+//
+//go:embed data/strokes/*.txt
+var strokeFiles embed.FS
+
+var (
+	letterStrokeData map[rune][]string
+	strokesOnce      sync.Once
+)
+
+// loadStrokes parses the bundled stroke files.
+func loadStrokes() {
+	strokesOnce.Do(func() {
+		letterStrokeData = make(map[rune][]string)
+		files, _ := fs.Glob(strokeFiles, "data/strokes/*.txt")
+		for _, name := range files {
+			data, err := strokeFiles.ReadFile(name)
+			if err != nil {
+				continue
+			}
+			scanner := bufio.NewScanner(strings.NewReader(string(data)))
+			for scanner.Scan() {
+				line := strings.TrimSpace(scanner.Text())
+				if line == "" || strings.HasPrefix(line, "#") {
+					continue
+				}
+				letter, paths, ok := strings.Cut(line, ":")
+				r, size := utf8.DecodeRuneInString(letter)
+				if !ok || size != len(letter) {
+					continue
+				}
+				var strokes []string
+				for _, p := range strings.Split(paths, "|") {
+					if p = strings.TrimSpace(p); p != "" {
+						strokes = append(strokes, p)
+					}
+				}
+				letterStrokeData[r] = strokes
+			}
+		}
+	})
+}
+
+// letterStrokes returns how letter is written, as an SVG path for each
+// stroke in the order they are drawn, or nil when the stroke files don't
+// have it.
+func letterStrokes(letter rune) []string {
+	loadStrokes()
+	return letterStrokeData[letter]
+}
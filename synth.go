@@ -0,0 +1,117 @@
+package phonical
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// note is one step of a synthesized tone sequence.
+type note struct {
+	freq     float64
+	duration time.Duration
+}
+
+// synthTone renders notes as sine tones at amplitude amp, each with a quick
+// attack and exponential decay so they sound soft rather than beepy.
+func synthTone(amp float64, notes ...note) *beep.Buffer {
+	buffer := beep.NewBuffer(outputFormat)
+	sr := float64(outputFormat.SampleRate)
+	for _, nt := range notes {
+		n := outputFormat.SampleRate.N(nt.duration)
+		attack := n / 20
+		i := 0
+		buffer.Append(beep.StreamerFunc(func(samples [][2]float64) (int, bool) {
+			if i >= n {
+				return 0, false
+			}
+			filled := 0
+			for filled < len(samples) && i < n {
+				envelope := math.Exp(-4 * float64(i) / float64(n))
+				if i < attack {
+					envelope *= float64(i) / float64(attack)
+				}
+				v := amp * envelope * math.Sin(2*math.Pi*nt.freq*float64(i)/sr)
+				samples[filled] = [2]float64{v, v}
+				filled++
+				i++
+			}
+			return filled, true
+		}))
+	}
+	return buffer
+}
+
+var (
+	synthCues     = map[string]*beep.Buffer{}
+	synthCuesOnce sync.Once
+
+	mutedBuffer *beep.Buffer
+	mutedOnce   sync.Once
+
+	sparkleBuffer *beep.Buffer
+	sparkleOnce   sync.Once
+
+	fallbackBuffer *beep.Buffer
+	fallbackOnce   sync.Once
+
+	tickBuffer *beep.Buffer
+	tickOnce   sync.Once
+)
+
+// boxTick returns the soft wooden tick played before each phoneme in
+// ModeBoxes.
+func boxTick() *beep.Buffer {
+	tickOnce.Do(func() {
+		tickBuffer = synthTone(0.12, note{freq: 880, duration: 30 * time.Millisecond})
+	})
+	return tickBuffer
+}
+
+// fallbackTone returns the neutral tone played in place of a sound that
+// failed to load.
+func fallbackTone() *beep.Buffer {
+	fallbackOnce.Do(func() {
+		fallbackBuffer = synthTone(0.15, note{freq: 440, duration: 120 * time.Millisecond})
+	})
+	return fallbackBuffer
+}
+
+// sparkle returns a quick rising arpeggio celebrating a first-time letter.
+func sparkle() *beep.Buffer {
+	sparkleOnce.Do(func() {
+		sparkleBuffer = synthTone(0.18,
+			note{freq: 1047, duration: 60 * time.Millisecond},
+			note{freq: 1319, duration: 60 * time.Millisecond},
+			note{freq: 1568, duration: 60 * time.Millisecond},
+			note{freq: 2093, duration: 160 * time.Millisecond})
+	})
+	return sparkleBuffer
+}
+
+// mutedCue returns the brief quiet tick played for letters outside the
+// focus in focus mode.
+func mutedCue() *beep.Buffer {
+	mutedOnce.Do(func() {
+		mutedBuffer = synthTone(0.08, note{freq: 330, duration: 40 * time.Millisecond})
+	})
+	return mutedBuffer
+}
+
+// builtinCue returns the synthesized sound used for a cue the pack does not
+// provide: a low soft tap for space, a gentle rising pair for Enter and a
+// falling pair for Backspace.
+func builtinCue(name string) *beep.Buffer {
+	synthCuesOnce.Do(func() {
+		synthCues[CueSpace] = synthTone(0.2, note{freq: 220, duration: 70 * time.Millisecond})
+		synthCues[CueEnter] = synthTone(0.2,
+			note{freq: 523, duration: 80 * time.Millisecond},
+			note{freq: 784, duration: 120 * time.Millisecond})
+		synthCues[CueBackspace] = synthTone(0.2,
+			note{freq: 440, duration: 60 * time.Millisecond},
+			note{freq: 330, duration: 90 * time.Millisecond})
+	})
+	return synthCues[name]
+}
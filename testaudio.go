@@ -0,0 +1,77 @@
+package phonical
+
+import (
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// AudioInfo describes where the engine sends sound, for troubleshooting.
+type AudioInfo struct {
+	// Output is "speaker", "network", "dry run", "none" or "custom".
+	Output     string
+	SampleRate int
+	Channels   int
+	// Buffer is the speaker's buffer length.
+	Buffer time.Duration
+}
+
+// AudioInfo reports the output format and where sound goes.
+func (e *Engine) AudioInfo() AudioInfo {
+	info := AudioInfo{Output: "custom", SampleRate: int(outputFormat.SampleRate), Channels: outputFormat.NumChannels}
+	switch sink := e.sink.(type) {
+	case speakerSink:
+		info.Output = "speaker"
+		info.Buffer = sink.buffer
+	case *NetSink:
+		info.Output = "network"
+	case *dryRun:
+		info.Output = "dry run"
+	case silentSink:
+		info.Output = "none"
+	}
+	return info
+}
+
+// Channel checks played by TestAudio.
+const (
+	ChannelLeft  = "left"
+	ChannelRight = "right"
+	ChannelBoth  = "both"
+)
+
+var (
+	testToneBuffer *beep.Buffer
+	testToneOnce   sync.Once
+)
+
+// testTone returns the steady tone played by TestAudio.
+func testTone() *beep.Buffer {
+	testToneOnce.Do(func() {
+		testToneBuffer = synthTone(0.3, note{freq: 660, duration: 800 * time.Millisecond})
+	})
+	return testToneBuffer
+}
+
+// TestAudio plays a tone on the left channel, the right, then both,
+// calling show before each, to check the sound output on its own without
+// the keyboard hook.
+func TestAudio(show func(channel string)) Activity {
+	return ActivityFunc(func(t *Tutor) error {
+		for _, check := range []struct {
+			channel string
+			pan     float64
+		}{{ChannelLeft, -1}, {ChannelRight, 1}, {ChannelBoth, 0}} {
+			if t.Stopped() {
+				return ErrStopped
+			}
+			if show != nil {
+				show(check.channel)
+			}
+			t.e.playAndWait(&queuedSound{sound: "tone:" + check.channel, buffer: testTone(), pan: check.pan})
+			t.Pause(400 * time.Millisecond)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,69 @@
+package phonical_test
+
+import (
+	"io"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/faiface/beep"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// channelPeaks returns the loudest sample on each channel of s.
+func channelPeaks(s beep.Streamer) (left, right float64) {
+	buf := make([][2]float64, 512)
+	for {
+		n, ok := s.Stream(buf)
+		for _, sample := range buf[:n] {
+			left = math.Max(left, math.Abs(sample[0]))
+			right = math.Max(right, math.Abs(sample[1]))
+		}
+		if !ok {
+			return left, right
+		}
+	}
+}
+
+func TestTestAudio(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithWatchdog(0))
+	var shown []string
+	done := runActivity(h, phonical.TestAudio(func(channel string) { shown = append(shown, channel) }))
+	played := waitPlayed(t, h, 3)
+	// The last tone is followed by a pause
+	waitWaiters(t, h.Clock, 1)
+	h.Clock.AdvanceToNext()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{phonical.ChannelLeft, phonical.ChannelRight, phonical.ChannelBoth}; !reflect.DeepEqual(shown, want) {
+		t.Errorf("showed %q, want %q", shown, want)
+	}
+	if want := []string{"tone:left", "tone:right", "tone:both"}; !reflect.DeepEqual(played, want) {
+		t.Errorf("played %q, want %q", played, want)
+	}
+
+	// Each tone is heard only on its own side
+	for i, want := range [][2]bool{{true, false}, {false, true}, {true, true}} {
+		left, right := channelPeaks(h.Sink.Playbacks()[i].Streamer)
+		if (left > 0.01) != want[0] || (right > 0.01) != want[1] {
+			t.Errorf("%s tone peaks at %g left and %g right", shown[i], left, right)
+		}
+	}
+}
+
+func TestAudioInfo(t *testing.T) {
+	h := phonicaltest.New(t)
+	if info := h.Engine.AudioInfo(); info.Output != "custom" || info.SampleRate != 44100 || info.Channels != 2 {
+		t.Errorf("audio info with a test sink is %+v", info)
+	}
+	engine, err := phonical.New(phonical.WithDryRun(io.Discard))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info := engine.AudioInfo(); info.Output != "dry run" {
+		t.Errorf("dry run output is %q", info.Output)
+	}
+}
@@ -0,0 +1,104 @@
+package phonical
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// WithTheme layers theme over the pack: its cues, encouragement and timed
+// clips are played in place of the pack's, while the letter sounds stay the
+// pack's own.
+func WithTheme(theme *Pack) Option {
+	return func(e *Engine) {
+		e.theme = theme
+	}
+}
+
+// ThemesDir returns where installed themes are kept, one directory each.
+func ThemesDir() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "themes"), nil
+}
+
+// LoadTheme reads the theme in dir: a partial pack with only [cues],
+// [timed] and encouragement clips, such as a spooky set for October.
+// Themes never replace letter sounds, so a theme with any is rejected.
+func LoadTheme(dir string) (*Pack, error) {
+	p, err := LoadPack(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.Letters)+len(p.Graphemes)+len(p.Intros)+len(p.Words)+len(p.Variants)+len(p.Syllables) > 0 {
+		return nil, fmt.Errorf("theme %s: themes can only have cues, timed and encouragement clips, not letter sounds", p.Name)
+	}
+	return p, nil
+}
+
+// InstalledThemes loads every theme in ThemesDir, sorted by name.
+func InstalledThemes() ([]*Pack, error) {
+	dir, err := ThemesDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var themes []*Pack
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		theme, err := LoadTheme(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		themes = append(themes, theme)
+	}
+	sort.Slice(themes, func(i, j int) bool { return themes[i].Name < themes[j].Name })
+	return themes, nil
+}
+
+// SeasonalTheme returns the first of themes whose months include the month
+// of now, or nil when none is in season.
+func SeasonalTheme(themes []*Pack, now time.Time) *Pack {
+	for _, theme := range themes {
+		for _, month := range theme.Months {
+			if month == now.Month() {
+				return theme
+			}
+		}
+	}
+	return nil
+}
+
+// layers returns the theme, if any, then the pack, in the order their
+// cues and clips are looked up.
+func (e *Engine) layers() []*Pack {
+	if e.theme != nil {
+		return []*Pack{e.theme, e.pack.Load()}
+	}
+	return []*Pack{e.pack.Load()}
+}
+
+// encouragementClip picks one of the encouragement clips of the theme, or
+// of the pack when the theme has none.
+func (e *Engine) encouragementClip() (*Pack, string, bool) {
+	for _, pack := range e.layers() {
+		if n := len(pack.Encouragement); n > 0 {
+			return pack, pack.Encouragement[rand.Intn(n)], true
+		}
+	}
+	return nil, "", false
+}
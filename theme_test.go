@@ -0,0 +1,50 @@
+package phonical_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestTheme(t *testing.T) {
+	wav, err := os.ReadFile(filepath.Join("sounds", phonical.DefaultSounds, "a.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	theme, err := phonical.LoadTheme(writePack(t, "name = 'Spooky'\nmonths = [10]\n[timed.morning]\ngreeting = 'boo.wav'\n",
+		map[string][]byte{"boo.wav": wav}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The theme's greeting wins, and the letters stay the pack's
+	h := phonicaltest.New(t, phonical.WithTheme(theme), phonical.WithTimeOfDay(true))
+	h.Type("a")
+	h.Expect("boo.wav", "a.wav")
+
+	themes := []*phonical.Pack{theme}
+	if got := phonical.SeasonalTheme(themes, time.Date(2024, 10, 31, 0, 0, 0, 0, time.UTC)); got != theme {
+		t.Errorf("seasonal theme on Halloween is %v", got)
+	}
+	if got := phonical.SeasonalTheme(themes, time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)); got != nil {
+		t.Errorf("seasonal theme in November is %s", got.Name)
+	}
+}
+
+func TestThemeChecked(t *testing.T) {
+	wav, err := os.ReadFile(filepath.Join("sounds", phonical.DefaultSounds, "a.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, manifest := range []string{
+		"name = 'Letters'\n[letters]\na = 'a.wav'\n",
+		"name = 'Never'\nmonths = [13]\n",
+	} {
+		if _, err := phonical.LoadTheme(writePack(t, manifest, map[string][]byte{"a.wav": wav})); err == nil {
+			t.Errorf("loaded theme %q", manifest)
+		}
+	}
+}
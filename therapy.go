@@ -0,0 +1,180 @@
+package phonical
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// targetStretch is how much slower target phonemes play.
+const targetStretch = 1.6
+
+// targetCheckEvery is how often the targets file is checked for changes.
+const targetCheckEvery = time.Second
+
+// WithTherapyTargets plays the phonemes a speech therapist has chosen for
+// a child as an exaggerated model: slower, without changing pitch, and
+// louder. The targets are read from TargetsPath beside the stats, so each
+// profile has its own and a profile switch swaps them, and edits to the
+// file take effect while running. How often each target has been heard is
+// counted in Stats.Exposures. It needs WithStats.
+func WithTherapyTargets() Option {
+	return func(e *Engine) {
+		e.targets = &targets{}
+	}
+}
+
+// TargetsPath returns the file holding the therapy targets for the stats
+// file at statsPath.
+func TargetsPath(statsPath string) string {
+	return filepath.Join(filepath.Dir(statsPath), "targets.txt")
+}
+
+// LoadTargets reads a targets file: phonemes such as s, sh or th separated
+// by spaces, commas or new lines, with # starting a comment. A missing
+// file has no targets.
+func LoadTargets(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var units []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		for _, unit := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+			unit = strings.ToLower(unit)
+			if !seen[unit] {
+				seen[unit] = true
+				units = append(units, unit)
+			}
+		}
+	}
+	return units, scanner.Err()
+}
+
+// SaveTargets writes units to the targets file at path, replacing it. No
+// units removes the file.
+func SaveTargets(path string, units []string) error {
+	if len(units) == 0 {
+		err := os.Remove(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	content := "# Therapy targets, played slower and clearer\n" + strings.Join(units, " ") + "\n"
+	return os.WriteFile(path, []byte(content), 0o600)
+}
+
+// Exposure is one entry of Stats.Exposures.
+type Exposure struct {
+	Unit  string
+	Count int
+}
+
+// TargetExposures returns how often each of units has been heard, in the
+// order given.
+func (st *Stats) TargetExposures(units []string) []Exposure {
+	out := make([]Exposure, len(units))
+	for i, unit := range units {
+		out[i] = Exposure{Unit: unit, Count: st.Exposures[unit]}
+	}
+	return out
+}
+
+// targets is the current profile's therapy targets, reloaded when the
+// file or the profile changes.
+type targets struct {
+	mu      sync.Mutex
+	path    string
+	mod     time.Time
+	checked time.Time
+	units   map[string]bool
+}
+
+// has reports whether unit is a target for the stats at statsPath,
+// rereading the targets file when it may have changed.
+func (t *targets) has(e *Engine, statsPath, unit string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if statsPath != t.path || now.Sub(t.checked) >= targetCheckEvery {
+		t.checked = now
+		path := TargetsPath(statsPath)
+		var mod time.Time
+		if info, err := os.Stat(path); err == nil {
+			mod = info.ModTime()
+		}
+		if statsPath != t.path || !mod.Equal(t.mod) {
+			units, err := LoadTargets(path)
+			if err != nil {
+				e.logf("Reading therapy targets: %v", err)
+			}
+			t.path, t.mod = statsPath, mod
+			t.units = make(map[string]bool, len(units))
+			for _, u := range units {
+				t.units[u] = true
+			}
+			if len(units) > 0 {
+				e.debugf("Therapy targets: %s\n", strings.Join(sortedUnits(t.units), " "))
+			}
+		}
+	}
+	return t.units[unit]
+}
+
+func sortedUnits(units map[string]bool) []string {
+	out := make([]string, 0, len(units))
+	for u := range units {
+		out = append(out, u)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// checkTargets makes sure therapy targets have stats to be kept beside.
+func (e *Engine) checkTargets() error {
+	if e.targets != nil && e.stats == nil {
+		return errors.New("therapy targets need stats")
+	}
+	return nil
+}
+
+// isTarget reports whether unit, a letter or the grapheme it completed,
+// is one of the profile's therapy targets, counting its exposure if so.
+func (e *Engine) isTarget(unit string, now time.Time) bool {
+	stats := e.recordingStats()
+	if e.targets == nil {
+		return false
+	}
+	e.stats.mu.Lock()
+	path := e.stats.path
+	e.stats.mu.Unlock()
+	if !e.targets.has(e, path, unit, now) {
+		return false
+	}
+	if stats != nil {
+		stats.Update(func(st *Stats) {
+			if st.Exposures == nil {
+				st.Exposures = make(map[string]int)
+			}
+			st.Exposures[unit]++
+		})
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package phonical_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestTherapyTargets(t *testing.T) {
+	statsPath := filepath.Join(t.TempDir(), "stats.json")
+	if err := phonical.SaveTargets(phonical.TargetsPath(statsPath), []string{"s"}); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := phonical.OpenStats(statsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := phonicaltest.New(t, phonical.WithStats(stats), phonical.WithTherapyTargets())
+	h.Type("sas")
+	h.Expect("s.wav", "a.wav", "s.wav")
+
+	plain := phonicaltest.New(t)
+	plain.Type("sa")
+	plain.Expect("s.wav", "a.wav")
+
+	// The target plays slower, trimmed of silence, and the rest as recorded
+	played, want := h.Sink.Playbacks(), plain.Sink.Playbacks()
+	if got, base := samples(played[0].Streamer), samples(want[0].Streamer); got <= base {
+		t.Errorf("target s is %d samples to %d as recorded, want it slowed", got, base)
+	}
+	if got, base := samples(played[1].Streamer), samples(want[1].Streamer); got != base {
+		t.Errorf("a is %d samples, want %d as recorded", got, base)
+	}
+	st := stats.Snapshot()
+	if got := st.TargetExposures([]string{"s", "sh"}); !reflect.DeepEqual(got, []phonical.Exposure{{Unit: "s", Count: 2}, {Unit: "sh"}}) {
+		t.Errorf("exposures %+v, want s twice", got)
+	}
+}
+
+func TestLoadTargets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "targets.txt")
+	if err := os.WriteFile(path, []byte("# Week 2\nS, sh th\nsh # again\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	units, err := phonical.LoadTargets(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"s", "sh", "th"}; !reflect.DeepEqual(units, want) {
+		t.Errorf("targets %q, want %q", units, want)
+	}
+
+	// Saving none removes the file
+	if err := phonical.SaveTargets(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if units, err := phonical.LoadTargets(path); err != nil || units != nil {
+		t.Errorf("targets after clearing %q, %v", units, err)
+	}
+	if _, err := phonical.New(phonical.WithTherapyTargets()); err == nil {
+		t.Error("accepted therapy targets without stats")
+	}
+}
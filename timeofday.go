@@ -0,0 +1,111 @@
+package phonical
+
+import (
+	"fmt"
+	"time"
+)
+
+// Parts of the day recognised in a pack's [timed] tables, by local time:
+// morning from 5am, afternoon from noon, evening from 5pm and late from
+// 9pm.
+const (
+	PartMorning   = "morning"
+	PartAfternoon = "afternoon"
+	PartEvening   = "evening"
+	PartLate      = "late"
+)
+
+// Kinds of time-tagged clip in a pack's [timed] tables.
+const (
+	// ClipGreeting is played when the engine starts.
+	ClipGreeting = "greeting"
+	// ClipEncouragement follows praise, such as finding a prompted letter.
+	ClipEncouragement = "encouragement"
+)
+
+// spokenGreetings are said when the pack has no greeting clip for the part
+// of the day.
+var spokenGreetings = map[string]string{
+	PartMorning:   "Good morning!",
+	PartAfternoon: "Good afternoon!",
+	PartEvening:   "Good evening!",
+	PartLate:      "It's getting late!",
+}
+
+// WithTimeOfDay greets the child for the time of day when the engine
+// starts, and follows praise with the pack's encouragement clip for the
+// time of day, if it has one.
+func WithTimeOfDay(enabled bool) Option {
+	return func(e *Engine) {
+		e.timeOfDay = enabled
+	}
+}
+
+// partOfDay returns the part of the day local time t falls in.
+func partOfDay(t time.Time) string {
+	switch h := t.Hour(); {
+	case h >= 5 && h < 12:
+		return PartMorning
+	case h >= 12 && h < 17:
+		return PartAfternoon
+	case h >= 17 && h < 21:
+		return PartEvening
+	}
+	return PartLate
+}
+
+// isPartOfDay reports whether name is one of the Part constants.
+func isPartOfDay(name string) bool {
+	_, ok := spokenGreetings[name]
+	return ok
+}
+
+// timedClip returns the clip of kind for the part of the day at now, from
+// the theme if it has one and otherwise from the pack.
+func (e *Engine) timedClip(kind string, now time.Time) (*Pack, string, bool) {
+	for _, pack := range e.layers() {
+		if file, ok := pack.Timed[partOfDay(now)][kind]; ok {
+			return pack, file, true
+		}
+	}
+	return nil, "", false
+}
+
+// greet queues the greeting for the time of day.
+func (e *Engine) greet(now time.Time) {
+	part := partOfDay(now)
+	e.debugf("Greeting for the %s\n", part)
+	if pack, file, ok := e.timedClip(ClipGreeting, now); ok {
+		e.enqueue(&queuedSound{sound: file, pack: pack})
+		return
+	}
+	e.enqueueSay(spokenGreetings[part])
+}
+
+// praise says text, followed by an encouragement clip: the one for the
+// time of day when time-of-day clips are on, or else one of the theme's or
+// pack's encouragement clips.
+func (e *Engine) praise(text string) {
+	e.enqueueSay(text)
+	if e.timeOfDay {
+		if pack, file, ok := e.timedClip(ClipEncouragement, e.clock.Now()); ok {
+			e.enqueue(&queuedSound{sound: file, pack: pack})
+			return
+		}
+	}
+	if pack, file, ok := e.encouragementClip(); ok {
+		e.enqueue(&queuedSound{sound: file, pack: pack})
+	}
+}
+
+// checkTimed reports a [timed] table entry that isn't a part of the day
+// and clip kind.
+func checkTimed(part, kind string) error {
+	if !isPartOfDay(part) {
+		return fmt.Errorf("unknown part of the day %q (want morning, afternoon, evening or late)", part)
+	}
+	if kind != ClipGreeting && kind != ClipEncouragement {
+		return fmt.Errorf("unknown clip %q (want greeting or encouragement)", kind)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package phonical_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestTimeOfDayGreeting(t *testing.T) {
+	// The harness clock starts at 9am
+	h := phonicaltest.New(t, phonical.WithTimeOfDay(true))
+	h.Type("a")
+	h.Expect("say:Good morning!", "a.wav")
+}
+
+func TestTimedClips(t *testing.T) {
+	wav, err := os.ReadFile(filepath.Join("sounds", phonical.DefaultSounds, "a.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := writePack(t, "name = 'Timed'\n[letters]\na = 'a.wav'\n[timed.morning]\ngreeting = 'hello.wav'\n[timed.evening]\ngreeting = 'night.wav'\n",
+		map[string][]byte{"a.wav": wav, "hello.wav": wav, "night.wav": wav})
+	pack, err := phonical.LoadPack(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := phonicaltest.New(t, phonical.WithPack(pack), phonical.WithTimeOfDay(true))
+	h.Type("a")
+	h.Expect("hello.wav", "a.wav")
+
+	for _, timed := range []string{"[timed.noon]\ngreeting = 'a.wav'\n", "[timed.morning]\nsong = 'a.wav'\n"} {
+		dir := writePack(t, "name = 'Timed'\n[letters]\na = 'a.wav'\n"+timed, map[string][]byte{"a.wav": wav})
+		if _, err := phonical.LoadPack(dir); err == nil {
+			t.Errorf("loaded a pack with %q", timed)
+		}
+	}
+}
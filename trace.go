@@ -0,0 +1,76 @@
+package phonical
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTraceSize is how many recent events the engine keeps for Trace.
+const DefaultTraceSize = 500
+
+// TraceEvent is one diagnostic event kept for Trace: a key press, a sound
+// starting, a dropped sound or an error.
+type TraceEvent struct {
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+// WithTraceSize sets how many recent events Trace keeps. Events are kept
+// whether or not verbose output is on, so a problem can be looked into
+// after it happens. Defaults to DefaultTraceSize; 0 keeps none.
+func WithTraceSize(n int) Option {
+	return func(e *Engine) {
+		e.traceSize = n
+	}
+}
+
+// traceRing holds the most recent events, overwriting the oldest.
+type traceRing struct {
+	mu     sync.Mutex
+	events []TraceEvent
+	next   int
+	full   bool
+}
+
+func newTraceRing(size int) *traceRing {
+	return &traceRing{events: make([]TraceEvent, size)}
+}
+
+func (r *traceRing) add(ev TraceEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events[r.next] = ev
+	r.next = (r.next + 1) % len(r.events)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// snapshot returns the events oldest first.
+func (r *traceRing) snapshot() []TraceEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]TraceEvent(nil), r.events[:r.next]...)
+	}
+	return append(append([]TraceEvent(nil), r.events[r.next:]...), r.events[:r.next]...)
+}
+
+// Trace returns the most recent events, oldest first.
+func (e *Engine) Trace() []TraceEvent {
+	if e.trace == nil {
+		return nil
+	}
+	return e.trace.snapshot()
+}
+
+// tracef records an event for Trace without printing it.
+func (e *Engine) tracef(format string, args ...interface{}) {
+	if e.trace == nil {
+		return
+	}
+	msg := strings.TrimSuffix(fmt.Sprintf(format, args...), "\n")
+	e.trace.add(TraceEvent{Time: e.clock.Now(), Message: msg})
+}
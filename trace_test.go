@@ -0,0 +1,43 @@
+package phonical_test
+
+import (
+	"reflect"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestTrace(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithTraceSize(4), phonical.WithWatchdog(0))
+	h.Type("a")
+	h.Expect("a.wav")
+	h.Type("b")
+	h.Expect("a.wav", "b.wav")
+
+	// Only the last four events are kept, oldest first, whether or not
+	// verbose output is on
+	var got []string
+	for _, ev := range h.Engine.Trace() {
+		got = append(got, ev.Message)
+		if !ev.Time.Equal(h.Clock.Now()) {
+			t.Errorf("%q at %v, want the engine's clock", ev.Message, ev.Time)
+		}
+	}
+	want := []string{
+		"Key pressed: a - Playing: a.wav",
+		"Sound a.wav started",
+		"Key pressed: b - Playing: b.wav",
+		"Sound b.wav started",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("trace %q, want %q", got, want)
+	}
+
+	off := phonicaltest.New(t, phonical.WithTraceSize(0))
+	off.Type("a")
+	off.Expect("a.wav")
+	if trace := off.Engine.Trace(); len(trace) != 0 {
+		t.Errorf("kept %d events with tracing off", len(trace))
+	}
+}
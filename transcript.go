@@ -0,0 +1,138 @@
+package phonical
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTranscriptRetention is how long session transcripts are kept
+// before they are deleted.
+const DefaultTranscriptRetention = 30 * 24 * time.Hour
+
+// transcriptExt is the extension of a transcript file.
+const transcriptExt = ".jsonl"
+
+// TranscriptEntry is one line of a session transcript: a letter typed and
+// the sound played for it. Transcripts hold only letters, never the words
+// or text around them.
+type TranscriptEntry struct {
+	Time   time.Time `json:"time"`
+	Letter string    `json:"letter"`
+	Sound  string    `json:"sound"`
+}
+
+// WithTranscript writes a transcript of the letters typed in each session,
+// with the time of each, for review in speech therapy. Transcripts are
+// kept in TranscriptDir beside the stats, so they follow the profile, and
+// those older than keep are deleted when a session starts; 0 keeps them
+// all. It needs WithStats, and nothing is written in guest mode.
+func WithTranscript(keep time.Duration) Option {
+	return func(e *Engine) {
+		e.transcript = &transcript{keep: keep}
+	}
+}
+
+// TranscriptDir returns where transcripts are kept for the stats file at
+// statsPath.
+func TranscriptDir(statsPath string) string {
+	return filepath.Join(filepath.Dir(statsPath), "transcripts")
+}
+
+// PurgeTranscripts deletes the transcripts in dir older than age, or all
+// of them when age is 0, returning how many it deleted.
+func PurgeTranscripts(dir string, age time.Duration) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), transcriptExt) {
+			continue
+		}
+		if age > 0 {
+			info, err := entry.Info()
+			if err != nil || time.Since(info.ModTime()) < age {
+				continue
+			}
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// transcript is the session transcript being written. A new file is
+// started when the stats switch to another profile.
+type transcript struct {
+	keep time.Duration
+
+	mu        sync.Mutex
+	statsPath string
+	file      *os.File
+	enc       *json.Encoder
+}
+
+// record adds entry to the transcript for the stats at statsPath.
+func (t *transcript) record(statsPath string, entry TranscriptEntry) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.file == nil || statsPath != t.statsPath {
+		t.closeFile()
+		dir := TranscriptDir(statsPath)
+		if t.keep > 0 {
+			if _, err := PurgeTranscripts(dir, t.keep); err != nil {
+				return err
+			}
+		}
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return err
+		}
+		name := entry.Time.Format("2006-01-02T15-04-05") + transcriptExt
+		file, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return err
+		}
+		t.statsPath, t.file, t.enc = statsPath, file, json.NewEncoder(file)
+	}
+	return t.enc.Encode(&entry)
+}
+
+// close finishes the transcript at the end of the session.
+func (t *transcript) close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closeFile()
+}
+
+func (t *transcript) closeFile() {
+	if t.file != nil {
+		t.file.Close()
+		t.file = nil
+	}
+}
+
+// transcribe adds a typed letter to the session transcript, if one is
+// being kept.
+func (e *Engine) transcribe(letter rune, sound string, now time.Time) {
+	stats := e.recordingStats()
+	if e.transcript == nil || stats == nil {
+		return
+	}
+	stats.mu.Lock()
+	path := stats.path
+	stats.mu.Unlock()
+	if err := e.transcript.record(path, TranscriptEntry{Time: now, Letter: string(letter), Sound: sound}); err != nil {
+		e.logf("Writing transcript: %v", err)
+	}
+}
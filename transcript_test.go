@@ -0,0 +1,79 @@
+package phonical_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestTranscript(t *testing.T) {
+	statsPath := filepath.Join(t.TempDir(), "stats.json")
+	stats, err := phonical.OpenStats(statsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := phonicaltest.New(t, phonical.WithStats(stats), phonical.WithTranscript(0))
+	h.Type("ab")
+	h.Expect("a.wav", "b.wav")
+	h.Engine.Stop()
+
+	dir := phonical.TranscriptDir(statsPath)
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Name() != "2024-01-01T09-00-00.jsonl" {
+		t.Fatalf("transcripts %v, want one for the session", files)
+	}
+	f, err := os.Open(filepath.Join(dir, files[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var entries []phonical.TranscriptEntry
+	for dec := json.NewDecoder(f); dec.More(); {
+		var entry phonical.TranscriptEntry
+		if err := dec.Decode(&entry); err != nil {
+			t.Fatal(err)
+		}
+		entry.Time = entry.Time.UTC()
+		entries = append(entries, entry)
+	}
+	at := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	want := []phonical.TranscriptEntry{{Time: at, Letter: "a", Sound: "a.wav"}, {Time: at, Letter: "b", Sound: "b.wav"}}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("transcript %+v, want %+v", entries, want)
+	}
+}
+
+func TestPurgeTranscripts(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"old.jsonl", "new.jsonl", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "old.jsonl"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := phonical.PurgeTranscripts(dir, 24*time.Hour); err != nil || n != 1 {
+		t.Errorf("purging a day old deleted %d, %v; want 1", n, err)
+	}
+	if n, err := phonical.PurgeTranscripts(dir, 0); err != nil || n != 1 {
+		t.Errorf("purging all deleted %d, %v; want 1", n, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Errorf("purge touched other files: %v", err)
+	}
+	if n, err := phonical.PurgeTranscripts(filepath.Join(dir, "none"), 0); err != nil || n != 0 {
+		t.Errorf("purging a missing folder deleted %d, %v", n, err)
+	}
+}
@@ -0,0 +1,135 @@
+package phonical
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Ways to handle a typed character the pack has no sound for, set by
+// WithUnknownChars.
+const (
+	// UnknownSilent plays nothing, as before.
+	UnknownSilent = "silent"
+	// UnknownClick plays a soft click, so the key press is still heard.
+	UnknownClick = "click"
+	// UnknownSay speaks the character's name, such as "question mark".
+	UnknownSay = "say"
+	// UnknownLog plays nothing but counts the character in the stats, for
+	// `phonical sounds missing` to suggest sounds for.
+	UnknownLog = "log"
+)
+
+// UnknownModes lists the ways of handling unknown characters.
+func UnknownModes() []string {
+	return []string{UnknownSilent, UnknownClick, UnknownSay, UnknownLog}
+}
+
+// WithUnknownChars sets what happens when a character the pack has no sound
+// for is typed: UnknownSilent, UnknownClick, UnknownSay or UnknownLog.
+// Space and Enter keep their cues. Defaults to UnknownSilent.
+func WithUnknownChars(mode string) Option {
+	return func(e *Engine) {
+		e.unknown = mode
+	}
+}
+
+// checkUnknown validates the unknown character mode for New.
+func (e *Engine) checkUnknown() error {
+	for _, mode := range UnknownModes() {
+		if e.unknown == mode {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown character handling %q (want %s)", e.unknown, strings.Join(UnknownModes(), ", "))
+}
+
+// symbolNames are the spoken names of characters packs rarely record.
+var symbolNames = map[rune]string{
+	'0': "zero", '1': "one", '2': "two", '3': "three", '4': "four",
+	'5': "five", '6': "six", '7': "seven", '8': "eight", '9': "nine",
+	'.': "full stop", ',': "comma", '?': "question mark", '!': "exclamation mark",
+	':': "colon", ';': "semicolon", '\'': "apostrophe", '"': "quotation mark",
+	'-': "dash", '_': "underscore", '(': "open bracket", ')': "close bracket",
+	'[': "open square bracket", ']': "close square bracket",
+	'{': "open curly bracket", '}': "close curly bracket",
+	'/': "slash", '\\': "backslash", '|': "bar", '@': "at sign", '#': "hash",
+	'$': "dollar", '£': "pound", '€': "euro", '%': "percent", '&': "and sign",
+	'*': "star", '+': "plus", '=': "equals", '<': "less than", '>': "greater than",
+	'^': "caret", '~': "tilde", '`': "backtick",
+}
+
+// SymbolName returns what r is called when spoken, such as "question mark"
+// for ?, or r itself when it has no name.
+func SymbolName(r rune) string {
+	if name, ok := symbolNames[r]; ok {
+		return name
+	}
+	return string(r)
+}
+
+// SuggestSound suggests a file for the pack to play for r: the plain
+// letter's recording for an accented letter, otherwise a new file named
+// after r.
+func (p *Pack) SuggestSound(r rune) string {
+	if base, ok := baseLetters[r]; ok {
+		if file, ok := p.Letters[base]; ok {
+			return file
+		}
+	}
+	if name, ok := symbolNames[r]; ok {
+		return strings.ReplaceAll(name, " ", "-") + ".wav"
+	}
+	if r < unicode.MaxASCII && unicode.IsLetter(r) {
+		return string(r) + ".wav"
+	}
+	return fmt.Sprintf("u%04x.wav", r)
+}
+
+// MissingChar is one entry of Stats.Missing.
+type MissingChar struct {
+	Char  rune
+	Count int
+}
+
+// MissingChars returns the characters typed that had no sound, most often
+// typed first.
+func (st *Stats) MissingChars() []MissingChar {
+	var chars []MissingChar
+	for key, n := range st.Missing {
+		r, _ := utf8.DecodeRuneInString(key)
+		chars = append(chars, MissingChar{Char: r, Count: n})
+	}
+	sort.Slice(chars, func(i, j int) bool {
+		if chars[i].Count != chars[j].Count {
+			return chars[i].Count > chars[j].Count
+		}
+		return chars[i].Char < chars[j].Char
+	})
+	return chars
+}
+
+// unknownChar handles char, a typed character the pack has no sound for.
+func (e *Engine) unknownChar(char rune) {
+	if unicode.IsSpace(char) || unicode.IsControl(char) {
+		return
+	}
+	switch e.unknown {
+	case UnknownClick:
+		e.enqueue(&queuedSound{sound: "unknown:" + string(char), buffer: keyClick()})
+	case UnknownSay:
+		e.enqueueSay(SymbolName(char))
+	case UnknownLog:
+		e.debugf("No sound for %q\n", char)
+		if stats := e.recordingStats(); stats != nil {
+			stats.Update(func(st *Stats) {
+				if st.Missing == nil {
+					st.Missing = make(map[string]int)
+				}
+				st.Missing[string(char)]++
+			})
+		}
+	}
+}
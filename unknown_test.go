@@ -0,0 +1,56 @@
+package phonical_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestUnknownChars(t *testing.T) {
+	for _, tt := range []struct {
+		mode string
+		want []string
+	}{
+		{phonical.UnknownSilent, []string{"a.wav"}},
+		{phonical.UnknownClick, []string{"a.wav", "unknown:?"}},
+		{phonical.UnknownSay, []string{"a.wav", "say:question mark"}},
+	} {
+		h := phonicaltest.New(t, phonical.WithUnknownChars(tt.mode))
+		h.Type("a?")
+		h.Expect(tt.want...)
+	}
+}
+
+func TestUnknownCharsLogged(t *testing.T) {
+	if !phonical.HasBuiltinSounds() {
+		t.Skip("lite build")
+	}
+	stats, err := phonical.OpenStats(filepath.Join(t.TempDir(), "stats.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := phonicaltest.New(t, phonical.WithStats(stats), phonical.WithUnknownChars(phonical.UnknownLog))
+	h.Type("?a?!")
+	h.Expect("a.wav")
+	st := stats.Snapshot()
+	want := []phonical.MissingChar{{Char: '?', Count: 2}, {Char: '!', Count: 1}}
+	if got := st.MissingChars(); !reflect.DeepEqual(got, want) {
+		t.Errorf("missing %+v, want %+v", got, want)
+	}
+
+	pack := phonical.DefaultPack()
+	for r, want := range map[rune]string{'é': pack.Letters['e'], '?': "question-mark.wav", 'ß': "u00df.wav"} {
+		if got := pack.SuggestSound(r); got != want {
+			t.Errorf("suggested %q for %q, want %q", got, r, want)
+		}
+	}
+}
+
+func TestUnknownCharsChecked(t *testing.T) {
+	if _, err := phonical.New(phonical.WithUnknownChars("beep")); err == nil {
+		t.Error("accepted an unknown way of handling unknown characters")
+	}
+}
@@ -0,0 +1,54 @@
+package phonical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// silentSayTime is how long a spoken phrase is assumed to take when audio
+// is off, so activities keep their pace while the caption is read.
+const silentSayTime = 1500 * time.Millisecond
+
+// WithVisual shows every letter as a bright flash in the terminal and
+// prints spoken phrases as captions, for children who are deaf or hard of
+// hearing.
+func WithVisual(enabled bool) Option {
+	return func(e *Engine) {
+		e.visual = enabled
+	}
+}
+
+// WithSilent turns audio off entirely. It is meant to be combined with
+// WithVisual; no audio device is opened.
+func WithSilent(enabled bool) Option {
+	return func(e *Engine) {
+		e.silent = enabled
+	}
+}
+
+// flashLetter shows letter in bold reverse video so it stands out.
+// With an output latency set, it waits that long so the letter appears as
+// its sound is heard.
+func (e *Engine) flashLetter(letter rune) {
+	show := func() {
+		fmt.Fprintf(e.out, "\x1b[1;7;93m   %c %c   \x1b[0m\n", unicode.ToUpper(letter), letter)
+	}
+	if e.outputLatency <= 0 {
+		show()
+		return
+	}
+	go func() {
+		select {
+		case <-e.clock.After(e.outputLatency):
+			show()
+		case <-e.ctx.Done():
+		}
+	}()
+}
+
+// caption prints a spoken phrase.
+func (e *Engine) caption(text string) {
+	fmt.Fprintf(e.out, "\x1b[1m» %s\x1b[0m\n", strings.TrimSpace(text))
+}
@@ -0,0 +1,60 @@
+package phonical_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// syncBuffer is a bytes.Buffer safe to write from the engine while a test
+// reads it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// waitOutput lets virtual time run on until out contains want.
+func waitOutput(t *testing.T, h *phonicaltest.Harness, out *syncBuffer, want string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(out.String(), want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("output %q, want %q in it", out.String(), want)
+		}
+		h.Clock.AdvanceToNext()
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestVisualWithoutSound(t *testing.T) {
+	var out syncBuffer
+	h := phonicaltest.New(t, phonical.WithVisual(true), phonical.WithSilent(true),
+		phonical.WithMode(phonical.ModeBlend), phonical.WithOutput(&out))
+
+	// Letters flash and the spoken word is captioned, with nothing played
+	h.Type("at{space}")
+	waitOutput(t, h, &out, "   A a   ")
+	waitOutput(t, h, &out, "   T t   ")
+	waitOutput(t, h, &out, "» at")
+	h.Expect()
+	if h.Sink.Inits() != 0 {
+		t.Error("silent engine opened the speaker")
+	}
+}
@@ -0,0 +1,106 @@
+package phonical
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/faiface/beep"
+)
+
+// Voice is a playful effect applied to letter sounds. Spoken phrases use
+// the announcer's own voice and are not affected.
+type Voice int
+
+const (
+	// VoiceNormal plays sounds unchanged.
+	VoiceNormal Voice = iota
+	// VoiceChipmunk plays sounds faster and higher.
+	VoiceChipmunk
+	// VoiceGiant plays sounds slower and deeper.
+	VoiceGiant
+	// VoiceRobot gives sounds a metallic, buzzing tone.
+	VoiceRobot
+)
+
+var voiceNames = map[Voice]string{
+	VoiceNormal:   "normal",
+	VoiceChipmunk: "chipmunk",
+	VoiceGiant:    "giant",
+	VoiceRobot:    "robot",
+}
+
+func (v Voice) String() string {
+	if name, ok := voiceNames[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("Voice(%d)", int(v))
+}
+
+// ParseVoice converts a voice name such as "chipmunk" into a Voice.
+func ParseVoice(name string) (Voice, error) {
+	for v, n := range voiceNames {
+		if strings.EqualFold(n, name) {
+			return v, nil
+		}
+	}
+	return VoiceNormal, fmt.Errorf("unknown voice %q (want normal, chipmunk, giant or robot)", name)
+}
+
+// robotFrequency is the ring-modulator carrier for VoiceRobot.
+const robotFrequency = 60
+
+// apply wraps streamer with the voice's effect.
+func (v Voice) apply(streamer beep.Streamer) beep.Streamer {
+	switch v {
+	case VoiceChipmunk:
+		return beep.ResampleRatio(4, 1.6, streamer)
+	case VoiceGiant:
+		return beep.ResampleRatio(4, 0.65, streamer)
+	case VoiceRobot:
+		return ringModulate(streamer, robotFrequency)
+	}
+	return streamer
+}
+
+// ringModulate multiplies streamer by a sine carrier at freq.
+func ringModulate(streamer beep.Streamer, freq float64) beep.Streamer {
+	sr := float64(outputFormat.SampleRate)
+	i := 0
+	return beep.StreamerFunc(func(samples [][2]float64) (int, bool) {
+		n, ok := streamer.Stream(samples)
+		for j := range samples[:n] {
+			// Keep some of the dry signal so phonemes stay recognisable
+			g := 0.4 + 0.6*math.Sin(2*math.Pi*freq*float64(i)/sr)
+			samples[j][0] *= g
+			samples[j][1] *= g
+			i++
+		}
+		return n, ok
+	})
+}
+
+// currentVoice returns the voice in use.
+func (e *Engine) currentVoice() Voice {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.voice
+}
+
+// SetVoice changes the voice while the engine runs.
+func (e *Engine) SetVoice(v Voice) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.voice = v
+}
+
+// nextVoice switches to the next voice and announces it.
+func (e *Engine) nextVoice() {
+	e.mu.Lock()
+	e.voice = (e.voice + 1) % Voice(len(voiceNames))
+	v := e.voice
+	e.mu.Unlock()
+
+	e.debugf("Voice: %s\n", v)
+	e.enqueueSay(v.String() + " voice")
+}
@@ -0,0 +1,67 @@
+package phonical
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultWatchdog is how long letters may be typed without a single sound
+// playing before the watchdog steps in.
+const DefaultWatchdog = 15 * time.Second
+
+// watchdogLetters is how many letters must be typed in one watchdog period
+// for silence to count as a failure rather than a pause.
+const watchdogLetters = 3
+
+var errSilent = errors.New("letters typed but no sound played")
+
+// WithWatchdog sets how long letters may be typed without any sound playing,
+// as when every recording fails to decode or the speaker has died quietly,
+// before the engine notifies and restarts audio. 0 turns the watchdog off.
+// Defaults to DefaultWatchdog.
+func WithWatchdog(d time.Duration) Option {
+	return func(e *Engine) {
+		e.watchdog = d
+	}
+}
+
+// watch checks every watchdog period that letters typed are being heard,
+// until ctx is cancelled. It steps in once per silent spell.
+func (e *Engine) watch(ctx context.Context) {
+	letters, heard := e.letterCount(), e.heard.Load()
+	decodeErrors := e.metrics.decodeErrors.Load()
+	tripped := false
+	for {
+		select {
+		case <-e.clock.After(e.watchdog):
+		case <-ctx.Done():
+			return
+		}
+		nowLetters, nowHeard := e.letterCount(), e.heard.Load()
+		nowErrors := e.metrics.decodeErrors.Load()
+		switch {
+		case nowHeard != heard:
+			tripped = false
+		case !tripped && !e.muted() && nowLetters-letters >= watchdogLetters:
+			tripped = true
+			e.metrics.watchdogTrips.Add(1)
+			if nowErrors > decodeErrors {
+				e.logf("Watchdog: %v; sounds are failing to load (%d errors)", errSilent, nowErrors-decodeErrors)
+				e.notify("Phonical's sounds aren't loading. Check the sound pack, or run phonical debug dump.")
+			} else {
+				e.logf("Watchdog: %v; restarting audio", errSilent)
+				e.notify("Phonical went quiet, so it is restarting its sound.")
+			}
+			e.restartAudio.Store(true)
+		}
+		letters, heard, decodeErrors = nowLetters, nowHeard, nowErrors
+	}
+}
+
+// letterCount returns how many letters have been typed this session.
+func (e *Engine) letterCount() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.session.Letters
+}
@@ -0,0 +1,73 @@
+package phonical_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+// watchdogTrips waits for the engine's watchdog to have tripped want
+// times, failing if it hasn't within a second.
+func watchdogTrips(t *testing.T, h *phonicaltest.Harness, want int) {
+	t.Helper()
+	line := fmt.Sprintf("phonical_watchdog_trips_total %d\n", want)
+	deadline := time.Now().Add(time.Second)
+	for {
+		var buf bytes.Buffer
+		if err := h.Engine.WriteMetrics(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(buf.String(), line) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("metrics missing %q:\n%s", line, buf.String())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWatchdog(t *testing.T) {
+	// Every recording fails to decode, so only the fallback tone plays
+	dir := writePack(t, "name = 'Broken'\n[letters]\na = 'a.wav'\nb = 'b.wav'\nc = 'c.wav'\n", map[string][]byte{
+		"a.wav": []byte("watchdog a"), "b.wav": []byte("watchdog b"), "c.wav": []byte("watchdog c"),
+	})
+	pack, err := phonical.LoadPack(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := phonicaltest.New(t, phonical.WithPack(pack), phonical.WithWatchdog(time.Second))
+	// The watchdog counts letters from when it starts
+	waitWaiters(t, h.Clock, 1)
+	h.Type("abc")
+	h.Expect("a.wav", "b.wav", "c.wav")
+	for i := 0; i < 2; i++ {
+		waitWaiters(t, h.Clock, 1)
+		h.Clock.Advance(time.Second)
+	}
+	watchdogTrips(t, h, 1)
+
+	// It steps in once per silent spell
+	h.Type("abc")
+	h.Expect("a.wav", "b.wav", "c.wav", "a.wav", "b.wav", "c.wav")
+	waitWaiters(t, h.Clock, 1)
+	h.Clock.Advance(time.Second)
+	watchdogTrips(t, h, 1)
+}
+
+func TestWatchdogQuietWhenHeard(t *testing.T) {
+	h := phonicaltest.New(t, phonical.WithWatchdog(time.Second))
+	waitWaiters(t, h.Clock, 1)
+	h.Type("abc")
+	h.Expect("a.wav", "b.wav", "c.wav")
+	for i := 0; i < 2; i++ {
+		waitWaiters(t, h.Clock, 1)
+		h.Clock.Advance(time.Second)
+	}
+	watchdogTrips(t, h, 0)
+}
@@ -0,0 +1,88 @@
+package phonical_test
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// wsClient is just enough of a WebSocket client to talk to phonical's
+// servers.
+type wsClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// dialWebSocket opens a WebSocket to path on srv, closing it when the test
+// ends.
+func dialWebSocket(t *testing.T, srv *httptest.Server, path string) *wsClient {
+	t.Helper()
+	addr := srv.Listener.Addr().String()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	io.WriteString(conn, "GET "+path+" HTTP/1.1\r\nHost: "+addr+
+		"\r\nConnection: Upgrade\r\nUpgrade: websocket\r\nSec-WebSocket-Version: 13"+
+		"\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n")
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake for %s: status %d", path, resp.StatusCode)
+	}
+	return &wsClient{conn: conn, r: r}
+}
+
+// send sends msg as a masked text frame, as clients must.
+func (c *wsClient) send(t *testing.T, msg string) {
+	t.Helper()
+	frame := []byte{0x81, 0x80 | byte(len(msg)), 1, 2, 3, 4}
+	for i := 0; i < len(msg); i++ {
+		frame = append(frame, msg[i]^frame[2+i%4])
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// read returns the next text message.
+func (c *wsClient) read(t *testing.T) string {
+	t.Helper()
+	var head [2]byte
+	if _, err := io.ReadFull(c.r, head[:]); err != nil {
+		t.Fatal(err)
+	}
+	n := int(head[1] & 0x7F)
+	if n == 126 {
+		var ext [2]byte
+		if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+			t.Fatal(err)
+		}
+		n = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		t.Fatal(err)
+	}
+	return string(payload)
+}
+
+// closed reports whether the server has closed the connection, reading
+// anything left. A reset counts, since the server may close with frames
+// from the client still unread.
+func (c *wsClient) closed() bool {
+	_, err := io.Copy(io.Discard, c.r)
+	var ne net.Error
+	return !errors.As(err, &ne) || !ne.Timeout()
+}
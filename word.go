@@ -0,0 +1,73 @@
+package phonical
+
+// wordBuffer holds the word currently being typed. It models edits as well
+// as appends so it stays in step with what is actually on screen.
+type wordBuffer struct {
+	runes  []rune
+	cursor int
+}
+
+// insert adds r at the cursor.
+func (w *wordBuffer) insert(r rune) {
+	w.runes = append(w.runes, 0)
+	copy(w.runes[w.cursor+1:], w.runes[w.cursor:])
+	w.runes[w.cursor] = r
+	w.cursor++
+}
+
+// backspace removes the letter before the cursor.
+func (w *wordBuffer) backspace() (rune, bool) {
+	if w.cursor == 0 {
+		return 0, false
+	}
+	w.cursor--
+	r := w.runes[w.cursor]
+	w.runes = append(w.runes[:w.cursor], w.runes[w.cursor+1:]...)
+	return r, true
+}
+
+// delete removes the letter after the cursor.
+func (w *wordBuffer) delete() (rune, bool) {
+	if w.cursor >= len(w.runes) {
+		return 0, false
+	}
+	r := w.runes[w.cursor]
+	w.runes = append(w.runes[:w.cursor], w.runes[w.cursor+1:]...)
+	return r, true
+}
+
+// move shifts the cursor by delta, staying within the word.
+func (w *wordBuffer) move(delta int) {
+	w.cursor += delta
+	if w.cursor < 0 {
+		w.cursor = 0
+	}
+	if w.cursor > len(w.runes) {
+		w.cursor = len(w.runes)
+	}
+}
+
+// set replaces the buffer with word, cursor at the end.
+func (w *wordBuffer) set(word string) {
+	w.runes = []rune(word)
+	w.cursor = len(w.runes)
+}
+
+// beforeCursor returns the letters up to the cursor.
+func (w *wordBuffer) beforeCursor() string {
+	return string(w.runes[:w.cursor])
+}
+
+// reset empties the buffer.
+func (w *wordBuffer) reset() {
+	w.runes = w.runes[:0]
+	w.cursor = 0
+}
+
+func (w *wordBuffer) len() int {
+	return len(w.runes)
+}
+
+func (w *wordBuffer) String() string {
+	return string(w.runes)
+}
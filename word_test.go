@@ -0,0 +1,34 @@
+package phonical_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"phonical"
+	"phonical/phonicaltest"
+)
+
+func TestReopenedWordCountedOnce(t *testing.T) {
+	var mu sync.Mutex
+	var words []string
+	h := phonicaltest.New(t, phonical.OnWord(func(ev phonical.WordEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		words = append(words, ev.Word)
+	}))
+
+	// Backspace over the space reopens cat, and finishing it as cats
+	// doesn't count another word
+	h.Type("cat{space}{backspace}s{space}dog{space}x")
+	h.Expect("c.wav", "a.wav", "t.wav", "s.wav", "d.wav", "o.wav", "g.wav", "x.wav")
+
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"cat", "dog"}; !reflect.DeepEqual(words, want) {
+		t.Errorf("words %q, want %q", words, want)
+	}
+	if last := h.Engine.Status().LastWord; last != "dog" {
+		t.Errorf("last word %q, want dog", last)
+	}
+}